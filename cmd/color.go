@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Values accepted by the --color flag.
+const (
+	colorAuto   = "auto"   // Detect from the terminal and NO_COLOR.
+	colorAlways = "always" // Emit color even when output is piped.
+	colorNever  = "never"  // Emit plain text.
+)
+
+// ColorProfileSetter applies a color profile to everything rendered with lipgloss.
+type ColorProfileSetter func(profile termenv.Profile)
+
+// currentColorProfileSetter holds the active profile setter (can be overridden in tests).
+var currentColorProfileSetter ColorProfileSetter = lipgloss.SetColorProfile
+
+// setColorProfileSetter allows tests to observe the applied profile without a terminal.
+// Returns a cleanup function to restore the original setter.
+func setColorProfileSetter(setter ColorProfileSetter) func() {
+	original := currentColorProfileSetter
+	currentColorProfileSetter = setter
+	return func() {
+		currentColorProfileSetter = original
+	}
+}
+
+// applyColorMode overrides lipgloss's detected color profile according to the --color flag.
+// "auto" (or an empty mode) keeps the detection, which already honours NO_COLOR.
+func applyColorMode(mode string) error {
+	switch mode {
+	case "", colorAuto:
+		return nil
+	case colorAlways:
+		currentColorProfileSetter(termenv.TrueColor)
+	case colorNever:
+		currentColorProfileSetter(termenv.Ascii)
+	default:
+		return fmt.Errorf("invalid --color value %q: must be %s, %s or %s", mode, colorAuto, colorAlways, colorNever)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyColorMode(t *testing.T) {
+	trueColor, ascii := termenv.TrueColor, termenv.Ascii
+	tests := []struct {
+		name        string
+		mode        string
+		wantProfile *termenv.Profile
+	}{
+		{name: "always forces true color", mode: colorAlways, wantProfile: &trueColor},
+		{name: "never disables color", mode: colorNever, wantProfile: &ascii},
+		{name: "auto keeps detection", mode: colorAuto, wantProfile: nil},
+		{name: "empty keeps detection", mode: "", wantProfile: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied *termenv.Profile
+			restore := setColorProfileSetter(func(profile termenv.Profile) {
+				applied = &profile
+			})
+			defer restore()
+
+			require.NoError(t, applyColorMode(tt.mode))
+			assert.Equal(t, tt.wantProfile, applied)
+		})
+	}
+}
+
+func TestApplyColorMode_Invalid(t *testing.T) {
+	called := false
+	restore := setColorProfileSetter(func(termenv.Profile) { called = true })
+	defer restore()
+
+	err := applyColorMode("sometimes")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sometimes")
+	assert.False(t, called)
+}
@@ -4,8 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/tui"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -141,3 +144,293 @@ commands:
 		})
 	}
 }
+
+// writeHomeAndProjectConfigs writes .terrax.yaml files into a fake home directory and a
+// project directory, points HOME at the former and changes into the latter.
+func writeHomeAndProjectConfigs(t *testing.T, homeContent, projectContent string) {
+	t.Helper()
+	homeDir := t.TempDir()
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(homeDir, ".terrax.yaml"), []byte(homeContent), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".terrax.yaml"), []byte(projectContent), 0644))
+	t.Setenv("HOME", homeDir)
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(projectDir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(originalWd))
+		viper.Reset()
+	})
+}
+
+// TestInitConfig_MergeWithHome tests that with config_precedence: merge, keys unset in the
+// project config are inherited from the home config while set keys override it.
+func TestInitConfig_MergeWithHome(t *testing.T) {
+	writeHomeAndProjectConfigs(t, `emoji: false
+column_width: 30
+commands:
+  - plan
+history:
+  max_entries: 42
+`, `config_precedence: merge
+commands:
+  - apply
+  - destroy
+history:
+  table:
+    striped: false
+`)
+
+	initConfig()
+
+	assert.Equal(t, []string{"apply", "destroy"}, viper.GetStringSlice("commands"))
+	assert.False(t, viper.GetBool("history.table.striped"))
+	assert.False(t, viper.GetBool("emoji"))
+	assert.Equal(t, 30, viper.GetInt("column_width"))
+	assert.Equal(t, 42, viper.GetInt("history.max_entries"))
+}
+
+// TestInitConfig_MergeSetInHome tests that config_precedence can be enabled from the home config.
+func TestInitConfig_MergeSetInHome(t *testing.T) {
+	writeHomeAndProjectConfigs(t, `config_precedence: merge
+column_width: 30
+`, `commands:
+  - apply
+`)
+
+	initConfig()
+
+	assert.Equal(t, []string{"apply"}, viper.GetStringSlice("commands"))
+	assert.Equal(t, 30, viper.GetInt("column_width"))
+}
+
+// TestInitConfig_OverrideIgnoresHome tests that by default the project config replaces the
+// home config wholesale.
+func TestInitConfig_OverrideIgnoresHome(t *testing.T) {
+	writeHomeAndProjectConfigs(t, `column_width: 30
+`, `commands:
+  - apply
+`)
+
+	initConfig()
+
+	assert.Equal(t, []string{"apply"}, viper.GetStringSlice("commands"))
+	assert.Equal(t, 0, viper.GetInt("column_width"))
+}
+
+// TestInitConfig_XDGConfigHome tests that a .terrax.yaml in $XDG_CONFIG_HOME/terrax replaces
+// the one in the home directory, which is used when XDG_CONFIG_HOME is unset.
+func TestInitConfig_XDGConfigHome(t *testing.T) {
+	writeHomeAndProjectConfigs(t, "column_width: 20\n", "")
+	require.NoError(t, os.Remove(".terrax.yaml"))
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	initConfig()
+	assert.Equal(t, 20, viper.GetInt("column_width"), "unset XDG_CONFIG_HOME falls back to the home directory")
+
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	viper.Reset()
+	initConfig()
+	assert.Equal(t, 20, viper.GetInt("column_width"), "an XDG directory without a config falls back to the home directory")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "terrax"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, "terrax", ".terrax.yaml"), []byte("column_width: 30\n"), 0644))
+	viper.Reset()
+	initConfig()
+	assert.Equal(t, 30, viper.GetInt("column_width"))
+}
+
+// TestInitConfig_ExpandsPaths tests that ~ and environment variables in path-like keys are expanded.
+func TestInitConfig_ExpandsPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TERRAX_REPORTS", "/srv/reports")
+
+	configContent := `plan:
+  json_out_dir: ~/.terrax/plans
+run_logs:
+  dir: $HOME/.terrax/logs
+features:
+  report:
+    file: ${TERRAX_REPORTS}/report.md
+state:
+  aws_config_file: /etc/aws/config
+commands:
+  - $HOME
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".terrax.yaml"), []byte(configContent), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(originalWd))
+		viper.Reset()
+	})
+
+	initConfig()
+
+	assert.Equal(t, filepath.Join(home, ".terrax", "plans"), viper.GetString("plan.json_out_dir"))
+	assert.Equal(t, home+"/.terrax/logs", viper.GetString("run_logs.dir"))
+	assert.Equal(t, "/srv/reports/report.md", viper.GetString("features.report.file"))
+	assert.Equal(t, "/etc/aws/config", viper.GetString("state.aws_config_file"), "absolute paths are unchanged")
+	assert.Equal(t, []string{"$HOME"}, viper.GetStringSlice("commands"), "non-path keys are not expanded")
+}
+
+// TestLoadTUIConfig_KeyBindings tests that keybindings from the config file are merged
+// with the defaults and that colliding keys fail to load.
+func TestLoadTUIConfig_KeyBindings(t *testing.T) {
+	writeConfig := func(t *testing.T, content string) {
+		tmpDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".terrax.yaml"), []byte(content), 0644))
+
+		originalWd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(tmpDir))
+		t.Cleanup(func() {
+			require.NoError(t, os.Chdir(originalWd))
+			viper.Reset()
+		})
+		initConfig()
+	}
+
+	t.Run("overrides merge with defaults", func(t *testing.T) {
+		writeConfig(t, `keybindings:
+  quit: x
+  page-down: ctrl+d
+`)
+		cfg, err := loadTUIConfig()
+		require.NoError(t, err)
+		assert.Equal(t, "x", cfg.KeyBindings[config.ActionQuit])
+		assert.Equal(t, "ctrl+d", cfg.KeyBindings[config.ActionPageDown])
+		assert.Equal(t, "/", cfg.KeyBindings[config.ActionFilter])
+	})
+
+	t.Run("colliding keys are rejected", func(t *testing.T) {
+		writeConfig(t, `keybindings:
+  filter: q
+`)
+		_, err := loadTUIConfig()
+		assert.ErrorContains(t, err, `key "q" is bound to both "filter" and "quit"`)
+	})
+}
+
+// TestLoadTUIConfig_IdleTimeout tests that idle_timeout is decoded as a duration and is
+// disabled by default.
+func TestLoadTUIConfig_IdleTimeout(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetDefault("idle_timeout", config.DefaultIdleTimeout)
+
+	cfg, err := loadTUIConfig()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.IdleTimeout)
+
+	viper.Set("idle_timeout", "15m")
+	cfg, err = loadTUIConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, cfg.IdleTimeout)
+}
+
+func TestLoadTUIConfig_RequireConfirmation(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetDefault("require_confirmation", config.DefaultRequireConfirmation)
+
+	cfg, err := loadTUIConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"destroy", "apply"}, cfg.RequireConfirmation)
+
+	viper.Set("require_confirmation", []string{})
+	cfg, err = loadTUIConfig()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.RequireConfirmation, "an empty list turns the prompt off")
+}
+
+// TestEnsureConfigFromWorkDir_Profile tests that the selected profile overlays its keys on
+// the base config and that an unknown profile fails to load.
+func TestEnsureConfigFromWorkDir_Profile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	configContent := `binary: terragrunt
+commands:
+  - plan
+  - apply
+terragrunt:
+  parallelism: 4
+profiles:
+  CI:
+    binary: tofu
+    commands:
+      - validate
+    terragrunt:
+      no_color: true
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".terrax.yaml"), []byte(configContent), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	t.Cleanup(func() {
+		require.NoError(t, os.Chdir(originalWd))
+		viper.Reset()
+	})
+
+	t.Run("no profile keeps the base config", func(t *testing.T) {
+		viper.Reset()
+		initConfig()
+		require.NoError(t, ensureConfigFromWorkDir(tmpDir))
+
+		assert.Equal(t, "terragrunt", viper.GetString("binary"))
+		assert.Equal(t, []string{"plan", "apply"}, viper.GetStringSlice("commands"))
+	})
+
+	t.Run("profile overrides binary and commands", func(t *testing.T) {
+		viper.Reset()
+		initConfig()
+		viper.Set("profile", "ci")
+		require.NoError(t, ensureConfigFromWorkDir(tmpDir))
+
+		assert.Equal(t, "tofu", viper.GetString("binary"))
+		assert.Equal(t, []string{"validate"}, viper.GetStringSlice("commands"))
+		assert.True(t, viper.GetBool("terragrunt.no_color"))
+		assert.Equal(t, 4, viper.GetInt("terragrunt.parallelism"), "keys the profile leaves out keep their value")
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		viper.Reset()
+		initConfig()
+		viper.Set("profile", "prod")
+
+		err := ensureConfigFromWorkDir(tmpDir)
+		require.Error(t, err)
+		assert.Equal(t, `unknown profile "prod" (available: ci)`, err.Error())
+	})
+}
+
+// TestLoadTUIConfig_Theme tests that the theme section is decoded into the TUI settings and
+// read the same way for the history viewer.
+func TestLoadTUIConfig_Theme(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetDefault("theme.preset", config.DefaultThemePreset)
+
+	cfg, err := loadTUIConfig()
+	require.NoError(t, err)
+	assert.Equal(t, config.TUITheme{Preset: "default"}, cfg.Theme)
+	assert.Equal(t, tui.DefaultTheme(), loadTheme())
+
+	viper.Set("theme.preset", "high-contrast")
+	viper.Set("theme.success", "#123456")
+	cfg, err = loadTUIConfig()
+	require.NoError(t, err)
+	assert.Equal(t, config.TUITheme{Preset: "high-contrast", Success: "#123456"}, cfg.Theme)
+
+	theme := loadTheme()
+	assert.Equal(t, lipgloss.Color("#123456"), theme.Success)
+	assert.Equal(t, tui.HighContrastTheme().Error, theme.Error)
+}
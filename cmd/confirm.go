@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// Prompter asks the user a yes/no question and reports whether they answered yes.
+type Prompter func(question string) bool
+
+// currentPrompter holds the active prompter (can be overridden in tests).
+var currentPrompter Prompter = stdinPrompter
+
+// stdinPrompter writes question to stderr and reads the answer from stdin.
+// Only "y" or "yes" (in any case) count as yes; anything else, including EOF, is no.
+func stdinPrompter(question string) bool {
+	fmt.Fprint(os.Stderr, question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// setPrompter allows tests to answer prompts without a terminal.
+// Returns a cleanup function to restore the original prompter.
+func setPrompter(prompter Prompter) func() {
+	original := currentPrompter
+	currentPrompter = prompter
+	return func() {
+		currentPrompter = original
+	}
+}
+
+// confirmStackCount counts the stacks under workDir when max_stacks is set and, when there
+// are more, asks before the tree is built. The count stops as soon as it passes max_stacks,
+// so an oversized tree is not walked twice. It returns an error when the user declines.
+// force skips the count.
+func confirmStackCount(workDir string, force bool) error {
+	maxStacks := viper.GetInt("max_stacks")
+	if maxStacks <= 0 || force {
+		return nil
+	}
+
+	stats, err := stack.ScanTreeLimit(workDir, viper.GetString("root_config_file"), treeOptions(), maxStacks)
+	if err != nil {
+		return fmt.Errorf("failed to scan stacks: %w", err)
+	}
+	if stats.Stacks <= maxStacks {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "⚠️  Found more than max_stacks (%d) stacks under %s.\n", maxStacks, workDir)
+	if !currentPrompter("Build the full tree anyway? [y/N] ") {
+		return fmt.Errorf("found more than max_stacks (%d) stacks; rerun with --force or raise max_stacks", maxStacks)
+	}
+	return nil
+}
+
+// confirmDirtyRun asks before a dangerous command (dangerous_commands) runs against stacks
+// with uncommitted changes, when warn_on_dirty_apply is enabled. It returns false when the
+// user declines. Other commands, clean stacks and paths outside a repository proceed.
+func confirmDirtyRun(command string, stackPaths []string) bool {
+	if !viper.GetBool("warn_on_dirty_apply") || !slices.Contains(viper.GetStringSlice("dangerous_commands"), command) {
+		return true
+	}
+
+	var dirtyPaths []string
+	for _, path := range stackPaths {
+		if dirty, err := currentDirtyCheck(path); err == nil && dirty {
+			dirtyPaths = append(dirtyPaths, path)
+		}
+	}
+	if len(dirtyPaths) == 0 {
+		return true
+	}
+
+	fmt.Fprintln(os.Stderr, "⚠️  Uncommitted changes in:")
+	for _, path := range dirtyPaths {
+		fmt.Fprintf(os.Stderr, "   %s\n", path)
+	}
+	return currentPrompter(fmt.Sprintf("Run %s anyway? [y/N] ", command))
+}
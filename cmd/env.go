@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EnvVarConfig is one environment variable injected into executed commands, loaded from
+// env_vars in .terrax.yaml. Stack and Command scope it; with neither it applies to every run.
+type EnvVarConfig struct {
+	Name    string `mapstructure:"name"`
+	Value   string `mapstructure:"value"`
+	Stack   string `mapstructure:"stack"`   // Stack path prefix relative to the repo root (e.g. "prod/us-east-1")
+	Command string `mapstructure:"command"` // Command the variable applies to (e.g. "apply")
+}
+
+// loadEnvVars reads the env_vars section from viper config. Entries without a name are dropped.
+func loadEnvVars() []EnvVarConfig {
+	var vars []EnvVarConfig
+	if err := viper.UnmarshalKey("env_vars", &vars); err != nil {
+		return nil
+	}
+	return slices.DeleteFunc(vars, func(v EnvVarConfig) bool { return v.Name == "" })
+}
+
+// resolveEnvVars returns the environment variables to inject when command runs against
+// paths (relative to the repo root) as one execution of a stack group with groupEnv.
+// When a variable is set more than once, the most specific value wins: env_vars scoped
+// to a stack override the stack group's env, which overrides env_vars scoped to the
+// command, which override the unscoped ones. Among stack-scoped values the longest
+// prefix wins, and one also scoped to the command wins over one that is not. A stack
+// scope only applies when every path of the execution is under it.
+func resolveEnvVars(command string, paths []string, groupEnv map[string]string) map[string]string {
+	var matching []EnvVarConfig
+	for _, v := range loadEnvVars() {
+		if v.Command != "" && v.Command != command {
+			continue
+		}
+		if stackPrefix(v.Stack) != "" && !allUnderPrefix(paths, v.Stack) {
+			continue
+		}
+		matching = append(matching, v)
+	}
+	// Least specific first, so more specific values are applied over them.
+	slices.SortStableFunc(matching, func(a, b EnvVarConfig) int {
+		return cmp.Or(
+			cmp.Compare(len(stackPrefix(a.Stack)), len(stackPrefix(b.Stack))),
+			boolCompare(a.Command != "", b.Command != ""),
+		)
+	})
+
+	env := make(map[string]string, len(matching)+len(groupEnv))
+	stackScoped := slices.IndexFunc(matching, func(v EnvVarConfig) bool { return stackPrefix(v.Stack) != "" })
+	if stackScoped < 0 {
+		stackScoped = len(matching)
+	}
+	for _, v := range matching[:stackScoped] {
+		env[v.Name] = v.Value
+	}
+	for name, value := range groupEnv {
+		env[name] = value
+	}
+	for _, v := range matching[stackScoped:] {
+		env[v.Name] = v.Value
+	}
+	return env
+}
+
+// stackPrefix normalizes a stack scope of env_vars to a slash-separated path without
+// leading or trailing slashes.
+func stackPrefix(stack string) string {
+	return strings.Trim(filepath.ToSlash(stack), "/")
+}
+
+// allUnderPrefix reports whether every path is the stack prefix itself or below it.
+func allUnderPrefix(paths []string, stack string) bool {
+	prefix := stackPrefix(stack)
+	if len(paths) == 0 {
+		return false
+	}
+	for _, p := range paths {
+		p = strings.Trim(filepath.ToSlash(p), "/")
+		if p != prefix && !strings.HasPrefix(p, prefix+"/") {
+			return false
+		}
+	}
+	return true
+}
+
+// boolCompare orders false before true.
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
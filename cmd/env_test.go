@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolveEnvVars tests the environment assembled from env_vars and the stack group's
+// env for a command run against a set of stacks.
+func TestResolveEnvVars(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(`
+env_vars:
+  - name: AWS_PROFILE
+    value: prod-admin
+    stack: prod/
+  - name: AWS_PROFILE
+    value: default
+  - name: TF_LOG
+    value: DEBUG
+    command: apply
+  - name: AWS_PROFILE
+    value: apply-role
+    command: apply
+  - name: AWS_PROFILE
+    value: prod-east
+    stack: prod/us-east-1
+  - name: TF_VAR_region
+    value: us-east-1
+    stack: prod/us-east-1
+  - value: unnamed
+`)))
+
+	tests := []struct {
+		name     string
+		command  string
+		paths    []string
+		groupEnv map[string]string
+		want     map[string]string
+	}{
+		{
+			name:    "global only",
+			command: "plan",
+			paths:   []string{"dev/vpc"},
+			want:    map[string]string{"AWS_PROFILE": "default"},
+		},
+		{
+			name:    "command overrides global",
+			command: "apply",
+			paths:   []string{"dev/vpc"},
+			want:    map[string]string{"AWS_PROFILE": "apply-role", "TF_LOG": "DEBUG"},
+		},
+		{
+			name:    "stack overrides command",
+			command: "apply",
+			paths:   []string{"prod/us-west-2/vpc"},
+			want:    map[string]string{"AWS_PROFILE": "prod-admin", "TF_LOG": "DEBUG"},
+		},
+		{
+			name:    "longest stack prefix wins",
+			command: "plan",
+			paths:   []string{"prod/us-east-1/vpc", "prod/us-east-1/rds"},
+			want:    map[string]string{"AWS_PROFILE": "prod-east", "TF_VAR_region": "us-east-1"},
+		},
+		{
+			name:    "stack scope needs every path under it",
+			command: "plan",
+			paths:   []string{"prod/us-east-1/vpc", "prod/us-west-2/vpc"},
+			want:    map[string]string{"AWS_PROFILE": "prod-admin"},
+		},
+		{
+			name:    "prefix matches whole path segments",
+			command: "plan",
+			paths:   []string{"production/vpc"},
+			want:    map[string]string{"AWS_PROFILE": "default"},
+		},
+		{
+			name:     "stack group env sits between command and stack scopes",
+			command:  "apply",
+			paths:    []string{"prod/us-west-2/vpc"},
+			groupEnv: map[string]string{"AWS_PROFILE": "group", "TF_LOG": "TRACE"},
+			want:     map[string]string{"AWS_PROFILE": "prod-admin", "TF_LOG": "TRACE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resolveEnvVars(tt.command, tt.paths, tt.groupEnv))
+		})
+	}
+}
+
+// TestResolveEnvVars_NoneConfigured tests that only the stack group's env is injected
+// without env_vars.
+func TestResolveEnvVars_NoneConfigured(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	assert.Empty(t, resolveEnvVars("plan", []string{"dev"}, nil))
+	assert.Equal(t, map[string]string{"A": "1"}, resolveEnvVars("plan", []string{"dev"}, map[string]string{"A": "1"}))
+}
@@ -25,10 +25,19 @@ func reExecuteHistoryEntry(ctx context.Context, historyService *history.Service,
 	if entry.Command == "force-unlock" {
 		return runForceUnlock(ctx, historyService, absolutePath)
 	}
+	if len(entry.Args) > 0 {
+		// The extra args of the recorded run apply to this run only.
+		viper.Set("terraform.run_flags", entry.Args)
+	}
+
+	if !confirmDirtyRun(entry.Command, []string{absolutePath}) {
+		fmt.Println("Cancelled: stack has uncommitted changes.")
+		return nil
+	}
 
 	repoRoot, filterPaths := collectTransitiveDeps([]string{absolutePath})
 
-	if entry.Command == "plan" && (viper.GetBool("plan.summary_enabled") || viper.GetBool("plan.review_enabled")) {
+	if entry.Command == "plan" && !viper.GetBool("dry_run") && (viper.GetBool("plan.summary_enabled") || viper.GetBool("plan.review_enabled")) {
 		jsonOutDir := viper.GetString("plan.json_out_dir")
 		if jsonOutDir == "" {
 			jsonOutDir = config.DefaultJSONOutDir
@@ -50,10 +59,13 @@ func reExecuteHistoryEntry(ctx context.Context, historyService *history.Service,
 		if group.Skip {
 			continue
 		}
-		if err := executor.Run(ctx, historyService, entry.Command, absolutePath, repoRoot, group.Paths, group.EnvVars); err != nil {
+		if err := executor.Run(ctx, historyService, entry.Command, absolutePath, repoRoot, group.Paths, resolveEnvVars(entry.Command, group.Paths, group.EnvVars)); err != nil {
 			return err
 		}
 	}
+	if viper.GetBool("dry_run") {
+		return nil // Nothing ran, so there are no plans to summarize or review.
+	}
 	if entry.Command == "plan" && viper.GetBool("plan.summary_enabled") {
 		if err := runPlanSummary(ctx, absolutePath, repoRoot); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: plan summary failed: %v\n", err)
@@ -45,35 +45,44 @@ func runFind(cmd *cobra.Command, _ []string) error {
 }
 
 func runFindAll(workDir, rootConfigFile string) error {
-	paths, err := stack.CollectStackPaths(workDir)
+	paths, err := stack.CollectStackPaths(workDir, treeOptions())
 	if err != nil {
 		return fmt.Errorf("failed to collect stack paths: %w", err)
 	}
-	for _, p := range paths {
-		if _, err := fmt.Fprintln(os.Stdout, p); err != nil {
-			return fmt.Errorf("failed to write output: %w", err)
-		}
-	}
-	return nil
+	return printPaths(paths)
 }
 
 func runFindAffected(workDir, rootConfigFile, baseCommit string) error {
+	affected, err := collectAffectedStacks(workDir, rootConfigFile, baseCommit)
+	if err != nil {
+		return err
+	}
+	return printPaths(affected)
+}
+
+// collectAffectedStacks returns the absolute paths of stacks under workDir affected by
+// changes between baseCommit and HEAD.
+func collectAffectedStacks(workDir, rootConfigFile, baseCommit string) ([]string, error) {
 	graph, err := changes.BuildFileGraph(workDir, rootConfigFile)
 	if err != nil {
-		return fmt.Errorf("failed to build file graph: %w", err)
+		return nil, fmt.Errorf("failed to build file graph: %w", err)
 	}
 
-	tree, _, err := stack.FindAndBuildTree(workDir, rootConfigFile)
+	tree, _, err := stack.FindAndBuildTreeWithOptions(workDir, rootConfigFile, treeOptions())
 	if err != nil {
-		return fmt.Errorf("failed to build stack tree: %w", err)
+		return nil, fmt.Errorf("failed to build stack tree: %w", err)
 	}
 
 	affected, err := changes.AffectedStacks(workDir, baseCommit, graph, tree)
 	if err != nil {
-		return fmt.Errorf("failed to detect affected stacks: %w", err)
+		return nil, fmt.Errorf("failed to detect affected stacks: %w", err)
 	}
+	return affected, nil
+}
 
-	for _, p := range affected {
+// printPaths writes one path per line to stdout.
+func printPaths(paths []string) error {
+	for _, p := range paths {
 		if _, err := fmt.Fprintln(os.Stdout, p); err != nil {
 			return fmt.Errorf("failed to write output: %w", err)
 		}
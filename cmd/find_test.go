@@ -131,3 +131,69 @@ func TestFindCmd_WithBase_NoChanges_EmptyOutput(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, strings.TrimSpace(string(out)))
 }
+
+func TestListStacks_Text(t *testing.T) {
+	bin := buildTerrax(t)
+	root, _ := findTestRepo(t)
+
+	out, err := exec.Command(bin, "--list-stacks", "--dir", root).Output()
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Equal(t, []string{"workloads/prod/api", "workloads/prod/db"}, lines)
+}
+
+func TestListStacks_JSONWithFilter(t *testing.T) {
+	bin := buildTerrax(t)
+	root, _ := findTestRepo(t)
+
+	out, err := exec.Command(bin, "--list-stacks", "--format", "json", "--dir", root).Output()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["workloads/prod/api", "workloads/prod/db"]`, string(out))
+
+	out, err = exec.Command(bin, "--list-stacks", "--format", "json", "--filter", "*/*/d*", "--dir", root).Output()
+	require.NoError(t, err)
+	assert.JSONEq(t, `["workloads/prod/db"]`, string(out))
+
+	out, err = exec.Command(bin, "--list-stacks", "--format", "json", "--filter", "staging/*", "--dir", root).Output()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(out))
+}
+
+func TestListStacks_WithBase(t *testing.T) {
+	bin := buildTerrax(t)
+	root, baseSHA := findTestRepo(t)
+
+	apiHCL := filepath.Join(root, "workloads", "prod", "api", "terragrunt.hcl")
+	require.NoError(t, os.WriteFile(apiHCL, []byte("# changed"), 0644))
+	exec.Command("git", "-C", root, "add", ".").Run()          //nolint
+	exec.Command("git", "-C", root, "commit", "-m", "c").Run() //nolint
+
+	out, err := exec.Command(bin, "--list-stacks", "--base", baseSHA, "--dir", root).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "workloads/prod/api", strings.TrimSpace(string(out)))
+}
+
+func TestListStacks_InvalidFormat(t *testing.T) {
+	bin := buildTerrax(t)
+	root, _ := findTestRepo(t)
+
+	err := exec.Command(bin, "--list-stacks", "--format", "yaml", "--dir", root).Run()
+	assert.Error(t, err)
+}
+
+func TestListStacks_CountOnly(t *testing.T) {
+	bin := buildTerrax(t)
+	root, _ := findTestRepo(t)
+
+	out, err := exec.Command(bin, "--list-stacks", "--count-only", "--dir", root).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "stacks: 2\nmax depth: 3\nskipped: 1\n", string(out))
+
+	out, err = exec.Command(bin, "--list-stacks", "--count-only", "--format", "json", "--dir", root).Output()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"stacks": 2, "max_depth": 3, "skipped": 1}`, string(out))
+
+	err = exec.Command(bin, "--list-stacks", "--count-only", "--filter", "prod/*", "--dir", root).Run()
+	assert.Error(t, err)
+}
@@ -48,7 +48,9 @@ func runGroupsCmd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
-	ensureConfigFromWorkDir(workDir)
+	if err := ensureConfigFromWorkDir(workDir); err != nil {
+		return err
+	}
 
 	stackFlags, _ := cmd.Flags().GetStringArray("stack")
 
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -28,8 +29,7 @@ var currentHistoryTUIRunner HistoryTUIRunner = defaultHistoryTUIRunner
 func defaultHistoryTUIRunner(initialModel tui.Model) (tui.Model, error) {
 	p := tea.NewProgram(
 		initialModel,
-		tea.WithAltScreen(),
-		tea.WithOutput(os.Stderr),
+		append(initialModel.ProgramOptions(), tea.WithOutput(os.Stderr))...,
 	)
 	finalModel, err := p.Run()
 	if err != nil {
@@ -56,13 +56,16 @@ var historyCmd = &cobra.Command{
 	Use:   "history",
 	Short: "View or export command execution history",
 	Long: `Without --json: opens the interactive TUI history viewer.
-With --json: prints history for the current project as a JSON array for external tools such as editor extensions.`,
+With --json: prints history for the current project as a JSON array for external tools such as editor extensions.
+With --filter: keeps only entries whose note, command or stack path contains the text.`,
 	RunE: runHistoryCmd,
 }
 
 func init() {
 	historyCmd.Flags().String("dir", "", "Working directory (overrides current directory)")
 	historyCmd.Flags().Bool("json", false, "Print history as JSON instead of opening the interactive TUI")
+	historyCmd.Flags().String("filter", "", "Only show entries whose note, command or stack path contains this text")
+	historyCmd.Flags().Bool("group", false, "Open the history viewer grouped by stack, one row per stack with its latest run")
 	rootCmd.AddCommand(historyCmd)
 }
 
@@ -90,11 +93,6 @@ func runHistoryCmdJSON(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize history service: %w", err)
 	}
 
-	entries, err := historyService.LoadAll(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to load history: %w", err)
-	}
-
 	rootConfigFile := viper.GetString("root_config_file")
 	if rootConfigFile == "" {
 		rootConfigFile = config.DefaultRootConfigFile
@@ -109,34 +107,45 @@ func runHistoryCmdJSON(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// FilterByCurrentProject detects the project root from os.Getwd().
-	// Change to workDir first so detection uses the --dir argument.
-	originalDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-	defer func() { _ = os.Chdir(originalDir) }()
+	// A workDir outside any project keeps every entry, as FilterByCurrentProject does.
+	projectRoot, _ := history.FindProjectRoot(workDir, rootConfigFile)
+	textFilter, _ := cmd.Flags().GetString("filter")
 
-	if err := os.Chdir(workDir); err != nil {
-		return fmt.Errorf("failed to change directory: %w", err)
+	// Entries are streamed straight to stdout so large histories are never held in memory.
+	out := bufio.NewWriter(os.Stdout)
+	if _, err := out.WriteString("["); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
 	}
+	first := true
+	err = historyService.Stream(ctx, func(entry history.ExecutionLogEntry) error {
+		if projectRoot != "" && !history.InProject(entry, projectRoot) {
+			return nil
+		}
+		if !history.MatchesText(entry, textFilter) {
+			return nil
+		}
 
-	filtered, err := historyService.FilterByCurrentProject(entries)
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to serialize history: %w", err)
+		}
+		if !first {
+			data = append([]byte(","), data...)
+		}
+		first = false
+		if _, err := out.Write(data); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to filter history: %w", err)
-	}
-
-	// Ensure empty slice marshals as [] not null.
-	if filtered == nil {
-		filtered = []history.ExecutionLogEntry{}
+		return fmt.Errorf("failed to load history: %w", err)
 	}
 
-	data, err := json.Marshal(filtered)
-	if err != nil {
-		return fmt.Errorf("failed to serialize history: %w", err)
+	if _, err := out.WriteString("]\n"); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
 	}
-
-	if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+	if err := out.Flush(); err != nil {
 		return fmt.Errorf("failed to write output: %w", err)
 	}
 	return nil
@@ -159,7 +168,8 @@ func runHistoryCmdTUI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to initialize history service: %w", err)
 	}
 
-	entries, err := historyService.LoadAll(ctx)
+	// Only the tail the viewer can show is read, so huge history files load quickly.
+	entries, err := historyService.LoadRecent(ctx, viper.GetInt("history.max_entries"))
 	if err != nil {
 		return fmt.Errorf("failed to load history: %w", err)
 	}
@@ -181,8 +191,18 @@ func runHistoryCmdTUI(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to filter history: %v\n", err)
 		filteredEntries = entries
 	}
+	textFilter, _ := cmd.Flags().GetString("filter")
+	filteredEntries = history.FilterByText(filteredEntries, textFilter)
 
-	initialModel := tui.NewHistoryModel(filteredEntries)
+	groupFlag, _ := cmd.Flags().GetBool("group")
+	initialModel := tui.NewHistoryModel(filteredEntries).
+		WithHistoryGrouping(groupFlag).
+		WithHistoryTableStyle(loadHistoryTableStyle()).
+		WithAltScreen(!viper.GetBool("disable_alt_screen")).
+		WithLocale(resolveLocale()).
+		WithMessages(loadMessages()).
+		WithTheme(loadTheme()).
+		WithProjectSwitchNotice(historyService.DetectProjectSwitch(entries, workDir))
 
 	model, err := currentHistoryTUIRunner(initialModel)
 	if err != nil {
@@ -203,3 +223,24 @@ func runHistoryCmdTUI(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// loadHistoryTableStyle reads the history.table section and history.changes_exit_codes.
+// Unset values are left empty so the TUI falls back to its built-in defaults.
+func loadHistoryTableStyle() tui.HistoryTableStyle {
+	style := tui.HistoryTableStyle{
+		Striped:          viper.GetBool("history.table.striped"),
+		StripeColor:      viper.GetString("history.table.stripe_color"),
+		CursorForeground: viper.GetString("history.table.cursor_foreground"),
+		CursorBackground: viper.GetString("history.table.cursor_background"),
+	}
+
+	if viper.IsSet("history.changes_exit_codes") {
+		changesExitCodes := map[string][]int{}
+		if err := viper.UnmarshalKey("history.changes_exit_codes", &changesExitCodes); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid history.changes_exit_codes: %v\n", err)
+		} else {
+			style.ChangesExitCodes = changesExitCodes
+		}
+	}
+	return style
+}
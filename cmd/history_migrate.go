@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/israoo/terrax/internal/history"
+)
+
+var historyMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite the history file in the current format",
+	Long: `Rewrite the history file in the current entry format, filling defaults for fields that
+older versions did not record. The original file is kept next to it with a .bak suffix.
+Lines that are not history entries are left out of the rewritten file.`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryMigrateCmd,
+}
+
+func init() {
+	historyCmd.AddCommand(historyMigrateCmd)
+}
+
+// runHistoryMigrateCmd migrates the default history file and reports the outcome.
+func runHistoryMigrateCmd(cmd *cobra.Command, args []string) error {
+	historyPath, err := history.GetHistoryFilePath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve history file path: %w", err)
+	}
+
+	repo, err := history.NewFileRepository(historyPath)
+	if err != nil {
+		return fmt.Errorf("failed to create history repository: %w", err)
+	}
+	result, err := repo.Migrate(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to migrate history: %w", err)
+	}
+	if result.BackupPath == "" {
+		fmt.Printf("No history file at %s; nothing to migrate.\n", historyPath)
+		return nil
+	}
+
+	fmt.Printf("Migrated %d entries in %s\n", result.Migrated, historyPath)
+	if result.Dropped > 0 {
+		fmt.Printf("Left out %d lines that were not history entries\n", result.Dropped)
+	}
+	fmt.Printf("Backup: %s\n", result.BackupPath)
+	return nil
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/israoo/terrax/internal/history"
+	"github.com/israoo/terrax/internal/tui"
+)
+
+const (
+	// historyTailWidth is the table width used for non-interactive history output.
+	historyTailWidth = 120
+
+	// historyFollowInterval is how often the history file is polled in follow mode.
+	historyFollowInterval = 500 * time.Millisecond
+)
+
+// HistoryFollower watches the history file and calls emit for every entry appended
+// after it starts, until ctx is cancelled.
+// This allows dependency injection for testing without a real file watcher.
+type HistoryFollower func(ctx context.Context, filePath string, emit func(history.ExecutionLogEntry)) error
+
+// currentHistoryFollower holds the active history follower (can be overridden in tests).
+var currentHistoryFollower HistoryFollower = defaultHistoryFollower
+
+// defaultHistoryFollower polls the history file, starting from its current end.
+func defaultHistoryFollower(ctx context.Context, filePath string, emit func(history.ExecutionLogEntry)) error {
+	var offset int64
+	if info, err := os.Stat(filePath); err == nil {
+		offset = info.Size()
+	}
+	return history.Follow(ctx, filePath, offset, historyFollowInterval, emit)
+}
+
+// setHistoryFollower allows tests to inject a custom history follower.
+// Returns a cleanup function to restore the original follower.
+func setHistoryFollower(follower HistoryFollower) func() {
+	original := currentHistoryFollower
+	currentHistoryFollower = follower
+	return func() {
+		currentHistoryFollower = original
+	}
+}
+
+var historyTailCmd = &cobra.Command{
+	Use:   "tail",
+	Short: "Print the most recent history entries",
+	Long: `Print the most recent execution history entries for the current project as a table.
+With -f, keep running and print new entries as they are appended (e.g. by TerraX in another terminal).`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryTailCmd,
+}
+
+func init() {
+	historyTailCmd.Flags().String("dir", "", "Working directory (overrides current directory)")
+	historyTailCmd.Flags().IntP("lines", "n", 10, "Number of recent entries to print")
+	historyTailCmd.Flags().BoolP("follow", "f", false, "Follow the history file and print new entries as they are appended")
+	historyCmd.AddCommand(historyTailCmd)
+}
+
+// runHistoryTailCmd prints the last N entries of the current project's history in
+// chronological order and, in follow mode, streams entries appended afterwards.
+func runHistoryTailCmd(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	dirFlag, _ := cmd.Flags().GetString("dir")
+	workDir, err := getWorkingDirectory(dirFlag)
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	lines, _ := cmd.Flags().GetInt("lines")
+	follow, _ := cmd.Flags().GetBool("follow")
+
+	historyService, err := getHistoryService()
+	if err != nil {
+		return fmt.Errorf("failed to initialize history service: %w", err)
+	}
+
+	entries, err := historyService.LoadAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	// FilterByCurrentProject detects the project root from os.Getwd().
+	// Change to workDir first so detection uses the --dir argument.
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+	if err := os.Chdir(workDir); err != nil {
+		return fmt.Errorf("failed to change directory: %w", err)
+	}
+
+	filtered, err := historyService.FilterByCurrentProject(entries)
+	if err != nil {
+		return fmt.Errorf("failed to filter history: %w", err)
+	}
+
+	// Print the most recent N oldest-first, like tail(1), whatever history.order is.
+	filtered = history.SortEntries(filtered, history.OrderNewest)
+	if lines >= 0 && len(filtered) > lines {
+		filtered = filtered[:lines]
+	}
+
+	fmt.Println(tui.FormatHistoryTableHeader(historyTailWidth))
+	for i := len(filtered) - 1; i >= 0; i-- {
+		fmt.Println(tui.FormatHistoryTableRow(filtered[i], filtered[i].ID, historyTailWidth))
+	}
+
+	if !follow {
+		return nil
+	}
+
+	historyPath, err := history.GetHistoryFilePath()
+	if err != nil {
+		return fmt.Errorf("failed to resolve history file path: %w", err)
+	}
+
+	return currentHistoryFollower(ctx, historyPath, func(entry history.ExecutionLogEntry) {
+		matched, err := historyService.FilterByCurrentProject([]history.ExecutionLogEntry{entry})
+		if err != nil || len(matched) == 0 {
+			return
+		}
+		fmt.Println(tui.FormatHistoryTableRow(entry, entry.ID, historyTailWidth))
+	})
+}
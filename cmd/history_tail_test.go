@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/history"
+)
+
+// setupTailHistory points XDG_CONFIG_HOME at a temp dir and writes entries to the
+// history file in the order given (oldest first).
+func setupTailHistory(t *testing.T, entries []history.ExecutionLogEntry) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", "")
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	historyPath, err := history.GetHistoryFilePath()
+	require.NoError(t, err)
+
+	var lines []string
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		require.NoError(t, err)
+		lines = append(lines, string(data))
+	}
+	require.NoError(t, os.WriteFile(historyPath, []byte(strings.Join(lines, "\n")+"\n"), 0644))
+}
+
+func newHistoryTailTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("dir", "", "")
+	cmd.Flags().IntP("lines", "n", 10, "")
+	cmd.Flags().BoolP("follow", "f", false, "")
+	require.NoError(t, cmd.ParseFlags(append([]string{"--dir", t.TempDir()}, args...)))
+	return cmd
+}
+
+func TestHistoryTail_PrintsLastNEntries(t *testing.T) {
+	base := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	setupTailHistory(t, []history.ExecutionLogEntry{
+		{ID: 1, Timestamp: base, Command: "init", StackPath: "dev/vpc", AbsolutePath: "/infra/dev/vpc"},
+		{ID: 2, Timestamp: base.Add(time.Minute), Command: "plan", StackPath: "dev/rds", AbsolutePath: "/infra/dev/rds"},
+		{ID: 3, Timestamp: base.Add(2 * time.Minute), Command: "apply", StackPath: "dev/eks", AbsolutePath: "/infra/dev/eks", ExitCode: 1},
+	})
+
+	restore := captureStdout(t)
+	err := runHistoryTailCmd(newHistoryTailTestCmd(t, "-n", "2"), nil)
+	output := restore()
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "Stack Path")
+	assert.NotContains(t, output, "dev/vpc", "entries older than the last N must be omitted")
+
+	rdsIdx := strings.Index(output, "dev/rds")
+	eksIdx := strings.Index(output, "dev/eks")
+	require.GreaterOrEqual(t, rdsIdx, 0)
+	require.GreaterOrEqual(t, eksIdx, 0)
+	assert.Less(t, rdsIdx, eksIdx, "entries must be printed oldest first")
+	assert.Contains(t, output, "✗ 1")
+}
+
+func TestHistoryTail_FollowEmitsAppendedEntries(t *testing.T) {
+	setupTailHistory(t, []history.ExecutionLogEntry{
+		{ID: 1, Command: "plan", StackPath: "dev/vpc", AbsolutePath: "/infra/dev/vpc"},
+	})
+
+	var followedPath string
+	cleanup := setHistoryFollower(func(ctx context.Context, filePath string, emit func(history.ExecutionLogEntry)) error {
+		followedPath = filePath
+		// Simulate an append from another TerraX process.
+		emit(history.ExecutionLogEntry{ID: 2, Command: "destroy", StackPath: "dev/s3", AbsolutePath: "/infra/dev/s3"})
+		return nil
+	})
+	defer cleanup()
+
+	restore := captureStdout(t)
+	err := runHistoryTailCmd(newHistoryTailTestCmd(t, "-f"), nil)
+	output := restore()
+
+	require.NoError(t, err)
+	expectedPath, err := history.GetHistoryFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, expectedPath, followedPath)
+	assert.Contains(t, output, "dev/vpc")
+	assert.Contains(t, output, "destroy")
+	assert.Contains(t, output, "dev/s3")
+	assert.Less(t, strings.Index(output, "dev/vpc"), strings.Index(output, "dev/s3"))
+}
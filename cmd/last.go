@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -20,6 +21,23 @@ func init() {
 	rootCmd.AddCommand(lastCmd)
 }
 
+// HistoryEntryRunner re-executes a history entry.
+// This allows dependency injection for testing without running terragrunt.
+type HistoryEntryRunner func(ctx context.Context, historyService *history.Service, entry *history.ExecutionLogEntry) error
+
+// currentHistoryEntryRunner holds the active entry runner (can be overridden in tests).
+var currentHistoryEntryRunner HistoryEntryRunner = reExecuteHistoryEntry
+
+// setHistoryEntryRunner allows tests to inject a custom entry runner.
+// Returns a cleanup function to restore the original runner.
+func setHistoryEntryRunner(runner HistoryEntryRunner) func() {
+	original := currentHistoryEntryRunner
+	currentHistoryEntryRunner = runner
+	return func() {
+		currentHistoryEntryRunner = original
+	}
+}
+
 func runLastCmd(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
@@ -44,14 +62,44 @@ func executeLastCommand(ctx context.Context, historyService *history.Service) er
 		return nil
 	}
 
+	printLastCommandBanner(lastEntry)
+	return currentHistoryEntryRunner(ctx, historyService, lastEntry)
+}
+
+// printLastCommandBanner announces the re-execution of entry.
+func printLastCommandBanner(entry *history.ExecutionLogEntry) {
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Println("  🔄 Re-executing last command")
 	fmt.Println("═══════════════════════════════════════")
-	fmt.Printf("Command:    %s\n", lastEntry.Command)
-	fmt.Printf("Stack Path: %s\n", lastEntry.StackPath)
-	fmt.Printf("Previous:   %s (exit code: %d)\n", lastEntry.Timestamp.Format("2006-01-02 15:04:05"), lastEntry.ExitCode)
+	fmt.Printf("Command:    %s\n", entry.Command)
+	fmt.Printf("Stack Path: %s\n", entry.StackPath)
+	fmt.Printf("Previous:   %s (exit code: %d)\n", entry.Timestamp.Format("2006-01-02 15:04:05"), entry.ExitCode)
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Println()
+}
+
+// runLastInProject re-executes the most recent command run in the project holding workDir,
+// for --last. It reports false, so the caller opens the TUI instead, when the project has
+// no history or the stack of its last run no longer exists.
+func runLastInProject(ctx context.Context, historyService *history.Service, workDir string) (bool, error) {
+	lastEntry, err := historyService.GetLastExecutionInProject(ctx, selectionProjectRoot(workDir))
+	if err != nil {
+		return false, fmt.Errorf("failed to get last execution: %w", err)
+	}
+	if lastEntry == nil {
+		fmt.Println("⚠️  No execution history found for this project, opening the TUI")
+		return false, nil
+	}
+
+	absolutePath := lastEntry.AbsolutePath
+	if absolutePath == "" {
+		absolutePath = lastEntry.StackPath
+	}
+	if info, err := os.Stat(absolutePath); err != nil || !info.IsDir() {
+		fmt.Printf("⚠️  The last stack run, %s, no longer exists, opening the TUI\n", absolutePath)
+		return false, nil
+	}
 
-	return reExecuteHistoryEntry(ctx, historyService, lastEntry)
+	printLastCommandBanner(lastEntry)
+	return true, currentHistoryEntryRunner(ctx, historyService, lastEntry)
 }
@@ -2,20 +2,27 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/adrg/xdg"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/history"
+	"github.com/israoo/terrax/internal/tui"
 )
 
 func TestLastCmd_NoHistory(t *testing.T) {
 	// Redirect XDG_CONFIG_HOME to an empty temp dir so the history service finds no entries.
 	tmpDir := t.TempDir()
 	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", "")
 	xdg.Reload()
 	t.Cleanup(func() {
 		_ = os.Unsetenv("XDG_CONFIG_HOME")
@@ -44,3 +51,84 @@ func TestLastCmd_NoHistory(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, output, "No execution history found")
 }
+
+// TestRunTUI_Last tests that --last re-executes the project's most recent run without
+// opening the TUI, and falls back to the TUI when there is nothing to re-execute.
+func TestRunTUI_Last(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+	t.Cleanup(setSelectionFile(filepath.Join(t.TempDir(), "selections.json")))
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "root.hcl"), nil, 0644))
+	for _, env := range []string{"dev", "prod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(projectDir, env), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(projectDir, env, "terragrunt.hcl"), nil, 0644))
+	}
+	otherProject := t.TempDir()
+
+	historyPath, err := history.GetDefaultHistoryFilePath()
+	require.NoError(t, err)
+	repo, err := history.NewFileRepository(historyPath)
+	require.NoError(t, err)
+	appendEntry := func(entry history.ExecutionLogEntry) {
+		t.Helper()
+		_, err := repo.Append(context.Background(), entry)
+		require.NoError(t, err)
+	}
+
+	// run runs terrax --last in dir and returns the entry re-executed, if any, and whether
+	// the TUI was opened instead.
+	run := func(dir string) (*history.ExecutionLogEntry, bool) {
+		t.Helper()
+		var ranEntry *history.ExecutionLogEntry
+		defer setHistoryEntryRunner(func(_ context.Context, _ *history.Service, entry *history.ExecutionLogEntry) error {
+			ranEntry = entry
+			return nil
+		})()
+		openedTUI := false
+		defer setTUIRunner(func(m tui.Model) (tui.Model, error) {
+			openedTUI = true
+			return m, nil
+		})()
+
+		cmd := &cobra.Command{}
+		cmd.Flags().String("dir", "", "")
+		cmd.Flags().Bool("last", false, "")
+		require.NoError(t, cmd.ParseFlags([]string{"--dir", dir, "--last"}))
+		restore := captureStdout(t)
+		err := runTUI(cmd, []string{})
+		restore()
+		require.NoError(t, err)
+		return ranEntry, openedTUI
+	}
+
+	t.Run("no history", func(t *testing.T) {
+		ranEntry, openedTUI := run(projectDir)
+		assert.Nil(t, ranEntry)
+		assert.True(t, openedTUI, "falls back to the TUI")
+	})
+
+	appendEntry(history.ExecutionLogEntry{ID: 1, Command: "plan", StackPath: "dev", AbsolutePath: filepath.Join(projectDir, "dev")})
+	appendEntry(history.ExecutionLogEntry{ID: 2, Command: "apply", StackPath: "prod", AbsolutePath: filepath.Join(projectDir, "prod")})
+	appendEntry(history.ExecutionLogEntry{ID: 3, Command: "destroy", StackPath: "app", AbsolutePath: filepath.Join(otherProject, "app")})
+
+	t.Run("has last execution", func(t *testing.T) {
+		ranEntry, openedTUI := run(filepath.Join(projectDir, "dev"))
+		require.NotNil(t, ranEntry)
+		assert.Equal(t, 2, ranEntry.ID, "the most recent run in this project, not in others")
+		assert.Equal(t, "apply", ranEntry.Command)
+		assert.False(t, openedTUI)
+	})
+
+	t.Run("stack removed since", func(t *testing.T) {
+		require.NoError(t, os.RemoveAll(filepath.Join(projectDir, "prod")))
+		ranEntry, openedTUI := run(projectDir)
+		assert.Nil(t, ranEntry)
+		assert.True(t, openedTUI)
+	})
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// Output formats supported by --list-stacks.
+const (
+	listFormatText = "text"
+	listFormatJSON = "json"
+)
+
+// runListStacks prints the stacks under workDir as slash-separated paths relative to it,
+// one per line or as a JSON array, without launching the TUI.
+// --filter keeps paths matching a path.Match glob; --base keeps stacks affected by git
+// changes between that commit and HEAD. --count-only prints the tree stats instead.
+func runListStacks(cmd *cobra.Command, workDir string) error {
+	format, _ := cmd.Flags().GetString("format")
+	if format != listFormatText && format != listFormatJSON {
+		return fmt.Errorf("unsupported format %q: must be %q or %q", format, listFormatText, listFormatJSON)
+	}
+	pattern, _ := cmd.Flags().GetString("filter")
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid filter %q: %w", pattern, err)
+	}
+	baseCommit, _ := cmd.Flags().GetString("base")
+
+	if countOnly, _ := cmd.Flags().GetBool("count-only"); countOnly {
+		if pattern != "" || baseCommit != "" {
+			return fmt.Errorf("--count-only cannot be combined with --filter or --base")
+		}
+		return printStackCounts(workDir, format)
+	}
+
+	var absPaths []string
+	var err error
+	if baseCommit == "" {
+		absPaths, err = stack.CollectStackPaths(workDir, treeOptions())
+		if err != nil {
+			return fmt.Errorf("failed to collect stack paths: %w", err)
+		}
+	} else {
+		absPaths, err = collectAffectedStacks(workDir, viper.GetString("root_config_file"), baseCommit)
+		if err != nil {
+			return err
+		}
+	}
+
+	relPaths, err := relativeStackPaths(workDir, absPaths, pattern)
+	if err != nil {
+		return err
+	}
+
+	if format == listFormatJSON {
+		data, err := json.Marshal(relPaths)
+		if err != nil {
+			return fmt.Errorf("failed to serialize stack list: %w", err)
+		}
+		if _, err := fmt.Fprintln(os.Stdout, string(data)); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+		return nil
+	}
+	return printPaths(relPaths)
+}
+
+// relativeStackPaths converts absPaths to slash-separated paths relative to workDir,
+// keeping only those matching pattern when it is non-empty. The result is never nil
+// so the JSON output is always an array.
+func relativeStackPaths(workDir string, absPaths []string, pattern string) ([]string, error) {
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	relPaths := []string{}
+	for _, p := range absPaths {
+		rel, err := filepath.Rel(absWorkDir, p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute relative path for %s: %w", p, err)
+		}
+		rel = filepath.ToSlash(rel)
+		if pattern != "" {
+			if ok, _ := path.Match(pattern, rel); !ok {
+				continue
+			}
+		}
+		relPaths = append(relPaths, rel)
+	}
+	return relPaths, nil
+}
+
+// printStackCounts prints the number of stacks, the maximum depth and the number of
+// skipped directories of the tree under workDir, counted without building it.
+func printStackCounts(workDir, format string) error {
+	stats, err := stack.ScanTree(workDir, viper.GetString("root_config_file"), treeOptions())
+	if err != nil {
+		return fmt.Errorf("failed to scan stacks: %w", err)
+	}
+
+	output := fmt.Sprintf("stacks: %d\nmax depth: %d\nskipped: %d", stats.Stacks, stats.MaxDepth, stats.Skipped)
+	if format == listFormatJSON {
+		data, err := json.Marshal(stats)
+		if err != nil {
+			return fmt.Errorf("failed to serialize stack counts: %w", err)
+		}
+		output = string(data)
+	}
+	if _, err := fmt.Fprintln(os.Stdout, output); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
@@ -30,7 +30,9 @@ func runReviewCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	ensureConfigFromWorkDir(workDir)
+	if err := ensureConfigFromWorkDir(workDir); err != nil {
+		return err
+	}
 
 	if plansDir, _ := cmd.Flags().GetString("plans-dir"); plansDir != "" {
 		viper.Set("plan.json_out_dir", plansDir)
@@ -10,10 +10,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
@@ -44,9 +44,14 @@ var rootCmd = &cobra.Command{
 	Long: `TerraX is a professional CLI tool for interactive and centralized management
 of Terragrunt stacks. It provides a TUI for easy navigation
 and selection of infrastructure commands.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		initConfig()
+		if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+			viper.Set("profile", profile)
+		}
 		viper.Set("terrax.session_timestamp", time.Now().UnixNano())
+		colorMode, _ := cmd.Flags().GetString("color")
+		return applyColorMode(colorMode)
 	},
 	RunE: runTUI,
 }
@@ -58,8 +63,27 @@ func init() {
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true // main.go handles error printing to avoid duplicates.
 
+	rootCmd.PersistentFlags().String("profile", "", "Config profile from the profiles section to overlay on the config (overrides profile in config)")
+	rootCmd.PersistentFlags().String("color", colorAuto, "When to color output: auto, always or never (auto honours NO_COLOR and TTY detection)")
+
 	rootCmd.Flags().String("dir", "", "Working directory (overrides current directory)")
 	rootCmd.Flags().String("plans-dir", "", "Directory for JSON plan output files (overrides plan.json_out_dir in config)")
+	rootCmd.Flags().Bool("quiet", false, "Show a progress spinner instead of command output; output is printed only on failure (overrides quiet in config)")
+	rootCmd.Flags().Bool("dry-run", false, "Print the command that would run for the confirmed selection instead of running it (still recorded in history)")
+	rootCmd.Flags().String("note", "", "Note or tag stored with the execution in history (e.g. 'prod release')")
+	rootCmd.Flags().String("save-tree", "", "Write the scanned stack tree to this JSON file")
+	rootCmd.Flags().String("load-tree", "", "Load the stack tree from this JSON file instead of scanning the filesystem")
+	rootCmd.Flags().String("binary", "", "Executable that runs the selected command: terragrunt, terraform or tofu (overrides binary in config)")
+	rootCmd.Flags().Bool("force", false, "Build the stack tree even when the scan finds more stacks than max_stacks")
+	rootCmd.Flags().Bool("last", false, "Re-execute the last command run in this project instead of opening the TUI (opens the TUI when there is none)")
+	rootCmd.Flags().Bool("fresh", false, "Start at the tree root with the default command instead of the last selection made in this project")
+	rootCmd.Flags().Bool("no-cache", false, "Scan the filesystem even when a cached stack tree is available (overrides cache.enabled in config)")
+	rootCmd.Flags().Bool("debug", false, "Start with the debug overlay showing the TUI's navigation state (toggle with g then d)")
+	rootCmd.Flags().Bool("list-stacks", false, "Print stack paths relative to the working directory and exit")
+	rootCmd.Flags().String("format", listFormatText, "Output format for --list-stacks: text or json")
+	rootCmd.Flags().String("filter", "", "Glob matched against relative stack paths for --list-stacks (e.g. 'prod/*')")
+	rootCmd.Flags().String("base", "", "Base commit SHA; --list-stacks only lists stacks affected by changes since it")
+	rootCmd.Flags().Bool("count-only", false, "Print stack, depth and skipped-directory counts for --list-stacks without building the tree")
 }
 
 // Execute runs the root command.
@@ -68,10 +92,12 @@ func Execute() error {
 }
 
 // ensureConfigFromWorkDir reloads .terrax.yaml from the project root containing workDir,
-// then re-applies any .terrax.local.yaml overrides found alongside it.
+// layers it over the home config when config_precedence is "merge", then re-applies any
+// .terrax.local.yaml overrides found alongside it and the selected profile.
+// It returns an error when the selected profile is not defined.
 // initConfig reads from os.Getwd() at process start, which may differ from the project
 // root when commands are invoked via the VS Code extension with --dir flags.
-func ensureConfigFromWorkDir(workDir string) {
+func ensureConfigFromWorkDir(workDir string) error {
 	rootConfigFile := viper.GetString("root_config_file")
 	if rootConfigFile == "" {
 		rootConfigFile = config.DefaultRootConfigFile
@@ -83,29 +109,81 @@ func ensureConfigFromWorkDir(workDir string) {
 			fmt.Fprintf(os.Stderr, "Warning: error reading config from %s: %v\n", repoRoot, err)
 		}
 	}
+	if home, err := os.UserHomeDir(); err == nil {
+		mergeHomeConfig(userConfigDir(home))
+	}
 	mergeLocalConfig([]string{repoRoot})
+	if err := applyProfile(); err != nil {
+		return err
+	}
+	expandConfigPaths()
+	return nil
 }
 
 // initConfig initializes the configuration using Viper.
 func initConfig() {
 	viper.SetDefault("commands", config.DefaultCommands)
+	viper.SetDefault("dangerous_commands", config.DefaultDangerousCommands)
+	viper.SetDefault("stack_markers", config.DefaultStackMarkers)
+	viper.SetDefault("skip_directories", []string{})
+	viper.SetDefault("skip_directories_replace", false)
+	viper.SetDefault("scan_parallelism", config.DefaultScanParallelism)
+	viper.SetDefault("respect_gitignore", config.DefaultRespectGitignore)
+	viper.SetDefault("max_stacks", config.DefaultMaxStacks)
+	viper.SetDefault("warn_on_dirty_apply", config.DefaultWarnOnDirtyApply)
+	viper.SetDefault("emoji", config.DefaultEmoji)
+	viper.SetDefault("right_arrow_confirm", config.DefaultRightArrowConfirm)
+	viper.SetDefault("cyclic_navigation", config.DefaultCyclicNavigation)
+	viper.SetDefault("breadcrumb_command", config.DefaultBreadcrumbCommand)
+	viper.SetDefault("confirm_summary", config.DefaultConfirmSummary)
+	viper.SetDefault("require_confirmation", config.DefaultRequireConfirmation)
+	viper.SetDefault("disable_alt_screen", config.DefaultDisableAltScreen)
+	viper.SetDefault("fuzzy_filter", config.DefaultFuzzyFilter)
+	viper.SetDefault("highlight_matches", config.DefaultHighlightMatches)
+	viper.SetDefault("skip_single_command", config.DefaultSkipSingleCommand)
+	viper.SetDefault("keybindings", config.DefaultKeyBindings)
+	viper.SetDefault("enter_on_parent_stack", config.DefaultEnterOnParentStack)
+	viper.SetDefault("type_to_filter", config.DefaultTypeToFilter)
+	viper.SetDefault("theme.preset", config.DefaultThemePreset)
+	viper.SetDefault("filter_scope", config.DefaultFilterScope)
+	viper.SetDefault("idle_timeout", config.DefaultIdleTimeout)
+	viper.SetDefault("preview_lines", config.DefaultPreviewLines)
+	viper.SetDefault("remember_command_per_stack", config.DefaultRememberCommandPerStack)
+	viper.SetDefault("show_last_result", config.DefaultShowLastResult)
+	viper.SetDefault("group_stacks", config.DefaultGroupStacks)
+	viper.SetDefault("treat_root_as_stack", config.DefaultTreatRootAsStack)
 	viper.SetDefault("max_navigation_columns", config.DefaultMaxNavigationColumns)
+	viper.SetDefault("column_gap", config.DefaultColumnGap)
+	viper.SetDefault("command_timeout", config.DefaultCommandTimeout)
 	viper.SetDefault("history.max_entries", config.DefaultHistoryMaxEntries)
+	viper.SetDefault("history.max_age", config.DefaultHistoryMaxAge)
+	viper.SetDefault("history.trim_every", config.DefaultHistoryTrimEvery)
+	viper.SetDefault("history.trim_max_bytes", config.DefaultHistoryTrimMaxBytes)
+	viper.SetDefault("history.table.striped", config.DefaultHistoryTableStriped)
+	viper.SetDefault("history.order", config.DefaultHistoryOrder)
 	viper.SetDefault("root_config_file", config.DefaultRootConfigFile)
 	viper.SetDefault("log_format", config.DefaultLogFormat)
 	viper.SetDefault("terragrunt.parallelism", config.DefaultParallelism)
 	viper.SetDefault("terragrunt.no_color", config.DefaultNoColor)
+	viper.SetDefault("quiet", config.DefaultQuiet)
+	viper.SetDefault("run_logs.enabled", config.DefaultRunLogsEnabled)
 	viper.SetDefault("plan.review_enabled", config.DefaultPlanReviewEnabled)
+	viper.SetDefault("cache.enabled", config.DefaultCacheEnabled)
+	viper.SetDefault("cache.dir", config.DefaultCacheDir)
+	viper.SetDefault("cache.ttl", config.DefaultCacheTTL)
 	viper.SetDefault("plan.summary_enabled", config.DefaultPlanSummaryEnabled)
 	viper.SetDefault("plan.json_out_dir", config.DefaultJSONOutDir)
 	viper.SetDefault("include_dependencies", config.DefaultIncludeDependencies)
+	viper.SetDefault("config_precedence", config.DefaultConfigPrecedence)
 
 	viper.SetConfigName(".terrax")
 	viper.SetConfigType("yaml")
 
+	home, _ := os.UserHomeDir()
+	userDir := userConfigDir(home)
 	viper.AddConfigPath(".")
-	if home, err := os.UserHomeDir(); err == nil {
-		viper.AddConfigPath(home)
+	if userDir != "" {
+		viper.AddConfigPath(userDir)
 	}
 
 	if err := viper.ReadInConfig(); err != nil {
@@ -115,16 +193,114 @@ func initConfig() {
 			fmt.Fprintf(os.Stderr, "Warning: Error reading config file: %v\n", err)
 		}
 	}
+	mergeHomeConfig(userDir)
 
 	// Merge .terrax.local.yaml on top of the base config. Local config has priority and
 	// is intended for machine-specific overrides (gitignored). Deep-merge is used so only
 	// the keys present in the local file override their counterparts in the base config.
-	mergeLocalConfig([]string{".", func() string {
-		if home, err := os.UserHomeDir(); err == nil {
-			return home
+	mergeLocalConfig([]string{".", userDir})
+	expandConfigPaths()
+}
+
+// expandConfigPaths expands ~ and environment variables in the path-like keys set by the
+// config files. The expanded values are merged into the config layer rather than set as
+// overrides, so reloading the config from another directory still replaces them.
+func expandConfigPaths() {
+	expanded := map[string]any{}
+	for _, key := range config.PathKeys {
+		if !viper.InConfig(key) {
+			continue
 		}
-		return ""
-	}()})
+		value := viper.GetString(key)
+		if expandedValue := config.ExpandPath(value); expandedValue != value {
+			setNestedKey(expanded, key, expandedValue)
+		}
+	}
+	if len(expanded) == 0 {
+		return
+	}
+	if err := viper.MergeConfigMap(expanded); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Error expanding config paths: %v\n", err)
+	}
+}
+
+// setNestedKey stores value in settings under a dotted key such as "plan.json_out_dir",
+// creating the intermediate maps.
+func setNestedKey(settings map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := settings[part].(map[string]any)
+		if !ok {
+			child = map[string]any{}
+			settings[part] = child
+		}
+		settings = child
+	}
+	settings[parts[len(parts)-1]] = value
+}
+
+// mergeHomeConfig layers the project config file over the .terrax.yaml in homeDir when
+// config_precedence is "merge", so keys the project file leaves unset are inherited from
+// home instead of falling back to the defaults. The setting is read from the project file
+// first and then from the home file.
+func mergeHomeConfig(homeDir string) {
+	projectFile := viper.ConfigFileUsed()
+	if homeDir == "" || projectFile == "" {
+		return
+	}
+	homeFile := filepath.Join(homeDir, ".terrax.yaml")
+	if sameConfigFile(projectFile, homeFile) {
+		return // Only the home config was found.
+	}
+
+	home := viper.New()
+	home.SetConfigFile(homeFile)
+	if err := home.ReadInConfig(); err != nil {
+		return // No readable home config — nothing to inherit.
+	}
+
+	precedence := home.GetString("config_precedence")
+	if viper.InConfig("config_precedence") {
+		precedence = viper.GetString("config_precedence")
+	}
+	if precedence != config.ConfigPrecedenceMerge {
+		return
+	}
+
+	project := viper.New()
+	project.SetConfigFile(projectFile)
+	if err := project.ReadInConfig(); err != nil {
+		return
+	}
+
+	// Merge home, then the project file again so its keys keep priority.
+	if err := viper.MergeConfigMap(home.AllSettings()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Error merging home config: %v\n", err)
+		return
+	}
+	if err := viper.MergeConfigMap(project.AllSettings()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Error merging project config: %v\n", err)
+	}
+}
+
+// userConfigDir returns the directory holding the user's own .terrax.yaml (and
+// .terrax.local.yaml): $XDG_CONFIG_HOME/terrax when XDG_CONFIG_HOME is set and that
+// directory has a .terrax.yaml, otherwise home.
+func userConfigDir(home string) string {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		dir := filepath.Join(configHome, history.ConfigDirName)
+		if _, err := os.Stat(filepath.Join(dir, ".terrax.yaml")); err == nil {
+			return dir
+		}
+	}
+	return home
+}
+
+// sameConfigFile reports whether a and b refer to the same file path once made absolute.
+func sameConfigFile(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
 }
 
 // mergeLocalConfig loads .terrax.local.yaml from the first path in searchPaths where it exists
@@ -146,6 +322,39 @@ func mergeLocalConfig(searchPaths []string) {
 	}
 }
 
+// applyProfile overlays the profiles.<name> section of the config onto the rest of it, where
+// name is the profile config key (set by --profile). Keys the profile leaves out keep their
+// base value; flags still take precedence because they are set as overrides.
+func applyProfile() error {
+	name := viper.GetString("profile")
+	if name == "" {
+		return nil
+	}
+
+	profiles := viper.GetStringMap("profiles")
+	settings, ok := profiles[strings.ToLower(name)] // Viper lowercases map keys.
+	if !ok {
+		if len(profiles) == 0 {
+			return fmt.Errorf("unknown profile %q: no profiles are defined in the config", name)
+		}
+		names := make([]string, 0, len(profiles))
+		for profile := range profiles {
+			names = append(names, profile)
+		}
+		slices.Sort(names)
+		return fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	overrides, ok := settings.(map[string]any)
+	if !ok {
+		return fmt.Errorf("profile %q must be a map of config keys", name)
+	}
+	if err := viper.MergeConfigMap(overrides); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %w", name, err)
+	}
+	return nil
+}
+
 // getHistoryService creates and returns a new history service instance.
 func getHistoryService() (*history.Service, error) {
 	rootConfigFile := viper.GetString("root_config_file")
@@ -158,7 +367,13 @@ func getHistoryService() (*history.Service, error) {
 		return nil, fmt.Errorf("failed to create history repository: %w", err)
 	}
 
-	return history.NewService(repo, rootConfigFile), nil
+	svc := history.NewService(repo, rootConfigFile)
+	svc.SetOrder(viper.GetString("history.order"))
+	svc.SetTrimPolicy(history.TrimPolicy{
+		Every:    viper.GetInt("history.trim_every"),
+		MaxBytes: viper.GetInt64("history.trim_max_bytes"),
+	})
+	return svc, nil
 }
 
 // runTUI starts the TUI application.
@@ -176,38 +391,88 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 	workDir = resolveWorkDir(workDir)
-	ensureConfigFromWorkDir(workDir)
+	if err := ensureConfigFromWorkDir(workDir); err != nil {
+		return err
+	}
+
+	if listStacks, _ := cmd.Flags().GetBool("list-stacks"); listStacks {
+		return runListStacks(cmd, workDir)
+	}
 
 	if plansDir, _ := cmd.Flags().GetString("plans-dir"); plansDir != "" {
 		viper.Set("plan.json_out_dir", plansDir)
 	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		viper.Set("quiet", true)
+	}
+	if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+		viper.Set("cache.enabled", false)
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		viper.Set("dry_run", true)
+	}
+	if note, _ := cmd.Flags().GetString("note"); note != "" {
+		viper.Set("note", note)
+	}
+	if binary, _ := cmd.Flags().GetString("binary"); binary != "" {
+		viper.Set("binary", binary)
+	}
+	if err := validateBinaries(); err != nil {
+		return err
+	}
 
-	stackRoot, maxDepth, err := buildStackTree(workDir)
+	if last, _ := cmd.Flags().GetBool("last"); last {
+		if ran, err := runLastInProject(ctx, historyService, workDir); ran || err != nil {
+			return err
+		}
+	}
+
+	stackRoot, maxDepth, err := loadOrBuildStackTree(cmd, workDir)
 	if err != nil {
 		return fmt.Errorf("failed to build stack tree: %w", err)
 	}
 
-	commands := viper.GetStringSlice("commands")
-	if len(commands) == 0 {
-		commands = config.DefaultCommands
+	tuiConfig, err := loadTUIConfig()
+	if err != nil {
+		return err
 	}
 
-	maxNavColumns := viper.GetInt("max_navigation_columns")
-	if maxNavColumns < config.MinMaxNavigationColumns {
-		maxNavColumns = config.DefaultMaxNavigationColumns
+	initialModel := tui.NewModel(stackRoot, maxDepth, tuiConfig.Commands, tuiConfig.MaxNavigationColumns).
+		WithConfig(tuiConfig).
+		WithStackGrouping(viper.GetBool("group_stacks")).
+		WithIncludeRoot(findIncludeRoot(workDir)).
+		WithBranch(currentBranch(workDir)).
+		WithExecutionPreviewer(previewExecution).
+		WithConfigReloader(reloadTUIConfig(workDir))
+	if debug, _ := cmd.Flags().GetBool("debug"); debug {
+		initialModel = initialModel.WithDebugOverlay(true)
+	}
+	if viper.GetBool("remember_command_per_stack") {
+		initialModel = initialModel.WithLastCommands(loadLastCommands(ctx, historyService))
+	}
+	if viper.GetBool("show_last_result") {
+		initialModel = initialModel.WithLastRuns(loadLastRuns(ctx, historyService))
+	}
+	initialModel = initialModel.WithLastArgs(loadLastArgs(ctx, historyService, stackRoot))
+	projectRoot := selectionProjectRoot(workDir)
+	if fresh, _ := cmd.Flags().GetBool("fresh"); !fresh {
+		initialModel = restoreLastSelection(initialModel, projectRoot)
 	}
-
-	initialModel := tui.NewModel(stackRoot, maxDepth, commands, maxNavColumns)
 	model, err := currentTUIRunner(initialModel)
 	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
+	saveLastSelection(model, projectRoot)
 
 	displayResults(model)
 
 	if model.IsConfirmed() {
 		command := model.GetSelectedCommand()
 		stackPath := model.GetSelectedStackPath()
+		if args := selectedRunArgs(model); len(args) > 0 {
+			// Extra args of a favorite preset or typed in the TUI apply to this run only.
+			viper.Set("terraform.run_flags", args)
+		}
 
 		var execPaths []string
 		if model.HasSelectedPaths() {
@@ -215,6 +480,9 @@ func runTUI(cmd *cobra.Command, args []string) error {
 		} else {
 			execPaths = []string{stackPath}
 		}
+		if !stackRoot.IsStack {
+			execPaths = expandRootPath(execPaths, stackRoot.Path)
+		}
 		primaryPath := execPaths[0]
 
 		if command == "force-unlock" {
@@ -226,9 +494,14 @@ func runTUI(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		if !confirmDirtyRun(command, execPaths) {
+			fmt.Println("Cancelled: stacks have uncommitted changes.")
+			return nil
+		}
+
 		repoRoot, filterPaths := collectTransitiveDeps(execPaths)
 
-		if command == "plan" && (viper.GetBool("plan.summary_enabled") || viper.GetBool("plan.review_enabled")) {
+		if command == "plan" && !viper.GetBool("dry_run") && (viper.GetBool("plan.summary_enabled") || viper.GetBool("plan.review_enabled")) {
 			jsonOutDir := viper.GetString("plan.json_out_dir")
 			if jsonOutDir == "" {
 				jsonOutDir = config.DefaultJSONOutDir
@@ -250,10 +523,13 @@ func runTUI(cmd *cobra.Command, args []string) error {
 			if group.Skip {
 				continue
 			}
-			if err := executor.Run(ctx, historyService, command, primaryPath, repoRoot, group.Paths, group.EnvVars); err != nil {
+			if err := executor.Run(ctx, historyService, command, primaryPath, repoRoot, group.Paths, resolveEnvVars(command, group.Paths, group.EnvVars)); err != nil {
 				return err
 			}
 		}
+		if viper.GetBool("dry_run") {
+			return nil // Nothing ran, so there are no plans to summarize or review.
+		}
 		if command == "plan" && viper.GetBool("plan.summary_enabled") {
 			if err := runPlanSummary(ctx, primaryPath, repoRoot); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: plan summary failed: %v\n", err)
@@ -269,6 +545,106 @@ func runTUI(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loadLastCommands returns the command last run against each stack, keyed by absolute path.
+// History that cannot be read yields an empty map so the TUI falls back to the default command.
+func loadLastCommands(ctx context.Context, historyService *history.Service) map[string]string {
+	entries, err := historyService.LoadAll(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load history: %v\n", err)
+		return map[string]string{}
+	}
+	return historyService.LastCommandByStack(entries)
+}
+
+// loadLastRuns returns the latest history entry of each stack, keyed by absolute path.
+// History that cannot be read yields an empty map so no indicators are shown.
+func loadLastRuns(ctx context.Context, historyService *history.Service) map[string]history.ExecutionLogEntry {
+	entries, err := historyService.LoadAll(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load history: %v\n", err)
+		return map[string]history.ExecutionLogEntry{}
+	}
+	return historyService.LastRunByStack(entries)
+}
+
+// loadLastArgs returns the extra args each command was last run with, keyed by command under
+// the "" key and, when the tree's first level lists projects, under each project's root from
+// that project's history alone. History that cannot be read yields an empty map so the args
+// input starts empty.
+func loadLastArgs(ctx context.Context, historyService *history.Service, stackRoot *stack.Node) map[string]map[string][]string {
+	entries, err := historyService.LoadAll(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load history: %v\n", err)
+		return map[string]map[string][]string{}
+	}
+
+	lastArgs := map[string]map[string][]string{"": historyService.LastArgsByCommand(entries)}
+	for _, child := range stackRoot.Children {
+		if child.IsProject {
+			lastArgs[child.Path] = historyService.LastArgsByCommand(historyService.FilterByProjectRoot(entries, child.Path))
+		}
+	}
+	return lastArgs
+}
+
+// selectedRunArgs returns the extra args of the confirmed run: those of the chosen favorite
+// preset, followed by those typed for the command.
+func selectedRunArgs(model tui.Model) []string {
+	return append(slices.Clone(model.GetSelectedArgs()), model.GetExtraArgs()...)
+}
+
+// loadTUIConfig decodes the TUI settings from the loaded configuration.
+func loadTUIConfig() (config.TUI, error) {
+	var cfg config.TUI
+	if err := viper.Unmarshal(&cfg); err != nil {
+		return cfg, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+	cfg.Normalize()
+	if err := cfg.ResolveKeyBindings(); err != nil {
+		return cfg, fmt.Errorf("invalid keybindings: %w", err)
+	}
+	return cfg, nil
+}
+
+// reloadTUIConfig returns a reloader that re-runs the configuration loading for workDir
+// and applies the re-decoded TUI settings to the running model.
+func reloadTUIConfig(workDir string) tui.ConfigReloader {
+	return func(m tui.Model) (tui.Model, error) {
+		initConfig()
+		if err := ensureConfigFromWorkDir(workDir); err != nil {
+			return m, err
+		}
+		cfg, err := loadTUIConfig()
+		if err != nil {
+			return m, err
+		}
+		return m.WithConfig(cfg), nil
+	}
+}
+
+// resolveLocale returns the UI locale from the locale config key, falling back to LANG.
+func resolveLocale() string {
+	return tui.ResolveLocale(viper.GetString("locale"), os.Getenv("LANG"))
+}
+
+// loadMessages reads the messages section. Unset values are left empty so the TUI
+// falls back to its built-in text.
+func loadMessages() tui.Messages {
+	return tui.Messages{
+		Initializing:   viper.GetString("messages.initializing"),
+		ScanningStacks: viper.GetString("messages.scanning_stacks"),
+	}
+}
+
+// loadTheme reads the theme section. Unset colors keep the colors of its preset.
+func loadTheme() tui.Theme {
+	var cfg config.TUITheme
+	if err := viper.UnmarshalKey("theme", &cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid theme: %v\n", err)
+	}
+	return tui.ThemeFromConfig(cfg)
+}
+
 // getWorkingDirectory returns dir if non-empty, otherwise the current working directory.
 func getWorkingDirectory(dir string) (string, error) {
 	if dir != "" {
@@ -282,11 +658,12 @@ func getWorkingDirectory(dir string) (string, error) {
 }
 
 // resolveWorkDir returns the parent directory when dir is a leaf stack — a directory
-// that has a terragrunt.hcl file but no sub-directories that are also stacks.
+// that has a stack marker but no sub-directories that are also stacks.
 // TerraX requires sub-directories to navigate, so pointing it at a leaf stack would
 // fail; using the parent lets the TUI navigate to the stack as a selectable node.
 func resolveWorkDir(dir string) string {
-	if _, err := os.Stat(filepath.Join(dir, "terragrunt.hcl")); err != nil {
+	opts := treeOptions()
+	if !opts.IsStack(dir) {
 		return dir
 	}
 	entries, err := os.ReadDir(dir)
@@ -297,18 +674,54 @@ func resolveWorkDir(dir string) string {
 		if !e.IsDir() {
 			continue
 		}
-		if _, err := os.Stat(filepath.Join(dir, e.Name(), "terragrunt.hcl")); err == nil {
+		if opts.IsStack(filepath.Join(dir, e.Name())) {
 			return dir
 		}
 	}
 	return filepath.Dir(dir)
 }
 
+// treeOptions returns the scan settings from the loaded configuration.
+func treeOptions() stack.TreeOptions {
+	return stack.TreeOptions{
+		IgnoreDirs:             viper.GetStringSlice("ignore_dirs"),
+		StackMarkers:           viper.GetStringSlice("stack_markers"),
+		SkipDirectories:        viper.GetStringSlice("skip_directories"),
+		ReplaceSkipDirectories: viper.GetBool("skip_directories_replace"),
+		Parallelism:            viper.GetInt("scan_parallelism"),
+		RespectGitignore:       viper.GetBool("respect_gitignore"),
+	}
+}
+
+// treeCache returns the stack tree cache configured under "cache". A relative cache.dir is
+// resolved against the project root containing workDir.
+func treeCache(workDir string) stack.TreeCache {
+	dir := viper.GetString("cache.dir")
+	if dir == "" {
+		dir = config.DefaultCacheDir
+	}
+	if !filepath.IsAbs(dir) {
+		rootConfigFile := viper.GetString("root_config_file")
+		if rootConfigFile == "" {
+			rootConfigFile = config.DefaultRootConfigFile
+		}
+		dir = filepath.Join(deps.FindRepoRoot(workDir, rootConfigFile), dir)
+	}
+	return stack.TreeCache{Dir: dir, TTL: viper.GetDuration("cache.ttl")}
+}
+
 // buildStackTree scans and builds the stack tree structure.
 func buildStackTree(workDir string) (*stack.Node, int, error) {
 	fmt.Println("🔍 Scanning for stacks in:", workDir)
 
-	stackRoot, maxDepth, err := stack.FindAndBuildTree(workDir, viper.GetString("root_config_file"))
+	var stackRoot *stack.Node
+	var maxDepth int
+	var err error
+	if viper.GetBool("cache.enabled") {
+		stackRoot, maxDepth, err = treeCache(workDir).FindAndBuildTree(workDir, viper.GetString("root_config_file"), treeOptions())
+	} else {
+		stackRoot, maxDepth, err = stack.FindAndBuildTreeWithOptions(workDir, viper.GetString("root_config_file"), treeOptions())
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -323,9 +736,78 @@ func buildStackTree(workDir string) (*stack.Node, int, error) {
 	return stackRoot, maxDepth, nil
 }
 
+// validateBinaries checks that binary and the binary_markers values name executables that
+// can run commands, and that an explicitly chosen binary is on PATH, so a wrong setting
+// fails before the stacks are scanned.
+func validateBinaries() error {
+	for _, binary := range viper.GetStringMapString("binary_markers") {
+		if err := executor.ValidateBinary(binary); err != nil {
+			return fmt.Errorf("invalid binary_markers: %w", err)
+		}
+	}
+
+	binary := viper.GetString("binary")
+	if binary == "" {
+		return nil
+	}
+	if err := executor.ValidateBinary(binary); err != nil {
+		return err
+	}
+	if _, err := executor.CheckBinary(binary); err != nil {
+		return err
+	}
+	return nil
+}
+
+// loadOrBuildStackTree returns the stack tree from --load-tree when set, otherwise scans workDir.
+// When --save-tree is set, the resulting tree is written to that file.
+func loadOrBuildStackTree(cmd *cobra.Command, workDir string) (*stack.Node, int, error) {
+	loadTreeFile, _ := cmd.Flags().GetString("load-tree")
+	saveTreeFile, _ := cmd.Flags().GetString("save-tree")
+
+	var stackRoot *stack.Node
+	var maxDepth int
+	var err error
+	if loadTreeFile == "" {
+		force, _ := cmd.Flags().GetBool("force")
+		if err := confirmStackCount(workDir, force); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if loadTreeFile != "" {
+		fmt.Println("📂 Loading stack tree from:", loadTreeFile)
+		stackRoot, maxDepth, err = stack.LoadTree(loadTreeFile)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !stackRoot.HasChildren() {
+			return nil, 0, fmt.Errorf("loaded tree has no terragrunt directories")
+		}
+	} else {
+		stackRoot, maxDepth, err = buildStackTree(workDir)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if saveTreeFile != "" {
+		if err := stack.SaveTree(stackRoot, saveTreeFile); err != nil {
+			return nil, 0, err
+		}
+		fmt.Println("💾 Saved stack tree to:", saveTreeFile)
+	}
+
+	if !viper.GetBool("treat_root_as_stack") {
+		stackRoot.IsStack = false
+	}
+
+	return stackRoot, maxDepth, nil
+}
+
 // defaultTUIRunner is the default implementation that runs Bubble Tea interactively.
 func defaultTUIRunner(initialModel tui.Model) (tui.Model, error) {
-	return runBubbleTeaProgram(initialModel)
+	return tui.RunProgram(initialModel)
 }
 
 // setTUIRunner allows tests to inject a custom TUI runner.
@@ -338,19 +820,65 @@ func setTUIRunner(runner TUIRunner) func() {
 	}
 }
 
+// previewExecution completes the TUI's confirmation summary with the resolved binary, the
+// configured extra args and the names of the environment variables injected by env_vars
+// and the stack groups of the selected stacks.
+func previewExecution(details tui.ExecutionDetails) tui.ExecutionDetails {
+	details.Binary = executor.TerragruntBinary
+	if len(details.StackPaths) > 0 {
+		details.Binary = executor.ResolveBinary(details.StackPaths[0])
+	}
+	if path, err := executor.CheckBinary(details.Binary); err == nil {
+		details.Binary = path
+	}
+	details.ExtraArgs = executor.ExtraArgs(details.Command, details.ExtraArgs)
+
+	repoRoot, filterPaths := collectTransitiveDeps(details.StackPaths)
+	groups, err := buildGroupedExecution(filterPaths, repoRoot)
+	if err != nil {
+		return details
+	}
+	var names []string
+	for _, group := range groups {
+		if group.Skip {
+			continue
+		}
+		for name := range resolveEnvVars(details.Command, group.Paths, group.EnvVars) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	details.EnvVarNames = names
+	return details
+}
+
 // displayResults shows the final selection to the user.
 func displayResults(model tui.Model) {
 	fmt.Println()
 
+	if model.IdledOut() {
+		fmt.Println("⏱️  " + fmt.Sprintf(model.Text(tui.MsgIdleQuit), model.IdleTimeout()))
+		return
+	}
 	if !model.IsConfirmed() {
-		fmt.Println("⚠️  Selection cancelled")
+		fmt.Println("⚠️  " + model.Text(tui.MsgSelectionCancelled))
 		return
 	}
 
 	fmt.Println("═══════════════════════════════════════")
-	fmt.Println("  ✅ Selection confirmed")
+	fmt.Println("  ✅ " + model.Text(tui.MsgSelectionConfirmed))
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Printf("Command: %s\n", model.GetSelectedCommand())
+	binaryPath := model.GetSelectedStackPath()
+	if model.HasSelectedPaths() {
+		binaryPath = model.GetSelectedStackPaths()[0]
+	}
+	fmt.Printf("Binary: %s\n", executor.ResolveBinary(binaryPath))
+	if args := selectedRunArgs(model); len(args) > 0 {
+		fmt.Printf("Args: %s\n", strings.Join(args, " "))
+	}
 
 	if model.HasSelectedPaths() {
 		paths := model.GetSelectedStackPaths()
@@ -393,7 +921,8 @@ func runForceUnlock(ctx context.Context, historyService *history.Service, absolu
 		rootConfigFile = config.DefaultRootConfigFile
 	}
 
-	stackPaths, err := stack.CollectStackPaths(absoluteStackPath)
+	repoRoot := deps.FindRepoRoot(absoluteStackPath, rootConfigFile)
+	stackPaths, err := stack.CollectStackPathsIn(repoRoot, absoluteStackPath, treeOptions())
 	if err != nil {
 		return fmt.Errorf("failed to scan stacks: %w", err)
 	}
@@ -442,11 +971,39 @@ func runForceUnlock(ctx context.Context, historyService *history.Service, absolu
 	return nil
 }
 
+// expandRootPath replaces rootPath in paths with the stacks beneath it, so a root that is
+// not treated as a stack targets its children. Paths are returned unchanged when the root
+// holds no stacks below it.
+func expandRootPath(paths []string, rootPath string) []string {
+	if !slices.Contains(paths, rootPath) {
+		return paths
+	}
+	children, err := stack.CollectStackPaths(rootPath, treeOptions())
+	if err != nil {
+		return paths
+	}
+	children = slices.DeleteFunc(children, func(p string) bool { return p == rootPath })
+	if len(children) == 0 {
+		return paths
+	}
+
+	var expanded []string
+	for _, p := range paths {
+		if p == rootPath {
+			expanded = append(expanded, children...)
+		} else {
+			expanded = append(expanded, p)
+		}
+	}
+	return expanded
+}
+
 // collectTransitiveDeps computes the filter list for one or more stack paths.
 // When include_dependencies is true, transitive dependencies are resolved
 // via static HCL parsing and included in the filter list.
 // When false, only the selected stack(s) are included — no dependency traversal.
-// Non-leaf directories are expanded to all leaf stacks they contain via CollectStackPaths.
+// Non-leaf directories are expanded to all leaf stacks they contain via CollectStackPathsIn,
+// leaving out the directories the tree of the repository root skips.
 // All paths must reside under the same repository root; repoRoot is derived from stackPaths[0].
 func collectTransitiveDeps(stackPaths []string) (repoRoot string, filterPaths []string) {
 	if len(stackPaths) == 0 {
@@ -463,11 +1020,16 @@ func collectTransitiveDeps(stackPaths []string) (repoRoot string, filterPaths []
 	// Seed the BFS queue from all input paths, expanding non-leaf directories.
 	var seeds []string
 	for _, stackPath := range stackPaths {
-		hclFile := filepath.Join(stackPath, "terragrunt.hcl")
-		if _, err := os.Stat(hclFile); err == nil {
+		// The include root only holds shared configuration, even when root_config_file is
+		// terragrunt.hcl, so targeting it always expands to every stack beneath it.
+		includeRoot := isIncludeRoot(stackPath, repoRoot, rootConfigFile)
+		if treeOptions().IsStack(stackPath) && !includeRoot {
 			seeds = append(seeds, stackPath)
 		} else {
-			leafPaths, err := stack.CollectStackPaths(stackPath)
+			leafPaths, err := stack.CollectStackPathsIn(repoRoot, stackPath, treeOptions())
+			if includeRoot {
+				leafPaths = slices.DeleteFunc(leafPaths, func(p string) bool { return p == stackPath })
+			}
 			if err != nil || len(leafPaths) == 0 {
 				seeds = append(seeds, stackPath) // fallback.
 			} else {
@@ -507,6 +1069,26 @@ func collectTransitiveDeps(stackPaths []string) (repoRoot string, filterPaths []
 	return repoRoot, filterPaths
 }
 
+// isIncludeRoot reports whether path is the repository root holding the root config file.
+func isIncludeRoot(path, repoRoot, rootConfigFile string) bool {
+	if path != repoRoot {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(path, rootConfigFile))
+	return err == nil
+}
+
+// findIncludeRoot returns the directory holding the root config file above workDir,
+// or an empty string when there is none.
+func findIncludeRoot(workDir string) string {
+	rootConfigFile := viper.GetString("root_config_file")
+	if rootConfigFile == "" {
+		rootConfigFile = config.DefaultRootConfigFile
+	}
+	includeRoot, _ := history.FindProjectRoot(workDir, rootConfigFile)
+	return includeRoot
+}
+
 // runPlanSummary reads JSON plan files from the configured plans directory and prints a terminal count summary.
 func runPlanSummary(ctx context.Context, stackPath, repoRoot string) error {
 	jsonOutDir := viper.GetString("plan.json_out_dir")
@@ -571,14 +1153,14 @@ func runPlanReview(ctx context.Context, stackPath string) error {
 		return nil
 	}
 
-	initialModel := tui.NewPlanReviewModel(report)
+	initialModel := tui.NewPlanReviewModel(report).WithAltScreen(!viper.GetBool("disable_alt_screen"))
 	_, err = currentPlanReviewRunner(initialModel)
 	return err
 }
 
 // defaultPlanReviewRunner is the default implementation that runs Bubble Tea interactively.
 func defaultPlanReviewRunner(initialModel tui.Model) (tui.Model, error) {
-	return runBubbleTeaProgram(initialModel)
+	return tui.RunProgram(initialModel)
 }
 
 // setPlanReviewRunner allows tests to inject a custom Plan Review runner.
@@ -590,27 +1172,6 @@ func setPlanReviewRunner(runner PlanReviewRunner) func() {
 	}
 }
 
-// runBubbleTeaProgram runs a Bubble Tea program with standard options.
-func runBubbleTeaProgram(model tui.Model) (tui.Model, error) {
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),
-		tea.WithMouseCellMotion(),
-	)
-
-	finalModel, err := p.Run()
-	if err != nil {
-		return tui.Model{}, err
-	}
-
-	resultModel, ok := finalModel.(tui.Model)
-	if !ok {
-		return tui.Model{}, fmt.Errorf("unexpected model type")
-	}
-
-	return resultModel, nil
-}
-
 // StackGroupConfig holds the configuration for one stack group, loaded from stack_groups in .terrax.yaml.
 type StackGroupConfig struct {
 	Detect    string            `mapstructure:"detect"`
@@ -2,14 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
+	"time"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/stack"
 	"github.com/israoo/terrax/internal/tui"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -163,6 +168,7 @@ func TestDisplayResults(t *testing.T) {
 				"✅ Selection confirmed",
 				"Command:",
 				"plan",
+				"Binary: terragrunt",
 				"Stack Path:",
 				"/test/root",
 				"═══════════════════════════════════════",
@@ -171,6 +177,25 @@ func TestDisplayResults(t *testing.T) {
 				"⚠️  Selection cancelled",
 			},
 		},
+		{
+			name: "confirmed selection with extra args",
+			setupModel: func() tui.Model {
+				stackRoot := &stack.Node{Name: "root", Path: "/test/root"}
+				var model tea.Model = tui.NewTestModel(stackRoot, 1, testCommands, 3, true, "plan", "/test/root")
+				for _, msg := range []tea.KeyMsg{
+					{Type: tea.KeyRunes, Runes: []rune(tui.KeyArgs)},
+					{Type: tea.KeyRunes, Runes: []rune("-target=module.vpc -refresh=false")},
+					{Type: tea.KeyEnter},
+				} {
+					model, _ = model.Update(msg)
+				}
+				return model.(tui.Model)
+			},
+			expectedOutputHas: []string{
+				"Command: plan",
+				"Args: -target=module.vpc -refresh=false",
+			},
+		},
 		{
 			name: "cancelled selection",
 			setupModel: func() tui.Model {
@@ -610,9 +635,8 @@ func TestRunTUI_ConfigValidation(t *testing.T) {
 			require.NoError(t, err)
 
 			// Verify the model was initialized with correct config values
-			// Note: We can't directly access model.commands, but we verified
-			// the config values were set correctly via viper
-			assert.NotNil(t, capturedModel, "model should be captured")
+			assert.Len(t, capturedModel.GetCommands(), tt.expectedCommands)
+			assert.Equal(t, tt.expectedMaxNavCol, capturedModel.GetMaxNavigationColumns())
 		})
 	}
 }
@@ -654,6 +678,179 @@ func TestCollectTransitiveDeps_DeduplicatesPaths(t *testing.T) {
 	assert.Len(t, filterPaths, 1, "duplicate paths should be deduplicated")
 }
 
+// TestCollectTransitiveDeps_IncludeRoot tests that targeting the include root expands to every
+// stack beneath it, even when root_config_file is terragrunt.hcl.
+func TestCollectTransitiveDeps_IncludeRoot(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("root_config_file", "terragrunt.hcl")
+
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"", "dev", "prod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+
+	repoRoot, filterPaths := collectTransitiveDeps([]string{tmpDir})
+
+	assert.Equal(t, tmpDir, repoRoot)
+	assert.ElementsMatch(t, []string{"dev", "prod"}, filterPaths)
+}
+
+// TestCollectTransitiveDeps_StackMarkers tests that directories that are stacks only
+// through a configured stack marker are run when their parent is expanded.
+func TestCollectTransitiveDeps_StackMarkers(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("root_config_file", "root.hcl")
+	viper.Set("stack_markers", []string{"terragrunt.hcl", "main.tf"})
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+	for _, dir := range []string{"dev/vpc", "dev/rds"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "dev/vpc/main.tf"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "dev/rds/terragrunt.hcl"), []byte(""), 0644))
+
+	_, filterPaths := collectTransitiveDeps([]string{filepath.Join(tmpDir, "dev")})
+	assert.ElementsMatch(t, []string{"dev/vpc", "dev/rds"}, filterPaths)
+
+	_, filterPaths = collectTransitiveDeps([]string{filepath.Join(tmpDir, "dev", "vpc")})
+	assert.Equal(t, []string{"dev/vpc"}, filterPaths)
+
+	// A leaf stack found through its marker opens the TUI on its parent.
+	assert.Equal(t, filepath.Join(tmpDir, "dev"), resolveWorkDir(filepath.Join(tmpDir, "dev", "vpc")))
+}
+
+// TestCollectTransitiveDeps_SkippedChild tests that expanding a parent directory leaves out
+// the stacks below directories the tree skips.
+func TestCollectTransitiveDeps_SkippedChild(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		files  map[string]string
+	}{
+		{
+			name:   "skip_directories",
+			config: map[string]any{"skip_directories": []string{"cache"}},
+		},
+		{
+			name:   "ignore_dirs",
+			config: map[string]any{"ignore_dirs": []string{"*/cache"}},
+		},
+		{
+			name:  "terraxignore",
+			files: map[string]string{stack.IgnoreFileName: "dev/cache\n"},
+		},
+		{
+			name:   "respect_gitignore",
+			config: map[string]any{"respect_gitignore": true},
+			files:  map[string]string{".gitignore": "cache/\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("root_config_file", "root.hcl")
+			for key, value := range tt.config {
+				viper.Set(key, value)
+			}
+
+			tmpDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+			for _, dir := range []string{"dev/vpc", "dev/cache/vpc"} {
+				require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+			}
+			for name, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644))
+			}
+
+			_, filterPaths := collectTransitiveDeps([]string{filepath.Join(tmpDir, "dev")})
+			assert.Equal(t, []string{"dev/vpc"}, filterPaths)
+
+			assert.Equal(t, []string{filepath.Join(tmpDir, "dev", "vpc")}, expandRootPath([]string{tmpDir}, tmpDir))
+
+			// find and --list-stacks collect the same stacks.
+			paths, err := stack.CollectStackPaths(tmpDir, treeOptions())
+			require.NoError(t, err)
+			assert.Equal(t, []string{filepath.Join(tmpDir, "dev", "vpc")}, paths)
+		})
+	}
+}
+
+// TestTreatRootAsStack_Disabled tests that a root with its own terragrunt.hcl is not a
+// stack when treat_root_as_stack is false, and that targeting it runs its children.
+func TestTreatRootAsStack_Disabled(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("root_config_file", "root.hcl")
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+	for _, dir := range []string{"", "dev", "prod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("save-tree", "", "")
+	cmd.Flags().String("load-tree", "", "")
+
+	restore := captureStdout(t)
+	viper.Set("treat_root_as_stack", true)
+	stackRoot, _, err := loadOrBuildStackTree(cmd, tmpDir)
+	restore()
+	require.NoError(t, err)
+	assert.True(t, stackRoot.IsStack, "root should be a stack by default")
+
+	restore = captureStdout(t)
+	viper.Set("treat_root_as_stack", false)
+	stackRoot, _, err = loadOrBuildStackTree(cmd, tmpDir)
+	restore()
+	require.NoError(t, err)
+	assert.False(t, stackRoot.IsStack)
+
+	execPaths := expandRootPath([]string{stackRoot.Path}, stackRoot.Path)
+	assert.ElementsMatch(t, []string{filepath.Join(tmpDir, "dev"), filepath.Join(tmpDir, "prod")}, execPaths)
+
+	_, filterPaths := collectTransitiveDeps(execPaths)
+	assert.ElementsMatch(t, []string{"dev", "prod"}, filterPaths)
+}
+
+// TestExpandRootPath_LeavesOtherPaths tests that paths other than the root pass through unchanged.
+func TestExpandRootPath_LeavesOtherPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	dev := filepath.Join(tmpDir, "dev")
+	require.NoError(t, os.MkdirAll(dev, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dev, "terragrunt.hcl"), []byte(""), 0644))
+
+	assert.Equal(t, []string{dev}, expandRootPath([]string{dev}, tmpDir))
+}
+
+func TestTreeCache_Config(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+	workDir := filepath.Join(tmpDir, "env")
+	require.NoError(t, os.MkdirAll(workDir, 0755))
+
+	viper.Set("root_config_file", "root.hcl")
+	viper.Set("cache.ttl", "1h")
+	cache := treeCache(workDir)
+	assert.Equal(t, filepath.Join(tmpDir, ".terrax", "tree-cache"), cache.Dir, "relative dirs resolve against the project root")
+	assert.Equal(t, time.Hour, cache.TTL)
+
+	absDir := filepath.Join(t.TempDir(), "trees")
+	viper.Set("cache.dir", absDir)
+	assert.Equal(t, absDir, treeCache(workDir).Dir)
+}
+
 // TestCollectTransitiveDeps_EmptyInput tests that an empty input slice returns
 // empty repoRoot and filterPaths without panicking.
 func TestCollectTransitiveDeps_EmptyInput(t *testing.T) {
@@ -690,3 +887,459 @@ func TestRunTUI_TUIRunnerError(t *testing.T) {
 	assert.Error(t, err, "should return error when TUI runner fails")
 	assert.Contains(t, err.Error(), "TUI error", "error should be wrapped with context")
 }
+
+// TestRunTUI_SaveAndLoadTree tests that --save-tree writes the scanned tree and
+// --load-tree launches the TUI from that file without scanning the filesystem.
+func TestRunTUI_SaveAndLoadTree(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "env", "dev"), 0755))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpDir, "env", "dev", "terragrunt.hcl"),
+		[]byte("# test"), 0644))
+	treeFile := filepath.Join(t.TempDir(), "tree.json")
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("dir", "", "")
+		cmd.Flags().String("plans-dir", "", "")
+		cmd.Flags().String("save-tree", "", "")
+		cmd.Flags().String("load-tree", "", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return cmd
+	}
+
+	var capturedModel tui.Model
+	restoreRunner := setTUIRunner(func(initialModel tui.Model) (tui.Model, error) {
+		capturedModel = initialModel
+		return initialModel, nil
+	})
+	defer restoreRunner()
+
+	restore := captureStdout(t)
+	err := runTUI(newCmd("--dir", tmpDir, "--save-tree", treeFile), []string{})
+	restore()
+	require.NoError(t, err)
+	require.FileExists(t, treeFile)
+
+	scanned, _, err := stack.FindAndBuildTree(tmpDir, "")
+	require.NoError(t, err)
+	saved, _, err := stack.LoadTree(treeFile)
+	require.NoError(t, err)
+	assert.True(t, scanned.Equal(saved), "saved tree should match the scanned tree")
+
+	// Remove the stacks so only the saved file can provide the tree.
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, "env")))
+
+	restore = captureStdout(t)
+	err = runTUI(newCmd("--dir", tmpDir, "--load-tree", treeFile), []string{})
+	output := restore()
+	require.NoError(t, err)
+	assert.Contains(t, output, "Loading stack tree from")
+	assert.NotContains(t, output, "Scanning for stacks")
+	assert.Equal(t, saved.Path, capturedModel.GetSelectedStackPath())
+}
+
+// TestRunTUI_IncludeRootTarget tests that the configured root config file determines the
+// include root the commands column can target for aggregate runs.
+func TestRunTUI_IncludeRootTarget(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("root_config_file", "project.hcl")
+
+	tmpDir := t.TempDir()
+	liveDir := filepath.Join(tmpDir, "live")
+	require.NoError(t, os.MkdirAll(filepath.Join(liveDir, "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "project.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(liveDir, "root.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(liveDir, "vpc", "terragrunt.hcl"), []byte(""), 0644))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("dir", "", "")
+	cmd.Flags().String("plans-dir", "", "")
+	cmd.Flags().String("save-tree", "", "")
+	cmd.Flags().String("load-tree", "", "")
+	require.NoError(t, cmd.ParseFlags([]string{"--dir", liveDir}))
+
+	var targetPath string
+	restoreRunner := setTUIRunner(func(initialModel tui.Model) (tui.Model, error) {
+		updated, _ := initialModel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tui.KeyRoot)})
+		targetPath = updated.(tui.Model).GetSelectedStackPath()
+		return initialModel, nil
+	})
+	defer restoreRunner()
+
+	restore := captureStdout(t)
+	err := runTUI(cmd, []string{})
+	restore()
+	require.NoError(t, err)
+
+	expected, err := filepath.Abs(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, expected, targetPath, "project.hcl, not root.hcl, should locate the include root")
+}
+
+// TestRunTUI_ConfigReload tests that ctrl+r re-reads .terrax.yaml and applies it to the running model.
+func TestRunTUI_ConfigReload(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "env", "dev"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "env", "dev", "terragrunt.hcl"), []byte(""), 0644))
+	configPath := filepath.Join(tmpDir, ".terrax.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("commands: [plan]\nmax_navigation_columns: 2\n"), 0644))
+
+	originalWd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer func() { require.NoError(t, os.Chdir(originalWd)) }()
+	initConfig()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("dir", "", "")
+	cmd.Flags().String("plans-dir", "", "")
+	cmd.Flags().String("save-tree", "", "")
+	cmd.Flags().String("load-tree", "", "")
+
+	var before, after tui.Model
+	restoreRunner := setTUIRunner(func(initialModel tui.Model) (tui.Model, error) {
+		before = initialModel
+		require.NoError(t, os.WriteFile(configPath, []byte("commands: [plan, apply, destroy]\nmax_navigation_columns: 4\n"), 0644))
+		updated, _ := initialModel.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+		after = updated.(tui.Model)
+		return after, nil
+	})
+	defer restoreRunner()
+
+	restore := captureStdout(t)
+	err = runTUI(cmd, []string{})
+	restore()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"plan"}, before.GetCommands())
+	assert.Equal(t, 2, before.GetMaxNavigationColumns())
+	assert.Equal(t, []string{"plan", "apply", "destroy"}, after.GetCommands())
+	assert.Equal(t, 4, after.GetMaxNavigationColumns())
+}
+
+// TestRunTUI_Branch tests that the branch reported by the VCS is passed to the TUI header,
+// and that directories outside a repository get no branch.
+func TestRunTUI_Branch(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "vpc", "terragrunt.hcl"), []byte(""), 0644))
+
+	tests := []struct {
+		name     string
+		query    BranchQuery
+		expected string
+	}{
+		{
+			name:     "inside a repository",
+			query:    func(dir string) (string, error) { return "main", nil },
+			expected: "main",
+		},
+		{
+			name:     "not a repository",
+			query:    func(dir string) (string, error) { return "", errors.New("not a git repository") },
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queriedDir string
+			defer setBranchQuery(func(dir string) (string, error) {
+				queriedDir = dir
+				return tt.query(dir)
+			})()
+
+			var capturedModel tui.Model
+			defer setTUIRunner(func(initialModel tui.Model) (tui.Model, error) {
+				capturedModel = initialModel
+				return initialModel, nil
+			})()
+
+			cmd := &cobra.Command{}
+			cmd.Flags().String("dir", "", "")
+			cmd.Flags().String("plans-dir", "", "")
+			cmd.Flags().String("save-tree", "", "")
+			cmd.Flags().String("load-tree", "", "")
+			require.NoError(t, cmd.ParseFlags([]string{"--dir", tmpDir}))
+
+			restore := captureStdout(t)
+			err := runTUI(cmd, []string{})
+			restore()
+			require.NoError(t, err)
+
+			assert.NotEmpty(t, queriedDir)
+			assert.Equal(t, tt.expected, capturedModel.GetBranch())
+		})
+	}
+}
+
+func TestPreviewExecution(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	tmpDir := t.TempDir()
+	for rel, content := range map[string]string{
+		"root.hcl":                  "",
+		"private/db/terragrunt.hcl": "",
+		"private/db/stack.hcl":      "require_private_connection = true",
+	} {
+		p := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+	viper.Set("root_config_file", "root.hcl")
+	viper.Set("terragrunt.extra_flags", []string{"--non-interactive"})
+	viper.Set("terraform.extra_flags", []string{"-lock=false"})
+	viper.Set("stack_groups", map[string]any{
+		"private": map[string]any{
+			"detect": "require_private_connection = true",
+			"env":    map[string]any{"tf_var_token": "secret", "aws_profile": "prod"},
+		},
+	})
+
+	details := previewExecution(tui.ExecutionDetails{
+		Command:    "plan",
+		StackPaths: []string{filepath.Join(tmpDir, "private", "db")},
+		Branch:     "main",
+	})
+
+	assert.NotEmpty(t, details.Binary)
+	assert.Equal(t, "plan", details.Command)
+	assert.Equal(t, []string{"--non-interactive", "--", "-lock=false"}, details.ExtraArgs)
+	assert.Equal(t, []string{"aws_profile", "tf_var_token"}, details.EnvVarNames)
+	assert.Equal(t, "main", details.Branch)
+}
+
+// TestConfirmDirtyRun tests that warn_on_dirty_apply asks before a dangerous command runs
+// against stacks with uncommitted changes, and skips the check for other commands.
+func TestConfirmDirtyRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		enabled     bool
+		command     string
+		dirty       bool
+		answer      bool
+		wantChecked bool
+		wantPrompt  bool
+		wantProceed bool
+	}{
+		{name: "dirty apply declined", enabled: true, command: "apply", dirty: true, answer: false, wantChecked: true, wantPrompt: true, wantProceed: false},
+		{name: "dirty apply accepted", enabled: true, command: "apply", dirty: true, answer: true, wantChecked: true, wantPrompt: true, wantProceed: true},
+		{name: "clean apply proceeds", enabled: true, command: "apply", dirty: false, wantChecked: true, wantPrompt: false, wantProceed: true},
+		{name: "read-only command skips the check", enabled: true, command: "plan", dirty: true, wantChecked: false, wantPrompt: false, wantProceed: true},
+		{name: "disabled skips the check", enabled: false, command: "destroy", dirty: true, wantChecked: false, wantPrompt: false, wantProceed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("warn_on_dirty_apply", tt.enabled)
+			viper.Set("dangerous_commands", config.DefaultDangerousCommands)
+
+			checked, prompted := false, false
+			defer setDirtyCheck(func(string) (bool, error) {
+				checked = true
+				return tt.dirty, nil
+			})()
+			defer setPrompter(func(question string) bool {
+				prompted = true
+				assert.Contains(t, question, tt.command)
+				return tt.answer
+			})()
+
+			proceed := confirmDirtyRun(tt.command, []string{"/repo/dev/vpc"})
+
+			assert.Equal(t, tt.wantProceed, proceed)
+			assert.Equal(t, tt.wantChecked, checked)
+			assert.Equal(t, tt.wantPrompt, prompted)
+		})
+	}
+}
+
+// TestConfirmStackCount tests that max_stacks blocks a scan finding more stacks unless the
+// user confirms or --force is given.
+func TestConfirmStackCount(t *testing.T) {
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "root.hcl"), []byte(""), 0644))
+	for _, name := range []string{"vpc", "rds", "dns"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(workDir, name), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, name, "terragrunt.hcl"), []byte(""), 0644))
+	}
+
+	tests := []struct {
+		name       string
+		maxStacks  int
+		force      bool
+		answer     bool
+		wantPrompt bool
+		wantErr    bool
+	}{
+		{name: "over the limit declined", maxStacks: 2, answer: false, wantPrompt: true, wantErr: true},
+		{name: "over the limit confirmed", maxStacks: 2, answer: true, wantPrompt: true},
+		{name: "force skips the check", maxStacks: 2, force: true},
+		{name: "at the limit proceeds", maxStacks: 3},
+		{name: "disabled skips the check", maxStacks: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("max_stacks", tt.maxStacks)
+
+			prompted := false
+			defer setPrompter(func(string) bool {
+				prompted = true
+				return tt.answer
+			})()
+
+			err := confirmStackCount(workDir, tt.force)
+
+			assert.Equal(t, tt.wantPrompt, prompted)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "found more than max_stacks (2) stacks")
+				assert.ErrorContains(t, err, "--force")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestValidateBinaries tests that binary and binary_markers only accept known executables
+// and that an explicitly chosen binary must be on PATH.
+func TestValidateBinaries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub binary is a shell script")
+	}
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "tofu"), []byte("#!/bin/sh\n"), 0o755))
+	t.Setenv("PATH", binDir)
+
+	tests := []struct {
+		name    string
+		binary  string
+		markers map[string]string
+		wantErr string
+	}{
+		{name: "default binary is checked when running"},
+		{name: "binary on PATH", binary: "tofu", markers: map[string]string{"*.tf": "terraform"}},
+		{name: "binary missing from PATH", binary: "terraform", wantErr: "terraform not found in PATH"},
+		{name: "unknown binary", binary: "terrafrom", wantErr: `invalid binary "terrafrom"`},
+		{name: "unknown marker binary", markers: map[string]string{"*.tf": "tf"}, wantErr: `invalid binary_markers: invalid binary "tf"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("binary", tt.binary)
+			viper.Set("binary_markers", tt.markers)
+
+			err := validateBinaries()
+
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestConfirmDirtyRun_OutsideRepository tests that a failed VCS check does not block the run.
+func TestConfirmDirtyRun_OutsideRepository(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("warn_on_dirty_apply", true)
+	viper.Set("dangerous_commands", []string{"apply"})
+
+	defer setDirtyCheck(func(string) (bool, error) { return false, errors.New("not a git repository") })()
+	defer setPrompter(func(string) bool {
+		t.Fatal("should not prompt")
+		return false
+	})()
+
+	assert.True(t, confirmDirtyRun("apply", []string{"/tmp/stack"}))
+}
+
+// TestRunTUI_LastSelection tests that the command and stack selected when the TUI closes
+// are restored on the next launch in the same project unless --fresh is given.
+func TestRunTUI_LastSelection(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Cleanup(setSelectionFile(filepath.Join(t.TempDir(), "selections.json")))
+
+	tmpDir := t.TempDir()
+	for _, env := range []string{"dev", "prod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "env", env), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "env", env, "terragrunt.hcl"), []byte(""), 0644))
+	}
+	absDir, err := filepath.Abs(tmpDir)
+	require.NoError(t, err)
+
+	run := func(runner TUIRunner, args ...string) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.Flags().String("dir", "", "")
+		cmd.Flags().Bool("fresh", false, "")
+		require.NoError(t, cmd.ParseFlags(append([]string{"--dir", tmpDir}, args...)))
+		defer setTUIRunner(runner)()
+
+		restore := captureStdout(t)
+		err := runTUI(cmd, []string{})
+		restore()
+		require.NoError(t, err)
+	}
+	press := func(m tui.Model, keys ...tea.KeyMsg) tui.Model {
+		for _, key := range keys {
+			updated, _ := m.Update(key)
+			m = updated.(tui.Model)
+		}
+		return m
+	}
+	var initial tui.Model
+	capture := func(m tui.Model) (tui.Model, error) {
+		initial = m
+		return m, nil
+	}
+
+	run(func(m tui.Model) (tui.Model, error) {
+		return press(m,
+			tea.KeyMsg{Type: tea.KeyDown},
+			tea.KeyMsg{Type: tea.KeyRight},
+			tea.KeyMsg{Type: tea.KeyRight},
+			tea.KeyMsg{Type: tea.KeyDown},
+		), nil
+	})
+
+	run(capture)
+	assert.Equal(t, "apply", initial.GetSelectedCommand())
+	assert.Equal(t, filepath.Join(absDir, "env", "prod"), initial.GetSelectedStackPath())
+
+	run(capture, "--fresh")
+	assert.Equal(t, "plan", initial.GetSelectedCommand())
+	assert.Equal(t, absDir, initial.GetSelectedStackPath(), "--fresh starts at the root")
+
+	// --fresh still saves where the session ended; a stack removed since is ignored.
+	run(func(m tui.Model) (tui.Model, error) {
+		return press(m, tea.KeyMsg{Type: tea.KeyRight}, tea.KeyMsg{Type: tea.KeyRight}), nil
+	}, "--fresh")
+	require.NoError(t, os.RemoveAll(filepath.Join(tmpDir, "env", "dev")))
+	run(capture)
+	assert.Equal(t, "plan", initial.GetSelectedCommand())
+	assert.Equal(t, absDir, initial.GetSelectedStackPath(), "a missing stack leaves the model at the root")
+}
@@ -25,6 +25,8 @@ var runCmd = &cobra.Command{
 func init() {
 	runCmd.Flags().String("dir", "", "Working directory (overrides current directory)")
 	runCmd.Flags().String("plans-dir", "", "Directory for JSON plan output files (overrides plan.json_out_dir in config)")
+	runCmd.Flags().String("note", "", "Note or tag stored with the execution in history (e.g. 'prod release')")
+	runCmd.Flags().Bool("quiet", false, "Show a progress spinner instead of command output; output is printed only on failure (overrides quiet in config)")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -45,11 +47,22 @@ func runCommand(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
-	ensureConfigFromWorkDir(workDir)
+	if err := ensureConfigFromWorkDir(workDir); err != nil {
+		return err
+	}
 
 	if plansDir, _ := cmd.Flags().GetString("plans-dir"); plansDir != "" {
 		viper.Set("plan.json_out_dir", plansDir)
 	}
+	if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+		viper.Set("quiet", true)
+	}
+	if note, _ := cmd.Flags().GetString("note"); note != "" {
+		viper.Set("note", note)
+	}
+	if err := validateBinaries(); err != nil {
+		return err
+	}
 
 	historyService, err := getHistoryService()
 	if err != nil {
@@ -80,7 +93,7 @@ func runCommand(cmd *cobra.Command, args []string) error {
 		if group.Skip {
 			continue
 		}
-		if err := executor.Run(ctx, historyService, command, workDir, repoRoot, group.Paths, group.EnvVars); err != nil {
+		if err := executor.Run(ctx, historyService, command, workDir, repoRoot, group.Paths, resolveEnvVars(command, group.Paths, group.EnvVars)); err != nil {
 			return err
 		}
 	}
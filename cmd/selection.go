@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/history"
+	"github.com/israoo/terrax/internal/tui"
+)
+
+// currentSelectionFile returns the file the last selection of each project is kept in
+// (can be overridden in tests).
+var currentSelectionFile = history.GetSelectionFilePath
+
+// setSelectionFile allows tests to keep last selections in a temporary file.
+// Returns a cleanup function to restore the original location.
+func setSelectionFile(filePath string) func() {
+	original := currentSelectionFile
+	currentSelectionFile = func() (string, error) { return filePath, nil }
+	return func() {
+		currentSelectionFile = original
+	}
+}
+
+// selectionProjectRoot returns the key last selections are saved under for workDir: the
+// directory holding root_config_file above it or, when there is none, workDir itself.
+func selectionProjectRoot(workDir string) string {
+	rootConfigFile := viper.GetString("root_config_file")
+	if rootConfigFile == "" {
+		rootConfigFile = config.DefaultRootConfigFile
+	}
+	if root, err := history.FindProjectRoot(workDir, rootConfigFile); err == nil && root != "" {
+		return root
+	}
+	if absPath, err := filepath.Abs(workDir); err == nil {
+		return absPath
+	}
+	return workDir
+}
+
+// restoreLastSelection positions model at the command and stack selected when the TUI last
+// closed in projectRoot. Nothing saved, or a selection that no longer fits the tree, leaves
+// the model at its defaults.
+func restoreLastSelection(model tui.Model, projectRoot string) tui.Model {
+	filePath, err := currentSelectionFile()
+	if err != nil {
+		return model
+	}
+	selection, ok, err := history.LoadLastSelection(filePath, projectRoot)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not restore last selection: %v\n", err)
+		return model
+	}
+	if !ok {
+		return model
+	}
+	return model.WithLastSelection(selection.Command, selection.StackPath)
+}
+
+// saveLastSelection records the command and stack selected in model for projectRoot, so
+// the next launch in the project starts there.
+func saveLastSelection(model tui.Model, projectRoot string) {
+	stackPath := model.GetSelectedStackPath()
+	if stackPath == tui.NoItemSelected {
+		stackPath = ""
+	}
+	selection := history.LastSelection{Command: model.GetSelectedCommandLabel(), StackPath: stackPath}
+
+	filePath, err := currentSelectionFile()
+	if err == nil {
+		err = history.SaveLastSelection(filePath, projectRoot, selection)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not save last selection: %v\n", err)
+	}
+}
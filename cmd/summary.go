@@ -35,7 +35,9 @@ func runSummaryCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	ensureConfigFromWorkDir(workDir)
+	if err := ensureConfigFromWorkDir(workDir); err != nil {
+		return err
+	}
 	workDir = resolveWorkDir(workDir)
 
 	if plansDir, _ := cmd.Flags().GetString("plans-dir"); plansDir != "" {
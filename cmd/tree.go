@@ -30,7 +30,7 @@ func runTree(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	root, _, err := stack.FindAndBuildTree(workDir, viper.GetString("root_config_file"))
+	root, _, err := stack.FindAndBuildTreeWithOptions(workDir, viper.GetString("root_config_file"), treeOptions())
 	if err != nil {
 		return fmt.Errorf("failed to build stack tree: %w", err)
 	}
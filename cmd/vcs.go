@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// BranchQuery returns the VCS branch checked out in dir.
+// It returns an error when dir is not inside a repository.
+type BranchQuery func(dir string) (string, error)
+
+// currentBranchQuery holds the active branch query (can be overridden in tests).
+var currentBranchQuery BranchQuery = gitBranch
+
+// gitBranch asks git for the branch checked out in dir ("HEAD" when detached).
+func gitBranch(dir string) (string, error) {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// setBranchQuery allows tests to inject a fake VCS.
+// Returns a cleanup function to restore the original query.
+func setBranchQuery(query BranchQuery) func() {
+	original := currentBranchQuery
+	currentBranchQuery = query
+	return func() {
+		currentBranchQuery = original
+	}
+}
+
+// currentBranch returns the branch checked out in dir, or an empty string when dir is not
+// in a repository or git is unavailable.
+func currentBranch(dir string) string {
+	branch, err := currentBranchQuery(dir)
+	if err != nil {
+		return ""
+	}
+	return branch
+}
+
+// DirtyCheck reports whether dir has uncommitted changes, including untracked files.
+// It returns an error when dir is not inside a repository.
+type DirtyCheck func(dir string) (bool, error)
+
+// currentDirtyCheck holds the active dirty check (can be overridden in tests).
+var currentDirtyCheck DirtyCheck = gitDirty
+
+// gitDirty asks git for changes to the files under dir.
+func gitDirty(dir string) (bool, error) {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain", "--", ".").Output()
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
+
+// setDirtyCheck allows tests to inject a fake working tree status.
+// Returns a cleanup function to restore the original check.
+func setDirtyCheck(check DirtyCheck) func() {
+	original := currentDirtyCheck
+	currentDirtyCheck = check
+	return func() {
+		currentDirtyCheck = original
+	}
+}
@@ -0,0 +1,23 @@
+// Package bounds provides index-safety helpers shared by navigation and list views.
+//
+// Selection indices, cursors and pagination offsets all need the same range checks;
+// centralizing them here keeps the off-by-one handling in one tested place.
+package bounds
+
+// InRange reports whether i is a valid index into a list of length n (0 <= i < n).
+func InRange(i, n int) bool {
+	return i >= 0 && i < n
+}
+
+// ClampIndex returns i limited to the valid index range of a list of length n.
+// Negative indices clamp to 0 and indices past the end clamp to n-1.
+// For an empty list (n <= 0) it returns 0.
+func ClampIndex(i, n int) int {
+	if n <= 0 || i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
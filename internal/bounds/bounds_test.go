@@ -0,0 +1,51 @@
+package bounds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		i        int
+		n        int
+		expected bool
+	}{
+		{name: "first index", i: 0, n: 3, expected: true},
+		{name: "last index", i: 2, n: 3, expected: true},
+		{name: "one past the end", i: 3, n: 3, expected: false},
+		{name: "negative index", i: -1, n: 3, expected: false},
+		{name: "empty list", i: 0, n: 0, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, InRange(tt.i, tt.n))
+		})
+	}
+}
+
+func TestClampIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		i        int
+		n        int
+		expected int
+	}{
+		{name: "index within range", i: 1, n: 3, expected: 1},
+		{name: "last index", i: 2, n: 3, expected: 2},
+		{name: "past the end clamps to last", i: 10, n: 3, expected: 2},
+		{name: "negative clamps to first", i: -5, n: 3, expected: 0},
+		{name: "empty list", i: 4, n: 0, expected: 0},
+		{name: "negative length", i: 1, n: -1, expected: 0},
+		{name: "single item", i: 1, n: 1, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClampIndex(tt.i, tt.n))
+		})
+	}
+}
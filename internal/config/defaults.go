@@ -5,25 +5,145 @@
 // and provides a single source of truth for fallback values.
 package config
 
+// Values of the config_precedence setting.
+const (
+	ConfigPrecedenceOverride = "override" // The project config file replaces the home one wholesale.
+	ConfigPrecedenceMerge    = "merge"    // Keys unset in the project config file are inherited from home.
+)
+
 // Default configuration values for TerraX.
 const (
+	// DefaultPreviewLines is the number of terragrunt.hcl lines read for the preview pane.
+	DefaultPreviewLines = 40
+
 	// DefaultMaxNavigationColumns is the default number of navigation columns visible simultaneously.
 	DefaultMaxNavigationColumns = 3
 
 	// MinMaxNavigationColumns is the minimum allowed value for max navigation columns.
 	MinMaxNavigationColumns = 1
 
+	// DefaultColumnGap is the number of spaces between adjacent TUI columns.
+	DefaultColumnGap = 2
+
 	// DefaultHistoryMaxEntries is the default maximum number of history entries to keep.
 	// When the history exceeds this limit, older entries are automatically trimmed.
 	DefaultHistoryMaxEntries = 500
 
+	// DefaultHistoryTrimEvery trims the history on every append. Larger values trim on
+	// every Nth append only, letting the history briefly exceed its maximum size.
+	DefaultHistoryTrimEvery = 1
+
+	// DefaultHistoryTrimMaxBytes disables trimming triggered by the history file size.
+	DefaultHistoryTrimMaxBytes = 0
+
+	// DefaultCommandTimeout lets executed commands run without a time limit.
+	DefaultCommandTimeout = "0s"
+
+	// DefaultHistoryMaxAge disables dropping history entries by age; only max entries applies.
+	DefaultHistoryMaxAge = "0s"
+
 	// MinHistoryMaxEntries is the minimum allowed value for history max entries.
 	MinHistoryMaxEntries = 10
 
+	// DefaultHistoryTableStriped controls whether alternate rows in the history table
+	// are rendered with a distinct background (zebra striping).
+	DefaultHistoryTableStriped = false
+
+	// DefaultHistoryOrder is the order history entries are loaded and listed in:
+	// "newest" (highest ID first) or "oldest" (lowest ID first).
+	DefaultHistoryOrder = "newest"
+
+	// DefaultEmoji controls whether configured command icons may use emoji.
+	// When false, non-ASCII icons are rendered with an ASCII fallback.
+	DefaultEmoji = true
+
+	// DefaultRightArrowConfirm controls whether right-arrow on a leaf stack confirms the
+	// selection like enter. When false, right-arrow wraps to the commands column.
+	DefaultRightArrowConfirm = false
+
+	// DefaultCyclicNavigation controls whether up/down past either end of a column wraps
+	// around. When false, the selection stops at the first and last item.
+	DefaultCyclicNavigation = true
+
+	// DefaultBreadcrumbCommand controls whether the breadcrumb bar shows the selected
+	// command before the path ("plan @ /repo/env/dev").
+	DefaultBreadcrumbCommand = false
+
+	// DefaultConfirmSummary controls whether confirming a selection first shows a summary
+	// of the execution that must be accepted with enter.
+	DefaultConfirmSummary = false
+
+	// DefaultDisableAltScreen controls whether the TUI renders in the terminal's main screen
+	// buffer instead of the alternate one (useful when debugging the TUI).
+	DefaultDisableAltScreen = false
+
+	// DefaultEnterOnParentStack is what enter does on a node that is both a stack and a
+	// parent of other stacks: "confirm" runs the stack, "drill" moves into its children.
+	DefaultEnterOnParentStack = "confirm"
+
+	// DefaultThemePreset is the built-in color theme the theme section starts from.
+	DefaultThemePreset = "default"
+
+	// DefaultTypeToFilter is whether typing a printable key opens the column filter with it:
+	// "off", "unbound" (only keys without a shortcut) or "all" (shortcuts need other keys).
+	DefaultTypeToFilter = "off"
+
+	// DefaultFilterScope is which columns a filter narrows: "column" (each column keeps its
+	// own filter) or "global" (one filter follows focus from column to column).
+	DefaultFilterScope = "column"
+
+	// DefaultRememberCommandPerStack controls whether focusing a stack pre-selects the
+	// command last run against it (from history) instead of the first command.
+	DefaultRememberCommandPerStack = false
+
+	// DefaultShowLastResult controls whether navigation columns mark each stack with the
+	// outcome of its most recent run (from history).
+	DefaultShowLastResult = false
+
+	// DefaultGroupStacks controls whether navigation columns list stacks before plain
+	// directories, with a divider between the two groups.
+	DefaultGroupStacks = false
+
+	// DefaultFuzzyFilter controls whether column filters match typed characters as an
+	// ordered subsequence ranked by match quality instead of as a substring.
+	DefaultFuzzyFilter = false
+
+	// DefaultHighlightMatches controls whether filtered lists style the characters the
+	// column filter matched in each item.
+	DefaultHighlightMatches = true
+
+	// DefaultSkipSingleCommand controls whether the commands column is hidden when only one
+	// command is configured, so enter runs that command against the selected stack.
+	DefaultSkipSingleCommand = false
+
+	// DefaultWarnOnDirtyApply controls whether running a dangerous command against stacks with
+	// uncommitted VCS changes asks for confirmation first.
+	DefaultWarnOnDirtyApply = false
+
+	// DefaultTreatRootAsStack controls whether a scan root holding its own terragrunt.hcl is
+	// a stack; when false, targeting the root runs the stacks beneath it instead.
+	DefaultTreatRootAsStack = true
+
+	// DefaultScanParallelism is the number of directories scanned concurrently while
+	// building the stack tree; 0 uses GOMAXPROCS.
+	DefaultScanParallelism = 0
+
+	// DefaultRespectGitignore controls whether directories ignored by .gitignore files
+	// found during a scan are left out of the stack tree.
+	DefaultRespectGitignore = false
+
+	// DefaultMaxStacks is the number of stacks a scan may find before the tree is built only
+	// after confirmation (or --force); 0 disables the check.
+	DefaultMaxStacks = 0
+
 	// DefaultRootConfigFile is the default name of the root configuration file
 	// used to determine the project root directory.
 	DefaultRootConfigFile = "root.hcl"
 
+	// DefaultConfigPrecedence controls how the home .terrax.yaml combines with a project
+	// .terrax.yaml: "override" uses the project file alone, "merge" layers it over the home file.
+	DefaultConfigPrecedence = ConfigPrecedenceOverride
+
 	// DefaultLogFormat is the default terragrunt log format.
 	DefaultLogFormat = "pretty"
 
@@ -34,6 +154,14 @@ const (
 	// DefaultNoColor controls whether to disable colored output.
 	DefaultNoColor = false
 
+	// DefaultQuiet controls whether command output is buffered behind a progress spinner
+	// and printed only when the command fails, instead of being streamed.
+	DefaultQuiet = false
+
+	// DefaultRunLogsEnabled controls whether each run's output is also written to a
+	// timestamped file whose path is stored in the history entry.
+	DefaultRunLogsEnabled = false
+
 	// DefaultIncludeDependencies controls whether TerraX resolves transitive dependencies
 	// when building the filter list for command execution.
 	DefaultIncludeDependencies = true
@@ -53,6 +181,20 @@ const (
 	// DefaultPlanReviewEnabled controls whether plan file scanning and review TUI are active.
 	DefaultPlanReviewEnabled = true
 
+	// DefaultCacheEnabled controls whether the scanned stack tree is cached on disk and
+	// reused while the scanned directories are unchanged.
+	DefaultCacheEnabled = false
+
+	// DefaultCacheDir is the default directory for stack tree cache files, relative to the
+	// project root.
+	DefaultCacheDir = ".terrax/tree-cache"
+
+	// DefaultCacheTTL is the default maximum age of a cached stack tree.
+	DefaultCacheTTL = "24h"
+
+	// DefaultIdleTimeout disables quitting the TUI after a period without input.
+	DefaultIdleTimeout = "0s"
+
 	// DefaultJSONOutDir is the default output directory for Terragrunt JSON plan files.
 	DefaultJSONOutDir = ".terrax/plans"
 
@@ -71,3 +213,46 @@ var DefaultCommands = []string{
 	"refresh",
 	"destroy",
 }
+
+// DefaultStackMarkers is the default list of files whose presence makes a directory a stack.
+var DefaultStackMarkers = []string{
+	"terragrunt.hcl",
+}
+
+// DefaultSkipDirectories is the default list of directory names never scanned for stacks.
+var DefaultSkipDirectories = []string{
+	".git",
+	".terraform",
+	".terragrunt-cache",
+	"vendor",
+	".idea",
+	".vscode",
+}
+
+// DefaultKeyBindings maps each TUI key binding action to its default key, written as
+// Bubble Tea reports it (e.g. "q", "up", "pgdown", "ctrl+k").
+var DefaultKeyBindings = map[string]string{
+	ActionQuit:     "q",
+	ActionUp:       "up",
+	ActionDown:     "down",
+	ActionLeft:     "left",
+	ActionRight:    "right",
+	ActionFilter:   "/",
+	ActionConfirm:  "enter",
+	ActionPageUp:   "pgup",
+	ActionPageDown: "pgdown",
+}
+
+// DefaultRequireConfirmation is the default list of commands the TUI runs only after an
+// explicit "y" in a yes/no prompt.
+var DefaultRequireConfirmation = []string{
+	"destroy",
+	"apply",
+}
+
+// DefaultDangerousCommands is the default list of commands highlighted with a warning
+// style in the TUI because they modify or destroy infrastructure.
+var DefaultDangerousCommands = []string{
+	"apply",
+	"destroy",
+}
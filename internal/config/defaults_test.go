@@ -78,3 +78,45 @@ func TestDefaultCommands(t *testing.T) {
 	assert.Equal(t, expectedCommands, DefaultCommands)
 	assert.Len(t, DefaultCommands, 8)
 }
+
+// TestTUI_Normalize verifies that missing or invalid TUI settings fall back to defaults.
+func TestTUI_Normalize(t *testing.T) {
+	cfg := TUI{MaxNavigationColumns: 0}
+	cfg.Normalize()
+	assert.Equal(t, DefaultCommands, cfg.Commands)
+	assert.Equal(t, DefaultMaxNavigationColumns, cfg.MaxNavigationColumns)
+
+	cfg = TUI{Commands: []string{"plan"}, MaxNavigationColumns: 5}
+	cfg.Normalize()
+	assert.Equal(t, []string{"plan"}, cfg.Commands)
+	assert.Equal(t, 5, cfg.MaxNavigationColumns)
+	assert.Equal(t, 0, cfg.ColumnGap, "a zero column gap means no gap")
+
+	cfg = TUI{ColumnGap: -1}
+	cfg.Normalize()
+	assert.Equal(t, DefaultColumnGap, cfg.ColumnGap)
+}
+
+// TestTUI_ResolveKeyBindings verifies that configured keys override the defaults and that
+// unknown actions, empty keys and colliding keys are rejected.
+func TestTUI_ResolveKeyBindings(t *testing.T) {
+	cfg := TUI{}
+	assert.NoError(t, cfg.ResolveKeyBindings())
+	assert.Equal(t, DefaultKeyBindings, cfg.KeyBindings)
+
+	cfg = TUI{KeyBindings: map[string]string{ActionQuit: "x", ActionUp: "w"}}
+	assert.NoError(t, cfg.ResolveKeyBindings())
+	assert.Equal(t, "x", cfg.KeyBindings[ActionQuit])
+	assert.Equal(t, "w", cfg.KeyBindings[ActionUp])
+	assert.Equal(t, "down", cfg.KeyBindings[ActionDown])
+	assert.Len(t, cfg.KeyBindings, len(DefaultKeyBindings))
+
+	cfg = TUI{KeyBindings: map[string]string{"jump": "x"}}
+	assert.EqualError(t, cfg.ResolveKeyBindings(), `unknown keybindings action "jump"`)
+
+	cfg = TUI{KeyBindings: map[string]string{ActionQuit: ""}}
+	assert.EqualError(t, cfg.ResolveKeyBindings(), `keybindings action "quit" has no key`)
+
+	cfg = TUI{KeyBindings: map[string]string{ActionQuit: "up"}}
+	assert.EqualError(t, cfg.ResolveKeyBindings(), `key "up" is bound to both "quit" and "up"`)
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PathKeys lists the configuration keys holding file or directory paths.
+// Their values are expanded with ExpandPath when the configuration is loaded.
+var PathKeys = []string{
+	"plan.json_out_dir",
+	"run_logs.dir",
+	"cache.dir",
+	"features.report.file",
+	"state.aws_config_file",
+}
+
+// ExpandPath replaces a leading ~ with the user's home directory and expands $VAR and
+// ${VAR} references from the environment. Other paths, including absolute ones, are
+// returned unchanged. A leading ~ is kept when the home directory cannot be determined.
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	return os.ExpandEnv(path)
+}
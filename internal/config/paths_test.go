@@ -0,0 +1,36 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("TERRAX_TEST_DIR", "plans")
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "tilde prefix", path: "~/.terrax/history.jsonl", expected: filepath.Join(home, ".terrax", "history.jsonl")},
+		{name: "tilde alone", path: "~", expected: home},
+		{name: "HOME variable", path: "$HOME/.terrax/logs", expected: home + "/.terrax/logs"},
+		{name: "braced variable", path: "/tmp/${TERRAX_TEST_DIR}/out", expected: "/tmp/plans/out"},
+		{name: "absolute path unchanged", path: "/var/lib/terrax/plans", expected: "/var/lib/terrax/plans"},
+		{name: "relative path unchanged", path: ".terrax/plans", expected: ".terrax/plans"},
+		{name: "tilde inside name unchanged", path: "backup~/plans", expected: "backup~/plans"},
+		{name: "empty", path: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ExpandPath(tt.path))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Actions of the TUI that can be bound to keys under keybindings.
+const (
+	ActionQuit     = "quit"
+	ActionUp       = "up"
+	ActionDown     = "down"
+	ActionLeft     = "left"
+	ActionRight    = "right"
+	ActionFilter   = "filter"
+	ActionConfirm  = "confirm"
+	ActionPageUp   = "page-up"
+	ActionPageDown = "page-down"
+)
+
+// TUI holds the settings applied to the interactive TUI. It is decoded from the loaded
+// configuration as a whole, so the same settings can be re-read while the TUI is running.
+type TUI struct {
+	Commands             []string          `mapstructure:"commands"`
+	DangerousCommands    []string          `mapstructure:"dangerous_commands"`
+	CommandIcons         map[string]string `mapstructure:"command_icons"`
+	CommandDescriptions  map[string]string `mapstructure:"command_descriptions"`
+	CommandAliases       map[string]string `mapstructure:"command_aliases"`
+	CommandGroups        []TUICommandGroup `mapstructure:"command_groups"`
+	Emoji                bool              `mapstructure:"emoji"`
+	MaxNavigationColumns int               `mapstructure:"max_navigation_columns"`
+	ColumnWidth          int               `mapstructure:"column_width"`
+	ColumnGap            int               `mapstructure:"column_gap"` // 0 = no gap; negative = DefaultColumnGap
+	ColumnSeparator      string            `mapstructure:"column_separator"`
+	RightArrowConfirm    bool              `mapstructure:"right_arrow_confirm"`
+	CyclicNavigation     bool              `mapstructure:"cyclic_navigation"`
+	BreadcrumbCommand    bool              `mapstructure:"breadcrumb_command"`
+	EnterOnParentStack   string            `mapstructure:"enter_on_parent_stack"`
+	ConfirmSummary       bool              `mapstructure:"confirm_summary"`
+	RequireConfirmation  []string          `mapstructure:"require_confirmation"`
+	DisableAltScreen     bool              `mapstructure:"disable_alt_screen"`
+	FuzzyFilter          bool              `mapstructure:"fuzzy_filter"`
+	HighlightMatches     bool              `mapstructure:"highlight_matches"`
+	TypeToFilter         string            `mapstructure:"type_to_filter"`
+	FilterScope          string            `mapstructure:"filter_scope"`
+	IdleTimeout          time.Duration     `mapstructure:"idle_timeout"`
+	PreviewLines         int               `mapstructure:"preview_lines"`
+	SkipSingleCommand    bool              `mapstructure:"skip_single_command"`
+	Favorites            []string          `mapstructure:"favorites"`
+	Locale               string            `mapstructure:"locale"`
+	Messages             TUIMessages       `mapstructure:"messages"`
+	Theme                TUITheme          `mapstructure:"theme"`
+
+	// KeyBindings maps an action (ActionQuit, ActionUp, ...) to the key that triggers it.
+	// Actions left out keep their key from DefaultKeyBindings.
+	KeyBindings map[string]string `mapstructure:"keybindings"`
+
+	// CommandStackTypes maps a command to the stack types it applies to ("terragrunt",
+	// "terraform"). Commands left out of the map apply to every stack type.
+	CommandStackTypes map[string][]string `mapstructure:"command_stack_types"`
+}
+
+// TUICommandGroup is a labeled set of commands listed together under a header in the
+// commands column.
+type TUICommandGroup struct {
+	Name     string   `mapstructure:"name"`
+	Commands []string `mapstructure:"commands"`
+}
+
+// TUIMessages holds the configurable status text of the TUI.
+type TUIMessages struct {
+	Initializing   string `mapstructure:"initializing"`
+	ScanningStacks string `mapstructure:"scanning_stacks"`
+}
+
+// TUITheme holds the colors of the TUI. Preset names the built-in theme to start from
+// ("default" or "high-contrast"); each other field overrides the color of one role and is
+// left empty to keep the preset's color. Colors are hex ("#7D56F4") or ANSI ("205").
+type TUITheme struct {
+	Preset        string `mapstructure:"preset"`
+	FocusedBorder string `mapstructure:"focused_border"`
+	SelectedItem  string `mapstructure:"selected_item"`
+	StackMarker   string `mapstructure:"stack_marker"`
+	Success       string `mapstructure:"success"`
+	Error         string `mapstructure:"error"`
+	Header        string `mapstructure:"header"`
+}
+
+// Normalize replaces an empty command list, an out-of-range navigation column count and
+// a negative column gap with their defaults. A column gap of 0 is kept: it means no gap.
+func (c *TUI) Normalize() {
+	if c.ColumnGap < 0 {
+		c.ColumnGap = DefaultColumnGap
+	}
+	if len(c.Commands) == 0 {
+		c.Commands = DefaultCommands
+	}
+	if c.MaxNavigationColumns < MinMaxNavigationColumns {
+		c.MaxNavigationColumns = DefaultMaxNavigationColumns
+	}
+}
+
+// ResolveKeyBindings fills in the actions missing from KeyBindings with their default keys.
+// It fails on an unknown action, an empty key or a key bound to more than one action.
+func (c *TUI) ResolveKeyBindings() error {
+	bindings := make(map[string]string, len(DefaultKeyBindings))
+	for action, key := range DefaultKeyBindings {
+		bindings[action] = key
+	}
+	for action, key := range c.KeyBindings {
+		if _, ok := DefaultKeyBindings[action]; !ok {
+			return fmt.Errorf("unknown keybindings action %q", action)
+		}
+		if key == "" {
+			return fmt.Errorf("keybindings action %q has no key", action)
+		}
+		bindings[action] = key
+	}
+
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	boundTo := make(map[string]string, len(bindings))
+	for _, action := range actions {
+		key := bindings[action]
+		if other, ok := boundTo[key]; ok {
+			return fmt.Errorf("key %q is bound to both %q and %q", key, other, action)
+		}
+		boundTo[key] = action
+	}
+
+	c.KeyBindings = bindings
+	return nil
+}
@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Names of the executables that can run commands, resolved through PATH.
+const (
+	TerragruntBinary = "terragrunt"
+	TerraformBinary  = "terraform"
+	TofuBinary       = "tofu"
+)
+
+// Binaries lists the executables that can run commands. TerragruntBinary runs any number
+// of stacks with --filter; the others run Terraform/OpenTofu directly in one stack.
+var Binaries = []string{TerragruntBinary, TerraformBinary, TofuBinary}
+
+// ValidateBinary reports an error naming the accepted values when name is not in Binaries.
+func ValidateBinary(name string) error {
+	if !slices.Contains(Binaries, name) {
+		return fmt.Errorf("invalid binary %q: must be one of %s", name, strings.Join(Binaries, ", "))
+	}
+	return nil
+}
+
+// ResolveBinary returns the executable that runs commands for the stack in stackPath. The
+// binary of the first binary_markers pattern (in sorted order) matching a file in the
+// directory (compared in lower case) wins; otherwise it is the configured binary,
+// TerragruntBinary by default.
+func ResolveBinary(stackPath string) string {
+	markers := viper.GetStringMapString("binary_markers")
+	if len(markers) > 0 {
+		if entries, err := os.ReadDir(stackPath); err == nil {
+			patterns := slices.Sorted(maps.Keys(markers))
+			for _, pattern := range patterns {
+				for _, entry := range entries {
+					// Configuration keys are lowercased, so names are matched in lower case.
+					if matched, _ := filepath.Match(pattern, strings.ToLower(entry.Name())); matched && !entry.IsDir() {
+						return markers[pattern]
+					}
+				}
+			}
+		}
+	}
+
+	if binary := viper.GetString("binary"); binary != "" {
+		return binary
+	}
+	return TerragruntBinary
+}
+
+var (
+	// lookPath resolves a binary name through PATH (can be overridden in tests).
+	lookPath = exec.LookPath
+
+	// statFile returns the file info of a resolved binary (can be overridden in tests).
+	statFile = os.Stat
+)
+
+// setBinaryLookup allows tests to inject custom PATH lookup and stat functions.
+// Returns a cleanup function to restore the originals.
+func setBinaryLookup(look func(string) (string, error), stat func(string) (fs.FileInfo, error)) func() {
+	originalLook, originalStat := lookPath, statFile
+	lookPath, statFile = look, stat
+	return func() {
+		lookPath, statFile = originalLook, originalStat
+	}
+}
+
+// CheckBinary resolves name through PATH and verifies the result is an executable file,
+// returning its path. The error explains how to fix the problem, instead of the
+// lower-level error exec would report when starting the command.
+func CheckBinary(name string) (string, error) {
+	path, err := lookPath(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrPermission) {
+			return "", fmt.Errorf("%s is not executable: make it executable (chmod +x) or put an executable %s earlier in PATH", name, name)
+		}
+		return "", fmt.Errorf("%s not found in PATH: install it or add its directory to PATH", name)
+	}
+
+	info, err := statFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s at %s: %w", name, path, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s at %s is a directory, not an executable", name, path)
+	}
+	// Windows has no executable bit; LookPath already matched an executable extension.
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o111 == 0 {
+		return "", fmt.Errorf("%s at %s is not executable: run chmod +x %s", name, path, path)
+	}
+
+	return path, nil
+}
+
+// commandBinary returns the binary to invoke for name. Dry runs never start it, so they
+// do not require it in PATH: they use the resolved path when there is one and name
+// otherwise.
+func commandBinary(name string, dryRun bool) (string, error) {
+	binary, err := CheckBinary(name)
+	if err != nil && dryRun {
+		return name, nil
+	}
+	return binary, err
+}
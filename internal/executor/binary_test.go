@@ -0,0 +1,173 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTerragrunt makes CheckBinary resolve terragrunt to an executable script that exits
+// with an error, so tests do not depend on terragrunt being installed.
+func stubTerragrunt(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), TerragruntBinary)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+	t.Cleanup(setBinaryLookup(func(string) (string, error) { return path, nil }, os.Stat))
+}
+
+// TestCheckBinary tests the guidance errors for binaries that cannot be executed.
+func TestCheckBinary(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file mode executability does not apply on Windows")
+	}
+
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "executable")
+	require.NoError(t, os.WriteFile(executable, []byte("#!/bin/sh\n"), 0o755))
+	plainFile := filepath.Join(dir, "plain")
+	require.NoError(t, os.WriteFile(plainFile, []byte("#!/bin/sh\n"), 0o644))
+
+	tests := []struct {
+		name        string
+		resolved    string
+		lookErr     error
+		expectPath  string
+		expectError string
+	}{
+		{
+			name:       "executable binary passes",
+			resolved:   executable,
+			expectPath: executable,
+		},
+		{
+			name:        "file without executable bit",
+			resolved:    plainFile,
+			expectError: fmt.Sprintf("terragrunt at %s is not executable: run chmod +x %s", plainFile, plainFile),
+		},
+		{
+			name:        "directory",
+			resolved:    dir,
+			expectError: fmt.Sprintf("terragrunt at %s is a directory, not an executable", dir),
+		},
+		{
+			name:        "not found in PATH",
+			lookErr:     &exec.Error{Name: "terragrunt", Err: exec.ErrNotFound},
+			expectError: "terragrunt not found in PATH: install it or add its directory to PATH",
+		},
+		{
+			name:        "permission denied by lookup",
+			lookErr:     &exec.Error{Name: "terragrunt", Err: fs.ErrPermission},
+			expectError: "terragrunt is not executable: make it executable (chmod +x) or put an executable terragrunt earlier in PATH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer setBinaryLookup(func(string) (string, error) { return tt.resolved, tt.lookErr }, os.Stat)()
+
+			path, err := CheckBinary(TerragruntBinary)
+
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectPath, path)
+		})
+	}
+}
+
+// TestRun_NonExecutableBinary tests that Run stops before executing or logging history
+// when terragrunt cannot be executed.
+func TestRun_NonExecutableBinary(t *testing.T) {
+	defer setBinaryLookup(func(string) (string, error) { return "", exec.ErrNotFound }, os.Stat)()
+	runnerCalled := false
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		runnerCalled = true
+		return nil
+	})()
+
+	logger := &mockHistoryLogger{nextID: 1}
+	err := Run(context.Background(), logger, "plan", "/test/stack", t.TempDir(), []string{"."}, nil)
+
+	assert.EqualError(t, err, "terragrunt not found in PATH: install it or add its directory to PATH")
+	assert.False(t, runnerCalled)
+	assert.False(t, logger.appendCalled)
+}
+
+// TestResolveBinary tests that binary_markers pick the binary of a stack before the
+// configured one, which defaults to terragrunt.
+func TestResolveBinary(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+
+	tgStack := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tgStack, "terragrunt.hcl"), []byte(""), 0o644))
+	tofuStack := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tofuStack, "main.tofu"), []byte(""), 0o644))
+	tfStack := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tfStack, "Main.TF"), []byte(""), 0o644))
+
+	assert.Equal(t, TerragruntBinary, ResolveBinary(tofuStack), "terragrunt by default")
+
+	viper.Set("binary", TofuBinary)
+	assert.Equal(t, TofuBinary, ResolveBinary(tgStack))
+
+	viper.Set("binary", TerragruntBinary)
+	viper.Set("binary_markers", map[string]string{"*.tofu": TofuBinary, "*.tf": TerraformBinary})
+	assert.Equal(t, TofuBinary, ResolveBinary(tofuStack))
+	assert.Equal(t, TerraformBinary, ResolveBinary(tfStack), "names are matched in lower case")
+	assert.Equal(t, TerragruntBinary, ResolveBinary(tgStack), "no marker keeps the configured binary")
+}
+
+// TestValidateBinary tests that only the known executables are accepted.
+func TestValidateBinary(t *testing.T) {
+	for _, name := range Binaries {
+		assert.NoError(t, ValidateBinary(name))
+	}
+	assert.EqualError(t, ValidateBinary("terrafrom"), `invalid binary "terrafrom": must be one of terragrunt, terraform, tofu`)
+}
+
+// TestRun_DirectBinary tests that terraform and tofu run the command in the stack with
+// -chdir instead of Terragrunt's --filter, and refuse several stacks at once.
+func TestRun_DirectBinary(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("binary", TofuBinary)
+	viper.Set("terraform.run_flags", []string{"-target=module.vpc"})
+
+	binaryPath := filepath.Join(t.TempDir(), TofuBinary)
+	require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\n"), 0o755))
+	var looked string
+	t.Cleanup(setBinaryLookup(func(name string) (string, error) {
+		looked = name
+		return binaryPath, nil
+	}, os.Stat))
+	var ran []string
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		ran = cmd.Args
+		return nil
+	})()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = oldStdout, oldStderr }()
+
+	logger := &mockHistoryLogger{nextID: 1}
+	require.NoError(t, Run(context.Background(), logger, "plan", "/repo/dev/vpc", "/repo", []string{"dev/vpc"}, nil))
+	assert.Equal(t, TofuBinary, looked)
+	assert.Equal(t, []string{binaryPath, "-chdir=/repo/dev/vpc", "plan", "-target=module.vpc"}, ran)
+
+	err := Run(context.Background(), logger, "plan", "/repo/dev/vpc", "/repo", []string{"dev/vpc", "dev/rds"}, nil)
+	assert.ErrorContains(t, err, "tofu runs one stack at a time, got 2")
+}
@@ -6,16 +6,22 @@
 package executor
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 
+	"github.com/israoo/terrax/internal/clock"
 	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/history"
 )
@@ -25,26 +31,83 @@ type HistoryLogger interface {
 	GetNextID(ctx context.Context) (int, error)
 	Append(ctx context.Context, entry history.ExecutionLogEntry) error
 	TrimHistory(ctx context.Context, maxEntries int) error
+	TrimHistoryByAge(ctx context.Context, maxAge time.Duration) error
+}
+
+// CommandRunner runs a prepared Terragrunt command and returns its error.
+// This allows dependency injection for testing without a real terragrunt binary.
+type CommandRunner func(cmd *exec.Cmd) error
+
+// currentCommandRunner holds the active command runner (can be overridden in tests).
+var currentCommandRunner CommandRunner = defaultCommandRunner
+
+// defaultCommandRunner runs the command and waits for it to finish.
+func defaultCommandRunner(cmd *exec.Cmd) error {
+	return cmd.Run()
+}
+
+// setCommandRunner allows tests to inject a custom command runner.
+// Returns a cleanup function to restore the original runner.
+func setCommandRunner(runner CommandRunner) func() {
+	original := currentCommandRunner
+	currentCommandRunner = runner
+	return func() {
+		currentCommandRunner = original
+	}
+}
+
+// currentClock is the time source for execution timestamps and durations (can be overridden in tests).
+var currentClock clock.Clock = clock.Real{}
+
+// setClock allows tests to inject a fake clock.
+// Returns a cleanup function to restore the original clock.
+func setClock(c clock.Clock) func() {
+	original := currentClock
+	currentClock = c
+	return func() {
+		currentClock = original
+	}
 }
 
 // Run executes a Terragrunt command using explicit --filter flags.
 // filterPaths are paths relative to repoRoot and represent the exact set of stacks to execute.
 // envVars provides additional environment variables to be injected into the subprocess.
 // Terragrunt runs from repoRoot so that --filter paths and any relative output paths resolve correctly.
+// When ResolveBinary picks terraform or tofu instead, it runs with -chdir in absoluteStackPath,
+// which must then be the only stack in filterPaths.
 func Run(ctx context.Context, historyLogger HistoryLogger, command, absoluteStackPath, repoRoot string, filterPaths []string, envVars map[string]string) error {
+	binaryName := ResolveBinary(absoluteStackPath)
+	dryRun := viper.GetBool("dry_run")
+	binary, err := commandBinary(binaryName, dryRun)
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	if binaryName == TerragruntBinary {
+		args = buildFilterArgs(repoRoot, command, filterPaths)
+	} else {
+		if len(filterPaths) > 1 {
+			return fmt.Errorf("%s runs one stack at a time, got %d: select a single stack or use %s", binaryName, len(filterPaths), TerragruntBinary)
+		}
+		args = buildDirectArgs(absoluteStackPath, command)
+	}
+
 	nextID, err := historyLogger.GetNextID(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to get history ID: %v\n", err)
 		nextID = 0
 	}
 
-	startTime := time.Now()
-
-	args := buildFilterArgs(repoRoot, command, filterPaths)
-
-	fmt.Printf("🚀 Executing: terragrunt %v\n\n", args)
+	startTime := currentClock.Now()
+	if !dryRun {
+		fmt.Printf("🚀 Executing: %s %v\n\n", binaryName, args)
+	}
 
-	cmd := exec.CommandContext(ctx, "terragrunt", args...)
+	runCtx, cancel := commandContext(ctx)
+	defer cancel()
+	timeout := viper.GetDuration("command_timeout")
+	cmd := exec.CommandContext(runCtx, binary, args...)
 	cmd.Dir = repoRoot
 	if len(envVars) > 0 {
 		existing := os.Environ()
@@ -60,15 +123,66 @@ func Run(ctx context.Context, historyLogger HistoryLogger, command, absoluteStac
 		}
 		cmd.Env = merged
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if dryRun {
+		// The invocation is printed and recorded in history without running it.
+		fmt.Printf("🔎 Dry run: %s\n", formatInvocation(cmd, envVars))
+		logExecutionToHistory(ctx, historyLogger, nextID, startTime, command, absoluteStackPath, 0, 0, DryRunSummary, nil, "")
+		return nil
+	}
+	// With run_logs.enabled the output is also written to a file referenced from the history entry.
+	var logWriter io.Writer = io.Discard
+	logPath := ""
+	if viper.GetBool("run_logs.enabled") {
+		logFile, err := openRunLog(startTime, command, absoluteStackPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to open run log: %v\n", err)
+		} else {
+			defer func() { _ = logFile.Close() }()
+			logWriter = logFile
+			logPath = logFile.Name()
+		}
+	}
+
+	// The output is streamed to the terminal and teed to parse the change summary and keep
+	// the tail of a failure for the history entry. Stdin stays the terminal, so prompts work.
+	changes := &changeSummaryWriter{}
+	errTail := newTailWriter(failureTailLines) // Summarizes a failure in its history entry
+	cmd.Stdout = io.MultiWriter(os.Stdout, changes, logWriter)
+	cmd.Stderr = io.MultiWriter(os.Stderr, logWriter, errTail)
 	cmd.Stdin = os.Stdin
 
-	execErr := cmd.Run()
+	// In quiet mode the output is buffered instead of streamed, so a spinner shows progress
+	// on a terminal and the output is only printed if the command fails. Stdout and stderr
+	// then share one writer, so the failure tail covers both. A prompt would be buffered
+	// too, so the command gets no input and runs with the non-interactive flags.
+	quiet := viper.GetBool("quiet")
+	var output bytes.Buffer
+	stopSpinner := func() {}
+	if quiet {
+		cmd.Stdout = io.MultiWriter(&output, changes, logWriter, errTail)
+		cmd.Stderr = cmd.Stdout
+		cmd.Stdin = nil
+		if spinnerTerminal() {
+			stopSpinner = startSpinner(spinnerWriter, binaryName+" "+command, spinnerInterval)
+		}
+	}
+
+	cancelDone := configureCancel(runCtx, cmd, timeout > 0)
+	execErr := currentCommandRunner(cmd)
+	cancelDone()
+	stopSpinner()
+	if quiet && execErr != nil {
+		_, _ = os.Stdout.Write(output.Bytes())
+	}
 	exitCode := 0
 	summary := "Command completed successfully."
 
-	if execErr != nil {
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		execErr = fmt.Errorf("%s %s timed out after %s", binaryName, command, timeout)
+		fmt.Fprintf(os.Stderr, "\n⏱️  %v\n", execErr)
+		exitCode = TimeoutExitCode
+		summary = fmt.Sprintf("Command timed out after %s", timeout)
+	} else if execErr != nil {
 		fmt.Fprintf(os.Stderr, "\n❌ Command execution failed: %v\n", execErr)
 		if exitErr, ok := execErr.(*exec.ExitError); ok {
 			exitCode = exitErr.ExitCode()
@@ -76,42 +190,123 @@ func Run(ctx context.Context, historyLogger HistoryLogger, command, absoluteStac
 			exitCode = 1
 		}
 		summary = fmt.Sprintf("Command failed: %v", execErr)
+		if tail := errTail.Tail(); tail != "" {
+			summary += ": " + tail
+		}
 	} else {
 		fmt.Println("\n✅ Command execution completed")
 	}
+	changeSummary := changes.Summary()
+	if changeSummary != "" {
+		summary = changeSummary
+	}
 
-	duration := time.Since(startTime)
-	displayExecutionSummary(command, absoluteStackPath, duration, exitCode, startTime)
-	logExecutionToHistory(ctx, historyLogger, nextID, startTime, command, absoluteStackPath, exitCode, duration, summary)
+	duration := currentClock.Now().Sub(startTime)
+	displayExecutionSummary(command, absoluteStackPath, duration, exitCode, startTime, changeSummary)
+	if logPath != "" {
+		fmt.Printf("📄 Output log: %s\n", logPath)
+	}
+	logExecutionToHistory(ctx, historyLogger, nextID, startTime, command, absoluteStackPath, exitCode, duration, summary, changes.Counts(), logPath)
 
 	return execErr
 }
 
+// TimeoutExitCode is the exit code recorded for a command stopped by command_timeout,
+// as reported by the coreutils timeout command.
+const TimeoutExitCode = 124
+
+// commandContext returns the context a command runs under. Ctrl+C cancels it instead of
+// ending terrax, so the interrupted run is still recorded, and command_timeout, when set,
+// cancels it once expired.
+func commandContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	timeout := viper.GetDuration("command_timeout")
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// DryRunSummary is the summary recorded for a run skipped by dry_run.
+const DryRunSummary = "Dry run: command not executed."
+
+// formatInvocation returns the shell command line equivalent to cmd: a cd into its
+// directory when it has one, the injected environment variables and the binary with its
+// arguments, each quoted when it holds characters the shell would interpret.
+func formatInvocation(cmd *exec.Cmd, envVars map[string]string) string {
+	var parts []string
+	if cmd.Dir != "" {
+		parts = append(parts, "cd", shellQuote(cmd.Dir), "&&")
+	}
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		parts = append(parts, name+"="+shellQuote(envVars[name]))
+	}
+	for _, arg := range cmd.Args {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// shellQuote single-quotes s unless it only holds characters that are safe unquoted.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=./:,@+%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // RunForceUnlock executes a Terraform force-unlock for a specific stack.
-// Unlike Run, it uses --working-dir without --all and passes the lock ID directly.
+// Unlike Run, it uses --working-dir without --all and passes the lock ID directly
+// (-chdir when ResolveBinary picks terraform or tofu).
 // It logs the operation to history the same way Run does.
 func RunForceUnlock(ctx context.Context, historyLogger HistoryLogger, lockID, absoluteStackPath string) error {
+	binaryName := ResolveBinary(absoluteStackPath)
+	dryRun := viper.GetBool("dry_run")
+	binary, err := commandBinary(binaryName, dryRun)
+	if err != nil {
+		return err
+	}
+
 	nextID, err := historyLogger.GetNextID(ctx)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to get history ID: %v\n", err)
 		nextID = 0
 	}
 
-	startTime := time.Now()
+	startTime := currentClock.Now()
 
 	args := []string{
 		"run", "--working-dir", absoluteStackPath, "--non-interactive",
 		"--", "force-unlock", "-force", lockID,
 	}
+	if binaryName != TerragruntBinary {
+		args = []string{"-chdir=" + absoluteStackPath, "force-unlock", "-force", lockID}
+	}
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	if dryRun {
+		fmt.Printf("🔎 Dry run: %s\n", formatInvocation(cmd, nil))
+		logExecutionToHistory(ctx, historyLogger, nextID, startTime, "force-unlock", absoluteStackPath, 0, 0, DryRunSummary, nil, "")
+		return nil
+	}
 
-	fmt.Printf("🔓 Executing: terragrunt %v\n\n", args)
+	fmt.Printf("🔓 Executing: %s %v\n\n", binaryName, args)
 
-	cmd := exec.CommandContext(ctx, "terragrunt", args...)
+	errTail := newTailWriter(failureTailLines)
 	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, errTail)
 	cmd.Stdin = os.Stdin
 
-	execErr := cmd.Run()
+	execErr := currentCommandRunner(cmd)
 	exitCode := 0
 	summary := "Force unlock completed successfully."
 
@@ -123,13 +318,16 @@ func RunForceUnlock(ctx context.Context, historyLogger HistoryLogger, lockID, ab
 			exitCode = 1
 		}
 		summary = fmt.Sprintf("Force unlock failed: %v", execErr)
+		if tail := errTail.Tail(); tail != "" {
+			summary += ": " + tail
+		}
 	} else {
 		fmt.Println("\n✅ Force unlock completed")
 	}
 
-	duration := time.Since(startTime)
-	displayExecutionSummary("force-unlock", absoluteStackPath, duration, exitCode, startTime)
-	logExecutionToHistory(ctx, historyLogger, nextID, startTime, "force-unlock", absoluteStackPath, exitCode, duration, summary)
+	duration := currentClock.Now().Sub(startTime)
+	displayExecutionSummary("force-unlock", absoluteStackPath, duration, exitCode, startTime, "")
+	logExecutionToHistory(ctx, historyLogger, nextID, startTime, "force-unlock", absoluteStackPath, exitCode, duration, summary, nil, "")
 
 	return execErr
 }
@@ -172,10 +370,32 @@ func buildFilterArgs(repoRoot, command string, filterPaths []string) []string {
 
 	args = appendTerraformExtraFlags(args)
 	args = appendCommandTerraformFlags(args, command)
+	args = append(args, viper.GetStringSlice("terraform.run_flags")...)
+	args = appendQuietInputFlag(args)
 
 	return args
 }
 
+// buildDirectArgs constructs the arguments of a Terraform or OpenTofu binary running command
+// in the single stack at stackPath: -chdir, the command, then the configured Terraform flags
+// and the flags of this run (terraform.run_flags).
+func buildDirectArgs(stackPath, command string) []string {
+	args := []string{"-chdir=" + stackPath, command}
+	args = appendTerraformExtraFlags(args)
+	args = appendCommandTerraformFlags(args, command)
+	args = append(args, viper.GetStringSlice("terraform.run_flags")...)
+	return appendQuietInputFlag(args)
+}
+
+// appendQuietInputFlag appends -input=false in quiet mode, where the output is buffered:
+// Terraform then fails instead of waiting for an answer to a prompt nobody sees.
+func appendQuietInputFlag(args []string) []string {
+	if viper.GetBool("quiet") {
+		return append(args, "-input=false")
+	}
+	return args
+}
+
 // appendFeatureFlags appends flags derived from the features.* configuration section.
 // Each feature key maps to one or more Terragrunt flags, hiding multi-flag complexity
 // behind a single boolean toggle.
@@ -200,6 +420,19 @@ func appendFeatureFlags(args []string) []string {
 	return args
 }
 
+// ExtraArgs returns the extra flags Run adds for command: the configured Terragrunt flags,
+// followed by the configured Terraform flags and runArgs after a "--" separator when there
+// are any. runArgs are the flags Run takes from terraform.run_flags for a single run.
+func ExtraArgs(command string, runArgs []string) []string {
+	args := appendCommandTerragruntFlags(appendExtraTerragruntFlags(nil), command)
+	terraformArgs := appendCommandTerraformFlags(appendTerraformExtraFlags(nil), command)
+	terraformArgs = append(terraformArgs, runArgs...)
+	if len(terraformArgs) > 0 {
+		args = append(append(args, "--"), terraformArgs...)
+	}
+	return args
+}
+
 // appendExtraTerragruntFlags appends global extra Terragrunt flags from terragrunt.extra_flags.
 func appendExtraTerragruntFlags(args []string) []string {
 	return append(args, viper.GetStringSlice("terragrunt.extra_flags")...)
@@ -254,7 +487,8 @@ func appendTerragruntFlags(args []string) []string {
 	if viper.GetBool("terragrunt.no_color") {
 		args = append(args, "--terragrunt-no-color")
 	}
-	if viper.GetBool("terragrunt.non_interactive") {
+	if viper.GetBool("terragrunt.non_interactive") || viper.GetBool("quiet") {
+		// Quiet mode buffers the output, so prompts could not be seen or answered.
 		args = append(args, "--terragrunt-non-interactive")
 	}
 	if viper.GetBool("terragrunt.ignore_dependency_errors") {
@@ -270,8 +504,17 @@ func appendTerragruntFlags(args []string) []string {
 	return args
 }
 
-// displayExecutionSummary prints the summary of the execution.
-func displayExecutionSummary(command, path string, duration time.Duration, exitCode int, timestamp time.Time) {
+// displayExecutionSummary prints the summary of the execution, mirroring a history row.
+// changeSummary is the parsed Terraform change report; it is omitted when empty.
+func displayExecutionSummary(command, path string, duration time.Duration, exitCode int, timestamp time.Time, changeSummary string) {
+	status := "✓"
+	switch {
+	case exitCode != 0:
+		status = "✗"
+	case history.IsNoChangesSummary(changeSummary):
+		status = "=" // Neutral: nothing to change, distinct from a success that changed resources.
+	}
+
 	fmt.Println()
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Println("  📊 Execution Summary")
@@ -279,14 +522,18 @@ func displayExecutionSummary(command, path string, duration time.Duration, exitC
 	fmt.Printf("Command:    %s\n", command)
 	fmt.Printf("Stack Path: %s\n", path)
 	fmt.Printf("Duration:   %.2fs\n", duration.Seconds())
-	fmt.Printf("Exit Code:  %d\n", exitCode)
+	fmt.Printf("Exit Code:  %s %d\n", status, exitCode)
+	if changeSummary != "" {
+		fmt.Printf("Changes:    %s\n", changeSummary)
+	}
 	fmt.Printf("Timestamp:  %s\n", timestamp.Format("2006-01-02 15:04:05"))
 	fmt.Println("═══════════════════════════════════════")
 	fmt.Println()
 }
 
 // logExecutionToHistory handles the details of recording the execution to the history file.
-func logExecutionToHistory(ctx context.Context, logger HistoryLogger, id int, timestamp time.Time, command, absoluteStackPath string, exitCode int, duration time.Duration, summary string) {
+// changes holds the resource counts parsed from the output, or nil when none were reported.
+func logExecutionToHistory(ctx context.Context, logger HistoryLogger, id int, timestamp time.Time, command, absoluteStackPath string, exitCode int, duration time.Duration, summary string, changes *history.ChangeCounts, logPath string) {
 	rootConfigFile := viper.GetString("root_config_file")
 	if rootConfigFile == "" {
 		rootConfigFile = config.DefaultRootConfigFile
@@ -308,6 +555,11 @@ func logExecutionToHistory(ctx context.Context, logger HistoryLogger, id int, ti
 		ExitCode:     exitCode,
 		DurationS:    duration.Seconds(),
 		Summary:      summary,
+		Changes:      changes,
+		LogPath:      logPath,
+		Note:         viper.GetString("note"),
+		Args:         viper.GetStringSlice("terraform.run_flags"),
+		DryRun:       viper.GetBool("dry_run"),
 	}
 
 	if err := logger.Append(ctx, entry); err != nil {
@@ -322,4 +574,10 @@ func logExecutionToHistory(ctx context.Context, logger HistoryLogger, id int, ti
 	if err := logger.TrimHistory(ctx, maxEntries); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to trim history: %v\n", err)
 	}
+
+	if maxAge := viper.GetDuration("history.max_age"); maxAge > 0 {
+		if err := logger.TrimHistoryByAge(ctx, maxAge); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to trim history by age: %v\n", err)
+		}
+	}
 }
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/israoo/terrax/internal/clock"
 	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/history"
 )
@@ -236,8 +240,10 @@ type mockHistoryLogger struct {
 	nextID       int
 	appendCalled bool
 	trimCalled   bool
+	trimMaxAge   time.Duration // maxAge passed to TrimHistoryByAge (0 = not called)
 	appendErr    error
 	trimErr      error
+	lastEntry    history.ExecutionLogEntry
 }
 
 func (m *mockHistoryLogger) GetNextID(ctx context.Context) (int, error) {
@@ -246,6 +252,7 @@ func (m *mockHistoryLogger) GetNextID(ctx context.Context) (int, error) {
 
 func (m *mockHistoryLogger) Append(ctx context.Context, entry history.ExecutionLogEntry) error {
 	m.appendCalled = true
+	m.lastEntry = entry
 	return m.appendErr
 }
 
@@ -254,6 +261,11 @@ func (m *mockHistoryLogger) TrimHistory(ctx context.Context, maxEntries int) err
 	return m.trimErr
 }
 
+func (m *mockHistoryLogger) TrimHistoryByAge(ctx context.Context, maxAge time.Duration) error {
+	m.trimMaxAge = maxAge
+	return m.trimErr
+}
+
 // TestDisplayExecutionSummary tests the displayExecutionSummary function.
 func TestDisplayExecutionSummary(t *testing.T) {
 	oldStdout := os.Stdout
@@ -261,7 +273,7 @@ func TestDisplayExecutionSummary(t *testing.T) {
 	os.Stdout = w
 
 	timestamp := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-	displayExecutionSummary("plan", "/test/stack", 5*time.Second, 0, timestamp)
+	displayExecutionSummary("plan", "/test/stack", 5*time.Second, 0, timestamp, "")
 
 	require.NoError(t, w.Close())
 	os.Stdout = oldStdout
@@ -281,6 +293,28 @@ func TestDisplayExecutionSummary(t *testing.T) {
 	assert.Contains(t, output, "Timestamp")
 }
 
+// TestDisplayExecutionSummary_NoChanges tests that a run reporting no changes gets a
+// neutral status instead of the success check.
+func TestDisplayExecutionSummary_NoChanges(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	displayExecutionSummary("plan", "/test/stack", time.Second, 0, time.Now(), history.NoChangesSummary)
+
+	require.NoError(t, w.Close())
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	_, err := io.Copy(&buf, r)
+	require.NoError(t, err)
+	output := buf.String()
+
+	assert.Contains(t, output, "Exit Code:  = 0")
+	assert.NotContains(t, output, "✓")
+	assert.Contains(t, output, "Changes:    No changes.")
+}
+
 // TestLogExecutionToHistory tests the logExecutionToHistory function.
 func TestLogExecutionToHistory(t *testing.T) {
 	ctx := context.Background()
@@ -336,6 +370,8 @@ func TestLogExecutionToHistory(t *testing.T) {
 				0,
 				5*time.Second,
 				"Test execution",
+				nil,
+				"",
 			)
 
 			require.NoError(t, w.Close())
@@ -347,6 +383,37 @@ func TestLogExecutionToHistory(t *testing.T) {
 	}
 }
 
+// TestLogExecutionToHistory_Note tests that the note given for the execution is stored in the entry.
+func TestLogExecutionToHistory_Note(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("note", "prod release")
+	logger := &mockHistoryLogger{nextID: 1}
+
+	logExecutionToHistory(context.Background(), logger, 1, time.Now(), "apply", "/test/stack/path", 0, time.Second, "done", nil, "")
+
+	require.True(t, logger.appendCalled)
+	assert.Equal(t, "prod release", logger.lastEntry.Note)
+}
+
+// TestLogExecutionToHistory_MaxAge tests that entries are trimmed by age only when
+// history.max_age is set.
+func TestLogExecutionToHistory_MaxAge(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+
+	logger := &mockHistoryLogger{nextID: 1}
+	logExecutionToHistory(context.Background(), logger, 1, time.Now(), "plan", "/test/stack/path", 0, time.Second, "done", nil, "")
+	assert.True(t, logger.trimCalled)
+	assert.Zero(t, logger.trimMaxAge, "no age limit by default")
+
+	viper.Set("history.max_age", "2160h")
+	logger = &mockHistoryLogger{nextID: 1}
+	logExecutionToHistory(context.Background(), logger, 1, time.Now(), "plan", "/test/stack/path", 0, time.Second, "done", nil, "")
+	assert.True(t, logger.trimCalled, "the count limit still applies")
+	assert.Equal(t, 90*24*time.Hour, logger.trimMaxAge)
+}
+
 // TestBuildTerragruntArgs_FeatureFlags tests feature flag shortcuts via buildTerragruntArgs.
 func TestBuildTerragruntArgs_FeatureFlags(t *testing.T) {
 	tests := []struct {
@@ -709,13 +776,415 @@ func TestRunForceUnlock_Args(t *testing.T) {
 		_ = rErr.Close()
 	}()
 
+	stubTerragrunt(t)
 	logger := &mockHistoryLogger{}
 	ctx := context.Background()
 
-	// RunForceUnlock will fail because terragrunt is not installed in CI,
+	// RunForceUnlock will fail because the stub terragrunt exits with an error,
 	// but we only care that the function returns without panicking and logs
 	// to history with the correct command name.
 	_ = RunForceUnlock(ctx, logger, "lock-id-abc-123", "/path/to/stack")
 
 	assert.True(t, logger.appendCalled, "History should be logged after force-unlock.")
 }
+
+// TestRun_PostRunSummary tests the summary printed after a run completes.
+func TestRun_PostRunSummary(t *testing.T) {
+	tests := []struct {
+		name           string
+		output         string
+		runErr         error
+		expectStatus   string
+		expectSummary  string
+		noRunLog       bool
+		expectRecorded string
+		expectChanges  *history.ChangeCounts
+	}{
+		{
+			name:           "successful plan with changes",
+			output:         "stack a\nPlan: 1 to add, 2 to change, 0 to destroy.\nstack b\nPlan: 1 to add, 0 to change, 3 to destroy.\n",
+			expectStatus:   "Exit Code:  ✓ 0",
+			expectSummary:  "Changes:    Plan: 2 to add, 2 to change, 3 to destroy.",
+			expectRecorded: "Plan: 2 to add, 2 to change, 3 to destroy.",
+			expectChanges:  &history.ChangeCounts{Add: 2, Change: 2, Destroy: 3},
+		},
+		{
+			name:           "failed run without change report",
+			output:         "Error: something went wrong\n",
+			runErr:         fmt.Errorf("boom"),
+			expectStatus:   "Exit Code:  ✗ 1",
+			expectRecorded: "Command failed: boom",
+		},
+		{
+			name:           "parsed without a run log",
+			output:         "Plan: 1 to add, 0 to change, 0 to destroy.\n",
+			noRunLog:       true,
+			expectStatus:   "Exit Code:  ✓ 0",
+			expectSummary:  "Changes:    Plan: 1 to add, 0 to change, 0 to destroy.",
+			expectRecorded: "Plan: 1 to add, 0 to change, 0 to destroy.",
+			expectChanges:  &history.ChangeCounts{Add: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper()
+			stubTerragrunt(t)
+			if !tt.noRunLog {
+				viper.Set("run_logs.enabled", true)
+				viper.Set("run_logs.dir", t.TempDir())
+			}
+
+			oldStdout, oldStderr := os.Stdout, os.Stderr
+			r, w, _ := os.Pipe()
+			_, wErr, _ := os.Pipe()
+			os.Stdout, os.Stderr = w, wErr
+
+			restoreRunner := setCommandRunner(func(cmd *exec.Cmd) error {
+				_, err := fmt.Fprint(cmd.Stdout, tt.output)
+				require.NoError(t, err)
+				return tt.runErr
+			})
+			defer restoreRunner()
+
+			logger := &mockHistoryLogger{nextID: 1}
+			err := Run(context.Background(), logger, "plan", "/test/stack", t.TempDir(), []string{"."}, nil)
+
+			require.NoError(t, w.Close())
+			require.NoError(t, wErr.Close())
+			os.Stdout, os.Stderr = oldStdout, oldStderr
+
+			var buf bytes.Buffer
+			_, copyErr := io.Copy(&buf, r)
+			require.NoError(t, copyErr)
+			output := buf.String()
+
+			assert.Equal(t, tt.runErr, err)
+			assert.Contains(t, output, "Execution Summary")
+			assert.Contains(t, output, tt.expectStatus)
+			assert.Contains(t, output, "Duration:")
+			if tt.expectSummary != "" {
+				assert.Contains(t, output, tt.expectSummary)
+			} else {
+				assert.NotContains(t, output, "Changes:")
+			}
+			assert.Equal(t, tt.expectRecorded, logger.lastEntry.Summary)
+			assert.Equal(t, tt.expectChanges, logger.lastEntry.Changes)
+		})
+	}
+}
+
+// TestRun_QuietSpinner tests that quiet mode shows a spinner instead of streaming the output.
+func TestRun_QuietSpinner(t *testing.T) {
+	tests := []struct {
+		name          string
+		quiet         bool
+		terminal      bool
+		runErr        error
+		expectSpinner bool
+		expectOutput  bool
+	}{
+		{name: "streaming output has no spinner", quiet: false, terminal: true, expectSpinner: false, expectOutput: true},
+		{name: "quiet success hides output", quiet: true, terminal: true, expectSpinner: true, expectOutput: false},
+		{name: "quiet failure prints buffered output", quiet: true, terminal: true, runErr: fmt.Errorf("boom"), expectSpinner: true, expectOutput: true},
+		{name: "quiet without a terminal has no spinner", quiet: true, terminal: false, expectSpinner: false, expectOutput: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper()
+			viper.Set("quiet", tt.quiet)
+
+			var spinnerOut bytes.Buffer
+			oldWriter, oldInterval, oldTerminal := spinnerWriter, spinnerInterval, spinnerTerminal
+			spinnerWriter, spinnerInterval = &spinnerOut, time.Millisecond
+			spinnerTerminal = func() bool { return tt.terminal }
+			defer func() { spinnerWriter, spinnerInterval, spinnerTerminal = oldWriter, oldInterval, oldTerminal }()
+
+			stubTerragrunt(t)
+			restoreRunner := setCommandRunner(func(cmd *exec.Cmd) error {
+				if tt.quiet {
+					assert.Nil(t, cmd.Stdin, "quiet commands get no input")
+					assert.Contains(t, cmd.Args, "--terragrunt-non-interactive")
+					assert.Contains(t, cmd.Args, "-input=false")
+				}
+				_, _ = fmt.Fprintln(cmd.Stdout, "terraform says hello")
+				time.Sleep(30 * time.Millisecond)
+				return tt.runErr
+			})
+			defer restoreRunner()
+
+			oldStdout, oldStderr := os.Stdout, os.Stderr
+			r, w, _ := os.Pipe()
+			_, wErr, _ := os.Pipe()
+			os.Stdout, os.Stderr = w, wErr
+
+			err := Run(context.Background(), &mockHistoryLogger{nextID: 1}, "plan", "/test/stack", t.TempDir(), []string{"."}, nil)
+
+			require.NoError(t, w.Close())
+			require.NoError(t, wErr.Close())
+			os.Stdout, os.Stderr = oldStdout, oldStderr
+
+			var buf bytes.Buffer
+			_, copyErr := io.Copy(&buf, r)
+			require.NoError(t, copyErr)
+
+			assert.Equal(t, tt.runErr, err)
+			if tt.expectSpinner {
+				assert.GreaterOrEqual(t, strings.Count(spinnerOut.String(), "terragrunt plan ("), 2, "spinner should tick while the command runs")
+				assert.True(t, strings.HasSuffix(spinnerOut.String(), "\r\033[K"), "spinner should stop when the command completes")
+			} else {
+				assert.Empty(t, spinnerOut.String())
+			}
+			if tt.expectOutput {
+				assert.Contains(t, buf.String(), "terraform says hello")
+			} else {
+				assert.NotContains(t, buf.String(), "terraform says hello")
+			}
+		})
+	}
+}
+
+// TestRun_FakeClock tests that execution timestamps and durations come from the clock.
+func TestRun_FakeClock(t *testing.T) {
+	resetViper()
+	stubTerragrunt(t)
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	defer setClock(fake)()
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		fake.Advance(90 * time.Second)
+		return nil
+	})()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	_, wErr, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, wErr
+
+	logger := &mockHistoryLogger{nextID: 1}
+	err := Run(context.Background(), logger, "plan", "/test/stack", t.TempDir(), []string{"."}, nil)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, wErr.Close())
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+
+	var buf bytes.Buffer
+	_, copyErr := io.Copy(&buf, r)
+	require.NoError(t, copyErr)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "Duration:   90.00s")
+	assert.Equal(t, start, logger.lastEntry.Timestamp)
+	assert.Equal(t, 90.0, logger.lastEntry.DurationS)
+}
+
+// TestRun_RecordsExecution runs real commands in place of Terragrunt and checks the entry
+// appended through the history service: exit code, duration, user, stack paths and, for
+// a failure, the tail of its stderr.
+func TestRun_RecordsExecution(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake binaries are shell scripts")
+	}
+
+	tests := []struct {
+		name        string
+		script      string
+		runLogs     bool
+		wantExit    int
+		wantSummary string
+	}{
+		{
+			name:        "success",
+			script:      "sleep 0.05\nexit 0\n",
+			wantExit:    0,
+			wantSummary: "Command completed successfully.",
+		},
+		{
+			name:        "failure",
+			script:      "sleep 0.05\necho 'Initializing...'\necho 'Error: backend not configured' >&2\necho 'on main.tf line 3' >&2\nexit 3\n",
+			runLogs:     true,
+			wantExit:    3,
+			wantSummary: "Command failed: exit status 3: Error: backend not configured | on main.tf line 3",
+		},
+		{
+			name:        "failure without run log",
+			script:      "sleep 0.05\necho 'Error: backend not configured' >&2\nexit 3\n",
+			wantExit:    3,
+			wantSummary: "Command failed: exit status 3: Error: backend not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper()
+			t.Cleanup(resetViper)
+
+			dir := t.TempDir()
+			binaryPath := filepath.Join(dir, TerragruntBinary)
+			require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\n"+tt.script), 0o755))
+			t.Cleanup(setBinaryLookup(func(string) (string, error) { return binaryPath, nil }, os.Stat))
+
+			repoRoot := filepath.Join(dir, "repo")
+			stackPath := filepath.Join(repoRoot, "dev", "vpc")
+			require.NoError(t, os.MkdirAll(stackPath, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(repoRoot, config.DefaultRootConfigFile), nil, 0o644))
+
+			repo, err := history.NewFileRepository(filepath.Join(dir, history.HistoryFileName))
+			require.NoError(t, err)
+			svc := history.NewService(repo, config.DefaultRootConfigFile)
+			if tt.runLogs {
+				viper.Set("run_logs.enabled", true)
+				viper.Set("run_logs.dir", filepath.Join(dir, "logs"))
+			}
+
+			oldStdout, oldStderr := os.Stdout, os.Stderr
+			_, w, _ := os.Pipe()
+			os.Stdout, os.Stderr = w, w
+			runErr := Run(context.Background(), svc, "plan", stackPath, repoRoot, []string{"dev/vpc"}, nil)
+			os.Stdout, os.Stderr = oldStdout, oldStderr
+			require.NoError(t, w.Close())
+
+			if tt.wantExit == 0 {
+				require.NoError(t, runErr)
+			} else {
+				require.Error(t, runErr)
+			}
+
+			entries, err := svc.LoadAll(context.Background())
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			entry := entries[0]
+			assert.Equal(t, 1, entry.ID)
+			assert.Equal(t, "plan", entry.Command)
+			assert.Equal(t, tt.wantExit, entry.ExitCode)
+			assert.GreaterOrEqual(t, entry.DurationS, 0.05, "the subprocess is timed")
+			assert.Equal(t, history.GetCurrentUser(), entry.User)
+			assert.Equal(t, stackPath, entry.AbsolutePath)
+			assert.Equal(t, "dev/vpc", entry.StackPath)
+			assert.Equal(t, tt.wantSummary, entry.Summary)
+		})
+	}
+}
+
+// TestRun_DryRun tests that dry_run prints the command instead of running it and records
+// the run in history as a dry run.
+func TestRun_DryRun(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("dry_run", true)
+	viper.Set("terraform.run_flags", []string{"-target=module.vpc"})
+
+	binaryPath := filepath.Join(t.TempDir(), TerragruntBinary)
+	require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\nexit 1\n"), 0o755))
+	t.Cleanup(setBinaryLookup(func(string) (string, error) { return binaryPath, nil }, os.Stat))
+	ran := false
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		ran = true
+		return nil
+	})()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	_, wErr, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, wErr
+
+	repoRoot := filepath.Join(t.TempDir(), "my repo")
+	logger := &mockHistoryLogger{nextID: 1}
+	err := Run(context.Background(), logger, "plan", filepath.Join(repoRoot, "dev"), repoRoot, []string{"dev"}, map[string]string{"TF_VAR_env": "dev"})
+
+	require.NoError(t, w.Close())
+	require.NoError(t, wErr.Close())
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+
+	var buf bytes.Buffer
+	_, copyErr := io.Copy(&buf, r)
+	require.NoError(t, copyErr)
+
+	require.NoError(t, err)
+	assert.False(t, ran, "no subprocess should run in dry-run mode")
+	args := buildFilterArgs(repoRoot, "plan", []string{"dev"})
+	want := "cd '" + repoRoot + "' && TF_VAR_env=dev " + binaryPath + " " + strings.Join(args, " ")
+	assert.Equal(t, "🔎 Dry run: "+want+"\n", buf.String())
+	assert.Contains(t, want, "-target=module.vpc")
+	assert.True(t, logger.lastEntry.DryRun)
+	assert.Equal(t, DryRunSummary, logger.lastEntry.Summary)
+	assert.Equal(t, 0, logger.lastEntry.ExitCode)
+}
+
+// TestDryRun_BinaryNotInPath tests that dry runs print the configured binary name when it
+// is not in PATH, instead of failing like real runs.
+func TestDryRun_BinaryNotInPath(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("dry_run", true)
+	t.Cleanup(setBinaryLookup(func(string) (string, error) { return "", exec.ErrNotFound }, os.Stat))
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		t.Fatal("no subprocess should run in dry-run mode")
+		return nil
+	})()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	stackPath := filepath.Join(t.TempDir(), "dev")
+	logger := &mockHistoryLogger{nextID: 1}
+	runErr := Run(context.Background(), logger, "plan", stackPath, filepath.Dir(stackPath), []string{"dev"}, nil)
+	unlockErr := RunForceUnlock(context.Background(), logger, "lock-id", stackPath)
+
+	require.NoError(t, w.Close())
+	os.Stdout = oldStdout
+	var buf bytes.Buffer
+	_, copyErr := io.Copy(&buf, r)
+	require.NoError(t, copyErr)
+
+	require.NoError(t, runErr)
+	require.NoError(t, unlockErr)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "&& "+TerragruntBinary+" run ")
+	assert.True(t, strings.HasPrefix(lines[1], "🔎 Dry run: "+TerragruntBinary+" run "), lines[1])
+
+	resetViper()
+	assert.Error(t, Run(context.Background(), logger, "plan", stackPath, filepath.Dir(stackPath), []string{"dev"}, nil),
+		"real runs still require the binary")
+}
+
+// TestShellQuote tests quoting of command line words for the dry-run output.
+func TestShellQuote(t *testing.T) {
+	assert.Equal(t, "--filter=./dev/vpc", shellQuote("--filter=./dev/vpc"))
+	assert.Equal(t, "''", shellQuote(""))
+	assert.Equal(t, "'my repo'", shellQuote("my repo"))
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
+
+// TestExtraArgs tests the extra flags reported for a command.
+func TestExtraArgs(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+
+	assert.Empty(t, ExtraArgs("plan", nil))
+
+	viper.Set("terragrunt.extra_flags", []string{"--non-interactive"})
+	viper.Set("terragrunt.command_flags.plan", []string{"--queue-include-external"})
+	assert.Equal(t, []string{"--non-interactive", "--queue-include-external"}, ExtraArgs("plan", nil))
+
+	viper.Set("terraform.command_flags.plan", []string{"-refresh=false"})
+	assert.Equal(t, []string{"--non-interactive", "--queue-include-external", "--", "-refresh=false"}, ExtraArgs("plan", nil))
+	assert.Equal(t, []string{"--non-interactive"}, ExtraArgs("apply", nil))
+	assert.Equal(t, []string{"--non-interactive", "--", "-target=module.vpc"}, ExtraArgs("apply", []string{"-target=module.vpc"}))
+}
+
+// TestBuildFilterArgs_RunFlags tests that flags for a single run follow the configured Terraform flags.
+func TestBuildFilterArgs_RunFlags(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("log_format", "pretty")
+	viper.Set("terraform.extra_flags", []string{"-lock=false"})
+	viper.Set("terraform.run_flags", []string{"-refresh=false"})
+
+	args := buildFilterArgs("/repo", "plan", []string{"dev/vpc"})
+
+	assert.Equal(t, []string{"--", "plan", "-lock=false", "-refresh=false"}, args[len(args)-4:])
+}
@@ -0,0 +1,88 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// timeoutGracePeriod is how long a command stopped by command_timeout may take to exit
+// after SIGINT before it is killed (can be overridden in tests).
+var timeoutGracePeriod = 10 * time.Second
+
+// configureCancel sets how cancelling ctx stops cmd and returns a function to call once cmd
+// has exited. Untimed commands share the terminal's process group, so Ctrl+C already
+// reaches them; nothing more is sent. A timed command runs in its own process group so the
+// timeout reaches terraform and anything else terragrunt started: the group is sent SIGINT,
+// which lets terraform release its state lock, and killed if it is still running after
+// timeoutGracePeriod. When terrax holds the terminal, that group is made its foreground
+// group, so the command can still prompt and gets Ctrl+C, and the terminal is taken back
+// once it exits. Set it up after cmd.Stdin.
+func configureCancel(ctx context.Context, cmd *exec.Cmd, timed bool) func() {
+	cmd.Cancel = func() error { return nil }
+	if !timed {
+		return func() {}
+	}
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	ttyFd, foreground := foregroundTerminal(cmd)
+	if foreground {
+		cmd.SysProcAttr.Foreground = true
+		cmd.SysProcAttr.Ctty = ttyFd
+	}
+	// Output copied through pipes is not waited for forever when a process left in the
+	// group keeps them open.
+	cmd.WaitDelay = 2 * timeoutGracePeriod
+
+	var mu sync.Mutex
+	var killTimer *time.Timer
+	cmd.Cancel = func() error {
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil
+		}
+		pgid := cmd.Process.Pid
+		mu.Lock()
+		killTimer = time.AfterFunc(timeoutGracePeriod, func() { _ = syscall.Kill(-pgid, syscall.SIGKILL) })
+		mu.Unlock()
+		return syscall.Kill(-pgid, syscall.SIGINT)
+	}
+
+	return func() {
+		mu.Lock()
+		if killTimer != nil {
+			killTimer.Stop()
+		}
+		mu.Unlock()
+		if foreground {
+			reclaimTerminal(ttyFd)
+		}
+	}
+}
+
+// foregroundTerminal returns the descriptor of the terminal cmd reads from when terrax is
+// its foreground process group, and whether it is.
+func foregroundTerminal(cmd *exec.Cmd) (int, bool) {
+	if cmd.Stdin != os.Stdin {
+		return 0, false
+	}
+	fd := int(os.Stdin.Fd())
+	pgrp, err := unix.IoctlGetInt(fd, unix.TIOCGPGRP)
+	return fd, err == nil && pgrp == syscall.Getpgrp()
+}
+
+// reclaimTerminal makes terrax's process group the foreground group of the terminal at fd
+// again. SIGTTOU is ignored meanwhile, since terrax asks from the background.
+func reclaimTerminal(fd int) {
+	signal.Ignore(syscall.SIGTTOU)
+	defer signal.Reset(syscall.SIGTTOU)
+	_ = unix.IoctlSetPointerInt(fd, unix.TIOCSPGRP, syscall.Getpgrp())
+}
@@ -0,0 +1,146 @@
+//go:build !windows
+
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRun_CommandTimeout tests that a command running past command_timeout is interrupted,
+// killed when it ignores the interrupt, and recorded as timed out.
+func TestRun_CommandTimeout(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		trap        string
+		interrupted bool
+	}{
+		{name: "stops on interrupt", trap: "trap 'touch %q; exit 130' INT", interrupted: true},
+		{name: "killed after grace period", trap: "trap '' INT # %q"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetViper()
+			t.Cleanup(resetViper)
+			viper.Set("command_timeout", 200*time.Millisecond)
+			oldGrace := timeoutGracePeriod
+			timeoutGracePeriod = 300 * time.Millisecond
+			t.Cleanup(func() { timeoutGracePeriod = oldGrace })
+
+			dir := t.TempDir()
+			marker := filepath.Join(dir, "interrupted")
+			binaryPath := filepath.Join(dir, TerragruntBinary)
+			script := "#!/bin/sh\n" + fmt.Sprintf(tt.trap, marker) + "\nsleep 5 >/dev/null 2>&1 &\nwait\n"
+			require.NoError(t, os.WriteFile(binaryPath, []byte(script), 0o755))
+			t.Cleanup(setBinaryLookup(func(string) (string, error) { return binaryPath, nil }, os.Stat))
+
+			oldStdout, oldStderr := os.Stdout, os.Stderr
+			_, w, _ := os.Pipe()
+			os.Stdout, os.Stderr = w, w
+			logger := &mockHistoryLogger{nextID: 1}
+			start := time.Now()
+			err := Run(context.Background(), logger, "apply", "/test/stack", dir, []string{"."}, nil)
+			elapsed := time.Since(start)
+			os.Stdout, os.Stderr = oldStdout, oldStderr
+			require.NoError(t, w.Close())
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "timed out after 200ms")
+			assert.Less(t, elapsed, 5*time.Second, "the hung command should be stopped")
+			assert.Equal(t, TimeoutExitCode, logger.lastEntry.ExitCode)
+			assert.Equal(t, "Command timed out after 200ms", logger.lastEntry.Summary)
+			if tt.interrupted {
+				assert.FileExists(t, marker)
+			}
+		})
+	}
+}
+
+// TestRun_CommandTimeout_ReadsStdin tests that a command run with command_timeout, in its own
+// process group, can still read a prompt's answer from stdin.
+func TestRun_CommandTimeout_ReadsStdin(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("command_timeout", 5*time.Second)
+
+	dir := t.TempDir()
+	answerFile := filepath.Join(dir, "answer")
+	binaryPath := filepath.Join(dir, TerragruntBinary)
+	script := fmt.Sprintf("#!/bin/sh\nread answer\necho \"$answer $(ps -o pgid= $$)\" > %q\n", answerFile)
+	require.NoError(t, os.WriteFile(binaryPath, []byte(script), 0o755))
+	t.Cleanup(setBinaryLookup(func(string) (string, error) { return binaryPath, nil }, os.Stat))
+
+	stdinR, stdinW, err := os.Pipe()
+	require.NoError(t, err)
+	_, err = stdinW.WriteString("yes\n")
+	require.NoError(t, err)
+	require.NoError(t, stdinW.Close())
+
+	oldStdin, oldStdout, oldStderr := os.Stdin, os.Stdout, os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdin, os.Stdout, os.Stderr = stdinR, w, w
+	err = Run(context.Background(), &mockHistoryLogger{nextID: 1}, "apply", "/test/stack", dir, []string{"."}, nil)
+	os.Stdin, os.Stdout, os.Stderr = oldStdin, oldStdout, oldStderr
+	require.NoError(t, w.Close())
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(answerFile)
+	require.NoError(t, err)
+	fields := strings.Fields(string(data))
+	require.Len(t, fields, 2)
+	assert.Equal(t, "yes", fields[0])
+	assert.NotEqual(t, fmt.Sprint(syscall.Getpgrp()), fields[1], "the command has its own process group")
+}
+
+// TestRun_CommandTimeout_KillsGroup tests that the timeout kills the processes the command
+// started, which would otherwise keep running and hold the output pipe open.
+func TestRun_CommandTimeout_KillsGroup(t *testing.T) {
+	resetViper()
+	t.Cleanup(resetViper)
+	viper.Set("command_timeout", 200*time.Millisecond)
+	oldGrace := timeoutGracePeriod
+	timeoutGracePeriod = 300 * time.Millisecond
+	t.Cleanup(func() { timeoutGracePeriod = oldGrace })
+
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "pid")
+	binaryPath := filepath.Join(dir, TerragruntBinary)
+	// The child ignores SIGINT and inherits stdout, like a terraform that will not stop; the
+	// wrapper exits on SIGINT, leaving it behind.
+	script := fmt.Sprintf("#!/bin/sh\n(trap '' INT; exec sleep 30) &\necho $! > %q\nwait\n", pidFile)
+	require.NoError(t, os.WriteFile(binaryPath, []byte(script), 0o755))
+	t.Cleanup(setBinaryLookup(func(string) (string, error) { return binaryPath, nil }, os.Stat))
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	_, w, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, w
+	start := time.Now()
+	err := Run(context.Background(), &mockHistoryLogger{nextID: 1}, "apply", "/test/stack", dir, []string{"."}, nil)
+	elapsed := time.Since(start)
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	require.NoError(t, w.Close())
+
+	require.Error(t, err)
+	assert.Less(t, elapsed, 5*time.Second, "the run returns although the child held its output")
+
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	var pid int
+	_, err = fmt.Sscan(string(data), &pid)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(pid, 0) == syscall.ESRCH
+	}, 5*time.Second, 20*time.Millisecond, "the child is killed with its group")
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// configureCancel sets how cancelling ctx stops cmd and returns a function to call once cmd
+// has exited. Ctrl+C already reaches the command through the console, so nothing more is
+// sent for it; when the timeout expires, the command is killed.
+func configureCancel(ctx context.Context, cmd *exec.Cmd, timed bool) func() {
+	cmd.Cancel = func() error {
+		if timed && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	return func() {}
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/israoo/terrax/internal/history"
+)
+
+// runLogTimeFormat names log files so they sort chronologically.
+const runLogTimeFormat = "20060102-150405.000"
+
+// runLogDir returns the directory per-run logs are written to: run_logs.dir when set,
+// otherwise a logs directory next to the history file.
+func runLogDir() (string, error) {
+	if dir := viper.GetString("run_logs.dir"); dir != "" {
+		return dir, nil
+	}
+	historyPath, err := history.GetHistoryFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(historyPath), "logs"), nil
+}
+
+// runLogPath returns the log file path for a run of command against stackPath started at start,
+// e.g. <dir>/20240501-120000.000_plan_vpc.log.
+func runLogPath(dir string, start time.Time, command, stackPath string) string {
+	name := fmt.Sprintf("%s_%s_%s.log", start.Format(runLogTimeFormat), logNamePart(command), logNamePart(filepath.Base(stackPath)))
+	return filepath.Join(dir, name)
+}
+
+// logNamePart replaces characters that are unsafe in file names with "-".
+func logNamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
+
+// openRunLog creates the log file for a run, creating the logs directory as needed.
+func openRunLog(start time.Time, command, stackPath string) (*os.File, error) {
+	dir, err := runLogDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve logs directory: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create logs directory: %w", err)
+	}
+	file, err := os.Create(runLogPath(dir, start, command, stackPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	return file, nil
+}
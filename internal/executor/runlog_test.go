@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/clock"
+)
+
+// TestRunLogPath tests the naming of per-run log files.
+func TestRunLogPath(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 30, 45, 123000000, time.UTC)
+
+	assert.Equal(t,
+		filepath.Join("/logs", "20240501-123045.123_plan_vpc.log"),
+		runLogPath("/logs", start, "plan", "/repo/env/dev/vpc"))
+	assert.Equal(t,
+		filepath.Join("/logs", "20240501-123045.123_force-unlock_my-stack-.log"),
+		runLogPath("/logs", start, "force-unlock", "/repo/my stack!"))
+}
+
+// TestRun_WritesRunLog tests that a run with run_logs.enabled tees its output to a log file
+// in the configured directory and records the file in the history entry.
+func TestRun_WritesRunLog(t *testing.T) {
+	resetViper()
+	t.Cleanup(viper.Reset)
+	stubTerragrunt(t)
+	logsDir := filepath.Join(t.TempDir(), "nested", "logs")
+	viper.Set("run_logs.enabled", true)
+	viper.Set("run_logs.dir", logsDir)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	defer setClock(clock.NewFake(start))()
+	defer setCommandRunner(func(cmd *exec.Cmd) error {
+		_, _ = fmt.Fprintln(cmd.Stdout, "Plan: 1 to add, 0 to change, 0 to destroy.")
+		_, _ = fmt.Fprintln(cmd.Stderr, "a warning")
+		return nil
+	})()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	r, w, _ := os.Pipe()
+	_, wErr, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, wErr
+
+	logger := &mockHistoryLogger{nextID: 1}
+	err := Run(context.Background(), logger, "plan", "/repo/env/vpc", t.TempDir(), []string{"."}, nil)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, wErr.Close())
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	var buf bytes.Buffer
+	_, copyErr := io.Copy(&buf, r)
+	require.NoError(t, copyErr)
+
+	require.NoError(t, err)
+	expectedPath := filepath.Join(logsDir, "20240501-120000.000_plan_vpc.log")
+	assert.Equal(t, expectedPath, logger.lastEntry.LogPath)
+	assert.Contains(t, buf.String(), expectedPath)
+
+	content, readErr := os.ReadFile(expectedPath)
+	require.NoError(t, readErr)
+	assert.Contains(t, string(content), "Plan: 1 to add, 0 to change, 0 to destroy.")
+	assert.Contains(t, string(content), "a warning")
+}
+
+// TestRun_RunLogsDisabled tests that no log file is referenced when run logs are off.
+func TestRun_RunLogsDisabled(t *testing.T) {
+	resetViper()
+	stubTerragrunt(t)
+	defer setCommandRunner(func(cmd *exec.Cmd) error { return nil })()
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	_, w, _ := os.Pipe()
+	_, wErr, _ := os.Pipe()
+	os.Stdout, os.Stderr = w, wErr
+
+	logger := &mockHistoryLogger{nextID: 1}
+	err := Run(context.Background(), logger, "plan", "/repo/env/vpc", t.TempDir(), []string{"."}, nil)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, wErr.Close())
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+
+	require.NoError(t, err)
+	assert.Empty(t, logger.lastEntry.LogPath)
+}
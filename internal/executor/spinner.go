@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// spinnerFrames are drawn in turn while a quiet command runs.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+var (
+	// spinnerWriter receives the spinner frames (can be overridden in tests).
+	spinnerWriter io.Writer = os.Stderr
+
+	// spinnerInterval is the delay between frames (can be overridden in tests).
+	spinnerInterval = 100 * time.Millisecond
+
+	// spinnerTerminal reports whether spinnerWriter is a terminal, where the spinner can
+	// redraw its line (can be overridden in tests).
+	spinnerTerminal = func() bool { return isTerminal(spinnerWriter) }
+)
+
+// isTerminal reports whether w is a terminal rather than a file or pipe.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// startSpinner draws a spinner with label and the elapsed time on a single line of w,
+// redrawing it every interval until the returned stop function is called. Stop clears
+// the line and returns only after the last frame has been written.
+func startSpinner(w io.Writer, label string, interval time.Duration) (stop func()) {
+	start := currentClock.Now()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			elapsed := currentClock.Now().Sub(start).Seconds()
+			fmt.Fprintf(w, "\r%s %s (%.1fs)", spinnerFrames[frame%len(spinnerFrames)], label, elapsed)
+
+			select {
+			case <-done:
+				fmt.Fprint(w, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
@@ -0,0 +1,26 @@
+package executor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartSpinner(t *testing.T) {
+	var buf bytes.Buffer
+	stop := startSpinner(&buf, "terragrunt plan", time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	stop()
+
+	output := buf.String()
+	assert.GreaterOrEqual(t, strings.Count(output, "terragrunt plan ("), 2, "spinner should redraw while running")
+	assert.True(t, strings.HasSuffix(output, "\r\033[K"), "spinner should clear its line when stopped")
+
+	// No frames are written once stop has returned.
+	written := buf.Len()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, written, buf.Len())
+}
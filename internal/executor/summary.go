@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/israoo/terrax/internal/history"
+)
+
+var (
+	// ansiEscapePattern matches terminal color codes so colored output can be parsed.
+	ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+	planLinePattern    = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy\.`)
+	applyLinePattern   = regexp.MustCompile(`Apply complete! Resources: (\d+) added, (\d+) changed, (\d+) destroyed\.`)
+	destroyLinePattern = regexp.MustCompile(`Destroy complete! Resources: (\d+) destroyed\.`)
+	noChangesPattern   = regexp.MustCompile(`No changes\.`)
+)
+
+// changeSummaryWriter scans Terraform output line by line as it is written and totals
+// the resource changes reported across all stacks. It keeps no output beyond the
+// current partial line, so it is safe to tee long-running command output into it.
+type changeSummaryWriter struct {
+	mu      sync.Mutex
+	partial []byte
+
+	plans, applies, destroys, noChanges int
+	plan, apply                         [3]int // add, change, destroy
+	destroyed                           int
+}
+
+// Write implements io.Writer.
+func (w *changeSummaryWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		w.scanLine(w.partial[:idx])
+		w.partial = w.partial[idx+1:]
+	}
+	return len(p), nil
+}
+
+// scanLine updates the totals from a single output line.
+func (w *changeSummaryWriter) scanLine(line []byte) {
+	line = ansiEscapePattern.ReplaceAll(line, nil)
+
+	if m := planLinePattern.FindSubmatch(line); m != nil {
+		w.plans++
+		addCounts(&w.plan, m[1:])
+		return
+	}
+	if m := applyLinePattern.FindSubmatch(line); m != nil {
+		w.applies++
+		addCounts(&w.apply, m[1:])
+		return
+	}
+	if m := destroyLinePattern.FindSubmatch(line); m != nil {
+		w.destroys++
+		n, _ := strconv.Atoi(string(m[1]))
+		w.destroyed += n
+		return
+	}
+	if noChangesPattern.Match(line) {
+		w.noChanges++
+	}
+}
+
+// addCounts adds the numeric submatches to totals.
+func addCounts(totals *[3]int, matches [][]byte) {
+	for i, m := range matches {
+		n, _ := strconv.Atoi(string(m))
+		totals[i] += n
+	}
+}
+
+// flush scans an unterminated last line. Callers hold the lock.
+func (w *changeSummaryWriter) flush() {
+	if len(w.partial) > 0 {
+		w.scanLine(w.partial)
+		w.partial = nil
+	}
+}
+
+// Summary returns a one-line change summary, or an empty string if the output
+// contained no recognizable Terraform change report.
+func (w *changeSummaryWriter) Summary() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flush()
+
+	switch {
+	case w.applies > 0:
+		return fmt.Sprintf("Apply complete! Resources: %d added, %d changed, %d destroyed.", w.apply[0], w.apply[1], w.apply[2])
+	case w.destroys > 0:
+		return fmt.Sprintf("Destroy complete! Resources: %d destroyed.", w.destroyed)
+	case w.plans > 0:
+		return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", w.plan[0], w.plan[1], w.plan[2])
+	case w.noChanges > 0:
+		return history.NoChangesSummary
+	}
+	return ""
+}
+
+// Counts returns the change report as resource counts, picked like Summary picks its line,
+// or nil if the output contained no recognizable Terraform change report.
+func (w *changeSummaryWriter) Counts() *history.ChangeCounts {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.flush()
+	switch {
+	case w.applies > 0:
+		return &history.ChangeCounts{Add: w.apply[0], Change: w.apply[1], Destroy: w.apply[2]}
+	case w.destroys > 0:
+		return &history.ChangeCounts{Destroy: w.destroyed}
+	case w.plans > 0:
+		return &history.ChangeCounts{Add: w.plan[0], Change: w.plan[1], Destroy: w.plan[2]}
+	case w.noChanges > 0:
+		return &history.ChangeCounts{}
+	}
+	return nil
+}
+
+// parseChangeCounts returns the resource counts reported in Terraform output, or nil when
+// it holds no change report (e.g. failed runs or commands such as validate).
+func parseChangeCounts(output string) *history.ChangeCounts {
+	w := &changeSummaryWriter{}
+	_, _ = w.Write([]byte(output))
+	return w.Counts()
+}
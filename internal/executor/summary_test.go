@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/israoo/terrax/internal/history"
+)
+
+func TestChangeSummaryWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		writes   []string
+		expected string
+	}{
+		{
+			name:     "no change report",
+			writes:   []string{"Initializing...\n", "Success!\n"},
+			expected: "",
+		},
+		{
+			name:     "plan totals across stacks",
+			writes:   []string{"Plan: 1 to add, 0 to change, 0 to destroy.\n", "Plan: 2 to add, 1 to change, 1 to destroy.\n"},
+			expected: "Plan: 3 to add, 1 to change, 1 to destroy.",
+		},
+		{
+			name:     "line split across writes",
+			writes:   []string{"Plan: 4 to ad", "d, 0 to change, 0 to destroy.\n"},
+			expected: "Plan: 4 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:     "colored output",
+			writes:   []string{"\x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 0 to destroy.\n"},
+			expected: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			name:     "no changes",
+			writes:   []string{"No changes. Your infrastructure matches the configuration.\n"},
+			expected: "No changes.",
+		},
+		{
+			name:     "apply complete",
+			writes:   []string{"Apply complete! Resources: 2 added, 1 changed, 0 destroyed.\n"},
+			expected: "Apply complete! Resources: 2 added, 1 changed, 0 destroyed.",
+		},
+		{
+			name:     "destroy complete without trailing newline",
+			writes:   []string{"Destroy complete! Resources: 5 destroyed."},
+			expected: "Destroy complete! Resources: 5 destroyed.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &changeSummaryWriter{}
+			for _, chunk := range tt.writes {
+				n, err := w.Write([]byte(chunk))
+				assert.NoError(t, err)
+				assert.Equal(t, len(chunk), n)
+			}
+			assert.Equal(t, tt.expected, w.Summary())
+		})
+	}
+}
+
+func TestChangeSummaryWriter_NoChangesPlanIsClassified(t *testing.T) {
+	w := &changeSummaryWriter{}
+	output := "Refreshing state...\n" +
+		"\x1b[1mNo changes.\x1b[0m Your infrastructure matches the configuration.\n" +
+		"Terraform has compared your real infrastructure against your configuration\n"
+	_, err := w.Write([]byte(output))
+	assert.NoError(t, err)
+
+	entry := history.ExecutionLogEntry{Command: "plan", ExitCode: 0, Summary: w.Summary()}
+	assert.True(t, entry.HasNoChanges())
+}
+
+// TestParseChangeCounts tests parsing resource counts from real-world Terraform and
+// Terragrunt output.
+func TestParseChangeCounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   *history.ChangeCounts
+	}{
+		{
+			name: "plan with changes",
+			output: `Terraform used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+
+Terraform will perform the following actions:
+
+  # aws_s3_bucket.logs will be created
+  + resource "aws_s3_bucket" "logs" {
+
+Plan: 3 to add, 1 to change, 0 to destroy.
+
+Changes to Outputs:
+  + bucket_arn = (known after apply)
+`,
+			want: &history.ChangeCounts{Add: 3, Change: 1},
+		},
+		{
+			name: "terragrunt prefixes and colors across stacks",
+			output: "15:04:05.000 STDOUT [dev/vpc] terraform: \x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 2 to destroy.\n" +
+				"15:04:06.000 STDOUT [dev/rds] terraform: Plan: 0 to add, 4 to change, 0 to destroy.\n",
+			want: &history.ChangeCounts{Add: 1, Change: 4, Destroy: 2},
+		},
+		{
+			name: "no changes",
+			output: `No changes. Your infrastructure matches the configuration.
+
+Terraform has compared your real infrastructure against your configuration
+and found no differences, so no changes are needed.
+`,
+			want: &history.ChangeCounts{},
+		},
+		{
+			name:   "apply complete",
+			output: "aws_s3_bucket.logs: Creation complete after 2s [id=logs]\n\nApply complete! Resources: 2 added, 0 changed, 1 destroyed.\n",
+			want:   &history.ChangeCounts{Add: 2, Destroy: 1},
+		},
+		{
+			name:   "destroy complete",
+			output: "aws_s3_bucket.logs: Destruction complete after 1s\n\nDestroy complete! Resources: 5 destroyed.",
+			want:   &history.ChangeCounts{Destroy: 5},
+		},
+		{
+			name: "error output",
+			output: `Error: Invalid reference
+
+  on main.tf line 12, in resource "aws_instance" "web":
+  12:   subnet_id = aws_subnet.missing.id
+
+A reference to a resource type must be followed by at least one attribute access.
+`,
+			want: nil,
+		},
+		{
+			name:   "validate output",
+			output: "Success! The configuration is valid.\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseChangeCounts(tt.output))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package executor
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+const (
+	// failureTailLines is how many trailing output lines of a failed run go into its summary.
+	failureTailLines = 3
+	// failureTailMaxLen caps the length of that tail, so one long line cannot bloat history.
+	failureTailMaxLen = 300
+)
+
+// tailWriter keeps the last non-empty lines written to it, without color codes. Like
+// changeSummaryWriter it is safe to share between the stdout and stderr of a command.
+type tailWriter struct {
+	mu       sync.Mutex
+	maxLines int
+	lines    []string
+	partial  []byte
+}
+
+// newTailWriter returns a tailWriter keeping the last maxLines lines.
+func newTailWriter(maxLines int) *tailWriter {
+	return &tailWriter{maxLines: maxLines}
+}
+
+// Write implements io.Writer.
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			break
+		}
+		w.addLine(w.partial[:idx])
+		w.partial = w.partial[idx+1:]
+	}
+	return len(p), nil
+}
+
+// addLine records line, dropping the oldest line once maxLines are kept.
+func (w *tailWriter) addLine(line []byte) {
+	text := strings.TrimSpace(string(ansiEscapePattern.ReplaceAll(line, nil)))
+	if text == "" {
+		return
+	}
+	w.lines = append(w.lines, text)
+	if len(w.lines) > w.maxLines {
+		w.lines = w.lines[len(w.lines)-w.maxLines:]
+	}
+}
+
+// Tail returns the kept lines joined by " | ", including an unterminated last line, and
+// shortened to failureTailMaxLen keeping the end. It returns "" when nothing was written.
+func (w *tailWriter) Tail() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	lines := w.lines
+	if text := strings.TrimSpace(string(ansiEscapePattern.ReplaceAll(w.partial, nil))); text != "" {
+		lines = append(lines[:len(lines):len(lines)], text)
+		if len(lines) > w.maxLines {
+			lines = lines[len(lines)-w.maxLines:]
+		}
+	}
+
+	tail := strings.Join(lines, " | ")
+	if len(tail) > failureTailMaxLen {
+		tail = "..." + tail[len(tail)-failureTailMaxLen:]
+	}
+	return tail
+}
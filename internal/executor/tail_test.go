@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTailWriter tests keeping the last lines of output across partial writes.
+func TestTailWriter(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{name: "nothing written", want: ""},
+		{name: "fewer lines than kept", writes: []string{"Error: boom\n"}, want: "Error: boom"},
+		{
+			name:   "keeps the last lines",
+			writes: []string{"one\ntwo\n", "three\nfour\n"},
+			want:   "two | three | four",
+		},
+		{
+			name:   "lines split across writes and blank lines",
+			writes: []string{"Error: inva", "lid config\n\n   \n", "on main.tf line 3"},
+			want:   "Error: invalid config | on main.tf line 3",
+		},
+		{
+			name:   "color codes are stripped",
+			writes: []string{"\x1b[31mError:\x1b[0m denied\n"},
+			want:   "Error: denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTailWriter(3)
+			for _, s := range tt.writes {
+				_, err := fmt.Fprint(w, s)
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, w.Tail())
+		})
+	}
+
+	t.Run("long tail keeps the end", func(t *testing.T) {
+		w := newTailWriter(3)
+		_, _ = fmt.Fprintln(w, strings.Repeat("x", failureTailMaxLen)+" the end")
+		tail := w.Tail()
+		assert.Len(t, tail, failureTailMaxLen+len("..."))
+		assert.True(t, strings.HasPrefix(tail, "..."))
+		assert.True(t, strings.HasSuffix(tail, " the end"))
+	})
+}
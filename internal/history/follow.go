@@ -0,0 +1,78 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Follow polls filePath for entries appended after offset and calls emit for each one,
+// in file order, until ctx is cancelled. Only complete (newline-terminated) lines are
+// emitted so a write that is still in progress is picked up on the next poll.
+// A missing file is treated as empty; it is read once it appears.
+func Follow(ctx context.Context, filePath string, offset int64, interval time.Duration, emit func(ExecutionLogEntry)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		newOffset, err := readAppendedEntries(filePath, offset, emit)
+		if err != nil {
+			return err
+		}
+		offset = newOffset
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readAppendedEntries reads complete lines from filePath starting at offset, emits the
+// entries that parse, and returns the offset just past the last complete line.
+// If the file shrank below offset (e.g. after a trim), reading restarts from the beginning.
+func readAppendedEntries(filePath string, offset int64, emit func(ExecutionLogEntry)) (int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return offset, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return offset, fmt.Errorf("failed to stat history file: %w", err)
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return offset, fmt.Errorf("failed to seek history file: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// Partial trailing line (or EOF): leave it for the next poll.
+			return offset, nil
+		}
+		offset += int64(len(line))
+
+		var entry ExecutionLogEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry.AbsolutePath == "" && entry.StackPath != "" {
+			entry.AbsolutePath = entry.StackPath
+		}
+		emit(entry)
+	}
+}
@@ -0,0 +1,113 @@
+package history
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollow_EmitsAppendedEntries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	historyPath := filepath.Join(t.TempDir(), HistoryFileName)
+	repo, err := NewFileRepository(historyPath)
+	require.NoError(t, err)
+
+	// Pre-existing entries before offset must not be emitted.
+	_, err = repo.Append(ctx, ExecutionLogEntry{ID: 1, Command: "plan", StackPath: "dev/vpc"})
+	require.NoError(t, err)
+	offset, err := readAppendedEntries(historyPath, 0, func(ExecutionLogEntry) {})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var got []ExecutionLogEntry
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, historyPath, offset, 5*time.Millisecond, func(e ExecutionLogEntry) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, e)
+		})
+	}()
+
+	_, err = repo.Append(ctx, ExecutionLogEntry{ID: 2, Command: "apply", StackPath: "dev/rds"})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, got[0].ID)
+	assert.Equal(t, "apply", got[0].Command)
+	assert.Equal(t, "dev/rds", got[0].AbsolutePath, "legacy entries fall back to StackPath")
+}
+
+func TestReadAppendedEntries(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		offset        int64
+		expectedIDs   []int
+		expectedDelta int64
+	}{
+		{
+			name:          "missing file",
+			content:       "",
+			offset:        0,
+			expectedIDs:   nil,
+			expectedDelta: 0,
+		},
+		{
+			name:          "partial trailing line is left for next poll",
+			content:       "{\"id\":1}\n{\"id\":2}",
+			offset:        0,
+			expectedIDs:   []int{1},
+			expectedDelta: int64(len("{\"id\":1}\n")),
+		},
+		{
+			name:          "invalid lines are skipped",
+			content:       "not json\n{\"id\":3}\n",
+			offset:        0,
+			expectedIDs:   []int{3},
+			expectedDelta: int64(len("not json\n{\"id\":3}\n")),
+		},
+		{
+			name:          "offset beyond size restarts from beginning",
+			content:       "{\"id\":4}\n",
+			offset:        1000,
+			expectedIDs:   []int{4},
+			expectedDelta: int64(len("{\"id\":4}\n")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			historyPath := filepath.Join(t.TempDir(), HistoryFileName)
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(historyPath, []byte(tt.content), 0644))
+			}
+
+			var ids []int
+			offset, err := readAppendedEntries(historyPath, tt.offset, func(e ExecutionLogEntry) {
+				ids = append(ids, e.ID)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedIDs, ids)
+			assert.Equal(t, tt.expectedDelta, offset)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package history
+
+// StackGroup holds the history entries recorded for one stack.
+type StackGroup struct {
+	StackPath string              // Relative stack path shared by every entry
+	Entries   []ExecutionLogEntry // Runs against the stack, most recent first
+}
+
+// Latest returns the most recent run in the group.
+func (g StackGroup) Latest() ExecutionLogEntry {
+	return g.Entries[0]
+}
+
+// GroupByStack groups entries by StackPath. entries may be in any order; each group lists
+// its runs most recent first, and groups are ordered by their latest run, most recent first.
+func GroupByStack(entries []ExecutionLogEntry) []StackGroup {
+	var groups []StackGroup
+	indexByPath := make(map[string]int)
+	for _, entry := range newestFirst(entries) {
+		i, ok := indexByPath[entry.StackPath]
+		if !ok {
+			i = len(groups)
+			indexByPath[entry.StackPath] = i
+			groups = append(groups, StackGroup{StackPath: entry.StackPath})
+		}
+		groups[i].Entries = append(groups[i].Entries, entry)
+	}
+	return groups
+}
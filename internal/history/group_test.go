@@ -0,0 +1,46 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupByStack(t *testing.T) {
+	entries := []ExecutionLogEntry{
+		{ID: 1, StackPath: "dev/vpc", Command: "plan"},
+		{ID: 2, StackPath: "dev/rds", Command: "plan"},
+		{ID: 3, StackPath: "dev/vpc", Command: "apply", ExitCode: 1},
+		{ID: 4, StackPath: "dev/rds", Command: "apply"},
+		{ID: 5, StackPath: "dev/eks", Command: "plan"},
+	}
+
+	groups := GroupByStack(entries)
+
+	require.Len(t, groups, 3)
+	assert.Equal(t, []string{"dev/eks", "dev/rds", "dev/vpc"},
+		[]string{groups[0].StackPath, groups[1].StackPath, groups[2].StackPath},
+		"groups are ordered by their latest run")
+
+	assert.Equal(t, []int{3, 1}, entryIDs(groups[2].Entries), "runs are listed most recent first")
+	assert.Equal(t, 3, groups[2].Latest().ID)
+	assert.Equal(t, 1, groups[2].Latest().ExitCode, "the latest outcome comes from the latest run")
+	assert.Equal(t, 4, groups[1].Latest().ID)
+}
+
+func TestGroupByStack_OrderIndependent(t *testing.T) {
+	oldestFirst := []ExecutionLogEntry{
+		{ID: 1, StackPath: "dev/vpc"},
+		{ID: 2, StackPath: "dev/vpc"},
+	}
+	newestFirst := []ExecutionLogEntry{oldestFirst[1], oldestFirst[0]}
+
+	assert.Equal(t, GroupByStack(oldestFirst), GroupByStack(newestFirst))
+	assert.Equal(t, []ExecutionLogEntry{{ID: 1, StackPath: "dev/vpc"}, {ID: 2, StackPath: "dev/vpc"}}, oldestFirst,
+		"the input is not reordered")
+}
+
+func TestGroupByStack_Empty(t *testing.T) {
+	assert.Empty(t, GroupByStack(nil))
+}
@@ -7,6 +7,7 @@ package history
 
 import (
 	"context"
+	"time"
 )
 
 var DefaultService *Service
@@ -41,9 +42,9 @@ func AppendToHistory(ctx context.Context, entry ExecutionLogEntry) error {
 	return DefaultService.Append(ctx, entry)
 }
 
-// LoadHistory wraps repo LoadAll directly (as it was logic-less).
+// LoadHistory wraps the service LoadAll, so it returns entries in the same order.
 func LoadHistory(ctx context.Context) ([]ExecutionLogEntry, error) {
-	return DefaultService.repo.LoadAll(ctx)
+	return DefaultService.LoadAll(ctx)
 }
 
 // TrimHistory wraps the service TrimHistory.
@@ -51,6 +52,11 @@ func TrimHistory(ctx context.Context, maxEntries int) error {
 	return DefaultService.TrimHistory(ctx, maxEntries)
 }
 
+// TrimHistoryByAge wraps the service TrimHistoryByAge.
+func TrimHistoryByAge(ctx context.Context, maxAge time.Duration) error {
+	return DefaultService.TrimHistoryByAge(ctx, maxAge)
+}
+
 // GetLastExecutionForProject wraps the service.
 func GetLastExecutionForProject(ctx context.Context, rootConfigFile string) (*ExecutionLogEntry, error) {
 	// Temporarily override config file for this call if it differs,
@@ -209,6 +209,52 @@ func TestGetLastExecutionForProject_EdgeCases(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotNil(t, lastEntry)
 	})
+
+	t.Run("dry runs are skipped", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := NewFileRepository(filepath.Join(tmpDir, "test_history.log"))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+
+		stackPath := filepath.Join(tmpDir, "dev")
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 1, Timestamp: time.Now(), Command: "plan", AbsolutePath: stackPath}))
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 2, Timestamp: time.Now(), Command: "apply", AbsolutePath: stackPath, DryRun: true}))
+
+		lastEntry, err := svc.GetLastExecutionForProject(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, lastEntry)
+		assert.Equal(t, "plan", lastEntry.Command)
+
+		lastEntry, err = svc.GetLastExecutionInProject(ctx, tmpDir)
+		require.NoError(t, err)
+		require.NotNil(t, lastEntry)
+		assert.Equal(t, "plan", lastEntry.Command)
+	})
+
+	t.Run("only entries under the project root", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		repo, err := NewFileRepository(filepath.Join(tmpDir, "test_history.log"))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+
+		projectA, projectB := filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "b")
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 1, Command: "plan", AbsolutePath: filepath.Join(projectA, "vpc")}))
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 2, Command: "apply", AbsolutePath: filepath.Join(projectB, "rds")}))
+
+		lastEntry, err := svc.GetLastExecutionInProject(ctx, projectA)
+		require.NoError(t, err)
+		require.NotNil(t, lastEntry)
+		assert.Equal(t, 1, lastEntry.ID)
+
+		lastEntry, err = svc.GetLastExecutionInProject(ctx, "")
+		require.NoError(t, err)
+		require.NotNil(t, lastEntry)
+		assert.Equal(t, 2, lastEntry.ID, "an empty project root matches every entry")
+
+		lastEntry, err = svc.GetLastExecutionInProject(ctx, filepath.Join(tmpDir, "c"))
+		require.NoError(t, err)
+		assert.Nil(t, lastEntry)
+	})
 }
 
 // TestLoadHistory_BackwardCompatibility tests loading old history entries.
@@ -3,14 +3,18 @@ package history
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/adrg/xdg"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/clock"
 )
 
 // Helper to split lines
@@ -37,6 +41,31 @@ func TestGetHistoryFilePath(t *testing.T) {
 	assert.True(t, info.IsDir())
 }
 
+func TestGetHistoryFilePath_XDG(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+
+	// Unset XDG_DATA_HOME keeps the history in the config directory.
+	t.Setenv("XDG_DATA_HOME", "")
+	path, err := GetDefaultHistoryFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configHome, ConfigDirName, HistoryFileName), path)
+
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	path, err = GetDefaultHistoryFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dataHome, ConfigDirName, HistoryFileName), path)
+
+	// An existing history in the config directory stays in use.
+	require.NoError(t, os.WriteFile(filepath.Join(configHome, ConfigDirName, HistoryFileName), nil, 0644))
+	path, err = GetDefaultHistoryFilePath()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(configHome, ConfigDirName, HistoryFileName), path)
+}
+
 func TestAppendToHistory(t *testing.T) {
 	ctx := context.Background()
 
@@ -210,6 +239,144 @@ func TestTrimHistory(t *testing.T) {
 	}
 }
 
+func TestTrimHistory_RemovesRunLogs(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+
+	repo, err := NewFileRepository(filepath.Join(tempDir, HistoryFileName))
+	require.NoError(t, err)
+	svc := NewService(repo, "root.hcl")
+
+	var logPaths []string
+	for id := 1; id <= 3; id++ {
+		logPath := filepath.Join(tempDir, fmt.Sprintf("run-%d.log", id))
+		require.NoError(t, os.WriteFile(logPath, []byte("output"), 0644))
+		logPaths = append(logPaths, logPath)
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: id, Command: "plan", LogPath: logPath}))
+	}
+	require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 4, Command: "plan"}))
+
+	require.NoError(t, svc.TrimHistory(ctx, 2))
+
+	assert.NoFileExists(t, logPaths[0])
+	assert.NoFileExists(t, logPaths[1])
+	assert.FileExists(t, logPaths[2])
+
+	entries, err := svc.LoadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, logPaths[2], entries[1].LogPath)
+}
+
+// TestTrimHistory_RemovesRunLogs_UndecodableLines tests that the run logs removed are those
+// of the entries the trim actually dropped when the history holds lines that are not entries.
+func TestTrimHistory_RemovesRunLogs_UndecodableLines(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	historyPath := filepath.Join(tempDir, HistoryFileName)
+
+	repo, err := NewFileRepository(historyPath)
+	require.NoError(t, err)
+	svc := NewService(repo, "root.hcl")
+
+	var logPaths []string
+	for id := 1; id <= 3; id++ {
+		logPath := filepath.Join(tempDir, fmt.Sprintf("run-%d.log", id))
+		require.NoError(t, os.WriteFile(logPath, []byte("output"), 0644))
+		logPaths = append(logPaths, logPath)
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: id, Command: "plan", LogPath: logPath}))
+	}
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	require.NoError(t, svc.TrimHistory(ctx, 2))
+
+	assert.NoFileExists(t, logPaths[0])
+	assert.NoFileExists(t, logPaths[1])
+	assert.FileExists(t, logPaths[2])
+}
+
+func TestTrimHistoryByAge(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	repo, err := NewFileRepository(filepath.Join(tempDir, HistoryFileName))
+	require.NoError(t, err)
+	svc := NewService(repo, "root.hcl")
+	svc.SetClock(clock.NewFake(now))
+
+	oldLog := filepath.Join(tempDir, "run-1.log")
+	require.NoError(t, os.WriteFile(oldLog, []byte("output"), 0644))
+	ages := []time.Duration{200 * day, 91 * day, 89 * day, day, 0}
+	for i, age := range ages {
+		entry := ExecutionLogEntry{ID: i + 1, Timestamp: now.Add(-age), Command: "plan", StackPath: "dev/vpc"}
+		if i == 0 {
+			entry.LogPath = oldLog
+		}
+		require.NoError(t, svc.Append(ctx, entry))
+	}
+
+	require.NoError(t, svc.TrimHistoryByAge(ctx, 90*day))
+
+	entries, err := svc.LoadAll(ctx)
+	require.NoError(t, err)
+	var ids []int
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []int{5, 4, 3}, ids, "only entries older than 90 days are removed")
+	assert.NoFileExists(t, oldLog)
+
+	// A non-positive age keeps everything.
+	require.NoError(t, svc.TrimHistoryByAge(ctx, 0))
+	entries, err = svc.LoadAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+}
+
+func TestFileRepository_TrimBefore(t *testing.T) {
+	ctx := context.Background()
+	historyPath := filepath.Join(t.TempDir(), HistoryFileName)
+	cutoff := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	repo, err := NewFileRepository(historyPath)
+	require.NoError(t, err)
+
+	// A missing file has nothing to trim.
+	dropped, err := repo.TrimBefore(ctx, cutoff)
+	require.NoError(t, err)
+	assert.Empty(t, dropped)
+	assert.NoFileExists(t, historyPath)
+
+	_, err = repo.Append(ctx, ExecutionLogEntry{ID: 1, Timestamp: cutoff.Add(-time.Hour)})
+	require.NoError(t, err)
+	_, err = repo.Append(ctx, ExecutionLogEntry{ID: 2, Timestamp: cutoff})
+	require.NoError(t, err)
+	f, err := os.OpenFile(historyPath, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("not json\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	dropped, err = repo.TrimBefore(ctx, cutoff)
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, 1, dropped[0].ID)
+
+	data, err := os.ReadFile(historyPath)
+	require.NoError(t, err)
+	lines := splitLines(string(data))
+	require.Len(t, lines, 2, "entries at the cutoff and undecodable lines are kept")
+	assert.Contains(t, lines[0], `"id":2`)
+	assert.Equal(t, "not json", lines[1])
+	assert.NoFileExists(t, historyPath+".tmp")
+}
+
 func TestTrimHistoryNonExistentFile(t *testing.T) {
 	ctx := context.Background()
 
@@ -225,6 +392,77 @@ func TestTrimHistoryNonExistentFile(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestTrimHistory_TrimPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	// appendAndTrim appends the entry with the given ID, trims to 2 entries as the executor
+	// does after each run, and returns the IDs left in the history, most recent first.
+	appendAndTrim := func(t *testing.T, svc *Service, id int) []int {
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: id, Command: "plan", StackPath: "dev/vpc"}))
+		require.NoError(t, svc.TrimHistory(ctx, 2))
+		entries, err := svc.LoadAll(ctx)
+		require.NoError(t, err)
+		ids := make([]int, len(entries))
+		for i, entry := range entries {
+			ids[i] = entry.ID
+		}
+		return ids
+	}
+
+	t.Run("every Nth append", func(t *testing.T) {
+		repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+		svc.SetTrimPolicy(TrimPolicy{Every: 3})
+
+		assert.Equal(t, []int{1}, appendAndTrim(t, svc, 1))
+		assert.Equal(t, []int{2, 1}, appendAndTrim(t, svc, 2))
+		assert.Equal(t, []int{3, 2}, appendAndTrim(t, svc, 3), "the third append trims")
+		assert.Equal(t, []int{4, 3, 2}, appendAndTrim(t, svc, 4), "trimming is skipped until the next threshold")
+		assert.Equal(t, []int{5, 4, 3, 2}, appendAndTrim(t, svc, 5))
+		assert.Equal(t, []int{6, 5}, appendAndTrim(t, svc, 6), "the most recent entries are retained")
+	})
+
+	t.Run("counts the ID the entry was written with", func(t *testing.T) {
+		repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+		svc.SetTrimPolicy(TrimPolicy{Every: 3})
+
+		assert.Equal(t, []int{1}, appendAndTrim(t, svc, 1))
+		// Another process appends ID 2 after this one obtained it.
+		_, err = repo.Append(ctx, ExecutionLogEntry{ID: 2, Command: "plan", StackPath: "dev/rds"})
+		require.NoError(t, err)
+		assert.Equal(t, []int{3, 2}, appendAndTrim(t, svc, 2), "the entry written as ID 3 trims")
+	})
+
+	t.Run("size threshold", func(t *testing.T) {
+		repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{ID: 1, Command: "plan", StackPath: "dev/vpc"}))
+		entrySize, err := repo.Size(ctx)
+		require.NoError(t, err)
+		// Allow three entries' worth of bytes before trimming.
+		svc.SetTrimPolicy(TrimPolicy{MaxBytes: 3 * entrySize})
+
+		assert.Equal(t, []int{2, 1}, appendAndTrim(t, svc, 2))
+		assert.Equal(t, []int{3, 2, 1}, appendAndTrim(t, svc, 3), "trimming is skipped while under the size threshold")
+		assert.Equal(t, []int{4, 3}, appendAndTrim(t, svc, 4), "exceeding the size threshold trims")
+	})
+
+	t.Run("zero policy trims on every append", func(t *testing.T) {
+		repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+		require.NoError(t, err)
+		svc := NewService(repo, "root.hcl")
+
+		appendAndTrim(t, svc, 1)
+		appendAndTrim(t, svc, 2)
+		assert.Equal(t, []int{3, 2}, appendAndTrim(t, svc, 3))
+	})
+}
+
 func TestExecutionLogEntry_JSONSerialization(t *testing.T) {
 	entry := ExecutionLogEntry{
 		ID:        42,
@@ -235,6 +473,7 @@ func TestExecutionLogEntry_JSONSerialization(t *testing.T) {
 		ExitCode:  0,
 		DurationS: 123.456,
 		Summary:   "5 added, 2 changed, 0 destroyed",
+		Note:      "prod release",
 	}
 
 	jsonData, err := json.Marshal(entry)
@@ -253,6 +492,8 @@ func TestExecutionLogEntry_JSONSerialization(t *testing.T) {
 	assert.Equal(t, entry.ExitCode, parsed.ExitCode)
 	assert.Equal(t, entry.DurationS, parsed.DurationS)
 	assert.Equal(t, entry.Summary, parsed.Summary)
+	assert.Equal(t, entry.Note, parsed.Note)
+	assert.Contains(t, string(jsonData), `"note":"prod release"`)
 }
 
 func TestGetCurrentUser(t *testing.T) {
@@ -438,6 +679,68 @@ func TestGetLastExecution(t *testing.T) {
 	})
 }
 
+func TestLoadAll_Order(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "root.hcl"), []byte("# root"), 0644))
+	for _, dir := range []string{"path/1", "path/2", "path/3"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, dir), 0755))
+	}
+
+	repo, err := NewFileRepository(filepath.Join(tempDir, HistoryFileName))
+	require.NoError(t, err)
+	svc := NewService(repo, "root.hcl")
+	for _, id := range []int{1, 3, 2} {
+		require.NoError(t, svc.Append(ctx, ExecutionLogEntry{
+			ID:           id,
+			AbsolutePath: filepath.Join(tempDir, "path", fmt.Sprint(id)),
+			Command:      "plan",
+		}))
+	}
+
+	origWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.Chdir(origWd))
+	}()
+	require.NoError(t, os.Chdir(tempDir))
+
+	ids := func(entries []ExecutionLogEntry) []int {
+		var result []int
+		for _, e := range entries {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		order    string
+		expected []int
+	}{
+		{name: "default is newest first", order: "", expected: []int{3, 2, 1}},
+		{name: "newest first", order: OrderNewest, expected: []int{3, 2, 1}},
+		{name: "oldest first", order: OrderOldest, expected: []int{1, 2, 3}},
+		{name: "unknown order falls back to newest", order: "random", expected: []int{3, 2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc.SetOrder(tt.order)
+
+			entries, err := svc.LoadAll(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ids(entries))
+
+			// The last execution is streamed from the end of the file: the latest appended.
+			last, err := svc.GetLastExecutionForProject(ctx)
+			require.NoError(t, err)
+			require.NotNil(t, last)
+			assert.Equal(t, 2, last.ID, "the last execution does not depend on the order")
+		})
+	}
+}
+
 func TestFindProjectRoot(t *testing.T) {
 	// Create temporary directory structure
 	tmpDir := t.TempDir()
@@ -526,6 +829,42 @@ func TestGetRelativeStackPath(t *testing.T) {
 	}
 }
 
+func TestGetRelativeStackPath_Normalization(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectRoot := filepath.Join(tmpDir, "project")
+	stackDir := filepath.Join(projectRoot, "live", "dev")
+	require.NoError(t, os.MkdirAll(stackDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectRoot, "root.hcl"), []byte("# root"), 0644))
+
+	// A symlink to the project root, and a workspace link into a directory below it.
+	linkedRoot := filepath.Join(tmpDir, "linked-project")
+	workspace := filepath.Join(tmpDir, "workspace")
+	if err := os.Symlink(projectRoot, linkedRoot); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	require.NoError(t, os.Symlink(filepath.Join(projectRoot, "live"), workspace))
+
+	sep := string(filepath.Separator)
+	tests := []struct {
+		name         string
+		absolutePath string
+	}{
+		{name: "clean path", absolutePath: stackDir},
+		{name: "trailing slash", absolutePath: stackDir + sep},
+		{name: "dot segments", absolutePath: filepath.Join(projectRoot, "live") + sep + "." + sep + "prod" + sep + ".." + sep + "dev"},
+		{name: "symlinked project root", absolutePath: filepath.Join(linkedRoot, "live", "dev") + sep},
+		{name: "symlink below project root", absolutePath: filepath.Join(workspace, "dev")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relPath, err := GetRelativeStackPath(tt.absolutePath, "root.hcl")
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join("live", "dev"), relPath)
+		})
+	}
+}
+
 func TestFilterHistoryByProject(t *testing.T) {
 	// Create temporary directory structure
 	tmpDir := t.TempDir()
@@ -696,3 +1035,184 @@ func TestDefaultServiceWrappers(t *testing.T) {
 		}
 	})
 }
+
+func TestFilterByProjectRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectA := filepath.Join(tmpDir, "team-a")
+	projectB := filepath.Join(tmpDir, "team-b")
+
+	entries := []ExecutionLogEntry{
+		{ID: 1, AbsolutePath: filepath.Join(projectA, "dev/vpc")},
+		{ID: 2, AbsolutePath: filepath.Join(projectB, "dev/rds")},
+		{ID: 3, AbsolutePath: filepath.Join(tmpDir, "team-a-legacy/dev/vpc")},
+		{ID: 4, AbsolutePath: ""},
+	}
+
+	repo, _ := NewFileRepository("")
+	svc := NewService(repo, "root.hcl")
+
+	filtered := svc.FilterByProjectRoot(entries, projectA)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 1, filtered[0].ID)
+
+	filtered = svc.FilterByProjectRoot(entries, projectB)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, 2, filtered[0].ID)
+
+	assert.Equal(t, entries, svc.FilterByProjectRoot(entries, ""), "empty project root should not filter")
+}
+
+func TestFilterByText(t *testing.T) {
+	entries := []ExecutionLogEntry{
+		{ID: 1, Command: "plan", StackPath: "dev/vpc"},
+		{ID: 2, Command: "apply", StackPath: "prod/vpc", Note: "Prod release"},
+		{ID: 3, Command: "apply", StackPath: "prod/rds", Note: "hotfix"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []int
+	}{
+		{name: "note text ignoring case", query: "prod RELEASE", expected: []int{2}},
+		{name: "note tag", query: "hotfix", expected: []int{3}},
+		{name: "command", query: "apply", expected: []int{2, 3}},
+		{name: "stack path", query: "vpc", expected: []int{1, 2}},
+		{name: "no match", query: "staging", expected: nil},
+		{name: "empty query keeps all", query: "  ", expected: []int{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []int
+			for _, entry := range FilterByText(entries, tt.query) {
+				ids = append(ids, entry.ID)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestLastCommandByStack(t *testing.T) {
+	repo, _ := NewFileRepository("")
+	svc := NewService(repo, "root.hcl")
+
+	// Most recent first, as returned by LoadAll.
+	entries := []ExecutionLogEntry{
+		{ID: 5, AbsolutePath: "/project/dev/rds", Command: "destroy", DryRun: true},
+		{ID: 4, AbsolutePath: "/project/dev/vpc", Command: "apply"},
+		{ID: 3, AbsolutePath: "/project/dev/rds", Command: "validate"},
+		{ID: 2, AbsolutePath: "/project/dev/vpc", Command: "plan"},
+		{ID: 1, AbsolutePath: "", Command: "plan"},
+	}
+
+	assert.Equal(t, map[string]string{
+		"/project/dev/vpc": "apply",
+		"/project/dev/rds": "validate", // The later destroy was a dry run
+	}, svc.LastCommandByStack(entries))
+	assert.Empty(t, svc.LastCommandByStack(nil))
+
+	// Oldest first gives the same result.
+	oldest := SortEntries(append([]ExecutionLogEntry(nil), entries...), OrderOldest)
+	assert.Equal(t, "apply", svc.LastCommandByStack(oldest)["/project/dev/vpc"])
+}
+
+func TestLastArgsByCommand(t *testing.T) {
+	repo, _ := NewFileRepository("")
+	svc := NewService(repo, "root.hcl")
+
+	entries := []ExecutionLogEntry{
+		{ID: 4, Command: "apply"},
+		{ID: 3, Command: "plan", Args: []string{"-var-file=prod.tfvars"}},
+		{ID: 2, Command: "apply", Args: []string{"-target=module.vpc"}},
+		{ID: 1, Command: "plan", Args: []string{"-refresh=false"}},
+	}
+
+	// The latest apply had no args, so its older args are not offered.
+	assert.Equal(t, map[string][]string{
+		"plan": {"-var-file=prod.tfvars"},
+	}, svc.LastArgsByCommand(entries))
+	assert.Empty(t, svc.LastArgsByCommand(nil))
+}
+
+func TestLastRunByStack(t *testing.T) {
+	repo, _ := NewFileRepository("")
+	svc := NewService(repo, "root.hcl")
+
+	// Most recent first, as returned by LoadAll.
+	entries := []ExecutionLogEntry{
+		{ID: 5, AbsolutePath: "/project/dev/rds", Command: "apply", DryRun: true},
+		{ID: 4, AbsolutePath: "/project/dev/vpc", Command: "apply", ExitCode: 1},
+		{ID: 3, AbsolutePath: "/project/dev/rds", Command: "plan", ExitCode: 2},
+		{ID: 2, AbsolutePath: "/project/dev/vpc", Command: "plan", ExitCode: 0},
+		{ID: 1, AbsolutePath: "", Command: "plan"},
+	}
+
+	lastRuns := svc.LastRunByStack(entries)
+	require.Len(t, lastRuns, 2)
+	assert.Equal(t, 4, lastRuns["/project/dev/vpc"].ID)
+	assert.Equal(t, 3, lastRuns["/project/dev/rds"].ID, "dry runs are skipped")
+	assert.Empty(t, svc.LastRunByStack(nil))
+
+	// Oldest first gives the same result.
+	oldest := SortEntries(append([]ExecutionLogEntry(nil), entries...), OrderOldest)
+	assert.Equal(t, 4, svc.LastRunByStack(oldest)["/project/dev/vpc"].ID)
+}
+
+func TestDetectProjectSwitch(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectA := filepath.Join(tmpDir, "project-a")
+	projectB := filepath.Join(tmpDir, "project-b")
+	require.NoError(t, os.MkdirAll(filepath.Join(projectA, "dev/vpc"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(projectB, "prod/rds"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectA, "root.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(projectB, "root.hcl"), []byte(""), 0644))
+
+	repo, _ := NewFileRepository("")
+	svc := NewService(repo, "root.hcl")
+
+	// Most recent first, as returned by LoadAll.
+	entries := []ExecutionLogEntry{
+		{ID: 2, AbsolutePath: filepath.Join(projectA, "dev/vpc")},
+		{ID: 1, AbsolutePath: filepath.Join(projectB, "prod/rds")},
+	}
+
+	tests := []struct {
+		name       string
+		entries    []ExecutionLogEntry
+		currentDir string
+		expected   string
+	}{
+		{name: "switched to another project", entries: entries, currentDir: filepath.Join(projectB, "prod"), expected: projectA},
+		{name: "same project", entries: entries, currentDir: filepath.Join(projectA, "dev"), expected: ""},
+		{name: "current dir outside any project", entries: entries, currentDir: tmpDir, expected: ""},
+		{name: "no history", entries: nil, currentDir: projectB, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, svc.DetectProjectSwitch(tt.entries, tt.currentDir))
+		})
+	}
+}
+
+func TestExecutionLogEntry_HasNoChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    ExecutionLogEntry
+		expected bool
+	}{
+		{name: "no changes line", entry: ExecutionLogEntry{Command: "plan", Summary: NoChangesSummary}, expected: true},
+		{name: "zero plan totals", entry: ExecutionLogEntry{Command: "plan", Summary: "Plan: 0 to add, 0 to change, 0 to destroy."}, expected: true},
+		{name: "zero apply totals", entry: ExecutionLogEntry{Command: "apply", Summary: "Apply complete! Resources: 0 added, 0 changed, 0 destroyed."}, expected: true},
+		{name: "plan with changes", entry: ExecutionLogEntry{Command: "plan", Summary: "Plan: 1 to add, 0 to change, 0 to destroy."}, expected: false},
+		{name: "generic success", entry: ExecutionLogEntry{Command: "plan", Summary: "Command completed successfully."}, expected: false},
+		{name: "failed run", entry: ExecutionLogEntry{Command: "plan", ExitCode: 1, Summary: NoChangesSummary}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.entry.HasNoChanges())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LockFileSuffix is appended to the history file path to name the lock file that
+// serializes writes from concurrent terrax processes.
+const LockFileSuffix = ".lock"
+
+// withLock runs fn while holding an exclusive advisory lock on the lock file next to the
+// history file. The lock is released when fn returns, including on errors.
+func (r *FileRepository) withLock(fn func() error) (err error) {
+	if err := os.MkdirAll(filepath.Dir(r.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	lock, err := os.OpenFile(r.filePath+LockFileSuffix, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history lock file: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, lock.Close())
+	}()
+
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock history file: %w", err)
+	}
+	defer func() {
+		if unlockErr := unlockFile(lock); unlockErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to unlock history file: %w", unlockErr))
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,120 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileRepository_ConcurrentWrites tests that writers with their own repositories, like
+// separate terrax processes, neither lose entries nor reuse IDs while appending and
+// trimming at the same time.
+func TestFileRepository_ConcurrentWrites(t *testing.T) {
+	ctx := context.Background()
+	historyPath := filepath.Join(t.TempDir(), HistoryFileName)
+	const writers, appendsPerWriter = 8, 10
+
+	seed, err := NewFileRepository(historyPath)
+	require.NoError(t, err)
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for id := 1; id <= 5; id++ {
+		_, err := seed.Append(ctx, ExecutionLogEntry{ID: id, Timestamp: old, Command: "plan"})
+		require.NoError(t, err)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo, err := NewFileRepository(historyPath)
+			if !assert.NoError(t, err) {
+				return
+			}
+			for i := 0; i < appendsPerWriter; i++ {
+				id, err := repo.GetNextID(ctx)
+				assert.NoError(t, err)
+				_, err = repo.Append(ctx, ExecutionLogEntry{ID: id, Timestamp: time.Now(), Command: "apply"})
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		repo, err := NewFileRepository(historyPath)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// Rewrites the file over and over while the writers append.
+		for i := 0; i < writers*appendsPerWriter; i++ {
+			id, err := repo.GetNextID(ctx)
+			assert.NoError(t, err)
+			_, err = repo.Append(ctx, ExecutionLogEntry{ID: id, Timestamp: old, Command: "plan"})
+			assert.NoError(t, err)
+			_, err = repo.TrimBefore(ctx, old.Add(time.Hour))
+			assert.NoError(t, err)
+		}
+	}()
+	wg.Wait()
+
+	entries, err := seed.LoadAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, entries, writers*appendsPerWriter, "no appended entry is lost")
+
+	ids := make(map[int]bool)
+	for _, entry := range entries {
+		assert.Equal(t, "apply", entry.Command, "the old entries are trimmed")
+		assert.False(t, ids[entry.ID], "ID %d is used twice", entry.ID)
+		ids[entry.ID] = true
+	}
+}
+
+// TestFileRepository_Append_TakenID tests that an entry whose ID is already taken is
+// written with the next free ID, which Append returns.
+func TestFileRepository_Append_TakenID(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+	require.NoError(t, err)
+
+	id, err := repo.Append(ctx, ExecutionLogEntry{ID: 1, Command: "plan"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	id, err = repo.Append(ctx, ExecutionLogEntry{ID: 1, Command: "apply"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, id)
+
+	entries, err := repo.LoadAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[0].ID)
+	assert.Equal(t, "apply", entries[0].Command)
+}
+
+// TestFileRepository_LockReleasedOnError tests that a failing locked operation does not
+// leave the history locked.
+func TestFileRepository_LockReleasedOnError(t *testing.T) {
+	ctx := context.Background()
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+	require.NoError(t, err)
+
+	assert.Error(t, repo.withLock(func() error { return assert.AnError }))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := repo.Append(ctx, ExecutionLogEntry{ID: 1, Command: "plan"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("append blocked on a lock that should have been released")
+	}
+}
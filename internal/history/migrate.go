@@ -0,0 +1,143 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// BackupSuffix is appended to the history file path for the copy Migrate keeps of the file
+// it rewrites.
+const BackupSuffix = ".bak"
+
+// MigrationResult reports what Migrate did to a history file.
+type MigrationResult struct {
+	Migrated   int    // Entries rewritten in the current format
+	Dropped    int    // Lines that were not history entries (kept only in the backup)
+	BackupPath string // Copy of the original file ("" when there was no file)
+}
+
+// Migrate rewrites the history file in the current entry format while holding the history
+// lock, after copying it to the file path plus BackupSuffix. Entries are read tolerantly:
+// fields missing from older formats get defaults and fields of an unexpected type are left
+// at their zero value. A missing file is not an error and is left missing.
+func (r *FileRepository) Migrate(ctx context.Context) (MigrationResult, error) {
+	var result MigrationResult
+	err := r.withLock(func() (err error) {
+		result, err = r.migrate()
+		return err
+	})
+	return result, err
+}
+
+// migrate rewrites the history file as described for Migrate. Callers hold the lock.
+func (r *FileRepository) migrate() (MigrationResult, error) {
+	filePath := r.filePath
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return MigrationResult{}, nil
+		}
+		return MigrationResult{}, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var result MigrationResult
+	var entries []ExecutionLogEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		entry, ok := decodeEntryTolerant(line)
+		if !ok {
+			result.Dropped++
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var out bytes.Buffer
+	for _, entry := range fillMigrationDefaults(entries) {
+		jsonData, err := json.Marshal(entry)
+		if err != nil {
+			return MigrationResult{}, fmt.Errorf("failed to marshal entry to JSON: %w", err)
+		}
+		out.Write(append(jsonData, '\n'))
+	}
+
+	result.BackupPath = filePath + BackupSuffix
+	if err := os.WriteFile(result.BackupPath, data, 0644); err != nil {
+		return MigrationResult{}, fmt.Errorf("failed to write history backup: %w", err)
+	}
+	if err := replaceFile(filePath, out.Bytes()); err != nil {
+		return MigrationResult{}, err
+	}
+
+	result.Migrated = len(entries)
+	return result, nil
+}
+
+// decodeEntryTolerant parses one history line like decodeEntry, but keeps the entry when
+// some fields have a type the current format does not expect (e.g. an ID written as a
+// string). It reports false for lines that are not JSON objects.
+func decodeEntryTolerant(line []byte) (ExecutionLogEntry, bool) {
+	var entry ExecutionLogEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		// Unmarshal skips fields of the wrong type and fills in the rest.
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return ExecutionLogEntry{}, false
+		}
+	}
+	if entry.AbsolutePath == "" && entry.StackPath != "" {
+		entry.AbsolutePath = entry.StackPath
+	}
+	return entry, true
+}
+
+// fillMigrationDefaults gives entries missing an ID the next free ones, in file order, and
+// records entries without a user as run by UnknownUser.
+func fillMigrationDefaults(entries []ExecutionLogEntry) []ExecutionLogEntry {
+	lastID := 0
+	for _, entry := range entries {
+		lastID = max(lastID, entry.ID)
+	}
+	for i := range entries {
+		if entries[i].ID <= 0 {
+			lastID++
+			entries[i].ID = lastID
+		}
+		if entries[i].User == "" {
+			entries[i].User = UnknownUser
+		}
+	}
+	return entries
+}
+
+// replaceFile writes data to a temporary file next to filePath and renames it over
+// filePath, so an interrupted write never leaves a partial file.
+func replaceFile(filePath string, data []byte) error {
+	tempPath := filePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		_ = os.Remove(tempPath) // Best effort
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := os.Rename(tempPath, filePath); err != nil {
+		// Fallback for Windows or cross-device link errors: remove target first
+		_ = os.Remove(filePath)
+		if renameErr := os.Rename(tempPath, filePath); renameErr != nil {
+			_ = os.Remove(tempPath)
+			return fmt.Errorf("failed to replace %s: %w", filePath, renameErr)
+		}
+	}
+	return nil
+}
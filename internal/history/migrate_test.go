@@ -0,0 +1,68 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRepository_Migrate(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), HistoryFileName)
+	// An old-format file: no absolute_path, user, note or args; one ID written as a string.
+	original := strings.Join([]string{
+		`{"id":1,"timestamp":"2024-01-02T10:00:00Z","stack_path":"/repo/dev/vpc","command":"plan","exit_code":0,"duration_s":1.5}`,
+		`{"id":"2","stack_path":"/repo/dev/rds","command":"apply","exit_code":1}`,
+		`not json`,
+		``,
+	}, "\n")
+	require.NoError(t, os.WriteFile(filePath, []byte(original), 0644))
+
+	repo, err := NewFileRepository(filePath)
+	require.NoError(t, err)
+	result, err := repo.Migrate(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, MigrationResult{Migrated: 2, Dropped: 1, BackupPath: filePath + BackupSuffix}, result)
+
+	backup, err := os.ReadFile(result.BackupPath)
+	require.NoError(t, err)
+	assert.Equal(t, original, string(backup), "the backup holds the original file")
+
+	entries, err := repo.LoadAll(t.Context())
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// Most recent first.
+	assert.Equal(t, 2, entries[0].ID, "an unreadable ID gets the next free one")
+	assert.Equal(t, "/repo/dev/rds", entries[0].AbsolutePath)
+	assert.Equal(t, "apply", entries[0].Command)
+	assert.Equal(t, 1, entries[0].ExitCode)
+	assert.Equal(t, UnknownUser, entries[0].User)
+
+	assert.Equal(t, 1, entries[1].ID)
+	assert.Equal(t, "/repo/dev/vpc", entries[1].AbsolutePath)
+	assert.Equal(t, 1.5, entries[1].DurationS)
+	assert.Empty(t, entries[1].Args)
+
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		assert.Contains(t, line, `"absolute_path":`, "entries are written in the current format")
+		assert.Contains(t, line, `"note":`)
+	}
+}
+
+func TestFileRepository_Migrate_MissingFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), HistoryFileName)
+	repo, err := NewFileRepository(filePath)
+	require.NoError(t, err)
+
+	result, err := repo.Migrate(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, MigrationResult{}, result)
+	assert.NoFileExists(t, filePath)
+	assert.NoFileExists(t, filePath+BackupSuffix)
+}
@@ -1,6 +1,7 @@
 package history
 
 import (
+	"slices"
 	"time"
 )
 
@@ -16,4 +17,45 @@ type ExecutionLogEntry struct {
 	ExitCode     int       `json:"exit_code"`     // Process exit code (0 = success)
 	DurationS    float64   `json:"duration_s"`    // Execution duration in seconds
 	Summary      string    `json:"summary"`       // Brief result summary (e.g., "3 added, 0 changed")
+	LogPath      string    `json:"log_path"`      // File holding the run's output ("" unless run_logs.enabled)
+	Note         string    `json:"note"`          // User note or tag given with --note (e.g. "prod release")
+
+	Args    []string      `json:"args,omitempty"`    // Extra args passed after the command (e.g. -target=...)
+	Changes *ChangeCounts `json:"changes,omitempty"` // Resource counts parsed from the output (nil = none reported)
+	DryRun  bool          `json:"dry_run,omitempty"` // The command was printed with --dry-run instead of run
+}
+
+// ChangeCounts is the number of resources a plan or apply added, changed and destroyed,
+// totaled across every stack in the run. A destroy run only reports Destroy.
+type ChangeCounts struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+// IsZero reports whether the counts report no changes at all.
+func (c ChangeCounts) IsZero() bool {
+	return c == ChangeCounts{}
+}
+
+// NoChangesSummary is the summary recorded when Terraform output reported "No changes.".
+const NoChangesSummary = "No changes."
+
+// noChangesSummaries are the change summaries that report nothing to change: the explicit
+// "No changes." line, and change reports whose totals are all zero.
+var noChangesSummaries = []string{
+	NoChangesSummary,
+	"Plan: 0 to add, 0 to change, 0 to destroy.",
+	"Apply complete! Resources: 0 added, 0 changed, 0 destroyed.",
+}
+
+// IsNoChangesSummary reports whether summary is a change summary reporting no changes.
+func IsNoChangesSummary(summary string) bool {
+	return slices.Contains(noChangesSummaries, summary)
+}
+
+// HasNoChanges reports whether the entry is a successful run whose output reported no
+// changes, as opposed to a success that changed (or planned to change) resources.
+func (e ExecutionLogEntry) HasNoChanges() bool {
+	return e.ExitCode == 0 && IsNoChangesSummary(e.Summary)
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/adrg/xdg"
 )
@@ -21,14 +22,22 @@ const (
 
 // Repository defines the interface for history persistence.
 type Repository interface {
-	// Append adds an entry to the history.
-	Append(ctx context.Context, entry ExecutionLogEntry) error
+	// Append adds an entry to the history and returns the ID it was written with.
+	Append(ctx context.Context, entry ExecutionLogEntry) (int, error)
 	// LoadAll returns all history entries sorted by most recent first.
 	LoadAll(ctx context.Context) ([]ExecutionLogEntry, error)
-	// Trim retains only the most recent maxEntries.
-	Trim(ctx context.Context, maxEntries int) error
+	// LoadLast returns up to n of the most recent entries, most recent first.
+	LoadLast(ctx context.Context, n int) ([]ExecutionLogEntry, error)
+	// Stream calls fn for each entry, most recent first, stopping at the first error.
+	Stream(ctx context.Context, fn func(ExecutionLogEntry) error) error
+	// Trim retains only the most recent maxEntries and returns the entries it dropped.
+	Trim(ctx context.Context, maxEntries int) ([]ExecutionLogEntry, error)
+	// TrimBefore drops the entries recorded before cutoff and returns them.
+	TrimBefore(ctx context.Context, cutoff time.Time) ([]ExecutionLogEntry, error)
 	// GetNextID returns the next available ID for a new entry.
 	GetNextID(ctx context.Context) (int, error)
+	// Size returns the size of the stored history in bytes.
+	Size(ctx context.Context) (int64, error)
 }
 
 // FileRepository implements Repository using a JSONL file.
@@ -49,8 +58,25 @@ func NewFileRepository(filePath string) (*FileRepository, error) {
 	return &FileRepository{filePath: filePath}, nil
 }
 
-// Append adds an entry to the history file.
-func (r *FileRepository) Append(ctx context.Context, entry ExecutionLogEntry) (err error) {
+// Append adds an entry to the history file while holding the history lock and returns the
+// ID it was written with. An entry whose ID is already taken (another process appended
+// since the ID was obtained) gets the next free ID instead.
+func (r *FileRepository) Append(ctx context.Context, entry ExecutionLogEntry) (int, error) {
+	err := r.withLock(func() error {
+		ids, lastID, err := r.usedIDs()
+		if err != nil {
+			return err
+		}
+		if entry.ID > 0 && ids[entry.ID] {
+			entry.ID = lastID + 1
+		}
+		return r.appendEntry(entry)
+	})
+	return entry.ID, err
+}
+
+// appendEntry writes entry as the last line of the history file.
+func (r *FileRepository) appendEntry(entry ExecutionLogEntry) (err error) {
 	// 0644 = rw-r--r-- (owner can read/write, others can read)
 	file, err := os.OpenFile(r.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
@@ -90,21 +116,16 @@ func (r *FileRepository) LoadAll(ctx context.Context) (_ []ExecutionLogEntry, er
 	scanner := bufio.NewScanner(file)
 
 	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+		line := scanner.Bytes()
+		if len(line) == 0 {
 			continue
 		}
 
-		var entry ExecutionLogEntry
-		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		entry, ok := decodeEntry(line)
+		if !ok {
 			continue
 		}
 
-		// Handle backward compatibility: if AbsolutePath is empty, use StackPath
-		if entry.AbsolutePath == "" && entry.StackPath != "" {
-			entry.AbsolutePath = entry.StackPath
-		}
-
 		entries = append(entries, entry)
 	}
 
@@ -119,19 +140,134 @@ func (r *FileRepository) LoadAll(ctx context.Context) (_ []ExecutionLogEntry, er
 	return entries, nil
 }
 
-// Trim retains only the most recent maxEntries.
-func (r *FileRepository) Trim(ctx context.Context, maxEntries int) error {
-	if maxEntries <= 0 {
-		return fmt.Errorf("maxEntries must be positive, got: %d", maxEntries)
+// errStopStream ends a Stream early without reporting an error.
+var errStopStream = errors.New("stop stream")
+
+// LoadLast returns up to n of the most recent entries, most recent first.
+// Only the tail of the file holding those entries is read.
+func (r *FileRepository) LoadLast(ctx context.Context, n int) ([]ExecutionLogEntry, error) {
+	entries := []ExecutionLogEntry{}
+	if n <= 0 {
+		return entries, nil
+	}
+
+	err := r.Stream(ctx, func(entry ExecutionLogEntry) error {
+		entries = append(entries, entry)
+		if len(entries) >= n {
+			return errStopStream
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return entries, nil
+}
 
+// Stream calls fn for each entry, most recent first, reading the file backwards so
+// entries are never all held in memory. It stops at the first error returned by fn
+// or when ctx is cancelled. A missing file streams no entries.
+func (r *FileRepository) Stream(ctx context.Context, fn func(ExecutionLogEntry) error) (err error) {
 	file, err := os.Open(r.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return nil // Nothing to trim
+			return nil
 		}
 		return fmt.Errorf("failed to open history file: %w", err)
 	}
+	defer func() {
+		err = errors.Join(err, file.Close())
+	}()
+
+	var streamErr error
+	readErr := readLinesReverse(file, func(line []byte) bool {
+		if streamErr = ctx.Err(); streamErr != nil {
+			return false
+		}
+		entry, ok := decodeEntry(line)
+		if !ok {
+			return true
+		}
+		streamErr = fn(entry)
+		return streamErr == nil
+	})
+	if errors.Is(streamErr, errStopStream) {
+		streamErr = nil
+	}
+	return errors.Join(readErr, streamErr)
+}
+
+// Trim retains only the most recent maxEntries and returns the entries it dropped, oldest
+// first, read under the same lock as the rewrite. Dropped lines that cannot be decoded are
+// not returned.
+func (r *FileRepository) Trim(ctx context.Context, maxEntries int) ([]ExecutionLogEntry, error) {
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("maxEntries must be positive, got: %d", maxEntries)
+	}
+
+	var dropped []ExecutionLogEntry
+	err := r.withLock(func() error {
+		lines, err := r.readLines()
+		if err != nil || len(lines) <= maxEntries {
+			return err // Nothing to trim
+		}
+		cut := len(lines) - maxEntries
+		if err := r.rewrite(lines[cut:]); err != nil {
+			return err
+		}
+		for _, line := range lines[:cut] {
+			var entry ExecutionLogEntry
+			if err := json.Unmarshal([]byte(line), &entry); err == nil {
+				dropped = append(dropped, entry)
+			}
+		}
+		return nil
+	})
+	return dropped, err
+}
+
+// TrimBefore drops the entries whose timestamp is before cutoff and returns them, oldest
+// first. Lines that cannot be decoded are kept, so a trim never loses data it does not
+// understand.
+func (r *FileRepository) TrimBefore(ctx context.Context, cutoff time.Time) ([]ExecutionLogEntry, error) {
+	var dropped []ExecutionLogEntry
+	err := r.withLock(func() error {
+		lines, err := r.readLines()
+		if err != nil {
+			return err
+		}
+
+		kept := make([]string, 0, len(lines))
+		var trimmed []ExecutionLogEntry
+		for _, line := range lines {
+			var entry ExecutionLogEntry
+			if err := json.Unmarshal([]byte(line), &entry); err == nil && entry.Timestamp.Before(cutoff) {
+				trimmed = append(trimmed, entry)
+				continue
+			}
+			kept = append(kept, line)
+		}
+		if len(trimmed) == 0 {
+			return nil // No trimming needed
+		}
+		if err := r.rewrite(kept); err != nil {
+			return err
+		}
+		dropped = trimmed
+		return nil
+	})
+	return dropped, err
+}
+
+// readLines returns the lines of the history file, oldest first. A missing file has none.
+func (r *FileRepository) readLines() ([]string, error) {
+	file, err := os.Open(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
 
 	var lines []string
 	scanner := bufio.NewScanner(file)
@@ -140,19 +276,18 @@ func (r *FileRepository) Trim(ctx context.Context, maxEntries int) error {
 	}
 	// Explicit close after reading to ensure no lock issues during rename later (if relevant)
 	if err := file.Close(); err != nil {
-		return fmt.Errorf("failed to close read handle: %w", err)
+		return nil, fmt.Errorf("failed to close read handle: %w", err)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("failed to read history file: %w", err)
+		return nil, fmt.Errorf("failed to read history file: %w", err)
 	}
+	return lines, nil
+}
 
-	if len(lines) <= maxEntries {
-		return nil // No trimming needed
-	}
-
-	trimmedLines := lines[len(lines)-maxEntries:]
-
+// rewrite atomically replaces the history file with lines by writing them to a temp file
+// and renaming it over the original.
+func (r *FileRepository) rewrite(lines []string) error {
 	tempPath := r.filePath + ".tmp"
 	tempFile, err := os.Create(tempPath)
 	if err != nil {
@@ -166,7 +301,7 @@ func (r *FileRepository) Trim(ctx context.Context, maxEntries int) error {
 	}
 
 	writer := bufio.NewWriter(tempFile)
-	for _, line := range trimmedLines {
+	for _, line := range lines {
 		if _, err := writer.WriteString(line + "\n"); err != nil {
 			cleanup()
 			return fmt.Errorf("failed to write to temp file: %w", err)
@@ -195,36 +330,74 @@ func (r *FileRepository) Trim(ctx context.Context, maxEntries int) error {
 	return nil
 }
 
-// GetNextID returns the next available ID.
-func (r *FileRepository) GetNextID(ctx context.Context) (_ int, err error) {
+// GetNextID returns the next available ID, read under the history lock so it never
+// observes a half-finished write.
+func (r *FileRepository) GetNextID(ctx context.Context) (int, error) {
+	var id int
+	err := r.withLock(func() (err error) {
+		id, err = r.nextID()
+		return err
+	})
+	return id, err
+}
+
+// nextID returns one past the highest ID in the history file. Callers hold the lock.
+func (r *FileRepository) nextID() (int, error) {
+	_, lastID, err := r.usedIDs()
+	return lastID + 1, err
+}
+
+// usedIDs returns the IDs in the history file and the highest of them. Callers hold the lock.
+func (r *FileRepository) usedIDs() (_ map[int]bool, lastID int, err error) {
+	ids := make(map[int]bool)
 	file, err := os.Open(r.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return 1, nil
+			return ids, 0, nil
 		}
-		return 0, fmt.Errorf("failed to open history file: %w", err)
+		return nil, 0, fmt.Errorf("failed to open history file: %w", err)
 	}
 	defer func() {
 		err = errors.Join(err, file.Close())
 	}()
 
-	var lastID int
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		var entry ExecutionLogEntry
 		if err := json.Unmarshal([]byte(scanner.Text()), &entry); err == nil {
+			ids[entry.ID] = true
 			if entry.ID > lastID {
 				lastID = entry.ID
 			}
 		}
 	}
 
-	return lastID + 1, nil
+	return ids, lastID, nil
 }
 
-// GetDefaultHistoryFilePath returns the standard XDG path for the history file.
+// Size returns the size of the history file in bytes, or 0 when it does not exist yet.
+func (r *FileRepository) Size(ctx context.Context) (int64, error) {
+	info, err := os.Stat(r.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to stat history file: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// GetDefaultHistoryFilePath returns the standard XDG path for the history file:
+// $XDG_DATA_HOME/terrax when XDG_DATA_HOME is set, otherwise the terrax directory in the
+// user config directory (e.g. ~/.config/terrax on Linux). A history file already kept in
+// the latter stays in use, so setting XDG_DATA_HOME later does not hide past runs.
 func GetDefaultHistoryFilePath() (string, error) {
 	configDir := filepath.Join(xdg.ConfigHome, ConfigDirName)
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		if _, err := os.Stat(filepath.Join(configDir, HistoryFileName)); os.IsNotExist(err) {
+			configDir = filepath.Join(dataHome, ConfigDirName)
+		}
+	}
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SelectionFileName is the file next to the history file holding the last selection made
+// in each project.
+const SelectionFileName = "selections.json"
+
+// LastSelection is what was focused when the TUI last closed in a project.
+type LastSelection struct {
+	Command   string `json:"command"`    // Selected command
+	StackPath string `json:"stack_path"` // Absolute path of the focused stack
+}
+
+// GetSelectionFilePath returns the path of the last selection file, next to the history file.
+func GetSelectionFilePath() (string, error) {
+	historyPath, err := GetHistoryFilePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(historyPath), SelectionFileName), nil
+}
+
+// LoadLastSelection returns the selection saved for projectRoot in filePath. It reports
+// false when the file does not exist or holds nothing for the project.
+func LoadLastSelection(filePath, projectRoot string) (LastSelection, bool, error) {
+	selections, err := readSelections(filePath)
+	if err != nil {
+		return LastSelection{}, false, err
+	}
+	selection, ok := selections[projectRoot]
+	return selection, ok, nil
+}
+
+// SaveLastSelection records selection for projectRoot in filePath, keeping the selections
+// saved for other projects.
+func SaveLastSelection(filePath, projectRoot string, selection LastSelection) error {
+	selections, err := readSelections(filePath)
+	if err != nil {
+		return err
+	}
+	selections[projectRoot] = selection
+
+	data, err := json.MarshalIndent(selections, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode selections: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create selections directory: %w", err)
+	}
+	return replaceFile(filePath, data)
+}
+
+// readSelections reads the selections in filePath keyed by project root. A missing file
+// yields no selections.
+func readSelections(filePath string) (map[string]LastSelection, error) {
+	selections := map[string]LastSelection{}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return selections, nil
+		}
+		return nil, fmt.Errorf("failed to read selections file: %w", err)
+	}
+	if err := json.Unmarshal(data, &selections); err != nil {
+		return nil, fmt.Errorf("failed to parse selections file: %w", err)
+	}
+	return selections, nil
+}
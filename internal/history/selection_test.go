@@ -0,0 +1,40 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastSelection(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "state", SelectionFileName)
+
+	_, ok, err := LoadLastSelection(filePath, "/repo")
+	require.NoError(t, err)
+	assert.False(t, ok, "nothing is saved before the file exists")
+
+	require.NoError(t, SaveLastSelection(filePath, "/repo", LastSelection{Command: "apply", StackPath: "/repo/dev/vpc"}))
+	require.NoError(t, SaveLastSelection(filePath, "/other", LastSelection{Command: "plan", StackPath: "/other/rds"}))
+	require.NoError(t, SaveLastSelection(filePath, "/repo", LastSelection{Command: "init", StackPath: "/repo/dev/rds"}))
+
+	selection, ok, err := LoadLastSelection(filePath, "/repo")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, LastSelection{Command: "init", StackPath: "/repo/dev/rds"}, selection, "saving again replaces the project's selection")
+
+	selection, ok, err = LoadLastSelection(filePath, "/other")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "/other/rds", selection.StackPath, "other projects are kept")
+
+	_, ok, err = LoadLastSelection(filePath, "/unknown")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, os.WriteFile(filePath, []byte("not json"), 0644))
+	_, _, err = LoadLastSelection(filePath, "/repo")
+	assert.ErrorContains(t, err, "failed to parse selections file")
+}
@@ -6,12 +6,36 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/israoo/terrax/internal/clock"
+)
+
+// Orders in which history entries can be loaded.
+const (
+	OrderNewest = "newest" // Highest ID (most recent) first.
+	OrderOldest = "oldest" // Lowest ID (oldest) first.
 )
 
 // Service handles business logic for execution history.
 type Service struct {
 	repo           Repository
 	rootConfigFile string
+	order          string      // Order returned by LoadAll ("" = OrderNewest)
+	clock          clock.Clock // Source of the current time for age-based trimming
+
+	trimPolicy   TrimPolicy // When TrimHistory actually trims (zero value = every call)
+	lastAppendID int        // ID the last entry appended through this service was written with
+}
+
+// TrimPolicy limits how often the history is trimmed, so a full history is not rewritten
+// on every append. Trimming happens when either condition holds; the zero value trims on
+// every append.
+type TrimPolicy struct {
+	Every    int   // Trim on every Every-th append, counted by entry ID (0 = no count condition)
+	MaxBytes int64 // Trim once the history exceeds this many bytes (0 = no size condition)
 }
 
 // NewService creates a new history service.
@@ -19,48 +43,220 @@ func NewService(repo Repository, rootConfigFile string) *Service {
 	return &Service{
 		repo:           repo,
 		rootConfigFile: rootConfigFile,
+		clock:          clock.Real{},
 	}
 }
 
+// SetClock sets the clock TrimHistoryByAge measures entry ages against.
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // Append adds a new execution entry to the history.
 func (s *Service) Append(ctx context.Context, entry ExecutionLogEntry) error {
-	return s.repo.Append(ctx, entry)
+	id, err := s.repo.Append(ctx, entry)
+	if err != nil {
+		return err
+	}
+	s.lastAppendID = id
+	return nil
 }
 
-// LoadAll returns all history entries sorted by most recent first.
-func (s *Service) LoadAll(ctx context.Context) ([]ExecutionLogEntry, error) {
-	return s.repo.LoadAll(ctx)
+// SetTrimPolicy sets when TrimHistory trims the history.
+func (s *Service) SetTrimPolicy(policy TrimPolicy) {
+	s.trimPolicy = policy
 }
 
-// GetLastExecutionForProject returns the most recent execution entry for the current project.
-func (s *Service) GetLastExecutionForProject(ctx context.Context) (*ExecutionLogEntry, error) {
+// trimDue reports whether the trim policy allows trimming after the last append.
+// Entry IDs increase by one per append, so they count appends across runs.
+func (s *Service) trimDue(ctx context.Context) (bool, error) {
+	policy := s.trimPolicy
+	if policy.Every <= 0 && policy.MaxBytes <= 0 {
+		return true, nil
+	}
+	if policy.Every > 0 && s.lastAppendID%policy.Every == 0 {
+		return true, nil
+	}
+	if policy.MaxBytes <= 0 {
+		return false, nil
+	}
+	size, err := s.repo.Size(ctx)
+	if err != nil {
+		return false, err
+	}
+	return size > policy.MaxBytes, nil
+}
+
+// SetOrder sets the order LoadAll returns entries in (OrderNewest or OrderOldest).
+// Unknown values fall back to OrderNewest.
+func (s *Service) SetOrder(order string) {
+	s.order = order
+}
+
+// LoadAll returns all history entries in the configured order (most recent first by default).
+func (s *Service) LoadAll(ctx context.Context) ([]ExecutionLogEntry, error) {
 	entries, err := s.repo.LoadAll(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return SortEntries(entries, s.order), nil
+}
+
+// LoadRecent returns up to n of the most recent history entries in the configured order,
+// reading only the tail of the history. A non-positive n loads every entry.
+func (s *Service) LoadRecent(ctx context.Context, n int) ([]ExecutionLogEntry, error) {
+	if n <= 0 {
+		return s.LoadAll(ctx)
+	}
+	entries, err := s.repo.LoadLast(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return SortEntries(entries, s.order), nil
+}
+
+// Stream calls fn for each history entry in the configured order, stopping at the first
+// error. Most-recent-first order streams from the repository without loading every entry;
+// OrderOldest has to load them all to sort them.
+func (s *Service) Stream(ctx context.Context, fn func(ExecutionLogEntry) error) error {
+	if s.order != OrderOldest {
+		return s.repo.Stream(ctx, fn)
+	}
+
+	entries, err := s.LoadAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortEntries orders entries by ID in place, highest first for OrderNewest (and unknown
+// orders) or lowest first for OrderOldest, and returns them. Entries with the same ID
+// keep their relative order.
+func SortEntries(entries []ExecutionLogEntry, order string) []ExecutionLogEntry {
+	if order == OrderOldest {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	} else {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	}
+	return entries
+}
+
+// newestFirst returns a copy of entries sorted most recent first, leaving entries untouched.
+func newestFirst(entries []ExecutionLogEntry) []ExecutionLogEntry {
+	return SortEntries(append([]ExecutionLogEntry(nil), entries...), OrderNewest)
+}
+
+// GetLastExecutionForProject returns the most recent entry run in the project holding the
+// current directory, or in any project when the directory is outside one. Dry runs are skipped.
+func (s *Service) GetLastExecutionForProject(ctx context.Context) (*ExecutionLogEntry, error) {
+	projectRoot := ""
+	if currentDir, err := os.Getwd(); err == nil {
+		projectRoot, _ = FindProjectRoot(currentDir, s.rootConfigFile)
+	}
+	return s.GetLastExecutionInProject(ctx, projectRoot)
+}
 
-	filtered, err := s.FilterByCurrentProject(entries)
+// GetLastExecutionInProject returns the most recent entry run in a stack under projectRoot,
+// or in any stack when projectRoot is empty, or nil when there is none. Dry runs are skipped.
+// The history is streamed from its end, so older entries are not read once one matches.
+func (s *Service) GetLastExecutionInProject(ctx context.Context, projectRoot string) (*ExecutionLogEntry, error) {
+	var last *ExecutionLogEntry
+	err := s.repo.Stream(ctx, func(entry ExecutionLogEntry) error {
+		if entry.DryRun || (projectRoot != "" && !InProject(entry, projectRoot)) {
+			return nil
+		}
+		last = &entry
+		return errStopStream
+	})
 	if err != nil {
-		// Log error but attempt to use unfiltered? Or fail?
-		// For safety, let's just return what we have if filtering fails strictly,
-		// but typically we'd want strictly filtered or nothing.
-		// Let's stick to previous behavior: fallback to all entries is too broad,
-		// but previous implementation did fallback.
-		// "If filtering fails, fall back to unfiltered entries" (from previous Code).
-		filtered = entries
+		return nil, err
+	}
+	return last, nil
+}
+
+// DetectProjectSwitch compares the project root of currentDir with the project root of
+// the most recent entry in entries (unfiltered, in any order).
+// It returns the previous project root when the two differ, or an empty string when they
+// match or either cannot be resolved.
+func (s *Service) DetectProjectSwitch(entries []ExecutionLogEntry, currentDir string) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	entries = newestFirst(entries)
+	if entries[0].AbsolutePath == "" {
+		return ""
 	}
 
-	if len(filtered) == 0 {
-		return nil, nil
+	currentRoot, err := FindProjectRoot(currentDir, s.rootConfigFile)
+	if err != nil || currentRoot == "" {
+		return ""
+	}
+	previousRoot, err := FindProjectRoot(entries[0].AbsolutePath, s.rootConfigFile)
+	if err != nil || previousRoot == "" {
+		return ""
 	}
 
-	// entries are already sorted by repository LoadAll (most recent first)
-	return &filtered[0], nil
+	if previousRoot == currentRoot {
+		return ""
+	}
+	return previousRoot
 }
 
-// TrimHistory trims the history to the specified number of entries.
+// TrimHistory trims the history to the specified number of entries and deletes the
+// output logs of the entries it drops. It does nothing until the trim policy is due.
 func (s *Service) TrimHistory(ctx context.Context, maxEntries int) error {
-	return s.repo.Trim(ctx, maxEntries)
+	due, err := s.trimDue(ctx)
+	if err != nil || !due {
+		return err
+	}
+
+	dropped, err := s.repo.Trim(ctx, maxEntries)
+	if err != nil {
+		return err
+	}
+	return removeRunLogs(dropped)
+}
+
+// TrimHistoryByAge drops the entries recorded more than maxAge ago and deletes their
+// output logs. A non-positive maxAge keeps every entry. Like TrimHistory, it does nothing
+// until the trim policy is due.
+func (s *Service) TrimHistoryByAge(ctx context.Context, maxAge time.Duration) error {
+	if maxAge <= 0 {
+		return nil
+	}
+	due, err := s.trimDue(ctx)
+	if err != nil || !due {
+		return err
+	}
+
+	now := time.Now()
+	if s.clock != nil {
+		now = s.clock.Now()
+	}
+	dropped, err := s.repo.TrimBefore(ctx, now.Add(-maxAge))
+	if err != nil {
+		return err
+	}
+	return removeRunLogs(dropped)
+}
+
+// removeRunLogs deletes the output logs of entries dropped from the history.
+func removeRunLogs(entries []ExecutionLogEntry) error {
+	for _, entry := range entries {
+		if entry.LogPath == "" {
+			continue
+		}
+		if err := os.Remove(entry.LogPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove run log: %w", err)
+		}
+	}
+	return nil
 }
 
 // GetNextID returns the next ID from the repository.
@@ -80,44 +276,143 @@ func (s *Service) FilterByCurrentProject(entries []ExecutionLogEntry) ([]Executi
 		return entries, nil
 	}
 
+	return s.FilterByProjectRoot(entries, projectRoot), nil
+}
+
+// FilterByProjectRoot filters entries executed in stacks under projectRoot.
+// An empty projectRoot returns entries unchanged.
+func (s *Service) FilterByProjectRoot(entries []ExecutionLogEntry, projectRoot string) []ExecutionLogEntry {
+	if projectRoot == "" {
+		return entries
+	}
+
 	var filtered []ExecutionLogEntry
 	for _, entry := range entries {
-		if entry.AbsolutePath == "" {
-			continue
+		if InProject(entry, projectRoot) {
+			filtered = append(filtered, entry)
 		}
-		if hasPrefix(entry.AbsolutePath, projectRoot) {
+	}
+	return filtered
+}
+
+// InProject reports whether entry was executed in a stack under projectRoot.
+func InProject(entry ExecutionLogEntry, projectRoot string) bool {
+	return entry.AbsolutePath != "" && hasPrefix(entry.AbsolutePath, projectRoot)
+}
+
+// FilterByText returns the entries whose note, command or stack path contains query,
+// ignoring case. An empty query returns entries unchanged.
+func FilterByText(entries []ExecutionLogEntry, query string) []ExecutionLogEntry {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return entries
+	}
+
+	var filtered []ExecutionLogEntry
+	for _, entry := range entries {
+		if MatchesText(entry, query) {
 			filtered = append(filtered, entry)
 		}
 	}
-	return filtered, nil
+	return filtered
 }
 
-// GetRelativeStackPath calculates the relative path from the project root to the stack path.
-func GetRelativeStackPath(absolutePath, rootConfigFile string) (string, error) {
-	absPath, err := filepath.Abs(absolutePath)
-	if err != nil {
-		return absolutePath, err
+// MatchesText reports whether entry's note, command or stack path contains query,
+// ignoring case. An empty query matches every entry.
+func MatchesText(entry ExecutionLogEntry, query string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return true
 	}
+	for _, field := range []string{entry.Note, entry.Command, entry.StackPath} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
 
-	projectRoot, err := FindProjectRoot(absPath, rootConfigFile)
-	if err != nil {
-		return absolutePath, err
+// LastCommandByStack maps each absolute stack path to the command most recently run against it.
+// Dry runs are skipped. entries may be in any order.
+func (s *Service) LastCommandByStack(entries []ExecutionLogEntry) map[string]string {
+	lastCommands := make(map[string]string)
+	for _, entry := range newestFirst(entries) {
+		if entry.AbsolutePath == "" || entry.Command == "" || entry.DryRun {
+			continue
+		}
+		if _, seen := lastCommands[entry.AbsolutePath]; !seen {
+			lastCommands[entry.AbsolutePath] = entry.Command
+		}
 	}
+	return lastCommands
+}
 
-	if projectRoot == "" {
-		return absolutePath, nil
+// LastArgsByCommand maps each command to the extra args of its most recent run. Commands
+// last run without extra args are left out. entries may be in any order.
+func (s *Service) LastArgsByCommand(entries []ExecutionLogEntry) map[string][]string {
+	lastArgs := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, entry := range newestFirst(entries) {
+		if entry.Command == "" || seen[entry.Command] {
+			continue
+		}
+		seen[entry.Command] = true
+		if len(entry.Args) > 0 {
+			lastArgs[entry.Command] = entry.Args
+		}
+	}
+	return lastArgs
+}
+
+// LastRunByStack maps each absolute stack path to the entry of the run most recently recorded
+// against it. Dry runs are skipped. entries may be in any order.
+func (s *Service) LastRunByStack(entries []ExecutionLogEntry) map[string]ExecutionLogEntry {
+	lastRuns := make(map[string]ExecutionLogEntry)
+	for _, entry := range newestFirst(entries) {
+		if entry.AbsolutePath == "" || entry.DryRun {
+			continue
+		}
+		if _, seen := lastRuns[entry.AbsolutePath]; !seen {
+			lastRuns[entry.AbsolutePath] = entry
+		}
 	}
+	return lastRuns
+}
 
-	relPath, err := filepath.Rel(projectRoot, absPath)
+// GetRelativeStackPath calculates the relative path from the project root to the stack path.
+// The input is normalized first (trailing slashes, "." and ".." segments). When no project
+// root encloses the path as given, its symlink-resolved form is tried as well, so a stack
+// reached through a symlinked directory records the same StackPath as its real location.
+func GetRelativeStackPath(absolutePath, rootConfigFile string) (string, error) {
+	absPath, err := filepath.Abs(absolutePath)
 	if err != nil {
 		return absolutePath, err
 	}
 
-	if len(relPath) >= 2 && relPath[0:2] == ".." {
-		return absolutePath, nil
+	candidates := []string{absPath}
+	if realPath, err := filepath.EvalSymlinks(absPath); err == nil && realPath != absPath {
+		candidates = append(candidates, realPath)
+	}
+
+	for _, candidate := range candidates {
+		projectRoot, err := FindProjectRoot(candidate, rootConfigFile)
+		if err != nil {
+			return absolutePath, err
+		}
+		if projectRoot == "" {
+			continue
+		}
+
+		relPath, err := filepath.Rel(projectRoot, candidate)
+		if err != nil {
+			return absolutePath, err
+		}
+		if relPath != ".." && !strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+			return relPath, nil
+		}
 	}
 
-	return relPath, nil
+	return absPath, nil
 }
 
 // FindProjectRoot searches for the project root by looking for the root config file.
@@ -146,11 +441,14 @@ func FindProjectRoot(startPath, rootConfigFile string) (string, error) {
 	}
 }
 
+// UnknownUser is recorded as the user when the OS username cannot be determined.
+const UnknownUser = "unknown"
+
 // GetCurrentUser returns the current OS username.
 func GetCurrentUser() string {
 	currentUser, err := user.Current()
 	if err != nil {
-		return "unknown"
+		return UnknownUser
 	}
 	return currentUser.Username
 }
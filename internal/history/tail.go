@@ -0,0 +1,65 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// tailChunkSize is the number of bytes readLinesReverse reads per step.
+// It is a variable so tests can force lines across chunk boundaries.
+var tailChunkSize int64 = 64 * 1024
+
+// readLinesReverse calls fn for each non-empty line of file, last line first, until fn
+// returns false. The file is read backwards in chunks of tailChunkSize, so stopping
+// early only reads the tail of the file.
+func readLinesReverse(file *os.File, fn func(line []byte) bool) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat history file: %w", err)
+	}
+
+	pos := info.Size()
+	var carry []byte // Start of a line whose beginning lies in an earlier chunk.
+	for pos > 0 {
+		n := min(tailChunkSize, pos)
+		pos -= n
+
+		buf := make([]byte, int(n)+len(carry))
+		if _, err := file.ReadAt(buf[:n], pos); err != nil {
+			return fmt.Errorf("failed to read history file: %w", err)
+		}
+		copy(buf[n:], carry)
+
+		end := len(buf)
+		for i := len(buf) - 1; i >= 0; i-- {
+			if buf[i] != '\n' {
+				continue
+			}
+			if line := bytes.TrimSpace(buf[i+1 : end]); len(line) > 0 && !fn(line) {
+				return nil
+			}
+			end = i
+		}
+		carry = buf[:end]
+	}
+
+	if line := bytes.TrimSpace(carry); len(line) > 0 {
+		fn(line)
+	}
+	return nil
+}
+
+// decodeEntry parses one history line, filling AbsolutePath from StackPath for entries
+// written before AbsolutePath existed. It reports false for lines that are not valid entries.
+func decodeEntry(line []byte) (ExecutionLogEntry, bool) {
+	var entry ExecutionLogEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return entry, false
+	}
+	if entry.AbsolutePath == "" && entry.StackPath != "" {
+		entry.AbsolutePath = entry.StackPath
+	}
+	return entry, true
+}
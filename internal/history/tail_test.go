@@ -0,0 +1,145 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setTailChunkSize overrides tailChunkSize for the duration of a test.
+func setTailChunkSize(t *testing.T, size int64) {
+	t.Helper()
+	original := tailChunkSize
+	tailChunkSize = size
+	t.Cleanup(func() { tailChunkSize = original })
+}
+
+// newTailRepo writes entries with IDs 1..count to a fresh history file.
+func newTailRepo(t *testing.T, count int) *FileRepository {
+	t.Helper()
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), HistoryFileName))
+	require.NoError(t, err)
+	for id := 1; id <= count; id++ {
+		_, err := repo.Append(context.Background(), ExecutionLogEntry{ID: id, Command: "plan", StackPath: "dev/vpc"})
+		require.NoError(t, err)
+	}
+	return repo
+}
+
+func entryIDs(entries []ExecutionLogEntry) []int {
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestFileRepository_LoadLast(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		n         int
+		chunkSize int64
+		wantIDs   []int
+	}{
+		{name: "last entries most recent first", count: 10, n: 3, chunkSize: 64 * 1024, wantIDs: []int{10, 9, 8}},
+		{name: "file smaller than n", count: 2, n: 5, chunkSize: 64 * 1024, wantIDs: []int{2, 1}},
+		{name: "lines straddle chunk boundaries", count: 10, n: 4, chunkSize: 7, wantIDs: []int{10, 9, 8, 7}},
+		{name: "whole file in tiny chunks", count: 3, n: 10, chunkSize: 1, wantIDs: []int{3, 2, 1}},
+		{name: "empty file", count: 0, n: 3, chunkSize: 64 * 1024, wantIDs: []int{}},
+		{name: "non-positive n", count: 3, n: 0, chunkSize: 64 * 1024, wantIDs: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTailChunkSize(t, tt.chunkSize)
+			repo := newTailRepo(t, tt.count)
+
+			entries, err := repo.LoadLast(context.Background(), tt.n)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantIDs, entryIDs(entries))
+		})
+	}
+}
+
+func TestFileRepository_LoadLast_SkipsInvalidLinesAndMissingNewline(t *testing.T) {
+	setTailChunkSize(t, 5)
+	historyPath := filepath.Join(t.TempDir(), HistoryFileName)
+	content := `{"id":1,"stack_path":"dev/vpc"}` + "\n" +
+		"not json\n" +
+		"\n" +
+		`{"id":2,"stack_path":"dev/rds"}` + "\n" +
+		`{"id":3,"stack_path":"dev/eks"}` // No trailing newline.
+	require.NoError(t, os.WriteFile(historyPath, []byte(content), 0644))
+	repo, err := NewFileRepository(historyPath)
+	require.NoError(t, err)
+
+	entries, err := repo.LoadLast(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 2, 1}, entryIDs(entries))
+	assert.Equal(t, "dev/eks", entries[0].AbsolutePath, "legacy entries fall back to StackPath")
+}
+
+func TestFileRepository_LoadLast_MissingFile(t *testing.T) {
+	repo, err := NewFileRepository(filepath.Join(t.TempDir(), "missing.log"))
+	require.NoError(t, err)
+
+	entries, err := repo.LoadLast(context.Background(), 5)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileRepository_Stream(t *testing.T) {
+	setTailChunkSize(t, 16)
+	repo := newTailRepo(t, 5)
+
+	var ids []int
+	require.NoError(t, repo.Stream(context.Background(), func(entry ExecutionLogEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	}))
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, ids)
+
+	// An error from fn stops the stream and is returned.
+	errBoom := errors.New("boom")
+	ids = nil
+	err := repo.Stream(context.Background(), func(entry ExecutionLogEntry) error {
+		ids = append(ids, entry.ID)
+		if len(ids) == 2 {
+			return errBoom
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, errBoom)
+	assert.Equal(t, []int{5, 4}, ids)
+}
+
+func TestService_LoadRecentAndStream_RespectOrder(t *testing.T) {
+	repo := newTailRepo(t, 5)
+	svc := NewService(repo, "root.hcl")
+
+	recent, err := svc.LoadRecent(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 4}, entryIDs(recent))
+
+	svc.SetOrder(OrderOldest)
+	recent, err = svc.LoadRecent(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5}, entryIDs(recent))
+
+	all, err := svc.LoadRecent(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, entryIDs(all))
+
+	var ids []int
+	require.NoError(t, svc.Stream(context.Background(), func(entry ExecutionLogEntry) error {
+		ids = append(ids, entry.ID)
+		return nil
+	}))
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
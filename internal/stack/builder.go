@@ -5,7 +5,11 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/deps"
@@ -13,35 +17,49 @@ import (
 
 // FindAndBuildTree scans the filesystem starting from rootDir and builds a tree structure.
 // rootConfigFile is used to locate the repository root; if empty, config.DefaultRootConfigFile is used.
+// When rootDir is not inside a project but contains two or more project roots (a monorepo of
+// projects), the first level of the tree holds one node per project instead of plain directories.
 // It returns the root node, maximum depth, and any error encountered.
 func FindAndBuildTree(rootDir, rootConfigFile string) (*Node, int, error) {
-	if rootDir == "" {
-		return nil, 0, fmt.Errorf("root directory cannot be empty")
-	}
+	return FindAndBuildTreeWithIgnore(rootDir, rootConfigFile, nil)
+}
+
+// FindAndBuildTreeWithIgnore is like FindAndBuildTree but excludes directories matching
+// ignoreDirs or the patterns in the scan root's .terraxignore file. Patterns are globs
+// matched against paths relative to rootDir; excluded directories are not descended into.
+func FindAndBuildTreeWithIgnore(rootDir, rootConfigFile string, ignoreDirs []string) (*Node, int, error) {
+	return FindAndBuildTreeWithOptions(rootDir, rootConfigFile, TreeOptions{IgnoreDirs: ignoreDirs})
+}
 
+// FindAndBuildTreeWithOptions is like FindAndBuildTree but scans according to opts.
+func FindAndBuildTreeWithOptions(rootDir, rootConfigFile string, opts TreeOptions) (*Node, int, error) {
 	if rootConfigFile == "" {
 		rootConfigFile = config.DefaultRootConfigFile
 	}
 
-	absPath, err := filepath.Abs(rootDir)
+	absPath, err := resolveScanRoot(rootDir)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to resolve absolute path: %w", err)
+		return nil, 0, err
 	}
 
-	info, err := os.Stat(absPath)
+	rules, err := opts.rules(absPath)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to access directory: %w", err)
-	}
-	if !info.IsDir() {
-		return nil, 0, fmt.Errorf("%s is not a directory", absPath)
+		return nil, 0, err
 	}
 
 	repoRoot := deps.FindRepoRoot(absPath, rootConfigFile)
+	projectRoots, err := splitProjectRoots(absPath, repoRoot, rootConfigFile, rules.ignore)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(projectRoots) > 0 {
+		return buildProjectsTree(absPath, projectRoots, rules)
+	}
 
 	root := &Node{
 		Name:         filepath.Base(absPath),
 		Path:         absPath,
-		IsStack:      isStackDirectory(absPath),
+		IsStack:      rules.isStack(absPath),
 		Children:     make([]*Node, 0),
 		Dependencies: []string{},
 		Dependents:   []string{},
@@ -53,7 +71,7 @@ func FindAndBuildTree(rootDir, rootConfigFile string) (*Node, int, error) {
 	}
 
 	maxDepth := 0
-	if err := buildTreeRecursive(root, &maxDepth, repoRoot); err != nil {
+	if err := buildTreeRecursive(root, &maxDepth, repoRoot, rules); err != nil {
 		return nil, 0, fmt.Errorf("failed to build tree: %w", err)
 	}
 
@@ -61,80 +79,272 @@ func FindAndBuildTree(rootDir, rootConfigFile string) (*Node, int, error) {
 	return root, maxDepth, nil
 }
 
+// resolveScanRoot returns the absolute path of rootDir after checking it is a directory.
+func resolveScanRoot(rootDir string) (string, error) {
+	if rootDir == "" {
+		return "", fmt.Errorf("root directory cannot be empty")
+	}
+
+	absPath, err := filepath.Abs(rootDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to access directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", absPath)
+	}
+
+	return absPath, nil
+}
+
+// splitProjectRoots returns the project roots the tree for absPath is split into when
+// absPath is not inside a project but contains two or more of them. Otherwise it returns nil.
+func splitProjectRoots(absPath, repoRoot, rootConfigFile string, ignore *ignoreMatcher) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(repoRoot, rootConfigFile)); err == nil {
+		return nil, nil
+	}
+
+	projectRoots, err := FindProjectRoots(absPath, rootConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project roots: %w", err)
+	}
+	projectRoots = slices.DeleteFunc(projectRoots, ignore.Match)
+	if len(projectRoots) < 2 {
+		return nil, nil
+	}
+	return projectRoots, nil
+}
+
+// buildProjectsTree builds a tree whose first level holds one node per project root.
+// Each project node is named by its path relative to absRoot and its subtree resolves
+// dependencies against its own root. Projects without any stacks are omitted.
+func buildProjectsTree(absRoot string, projectRoots []string, rules scanRules) (*Node, int, error) {
+	root := &Node{
+		Name:         filepath.Base(absRoot),
+		Path:         absRoot,
+		Children:     make([]*Node, 0),
+		Dependencies: []string{},
+		Dependents:   []string{},
+		Depth:        0,
+	}
+
+	maxDepth := 0
+	for _, projectRoot := range projectRoots {
+		name, err := filepath.Rel(absRoot, projectRoot)
+		if err != nil {
+			name = filepath.Base(projectRoot)
+		}
+		projectNode := &Node{
+			Name:         name,
+			Path:         projectRoot,
+			IsStack:      rules.isStack(projectRoot),
+			IsProject:    true,
+			Children:     make([]*Node, 0),
+			Dependencies: []string{},
+			Dependents:   []string{},
+			Depth:        1,
+		}
+		if projectNode.IsStack {
+			hclFile := filepath.Join(projectRoot, "terragrunt.hcl")
+			projectNode.Dependencies = deps.ParseDependencies(hclFile, projectRoot)
+		}
+
+		if err := buildTreeRecursive(projectNode, &maxDepth, projectRoot, rules); err != nil {
+			return nil, 0, fmt.Errorf("failed to build tree for project %s: %w", name, err)
+		}
+
+		if projectNode.IsStack || projectNode.HasChildren() {
+			root.Children = append(root.Children, projectNode)
+			if projectNode.Depth > maxDepth {
+				maxDepth = projectNode.Depth
+			}
+		}
+	}
+
+	AnalyzeGraph(root)
+	return root, maxDepth, nil
+}
+
+// FindProjectRoots returns the sorted absolute paths of all directories under rootDir that
+// contain rootConfigFile. Nested projects are not searched: once a project root is found,
+// its subdirectories are skipped. If rootDir itself is a project root, only rootDir is returned.
+func FindProjectRoots(rootDir, rootConfigFile string) ([]string, error) {
+	if rootConfigFile == "" {
+		rootConfigFile = config.DefaultRootConfigFile
+	}
+
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	var roots []string
+	err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return nil // Skip unreadable entries.
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != absRoot {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") || shouldSkipDirectory(name) {
+				return filepath.SkipDir
+			}
+		}
+		if _, err := os.Stat(filepath.Join(path, rootConfigFile)); err == nil {
+			roots = append(roots, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(roots)
+	return roots, nil
+}
+
 // buildTreeRecursive recursively builds the tree structure.
 // Only includes directories that are stacks or contain stacks in their hierarchy.
-func buildTreeRecursive(node *Node, maxDepth *int, repoRoot string) error {
+// Directories skipped by rules are left out along with everything below them.
+// Subtrees are built concurrently on up to rules.parallelism goroutines; children keep
+// the sorted order of their directory entries, so the result does not depend on timing.
+func buildTreeRecursive(node *Node, maxDepth *int, repoRoot string, rules scanRules) error {
+	workers := rules.parallelism
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	w := &treeWalker{
+		repoRoot: repoRoot,
+		rules:    rules,
+		slots:    make(chan struct{}, workers-1), // The calling goroutine is a worker too.
+	}
+
+	if deepest := w.build(node); deepest > *maxDepth {
+		*maxDepth = deepest
+	}
+	return nil
+}
+
+// treeWalker builds subtrees for buildTreeRecursive, handing them to extra goroutines
+// while slots has room and building them inline otherwise.
+type treeWalker struct {
+	repoRoot string
+	rules    scanRules
+	slots    chan struct{}
+}
+
+// build adds node's stack subtrees as its children and returns the depth of the deepest
+// node kept below it, or node.Depth when none is.
+func (w *treeWalker) build(node *Node) int {
 	entries, err := os.ReadDir(node.Path)
 	if err != nil {
-		return nil
+		return node.Depth
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+	children := make([]*Node, len(entries))
+	depths := make([]int, len(entries))
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		if !entry.IsDir() {
 			continue
 		}
 
-		if shouldSkipDirectory(entry.Name()) {
+		childPath := filepath.Join(node.Path, entry.Name())
+		if w.rules.skips(childPath) {
 			continue
 		}
 
-		childPath := filepath.Join(node.Path, entry.Name())
-		childNode := &Node{
+		children[i] = &Node{
 			Name:         entry.Name(),
 			Path:         childPath,
-			IsStack:      isStackDirectory(childPath),
+			IsStack:      w.rules.isStack(childPath),
 			Children:     make([]*Node, 0),
 			Dependencies: []string{},
 			Dependents:   []string{},
 			Depth:        node.Depth + 1,
 		}
 
-		if childNode.IsStack {
-			hclFile := filepath.Join(childPath, "terragrunt.hcl")
-			childNode.Dependencies = deps.ParseDependencies(hclFile, repoRoot)
+		select {
+		case w.slots <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-w.slots }()
+				depths[i] = w.buildChild(children[i])
+			}()
+		default:
+			depths[i] = w.buildChild(children[i])
 		}
+	}
+	wg.Wait()
 
-		// Recursively build children to find nested stacks.
-		if err := buildTreeRecursive(childNode, maxDepth, repoRoot); err != nil {
+	// Only keep children that are stacks or contain stacks.
+	deepest := node.Depth
+	for i, child := range children {
+		if child == nil || !(child.IsStack || child.HasChildren()) {
 			continue
 		}
+		node.Children = append(node.Children, child)
+		deepest = max(deepest, depths[i])
+	}
+	return deepest
+}
 
-		// Only add this node if it's a stack or contains stacks.
-		if childNode.IsStack || childNode.HasChildren() {
-			node.Children = append(node.Children, childNode)
-			if childNode.Depth > *maxDepth {
-				*maxDepth = childNode.Depth
-			}
-		}
+// buildChild resolves child's dependencies when it is a stack and builds its subtree
+// to find nested stacks. It returns the depth of the deepest node kept in the subtree.
+func (w *treeWalker) buildChild(child *Node) int {
+	if child.IsStack {
+		hclFile := filepath.Join(child.Path, "terragrunt.hcl")
+		child.Dependencies = deps.ParseDependencies(hclFile, w.repoRoot)
 	}
+	return w.build(child)
+}
 
-	return nil
+// CollectStackPaths returns the absolute paths of all stack directories found under rootDir,
+// including rootDir itself if it is a stack. It scans with the same rules as
+// FindAndBuildTreeWithOptions, so it returns the stacks the tree of rootDir shows.
+func CollectStackPaths(rootDir string, opts TreeOptions) ([]string, error) {
+	return CollectStackPathsIn(rootDir, rootDir, opts)
 }
 
-// CollectStackPaths returns the absolute paths of all stack directories (those containing
-// terragrunt.hcl) found under rootDir, including rootDir itself if it is a stack.
-func CollectStackPaths(rootDir string) ([]string, error) {
-	absRoot, err := filepath.Abs(rootDir)
+// CollectStackPathsIn is like CollectStackPaths but only walks dir, a directory under
+// scanRoot. Ignore patterns and .gitignore files are resolved as in a scan of scanRoot, so
+// it returns the stacks the tree of scanRoot shows below dir.
+func CollectStackPathsIn(scanRoot, dir string, opts TreeOptions) ([]string, error) {
+	absRoot, err := filepath.Abs(scanRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+	absDir, err := filepath.Abs(dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve absolute path: %w", err)
 	}
 
+	rules, err := opts.rules(absRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	var paths []string
-	err = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, walkErr error) error {
+	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, walkErr error) error {
 		if walkErr != nil {
 			return nil // Skip unreadable entries.
 		}
 		if !d.IsDir() {
 			return nil
 		}
-		// Skip hidden and known non-stack directories, but always descend into the root itself.
-		if path != absRoot {
-			name := d.Name()
-			if strings.HasPrefix(name, ".") || shouldSkipDirectory(name) {
-				return filepath.SkipDir
-			}
+		// Skip directories the tree leaves out, but always descend into dir itself.
+		if path != absDir && rules.skips(path) {
+			return filepath.SkipDir
 		}
-		if isStackDirectory(path) {
+		if rules.isStack(path) {
 			paths = append(paths, path)
 		}
 		return nil
@@ -144,29 +354,24 @@ func CollectStackPaths(rootDir string) ([]string, error) {
 
 // isStackDirectory checks if a directory contains stack definition files
 func isStackDirectory(dirPath string) bool {
-	if _, err := os.Stat(filepath.Join(dirPath, "terragrunt.hcl")); err == nil {
-		return true
-	}
-
-	return false
+	return hasStackMarker(dirPath, nil)
 }
 
-// shouldSkipDirectory returns true for directories that should be skipped during scanning
-func shouldSkipDirectory(name string) bool {
-	skipList := []string{
-		".git",
-		".terraform",
-		".terragrunt-cache",
-		"vendor",
-		".idea",
-		".vscode",
+// hasStackMarker reports whether dirPath holds any of markers, or of
+// config.DefaultStackMarkers when markers is empty.
+func hasStackMarker(dirPath string, markers []string) bool {
+	if len(markers) == 0 {
+		markers = config.DefaultStackMarkers
 	}
-
-	for _, skip := range skipList {
-		if name == skip {
+	for _, marker := range markers {
+		if _, err := os.Stat(filepath.Join(dirPath, marker)); err == nil {
 			return true
 		}
 	}
-
 	return false
 }
+
+// shouldSkipDirectory returns true for directories that should be skipped during scanning
+func shouldSkipDirectory(name string) bool {
+	return slices.Contains(config.DefaultSkipDirectories, name)
+}
@@ -0,0 +1,182 @@
+package stack
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/israoo/terrax/internal/config"
+)
+
+// TreeCache stores scanned stack trees on disk, one file per scan root, so launching in a
+// repository whose directories have not changed skips the filesystem walk.
+type TreeCache struct {
+	Dir string        // Directory holding the cache files
+	TTL time.Duration // Maximum age of a cached tree; 0 or less means no limit
+}
+
+// cachedTree is the content of a cache file.
+type cachedTree struct {
+	Key      string           `json:"key"`      // Scan root and options the tree was built with
+	Created  time.Time        `json:"created"`  // When the tree was scanned
+	MaxDepth int              `json:"maxDepth"` // Depth returned with the tree
+	Mtimes   map[string]int64 `json:"mtimes"`   // Modification time (UnixNano) of every input; 0 if missing
+	Tree     *Node            `json:"tree"`
+}
+
+// FindAndBuildTree returns the tree cached for rootDir when it was built with the same
+// rootConfigFile and opts, is younger than TTL and none of its inputs changed: the scanned
+// directories (whose mtime changes when entries are added, removed or renamed), the stacks'
+// terragrunt.hcl files and the .terraxignore file. Otherwise it scans with
+// FindAndBuildTreeWithOptions and caches the result. A cache that cannot be read or
+// written is ignored.
+func (c TreeCache) FindAndBuildTree(rootDir, rootConfigFile string, opts TreeOptions) (*Node, int, error) {
+	absPath, err := resolveScanRoot(rootDir)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rootConfigFile == "" {
+		rootConfigFile = config.DefaultRootConfigFile
+	}
+
+	key, err := cacheKey(absPath, rootConfigFile, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	cacheFile := c.file(key)
+
+	if cached, ok := c.load(cacheFile, key); ok {
+		return cached.Tree, cached.MaxDepth, nil
+	}
+
+	root, maxDepth, err := FindAndBuildTreeWithOptions(absPath, rootConfigFile, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Create the cache directory before recording mtimes, as doing so may change the
+	// mtime of a scanned directory.
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return root, maxDepth, nil
+	}
+	if rules, err := opts.rules(absPath); err == nil {
+		_ = c.save(cacheFile, cachedTree{
+			Key:      key,
+			Created:  time.Now(),
+			MaxDepth: maxDepth,
+			Mtimes:   treeInputMtimes(absPath, root, rules),
+			Tree:     root,
+		})
+	}
+	return root, maxDepth, nil
+}
+
+// cacheKey identifies a scan by its root and the settings that shape the tree.
+func cacheKey(absRoot, rootConfigFile string, opts TreeOptions) (string, error) {
+	opts.Parallelism = 0 // Does not change the tree.
+	data, err := json.Marshal(struct {
+		Root           string
+		RootConfigFile string
+		Options        TreeOptions
+	}{absRoot, rootConfigFile, opts})
+	if err != nil {
+		return "", fmt.Errorf("failed to build cache key: %w", err)
+	}
+	return string(data), nil
+}
+
+// file returns the cache file path for key.
+func (c TreeCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, "tree-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+// load returns the tree cached in cacheFile if it is usable for key.
+func (c TreeCache) load(cacheFile, key string) (cachedTree, bool) {
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return cachedTree{}, false
+	}
+
+	var cached cachedTree
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Tree == nil || cached.Key != key {
+		return cachedTree{}, false
+	}
+	if c.TTL > 0 && time.Since(cached.Created) > c.TTL {
+		return cachedTree{}, false
+	}
+	for path, mtime := range cached.Mtimes {
+		if fileMtime(path) != mtime {
+			return cachedTree{}, false
+		}
+	}
+
+	maxDepth := 0
+	if err := validateTree(cached.Tree, 0, &maxDepth); err != nil {
+		return cachedTree{}, false
+	}
+	return cached, true
+}
+
+// save writes cached to cacheFile.
+func (c TreeCache) save(cacheFile string, cached cachedTree) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to serialize tree cache: %w", err)
+	}
+	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write tree cache: %w", err)
+	}
+	return nil
+}
+
+// treeInputMtimes records the modification time of everything the tree for absRoot was
+// built from: each directory the scan descends into, the terragrunt.hcl of each stack in
+// root and the scan root's .terraxignore file.
+func treeInputMtimes(absRoot string, root *Node, rules scanRules) map[string]int64 {
+	mtimes := make(map[string]int64)
+	_ = filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || !d.IsDir() {
+			return nil
+		}
+		if path != absRoot && rules.skips(path) {
+			return filepath.SkipDir
+		}
+		mtimes[path] = fileMtime(path)
+		if rules.gitignore != nil {
+			gitignoreFile := filepath.Join(path, GitignoreFileName)
+			mtimes[gitignoreFile] = fileMtime(gitignoreFile)
+		}
+		return nil
+	})
+
+	var addStacks func(node *Node)
+	addStacks = func(node *Node) {
+		if node.IsStack {
+			hclFile := filepath.Join(node.Path, "terragrunt.hcl")
+			mtimes[hclFile] = fileMtime(hclFile)
+		}
+		for _, child := range node.Children {
+			addStacks(child)
+		}
+	}
+	addStacks(root)
+
+	ignoreFile := filepath.Join(absRoot, IgnoreFileName)
+	mtimes[ignoreFile] = fileMtime(ignoreFile)
+	return mtimes
+}
+
+// fileMtime returns the modification time of path in nanoseconds, or 0 if it cannot be read.
+func fileMtime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
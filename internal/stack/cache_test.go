@@ -0,0 +1,108 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheTestTree creates a repository with two stacks and returns its root.
+func cacheTestTree(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"env/dev/vpc", "env/dev/rds"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+	return tmpDir
+}
+
+// tamperCache renames the vpc node in every cache file under dir, so a tree served from
+// the cache can be told apart from a fresh scan.
+func tamperCache(t *testing.T, dir string) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "tree-*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(files[0], []byte(strings.ReplaceAll(string(data), `"name":"vpc"`, `"name":"cached-vpc"`)), 0644))
+}
+
+func TestTreeCache_Hit(t *testing.T) {
+	root := cacheTestTree(t)
+	cache := TreeCache{Dir: filepath.Join(t.TempDir(), "cache")}
+
+	tree, maxDepth, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, maxDepth)
+
+	tamperCache(t, cache.Dir)
+	cached, cachedDepth, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, maxDepth, cachedDepth)
+	assert.Equal(t, []string{"rds 📦", "cached-vpc 📦"}, cached.Children[0].Children[0].GetChildNames(), "unchanged tree should come from the cache")
+	assert.Equal(t, []string{"rds 📦", "vpc 📦"}, tree.Children[0].Children[0].GetChildNames())
+
+	// Different options are cached separately.
+	other, _, err := cache.FindAndBuildTree(root, "", TreeOptions{SkipDirectories: []string{"rds"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vpc 📦"}, other.Children[0].Children[0].GetChildNames())
+}
+
+func TestTreeCache_StaleMtime(t *testing.T) {
+	root := cacheTestTree(t)
+	cache := TreeCache{Dir: filepath.Join(t.TempDir(), "cache")}
+
+	_, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	tamperCache(t, cache.Dir)
+
+	// Adding a stack changes the mtime of its parent directory.
+	newStack := filepath.Join(root, "env", "dev", "dns")
+	require.NoError(t, os.MkdirAll(newStack, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(newStack, "terragrunt.hcl"), []byte(""), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Dir(newStack), later, later))
+
+	tree, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dns 📦", "rds 📦", "vpc 📦"}, tree.Children[0].Children[0].GetChildNames(), "stale cache should trigger a rescan")
+}
+
+func TestTreeCache_StaleStackFile(t *testing.T) {
+	root := cacheTestTree(t)
+	cache := TreeCache{Dir: filepath.Join(t.TempDir(), "cache")}
+
+	_, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	tamperCache(t, cache.Dir)
+
+	// Editing a stack's dependencies does not touch any directory.
+	hclFile := filepath.Join(root, "env", "dev", "rds", "terragrunt.hcl")
+	require.NoError(t, os.WriteFile(hclFile, []byte("dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n"), 0644))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(hclFile, later, later))
+
+	tree, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rds 📦", "vpc 📦"}, tree.Children[0].Children[0].GetChildNames())
+}
+
+func TestTreeCache_Expired(t *testing.T) {
+	root := cacheTestTree(t)
+	cache := TreeCache{Dir: filepath.Join(t.TempDir(), "cache"), TTL: time.Nanosecond}
+
+	_, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	tamperCache(t, cache.Dir)
+
+	tree, _, err := cache.FindAndBuildTree(root, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rds 📦", "vpc 📦"}, tree.Children[0].Children[0].GetChildNames(), "expired cache should trigger a rescan")
+}
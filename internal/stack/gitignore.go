@@ -0,0 +1,186 @@
+package stack
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/spf13/afero"
+)
+
+// GitignoreFileName is the file whose patterns exclude directories from the tree when
+// TreeOptions.RespectGitignore is set.
+const GitignoreFileName = ".gitignore"
+
+// gitignoreMatcher decides whether a directory under the scan root is ignored by the
+// .gitignore files found on the way down to it. Each file's patterns are relative to its
+// own directory, deeper files take precedence over shallower ones and, within a file, the
+// last matching pattern wins, so "!" patterns can re-include a directory. Files above the
+// scan root are not read. It is safe for concurrent use.
+type gitignoreMatcher struct {
+	fs   afero.Fs
+	root string
+
+	mu    sync.Mutex
+	files map[string][]gitignoreRule // Parsed .gitignore per directory; nil when it has none
+}
+
+// gitignoreRule is one pattern of a .gitignore file.
+type gitignoreRule struct {
+	re       *regexp.Regexp
+	negate   bool // "!" pattern: re-includes what earlier patterns ignored
+	anchored bool // Has a slash: matched against the path relative to the file's directory
+}
+
+// newGitignoreMatcher returns a matcher reading .gitignore files from fs for a scan
+// rooted at rootDir.
+func newGitignoreMatcher(fs afero.Fs, rootDir string) *gitignoreMatcher {
+	return &gitignoreMatcher{fs: fs, root: rootDir, files: make(map[string][]gitignoreRule)}
+}
+
+// Match reports whether the directory at dirPath is ignored. A nil matcher matches nothing.
+func (gm *gitignoreMatcher) Match(dirPath string) bool {
+	if gm == nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(gm.root, dirPath)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+
+	ignored := false
+	dir := gm.root
+	segments := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range segments {
+		relToDir := strings.Join(segments[i:], "/")
+		for _, rule := range gm.rules(dir) {
+			subject := relToDir
+			if !rule.anchored {
+				subject = path.Base(relToDir)
+			}
+			if rule.re.MatchString(subject) {
+				ignored = !rule.negate
+			}
+		}
+		dir = filepath.Join(dir, segments[i])
+	}
+	return ignored
+}
+
+// rules returns the parsed .gitignore of dir, reading it on first use.
+func (gm *gitignoreMatcher) rules(dir string) []gitignoreRule {
+	gm.mu.Lock()
+	rules, loaded := gm.files[dir]
+	gm.mu.Unlock()
+	if loaded {
+		return rules
+	}
+
+	// An unreadable file is treated like a missing one.
+	if data, err := afero.ReadFile(gm.fs, filepath.Join(dir, GitignoreFileName)); err == nil {
+		rules = parseGitignore(data)
+	}
+
+	gm.mu.Lock()
+	gm.files[dir] = rules
+	gm.mu.Unlock()
+	return rules
+}
+
+// parseGitignore returns the rules of a .gitignore file, skipping blank lines, comments
+// and patterns that cannot match a directory.
+func parseGitignore(data []byte) []gitignoreRule {
+	var rules []gitignoreRule
+	for _, line := range strings.Split(string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))), "\n") {
+		if rule, ok := parseGitignoreLine(line); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseGitignoreLine parses one .gitignore line, following gitignore(5): "#" starts a
+// comment, "!" negates, a trailing "/" only matches directories (all this matcher sees),
+// a slash at the start or middle anchors the pattern to the file's directory, and "\"
+// escapes the next character.
+func parseGitignoreLine(line string) (gitignoreRule, bool) {
+	line = trimGitignoreSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	var rule gitignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	line = strings.TrimSuffix(line, "/")
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+	rule.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	re, err := regexp.Compile("^" + gitignoreRegexp(line) + "$")
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// trimGitignoreSpace removes trailing spaces from line unless they are escaped with "\".
+func trimGitignoreSpace(line string) string {
+	trimmed := strings.TrimRight(line, " \t")
+	if strings.HasSuffix(trimmed, "\\") && len(trimmed) < len(line) {
+		trimmed += " "
+	}
+	return trimmed
+}
+
+// gitignoreRegexp translates a gitignore glob to a regular expression over slash-separated
+// paths: "*" and "?" do not cross a slash, a leading "**/" and a "/**/" match any number of
+// directories and a trailing "/**" matches everything inside.
+func gitignoreRegexp(pattern string) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		rest := pattern[i:]
+		switch {
+		case i == 0 && strings.HasPrefix(rest, "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += len("**/") - 1
+		case rest == "/**":
+			sb.WriteString("/.*")
+			i += len(rest) - 1
+		case strings.HasPrefix(rest, "/**/"):
+			sb.WriteString("/(?:.*/)?")
+			i += len("/**/") - 1
+		case rest[0] == '*':
+			sb.WriteString("[^/]*")
+		case rest[0] == '?':
+			sb.WriteString("[^/]")
+		case rest[0] == '\\' && len(rest) > 1:
+			sb.WriteString(regexp.QuoteMeta(rest[1:2]))
+			i++
+		case rest[0] == '[':
+			end := strings.IndexByte(rest[1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := rest[1 : end+1]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			sb.WriteString(regexp.QuoteMeta(rest[:1]))
+		}
+	}
+	return sb.String()
+}
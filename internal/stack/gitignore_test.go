@@ -0,0 +1,128 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitignoreMatcher_Match(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	// Structure:
+	//  /repo/.gitignore         generated/, /build, cache/**, **/tmp/out, keep*, !keep, \#notes
+	//  /repo/modules/.gitignore *.bak, /local, sub/gen, !generated
+	//  /repo/modules/sub/.gitignore  !local
+	require.NoError(t, afero.WriteFile(fs, "/repo/.gitignore", []byte(
+		"# Build output\ngenerated/\n/build\ncache/**\n**/tmp/out\nkeep*\n!keep\n\\#notes\n\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/repo/modules/.gitignore", []byte(
+		"*.bak\r\n/local\r\nsub/gen\r\n!generated\r\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/repo/modules/sub/.gitignore", []byte("!local\n"), 0644))
+
+	gm := newGitignoreMatcher(fs, "/repo")
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "name pattern at top level", path: "generated", expected: true},
+		{name: "name pattern at any depth", path: "dev/generated", expected: true},
+		{name: "anchored pattern at its level", path: "build", expected: true},
+		{name: "anchored pattern not deeper", path: "dev/build", expected: false},
+		{name: "trailing double star matches contents", path: "cache/tf", expected: true},
+		{name: "trailing double star not the directory itself", path: "cache", expected: false},
+		{name: "leading double star at any depth", path: "a/b/tmp/out", expected: true},
+		{name: "negation re-includes", path: "keep", expected: false},
+		{name: "negation leaves other matches", path: "keeper", expected: true},
+		{name: "escaped hash", path: "#notes", expected: true},
+		{name: "nested file glob", path: "modules/vpc.bak", expected: true},
+		{name: "nested file does not apply above it", path: "vpc.bak", expected: false},
+		{name: "nested anchored pattern is relative to its file", path: "modules/local", expected: true},
+		{name: "nested anchored pattern not at root", path: "local", expected: false},
+		{name: "nested pattern with middle slash", path: "modules/sub/gen", expected: true},
+		{name: "deeper file overrides shallower", path: "modules/generated", expected: false},
+		{name: "deeper negation for nested name", path: "modules/sub/local", expected: false},
+		{name: "unmatched path", path: "dev/vpc", expected: false},
+		{name: "scan root", path: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, gm.Match(filepath.Join("/repo", filepath.FromSlash(tt.path))))
+		})
+	}
+
+	var nilMatcher *gitignoreMatcher
+	assert.False(t, nilMatcher.Match("/repo/generated"), "nil matcher matches nothing")
+}
+
+func TestGitignoreRegexp(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		{pattern: "a/**/b", path: "a/b", matches: true},
+		{pattern: "a/**/b", path: "a/x/y/b", matches: true},
+		{pattern: "a/*/b", path: "a/x/y/b", matches: false},
+		{pattern: "env-?", path: "env-1", matches: true},
+		{pattern: "env-[0-9]", path: "env-a", matches: false},
+		{pattern: "env-[!0-9]", path: "env-a", matches: true},
+		{pattern: "v1.0", path: "v1x0", matches: false},
+		{pattern: "[unclosed", path: "[unclosed", matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			rule, ok := parseGitignoreLine(tt.pattern)
+			require.True(t, ok)
+			assert.Equal(t, tt.matches, rule.re.MatchString(tt.path))
+		})
+	}
+}
+
+func TestFindAndBuildTree_RespectGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"dev/vpc", "dev/out/cached", "prod/rds", "prod/keep"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, GitignoreFileName), []byte("out/\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "prod", GitignoreFileName), []byte("*\n!rds\n"), 0644))
+	names := func(node *Node) []string {
+		var names []string
+		for _, child := range node.Children {
+			names = append(names, child.Name)
+		}
+		return names
+	}
+
+	tree, _, err := FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{RespectGitignore: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"dev", "prod"}, names(tree))
+	assert.Equal(t, []string{"vpc"}, names(tree.Children[0]))
+	assert.Equal(t, []string{"rds"}, names(tree.Children[1]))
+
+	stats, err := ScanTree(tmpDir, "", TreeOptions{RespectGitignore: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Stacks)
+
+	paths, err := CollectStackPaths(tmpDir, TreeOptions{RespectGitignore: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "dev/vpc"), filepath.Join(tmpDir, "prod/rds")}, paths)
+
+	// Walking a subdirectory still applies the .gitignore files of the scan root.
+	paths, err = CollectStackPathsIn(tmpDir, filepath.Join(tmpDir, "dev"), TreeOptions{RespectGitignore: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(tmpDir, "dev/vpc")}, paths)
+
+	// Without the option .gitignore files are not read.
+	tree, _, err = FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"out", "vpc"}, names(tree.Children[0]))
+	assert.Equal(t, []string{"keep", "rds"}, names(tree.Children[1]))
+}
@@ -0,0 +1,111 @@
+package stack
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFileName is the file at the scan root listing directories to exclude from the tree.
+const IgnoreFileName = ".terraxignore"
+
+// ignoreMatcher decides whether a directory under the scan root is excluded from the tree.
+// Patterns use path.Match syntax and are matched against slash-separated paths relative
+// to the scan root. A pattern without a slash also matches a directory name at any depth,
+// like .gitignore.
+type ignoreMatcher struct {
+	root     string
+	patterns []string
+}
+
+// newIgnoreMatcher combines the patterns from rootDir's .terraxignore with extraPatterns.
+// Returns nil when there are no patterns, which matches nothing.
+func newIgnoreMatcher(rootDir string, extraPatterns []string) (*ignoreMatcher, error) {
+	patterns, err := loadIgnoreFile(filepath.Join(rootDir, IgnoreFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range extraPatterns {
+		if p = normalizeIgnorePattern(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return &ignoreMatcher{root: rootDir, patterns: patterns}, nil
+}
+
+// loadIgnoreFile reads ignore patterns from filePath. A missing file yields no patterns.
+func loadIgnoreFile(filePath string) ([]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", IgnoreFileName, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	patterns, err := parseIgnorePatterns(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", IgnoreFileName, err)
+	}
+	return patterns, nil
+}
+
+// parseIgnorePatterns returns one pattern per line, skipping blank lines and # comments.
+func parseIgnorePatterns(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if p := normalizeIgnorePattern(line); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// normalizeIgnorePattern trims whitespace, a leading "./" or "/" and a trailing "/".
+func normalizeIgnorePattern(pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+	pattern = strings.TrimPrefix(pattern, "./")
+	pattern = strings.TrimPrefix(pattern, "/")
+	return strings.TrimSuffix(pattern, "/")
+}
+
+// Match reports whether dirPath should be excluded. A nil matcher matches nothing.
+func (im *ignoreMatcher) Match(dirPath string) bool {
+	if im == nil {
+		return false
+	}
+
+	rel, err := filepath.Rel(im.root, dirPath)
+	if err != nil || rel == "." {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+	name := path.Base(rel)
+
+	for _, pattern := range im.patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIgnorePatterns(t *testing.T) {
+	content := `# Comment line
+modules
+
+  legacy/*  
+./sandbox/
+   # indented comment
+`
+	patterns, err := parseIgnorePatterns(strings.NewReader(content))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"modules", "legacy/*", "sandbox"}, patterns)
+}
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	root := filepath.FromSlash("/infra")
+	im := &ignoreMatcher{root: root, patterns: []string{"modules", "legacy/*", "prod/us-*/tmp"}}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected bool
+	}{
+		{name: "name pattern at top level", path: "modules", expected: true},
+		{name: "name pattern at any depth", path: "dev/modules", expected: true},
+		{name: "relative glob", path: "legacy/old-vpc", expected: true},
+		{name: "relative glob does not match deeper levels", path: "dev/legacy/old-vpc", expected: false},
+		{name: "nested relative glob", path: "prod/us-east-1/tmp", expected: true},
+		{name: "unmatched path", path: "dev/vpc", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, im.Match(filepath.Join(root, filepath.FromSlash(tt.path))))
+		})
+	}
+
+	var nilMatcher *ignoreMatcher
+	assert.False(t, nilMatcher.Match(filepath.Join(root, "modules")), "nil matcher matches nothing")
+}
+
+func TestFindAndBuildTree_TerraxIgnore(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"dev/vpc", "dev/scratch", "legacy/old", "prod/rds"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, IgnoreFileName),
+		[]byte("# Old stacks\nlegacy\n\n*/scratch\n"), 0644))
+
+	tree, _, err := FindAndBuildTreeWithIgnore(tmpDir, "", []string{"prod"})
+	require.NoError(t, err)
+
+	require.Len(t, tree.Children, 1, "legacy (ignore file) and prod (ignore_dirs) should be pruned")
+	dev := tree.Children[0]
+	assert.Equal(t, "dev", dev.Name)
+	require.Len(t, dev.Children, 1)
+	assert.Equal(t, "vpc", dev.Children[0].Name)
+
+	// Without extra patterns only the ignore file applies.
+	tree, _, err = FindAndBuildTree(tmpDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, tree.GetChildNames())
+}
@@ -0,0 +1,36 @@
+package stack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// MetadataFileName is the optional file in a stack directory describing the stack.
+const MetadataFileName = ".terrax-meta.yaml"
+
+// Metadata holds the human-oriented description of a stack read from MetadataFileName.
+type Metadata struct {
+	Description string `yaml:"description"` // What the stack manages
+	Owner       string `yaml:"owner"`       // Team or person responsible for the stack
+}
+
+// LoadMetadata reads the metadata file in stackPath. A missing file yields empty metadata.
+func LoadMetadata(stackPath string) (Metadata, error) {
+	filePath := filepath.Join(stackPath, MetadataFileName)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Metadata{}, nil
+		}
+		return Metadata{}, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var metadata Metadata
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return Metadata{}, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return metadata, nil
+}
@@ -0,0 +1,28 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	metadata, err := LoadMetadata(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{}, metadata, "a missing file yields empty metadata")
+
+	content := "description: Shared VPC for production\nowner: platform-team\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, MetadataFileName), []byte(content), 0644))
+	metadata, err = LoadMetadata(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{Description: "Shared VPC for production", Owner: "platform-team"}, metadata)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, MetadataFileName), []byte("description: [oops"), 0644))
+	_, err = LoadMetadata(tmpDir)
+	assert.ErrorContains(t, err, "failed to parse")
+}
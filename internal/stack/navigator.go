@@ -1,21 +1,118 @@
 package stack
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/israoo/terrax/internal/bounds"
+)
 
 // Navigator provides methods for navigating the stack tree hierarchy.
 // It encapsulates the business logic for tree traversal, path resolution,
 // and selection management, keeping the TUI layer clean and focused on presentation.
 type Navigator struct {
-	root     *Node
-	maxDepth int
+	root       *Node
+	maxDepth   int
+	cyclic     bool // Moving past either end of a column wraps to the other end
+	stacksOnly bool // Columns show only their stacks (see IsShown)
 }
 
 // NewNavigator creates a new Navigator instance for the given stack tree.
+// Navigation is cyclic until SetCyclic(false) is called.
 func NewNavigator(root *Node, maxDepth int) *Navigator {
 	return &Navigator{
 		root:     root,
 		maxDepth: maxDepth,
+		cyclic:   true,
+	}
+}
+
+// SetCyclic sets whether MoveUp and MoveDown wrap around at the ends of a column or
+// stop at the first and last item.
+func (nav *Navigator) SetCyclic(enabled bool) {
+	nav.cyclic = enabled
+}
+
+// IsCyclic reports whether moving past either end of a column wraps around.
+// A nil navigator is cyclic, matching the default.
+func (nav *Navigator) IsCyclic() bool {
+	return nav == nil || nav.cyclic
+}
+
+// SetStacksOnly sets whether columns show only their stacks, hiding the plain directories
+// listed next to them. PropagateSelection moves a selection that becomes hidden to the
+// first item shown.
+func (nav *Navigator) SetStacksOnly(enabled bool) {
+	nav.stacksOnly = enabled
+}
+
+// IsStacksOnly reports whether columns show only their stacks.
+func (nav *Navigator) IsStacksOnly() bool {
+	return nav != nil && nav.stacksOnly
+}
+
+// IsShown reports whether the item at index of the column at depth is shown. Every item
+// is shown unless stacks-only is set; then plain directories are hidden, except in a
+// column without any stack, so the stacks below them stay reachable.
+func (nav *Navigator) IsShown(state *NavigationState, depth, index int) bool {
+	parent := nav.columnParent(state, depth)
+	if parent == nil || !bounds.InRange(index, len(parent.Children)) {
+		return false
+	}
+	return !nav.stacksOnly || isShownStacksOnly(parent.Children, index)
+}
+
+// ShownItems returns the items of the column at depth that are shown (see IsShown).
+func (nav *Navigator) ShownItems(state *NavigationState, depth int) []string {
+	if !bounds.InRange(depth, nav.maxDepth) {
+		return []string{}
+	}
+	items := state.Columns[depth]
+	if !nav.stacksOnly {
+		return items
 	}
+	shown := make([]string, 0, len(items))
+	for i, item := range items {
+		if nav.IsShown(state, depth, i) {
+			shown = append(shown, item)
+		}
+	}
+	return shown
+}
+
+// columnParent returns the node whose children fill the column at depth, or nil when
+// that column is empty.
+func (nav *Navigator) columnParent(state *NavigationState, depth int) *Node {
+	if !bounds.InRange(depth, nav.maxDepth) {
+		return nil
+	}
+	if depth == 0 {
+		return nav.root
+	}
+	return state.CurrentNodes[depth-1]
+}
+
+// isShownStacksOnly reports whether children[index] is shown in stacks-only mode: it is a
+// stack, or none of its siblings is.
+func isShownStacksOnly(children []*Node, index int) bool {
+	if children[index].IsStack {
+		return true
+	}
+	for _, child := range children {
+		if child.IsStack {
+			return false
+		}
+	}
+	return true
+}
+
+// firstShownIndex returns the index of the first of children shown in stacks-only mode.
+func firstShownIndex(children []*Node) int {
+	for i := range children {
+		if isShownStacksOnly(children, i) {
+			return i
+		}
+	}
+	return 0
 }
 
 // NavigationState represents the current navigation state in the tree.
@@ -57,18 +154,15 @@ func (nav *Navigator) PropagateSelection(state *NavigationState) *Node {
 
 		state.Columns[depth] = currentNode.GetChildNames()
 
-		if state.SelectedIndices[depth] >= len(currentNode.Children) {
+		if !bounds.InRange(state.SelectedIndices[depth], len(currentNode.Children)) {
 			state.SelectedIndices[depth] = 0
 		}
-
-		if len(currentNode.Children) > 0 {
-			selectedIndex := state.SelectedIndices[depth]
-			currentNode = currentNode.Children[selectedIndex]
-			state.CurrentNodes[depth] = currentNode
-		} else {
-			currentNode = nil
-			state.CurrentNodes[depth] = nil
+		if nav.stacksOnly && !isShownStacksOnly(currentNode.Children, state.SelectedIndices[depth]) {
+			state.SelectedIndices[depth] = firstShownIndex(currentNode.Children)
 		}
+
+		currentNode = currentNode.Children[state.SelectedIndices[depth]]
+		state.CurrentNodes[depth] = currentNode
 	}
 
 	return currentNode
@@ -90,14 +184,18 @@ func (nav *Navigator) clearColumnsFrom(state *NavigationState, startDepth int) {
 // GetNodeAtDepth returns the selected node at a specific depth level.
 // Returns nil if the depth is invalid or no node exists at that level.
 func (nav *Navigator) GetNodeAtDepth(state *NavigationState, depth int) *Node {
-	if depth < 0 || depth >= nav.maxDepth {
+	if !bounds.InRange(depth, nav.maxDepth) {
 		return nil
 	}
 	return state.CurrentNodes[depth]
 }
 
 // GetMaxVisibleDepth returns the deepest depth level that has content.
+// It returns 0 for a nil navigator or state.
 func (nav *Navigator) GetMaxVisibleDepth(state *NavigationState) int {
+	if nav == nil || state == nil {
+		return 0
+	}
 	for depth := nav.maxDepth - 1; depth >= 0; depth-- {
 		if len(state.Columns[depth]) > 0 {
 			return depth + 1
@@ -106,17 +204,19 @@ func (nav *Navigator) GetMaxVisibleDepth(state *NavigationState) int {
 	return 0
 }
 
-// CanMoveUp checks if moving up is possible in the given depth column.
+// CanMoveUp checks if moving up is possible in the given depth column without wrapping.
+// With cyclic navigation disabled, MoveUp only moves when this holds.
 func (nav *Navigator) CanMoveUp(state *NavigationState, depth int) bool {
-	if depth < 0 || depth >= nav.maxDepth {
+	if !bounds.InRange(depth, nav.maxDepth) {
 		return false
 	}
 	return state.SelectedIndices[depth] > 0
 }
 
-// CanMoveDown checks if moving down is possible in the given depth column.
+// CanMoveDown checks if moving down is possible in the given depth column without
+// wrapping. With cyclic navigation disabled, MoveDown only moves when this holds.
 func (nav *Navigator) CanMoveDown(state *NavigationState, depth int) bool {
-	if depth < 0 || depth >= nav.maxDepth {
+	if !bounds.InRange(depth, nav.maxDepth) {
 		return false
 	}
 	maxIndex := len(state.Columns[depth]) - 1
@@ -125,9 +225,9 @@ func (nav *Navigator) CanMoveDown(state *NavigationState, depth int) bool {
 
 // MoveUp moves the selection up in the specified depth column.
 // Returns true if the move was successful.
-// Implements cyclic navigation: wraps to bottom when at top.
+// With cyclic navigation it wraps to the bottom when at the top; otherwise it stops there.
 func (nav *Navigator) MoveUp(state *NavigationState, depth int) bool {
-	if depth < 0 || depth >= nav.maxDepth {
+	if !bounds.InRange(depth, nav.maxDepth) {
 		return false
 	}
 	maxIndex := len(state.Columns[depth]) - 1
@@ -138,6 +238,9 @@ func (nav *Navigator) MoveUp(state *NavigationState, depth int) bool {
 	if state.SelectedIndices[depth] > 0 {
 		state.SelectedIndices[depth]--
 	} else {
+		if !nav.cyclic {
+			return false
+		}
 		// Wrap to bottom
 		state.SelectedIndices[depth] = maxIndex
 	}
@@ -146,9 +249,9 @@ func (nav *Navigator) MoveUp(state *NavigationState, depth int) bool {
 
 // MoveDown moves the selection down in the specified depth column.
 // Returns true if the move was successful.
-// Implements cyclic navigation: wraps to top when at bottom.
+// With cyclic navigation it wraps to the top when at the bottom; otherwise it stops there.
 func (nav *Navigator) MoveDown(state *NavigationState, depth int) bool {
-	if depth < 0 || depth >= nav.maxDepth {
+	if !bounds.InRange(depth, nav.maxDepth) {
 		return false
 	}
 	maxIndex := len(state.Columns[depth]) - 1
@@ -159,6 +262,9 @@ func (nav *Navigator) MoveDown(state *NavigationState, depth int) bool {
 	if state.SelectedIndices[depth] < maxIndex {
 		state.SelectedIndices[depth]++
 	} else {
+		if !nav.cyclic {
+			return false
+		}
 		// Wrap to top
 		state.SelectedIndices[depth] = 0
 	}
@@ -198,7 +304,7 @@ func (nav *Navigator) GetNavigationPath(state *NavigationState, depth int) strin
 		}
 
 		selectedIdx := state.SelectedIndices[i]
-		if selectedIdx >= 0 && selectedIdx < len(state.Columns[i]) {
+		if bounds.InRange(selectedIdx, len(state.Columns[i])) {
 			// Extract directory name (remove emoji marker if present)
 			dirName := state.Columns[i][selectedIdx]
 			// Remove " 📦" marker if it exists
@@ -238,11 +344,87 @@ func findNodeByPath(node *Node, path string) *Node {
 	return nil
 }
 
+// FindPath returns the chain of child indices leading from the root to the node whose
+// Path is target, one index per navigation depth, or nil when no node within the
+// navigable depth has that path. Paths are compared exactly, so "/a/dev" never
+// resolves to "/a/dev-us".
+func (nav *Navigator) FindPath(target string) []int {
+	if nav.root == nil {
+		return nil
+	}
+	return findPath(nav.root, filepath.ToSlash(filepath.Clean(target)), nav.maxDepth)
+}
+
+// findPath recursively searches the children of node, at most depthLeft levels down, for
+// the node whose path is target and returns the indices leading to it.
+func findPath(node *Node, target string, depthLeft int) []int {
+	if depthLeft <= 0 {
+		return nil
+	}
+	for i, child := range node.Children {
+		if filepath.ToSlash(child.Path) == target {
+			return []int{i}
+		}
+		if rest := findPath(child, target, depthLeft-1); rest != nil {
+			return append([]int{i}, rest...)
+		}
+	}
+	return nil
+}
+
+// SelectPath selects the node at absPath in every navigation column, resets the selection
+// of the columns below it and recalculates the columns. Nodes are matched by Path, so
+// display decorations such as the 📦 marker do not matter. It returns false, leaving
+// state unchanged, when absPath does not resolve to a node within the navigable depth
+// (the root itself has no column and never resolves).
+func (nav *Navigator) SelectPath(state *NavigationState, absPath string) bool {
+	if state == nil {
+		return false
+	}
+	chain := nav.FindPath(absPath)
+	if chain == nil {
+		return false
+	}
+
+	for depth := range state.SelectedIndices {
+		state.SelectedIndices[depth] = 0
+		if depth < len(chain) {
+			state.SelectedIndices[depth] = chain[depth]
+		}
+	}
+	nav.PropagateSelection(state)
+	return true
+}
+
+// StackPaths returns the paths of all stacks within the navigable depth, in tree order.
+func (nav *Navigator) StackPaths() []string {
+	if nav.root == nil {
+		return nil
+	}
+	var paths []string
+	collectStackPaths(nav.root, nav.maxDepth, &paths)
+	return paths
+}
+
+// collectStackPaths appends the paths of the stacks beneath node, at most depthLeft
+// levels down, to paths.
+func collectStackPaths(node *Node, depthLeft int, paths *[]string) {
+	if depthLeft <= 0 {
+		return
+	}
+	for _, child := range node.Children {
+		if child.IsStack {
+			*paths = append(*paths, child.Path)
+		}
+		collectStackPaths(child, depthLeft-1, paths)
+	}
+}
+
 // GetPathAtDepthAndIndex returns the absolute path of the item at position index
 // in the navigation column at the given depth. depth is 0-based (0 = first nav column).
 // Returns empty string if depth, index, or any required node is out of bounds or nil.
 func (nav *Navigator) GetPathAtDepthAndIndex(state *NavigationState, depth, index int) string {
-	if depth < 0 || depth >= nav.maxDepth || state == nil {
+	if !bounds.InRange(depth, nav.maxDepth) || state == nil {
 		return ""
 	}
 
@@ -253,7 +435,7 @@ func (nav *Navigator) GetPathAtDepthAndIndex(state *NavigationState, depth, inde
 		parent = state.CurrentNodes[depth-1]
 	}
 
-	if parent == nil || index < 0 || index >= len(parent.Children) {
+	if parent == nil || !bounds.InRange(index, len(parent.Children)) {
 		return ""
 	}
 
@@ -17,6 +17,7 @@ func TestNewNavigator(t *testing.T) {
 	require.NotNil(t, nav)
 	assert.Equal(t, root, nav.GetRoot())
 	assert.Equal(t, maxDepth, nav.GetMaxDepth())
+	assert.True(t, nav.IsCyclic(), "navigation is cyclic by default")
 }
 
 // TestNewNavigationState tests the NavigationState constructor.
@@ -455,6 +456,108 @@ func TestNavigator_MoveDown(t *testing.T) {
 	}
 }
 
+// TestNavigator_MoveUp_NonCyclic tests that upward navigation stops at the top when cyclic
+// navigation is disabled.
+func TestNavigator_MoveUp_NonCyclic(t *testing.T) {
+	tests := []struct {
+		name          string
+		cyclic        bool
+		initialIndex  int
+		expectedMoved bool
+		expectedIndex int
+	}{
+		{
+			name:          "cyclic move up from index 0 wraps to last",
+			cyclic:        true,
+			initialIndex:  0,
+			expectedMoved: true,
+			expectedIndex: 2,
+		},
+		{
+			name:          "non-cyclic move up from index 0 stays",
+			cyclic:        false,
+			initialIndex:  0,
+			expectedMoved: false,
+			expectedIndex: 0,
+		},
+		{
+			name:          "non-cyclic move up from index 2",
+			cyclic:        false,
+			initialIndex:  2,
+			expectedMoved: true,
+			expectedIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav := NewNavigator(&Node{}, 1)
+			nav.SetCyclic(tt.cyclic)
+			state := NewNavigationState(1)
+			state.SelectedIndices[0] = tt.initialIndex
+			state.Columns[0] = make([]string, 3)
+
+			assert.Equal(t, tt.cyclic, nav.IsCyclic())
+			if !tt.cyclic {
+				assert.Equal(t, tt.expectedMoved, nav.CanMoveUp(state, 0))
+			}
+			assert.Equal(t, tt.expectedMoved, nav.MoveUp(state, 0))
+			assert.Equal(t, tt.expectedIndex, state.SelectedIndices[0])
+		})
+	}
+}
+
+// TestNavigator_MoveDown_NonCyclic tests that downward navigation stops at the bottom when
+// cyclic navigation is disabled.
+func TestNavigator_MoveDown_NonCyclic(t *testing.T) {
+	tests := []struct {
+		name          string
+		cyclic        bool
+		initialIndex  int
+		expectedMoved bool
+		expectedIndex int
+	}{
+		{
+			name:          "cyclic move down from bottom wraps to top",
+			cyclic:        true,
+			initialIndex:  2,
+			expectedMoved: true,
+			expectedIndex: 0,
+		},
+		{
+			name:          "non-cyclic move down from bottom stays",
+			cyclic:        false,
+			initialIndex:  2,
+			expectedMoved: false,
+			expectedIndex: 2,
+		},
+		{
+			name:          "non-cyclic move down from index 0",
+			cyclic:        false,
+			initialIndex:  0,
+			expectedMoved: true,
+			expectedIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav := NewNavigator(&Node{}, 1)
+			nav.SetCyclic(tt.cyclic)
+			state := NewNavigationState(1)
+			state.SelectedIndices[0] = tt.initialIndex
+			state.Columns[0] = make([]string, 3)
+
+			assert.Equal(t, tt.cyclic, nav.IsCyclic())
+			if !tt.cyclic {
+				assert.Equal(t, tt.expectedMoved, nav.CanMoveDown(state, 0))
+			}
+			assert.Equal(t, tt.expectedMoved, nav.MoveDown(state, 0))
+			assert.Equal(t, tt.expectedIndex, state.SelectedIndices[0])
+		})
+	}
+}
+
 // TestNavigator_CanMoveUp tests the CanMoveUp predicate.
 func TestNavigator_CanMoveUp(t *testing.T) {
 	tests := []struct {
@@ -1065,3 +1168,181 @@ func TestNavigator_GetPathAtDepthAndIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestNavigator_FindPath(t *testing.T) {
+	root := &Node{
+		Name: "repo",
+		Path: "/repo",
+		Children: []*Node{
+			{Name: "dev-us", Path: "/repo/dev-us", IsStack: true},
+			{
+				Name: "dev",
+				Path: "/repo/dev",
+				Children: []*Node{
+					{Name: "vpc", Path: "/repo/dev/vpc", IsStack: true},
+					{
+						Name: "apps",
+						Path: "/repo/dev/apps",
+						Children: []*Node{
+							{Name: "api", Path: "/repo/dev/apps/api", IsStack: true},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		target   string
+		expected []int
+	}{
+		{name: "first level", maxDepth: 3, target: "/repo/dev-us", expected: []int{0}},
+		{name: "nested", maxDepth: 3, target: "/repo/dev/apps/api", expected: []int{1, 1, 0}},
+		{name: "prefix of a sibling matches exactly", maxDepth: 3, target: "/repo/dev", expected: []int{1}},
+		{name: "partial name does not match", maxDepth: 3, target: "/repo/de", expected: nil},
+		{name: "no match", maxDepth: 3, target: "/repo/prod", expected: nil},
+		{name: "root has no chain", maxDepth: 3, target: "/repo", expected: nil},
+		{name: "deeper than max depth", maxDepth: 2, target: "/repo/dev/apps/api", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav := NewNavigator(root, tt.maxDepth)
+			assert.Equal(t, tt.expected, nav.FindPath(tt.target))
+		})
+	}
+
+	t.Run("stack paths in tree order", func(t *testing.T) {
+		assert.Equal(t, []string{"/repo/dev-us", "/repo/dev/vpc", "/repo/dev/apps/api"}, NewNavigator(root, 3).StackPaths())
+		assert.Equal(t, []string{"/repo/dev-us", "/repo/dev/vpc"}, NewNavigator(root, 2).StackPaths())
+	})
+}
+
+func TestNavigator_SelectPath(t *testing.T) {
+	newTree := func() *Node {
+		return &Node{
+			Name: "repo",
+			Path: "/repo",
+			Children: []*Node{
+				{Name: "dev-us", Path: "/repo/dev-us", IsStack: true},
+				{
+					Name:    "dev",
+					Path:    "/repo/dev",
+					IsStack: true,
+					Children: []*Node{
+						{Name: "rds", Path: "/repo/dev/rds", IsStack: true},
+						{Name: "vpc", Path: "/repo/dev/vpc", IsStack: true},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		expectedOK      bool
+		expectedIndices []int
+	}{
+		{name: "leaf stack", path: "/repo/dev/vpc", expectedOK: true, expectedIndices: []int{1, 1}},
+		{name: "trailing slash", path: "/repo/dev/vpc/", expectedOK: true, expectedIndices: []int{1, 1}},
+		{name: "intermediate stack resets deeper columns", path: "/repo/dev", expectedOK: true, expectedIndices: []int{1, 0}},
+		{name: "partial segment", path: "/repo/de", expectedIndices: []int{1, 0}},
+		{name: "unresolved last segment", path: "/repo/dev/s3", expectedIndices: []int{1, 0}},
+		{name: "nonexistent path", path: "/other/dev", expectedIndices: []int{1, 0}},
+		{name: "root", path: "/repo", expectedIndices: []int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav := NewNavigator(newTree(), 2)
+			state := NewNavigationState(2)
+			state.SelectedIndices[0] = 1 // Start on /repo/dev/rds.
+			nav.PropagateSelection(state)
+
+			assert.Equal(t, tt.expectedOK, nav.SelectPath(state, tt.path))
+			assert.Equal(t, tt.expectedIndices, state.SelectedIndices)
+		})
+	}
+
+	t.Run("columns show decorated names of the selected path", func(t *testing.T) {
+		nav := NewNavigator(newTree(), 2)
+		state := NewNavigationState(2)
+		require.True(t, nav.SelectPath(state, "/repo/dev/rds"))
+
+		assert.Equal(t, "dev 📦", state.Columns[0][state.SelectedIndices[0]])
+		assert.Equal(t, []string{"rds 📦", "vpc 📦"}, state.Columns[1])
+		assert.Equal(t, "/repo/dev/rds", state.CurrentNodes[1].Path)
+	})
+
+	t.Run("nil state", func(t *testing.T) {
+		assert.False(t, NewNavigator(newTree(), 2).SelectPath(nil, "/repo/dev"))
+	})
+}
+
+func TestNavigator_ZeroDepth(t *testing.T) {
+	root := &Node{Name: "repo", Path: "/repo", IsStack: true}
+	nav := NewNavigator(root, 0)
+	state := NewNavigationState(0)
+
+	assert.Nil(t, nav.PropagateSelection(state))
+	assert.Equal(t, 0, nav.GetMaxVisibleDepth(state))
+	assert.Equal(t, 0, nav.GetMaxVisibleDepth(nil))
+	assert.Nil(t, nav.GetNodeAtDepth(state, 0))
+	assert.False(t, nav.CanMoveUp(state, 0))
+	assert.False(t, nav.CanMoveDown(state, 0))
+	assert.False(t, nav.MoveUp(state, 0))
+	assert.False(t, nav.MoveDown(state, 0))
+	assert.Empty(t, nav.GetPathAtDepthAndIndex(state, 0, 0))
+	assert.Equal(t, "/repo", nav.GetNavigationPath(state, 0))
+	assert.Nil(t, nav.FindPath("/repo"))
+	assert.False(t, nav.SelectPath(state, "/repo"))
+	assert.Empty(t, nav.StackPaths())
+
+	var nilNav *Navigator
+	assert.Equal(t, 0, nilNav.GetMaxVisibleDepth(state))
+}
+
+// TestNavigator_StacksOnly tests that stacks-only mode hides plain directories listed next
+// to stacks, keeps columns without stacks whole and moves hidden selections.
+func TestNavigator_StacksOnly(t *testing.T) {
+	root := &Node{
+		Name: "root",
+		Children: []*Node{
+			{Name: "modules", Children: []*Node{{Name: "vpc", IsStack: true}}},
+			{Name: "app", IsStack: true},
+			{Name: "env", Children: []*Node{
+				{Name: "dev", Children: []*Node{{Name: "db", IsStack: true}}},
+				{Name: "prod", Children: []*Node{{Name: "db", IsStack: true}}},
+			}},
+			{Name: "web", IsStack: true},
+		},
+	}
+	nav := NewNavigator(root, 3)
+	state := NewNavigationState(3)
+	nav.PropagateSelection(state)
+
+	assert.False(t, nav.IsStacksOnly())
+	assert.Equal(t, state.Columns[0], nav.ShownItems(state, 0), "every item is shown by default")
+
+	nav.SetStacksOnly(true)
+	nav.PropagateSelection(state)
+
+	assert.True(t, nav.IsStacksOnly())
+	assert.Equal(t, []string{"app 📦", "web 📦"}, nav.ShownItems(state, 0))
+	assert.Equal(t, 1, state.SelectedIndices[0], "the hidden selection moves to the first stack")
+	assert.False(t, nav.IsShown(state, 0, 0))
+	assert.True(t, nav.IsShown(state, 0, 1))
+	assert.False(t, nav.IsShown(state, 0, 4), "out of range")
+
+	// A column of plain directories keeps them so the stacks below stay reachable.
+	nav.SetStacksOnly(false)
+	state.SelectedIndices[0] = 2
+	nav.PropagateSelection(state)
+	nav.SetStacksOnly(true)
+	assert.Equal(t, []string{"dev", "prod"}, nav.ShownItems(state, 1))
+	assert.True(t, nav.IsShown(state, 1, 1))
+	assert.Empty(t, nav.ShownItems(state, 5), "invalid depth")
+}
@@ -0,0 +1,75 @@
+package stack
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/spf13/afero"
+)
+
+// TreeOptions controls which directories a tree scan descends into and which are stacks.
+// The zero value scans with the defaults.
+type TreeOptions struct {
+	IgnoreDirs             []string // Glob patterns of directories to exclude (see FindAndBuildTreeWithIgnore)
+	StackMarkers           []string // Files that make a directory a stack; empty uses config.DefaultStackMarkers
+	SkipDirectories        []string // Directory names never descended into, added to config.DefaultSkipDirectories
+	ReplaceSkipDirectories bool     // Use SkipDirectories instead of the defaults rather than alongside them
+	Parallelism            int      // Directories scanned concurrently; 0 uses GOMAXPROCS, 1 scans sequentially
+	RespectGitignore       bool     // Also exclude directories ignored by .gitignore files found during the scan
+}
+
+// skipDirectories returns the directory names the scan never descends into.
+func (o TreeOptions) skipDirectories() []string {
+	if o.ReplaceSkipDirectories {
+		return o.SkipDirectories
+	}
+	return append(slices.Clone(config.DefaultSkipDirectories), o.SkipDirectories...)
+}
+
+// IsStack reports whether the directory at path holds one of the stack markers, as a
+// scan with these options decides.
+func (o TreeOptions) IsStack(path string) bool {
+	return scanRules{markers: o.StackMarkers}.isStack(path)
+}
+
+// rules resolves the options for a scan rooted at rootDir, loading its .terraxignore file.
+func (o TreeOptions) rules(rootDir string) (scanRules, error) {
+	ignore, err := newIgnoreMatcher(rootDir, o.IgnoreDirs)
+	if err != nil {
+		return scanRules{}, err
+	}
+	rules := scanRules{
+		ignore:      ignore,
+		markers:     o.StackMarkers,
+		skipDirs:    o.skipDirectories(),
+		parallelism: o.Parallelism,
+	}
+	if o.RespectGitignore {
+		rules.gitignore = newGitignoreMatcher(afero.NewOsFs(), rootDir)
+	}
+	return rules, nil
+}
+
+// scanRules are the resolved TreeOptions shared by every level of a scan.
+type scanRules struct {
+	ignore      *ignoreMatcher    // Nil matches nothing
+	gitignore   *gitignoreMatcher // Nil unless RespectGitignore is set
+	markers     []string          // Empty uses config.DefaultStackMarkers
+	skipDirs    []string          // Directory names never descended into
+	parallelism int               // Goroutines building subtrees; 0 uses GOMAXPROCS
+}
+
+// skips reports whether the scan leaves out the directory at path: hidden directories,
+// directories named in skipDirs and those matched by ignore or gitignore.
+func (r scanRules) skips(path string) bool {
+	name := filepath.Base(path)
+	return strings.HasPrefix(name, ".") || slices.Contains(r.skipDirs, name) || r.ignore.Match(path) ||
+		r.gitignore.Match(path)
+}
+
+// isStack reports whether the directory at path holds one of the stack markers.
+func (r scanRules) isStack(path string) bool {
+	return hasStackMarker(path, r.markers)
+}
@@ -0,0 +1,38 @@
+package stack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigPreview holds the first lines of a stack's terragrunt.hcl.
+type ConfigPreview struct {
+	Lines      []string // Up to the requested number of lines, without line endings
+	TotalLines int      // Number of lines in the whole file
+}
+
+// ReadConfigPreview reads the first maxLines lines of the terragrunt.hcl in stackPath
+// and counts the rest. It fails when the file is missing or cannot be read.
+func ReadConfigPreview(stackPath string, maxLines int) (ConfigPreview, error) {
+	filePath := filepath.Join(stackPath, "terragrunt.hcl")
+	file, err := os.Open(filePath)
+	if err != nil {
+		return ConfigPreview{}, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	defer file.Close()
+
+	var preview ConfigPreview
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if preview.TotalLines < maxLines {
+			preview.Lines = append(preview.Lines, scanner.Text())
+		}
+		preview.TotalLines++
+	}
+	if err := scanner.Err(); err != nil {
+		return ConfigPreview{}, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	return preview, nil
+}
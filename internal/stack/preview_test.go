@@ -0,0 +1,31 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConfigPreview(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := ReadConfigPreview(tmpDir, 10)
+	assert.ErrorContains(t, err, "failed to open")
+
+	content := strings.Join([]string{"include \"root\" {", "  path = \"root.hcl\"", "}", "", "inputs = {}"}, "\n")
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "terragrunt.hcl"), []byte(content), 0644))
+
+	preview, err := ReadConfigPreview(tmpDir, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"include \"root\" {", "  path = \"root.hcl\"", "}", "", "inputs = {}"}, preview.Lines)
+	assert.Equal(t, 5, preview.TotalLines)
+
+	preview, err = ReadConfigPreview(tmpDir, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"include \"root\" {", "  path = \"root.hcl\""}, preview.Lines)
+	assert.Equal(t, 5, preview.TotalLines, "lines past the limit are still counted")
+}
@@ -0,0 +1,104 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/deps"
+)
+
+// ScanStats summarizes the tree FindAndBuildTreeWithOptions would build.
+type ScanStats struct {
+	Stacks   int `json:"stacks"`    // Stack directories in the tree
+	MaxDepth int `json:"max_depth"` // Depth of the deepest node in the tree
+	Skipped  int `json:"skipped"`   // Directories not descended into: hidden, tool caches or ignored
+}
+
+// passed reports whether more than maxStacks stacks were counted; never when maxStacks is 0
+// or less.
+func (s ScanStats) passed(maxStacks int) bool {
+	return maxStacks > 0 && s.Stacks > maxStacks
+}
+
+// ScanTree walks rootDir with the same rules as FindAndBuildTreeWithOptions and counts what
+// the tree would contain, without allocating nodes or parsing dependencies. It is meant for
+// large repositories where only the numbers are needed.
+func ScanTree(rootDir, rootConfigFile string, opts TreeOptions) (ScanStats, error) {
+	return ScanTreeLimit(rootDir, rootConfigFile, opts, 0)
+}
+
+// ScanTreeLimit is ScanTree stopping as soon as more than maxStacks stacks are found, so
+// checking a large repository against a limit does not walk all of it. When it stops early,
+// Stacks is maxStacks+1 and the other counts cover only the part walked. A maxStacks of 0 or
+// less walks the whole tree.
+func ScanTreeLimit(rootDir, rootConfigFile string, opts TreeOptions, maxStacks int) (ScanStats, error) {
+	if rootConfigFile == "" {
+		rootConfigFile = config.DefaultRootConfigFile
+	}
+
+	absPath, err := resolveScanRoot(rootDir)
+	if err != nil {
+		return ScanStats{}, err
+	}
+
+	rules, err := opts.rules(absPath)
+	if err != nil {
+		return ScanStats{}, err
+	}
+
+	var stats ScanStats
+	repoRoot := deps.FindRepoRoot(absPath, rootConfigFile)
+	projectRoots, err := splitProjectRoots(absPath, repoRoot, rootConfigFile, rules.ignore)
+	if err != nil {
+		return ScanStats{}, err
+	}
+	if len(projectRoots) > 0 {
+		for _, projectRoot := range projectRoots {
+			if stats.passed(maxStacks) {
+				break
+			}
+			scanDirectory(projectRoot, 1, &stats, rules, maxStacks)
+		}
+		return stats, nil
+	}
+
+	scanDirectory(absPath, 0, &stats, rules, maxStacks)
+	return stats, nil
+}
+
+// scanDirectory counts dirPath (at the given depth) and its subtree into stats, following
+// buildTreeRecursive. It reports whether dirPath would be kept in the tree, i.e. whether it
+// is a stack or contains stacks. It stops once more than maxStacks stacks are counted.
+func scanDirectory(dirPath string, depth int, stats *ScanStats, rules scanRules, maxStacks int) bool {
+	kept := false
+	if entries, err := os.ReadDir(dirPath); err == nil {
+		for _, entry := range entries {
+			if stats.passed(maxStacks) {
+				return kept
+			}
+			if !entry.IsDir() {
+				continue
+			}
+
+			childPath := filepath.Join(dirPath, entry.Name())
+			if rules.skips(childPath) {
+				stats.Skipped++
+				continue
+			}
+
+			if scanDirectory(childPath, depth+1, stats, rules, maxStacks) {
+				kept = true
+			}
+		}
+	}
+
+	if rules.isStack(dirPath) {
+		stats.Stacks++
+		kept = true
+	}
+	if kept && depth > stats.MaxDepth {
+		stats.MaxDepth = depth
+	}
+	return kept
+}
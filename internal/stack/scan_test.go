@@ -0,0 +1,130 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeScanFixture creates stack directories (with a terragrunt.hcl) and plain
+// directories under root.
+func writeScanFixture(t *testing.T, root string, stacks, dirs []string) {
+	t.Helper()
+	for _, dir := range dirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	for _, dir := range stacks {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+}
+
+// countTreeStacks returns the number of stack nodes in the tree rooted at node.
+func countTreeStacks(node *Node) int {
+	count := 0
+	if node.IsStack {
+		count++
+	}
+	for _, child := range node.Children {
+		count += countTreeStacks(child)
+	}
+	return count
+}
+
+func TestScanTree_MatchesBuiltTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeScanFixture(t, tmpDir,
+		[]string{"", "dev/vpc", "dev/rds", "prod/us-east-1/vpc", "prod/us-east-1/vpc/peering"},
+		[]string{"docs/images", "prod/us-east-1/.terraform", ".git/objects", "vendor/mod"},
+	)
+
+	stats, err := ScanTree(tmpDir, "", TreeOptions{})
+	require.NoError(t, err)
+
+	tree, maxDepth, err := FindAndBuildTree(tmpDir, "")
+	require.NoError(t, err)
+	stackPaths, err := CollectStackPaths(tmpDir, TreeOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, len(stackPaths), stats.Stacks)
+	assert.Equal(t, countTreeStacks(tree), stats.Stacks)
+	assert.Equal(t, maxDepth, stats.MaxDepth)
+	assert.Equal(t, 3, stats.Skipped, ".git, vendor and .terraform")
+}
+
+func TestScanTreeLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeScanFixture(t, tmpDir, []string{"a/vpc", "b/vpc", "c/vpc", "d/vpc", "e/vpc"}, nil)
+
+	tests := []struct {
+		name      string
+		maxStacks int
+		expected  int
+	}{
+		{name: "stops once past the limit", maxStacks: 2, expected: 3},
+		{name: "limit above the count walks everything", maxStacks: 10, expected: 5},
+		{name: "limit at the count walks everything", maxStacks: 5, expected: 5},
+		{name: "no limit", maxStacks: 0, expected: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, err := ScanTreeLimit(tmpDir, "", TreeOptions{}, tt.maxStacks)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, stats.Stacks)
+		})
+	}
+}
+
+func TestScanTree_IgnoredDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeScanFixture(t, tmpDir,
+		[]string{"dev/vpc", "legacy/old/deep/vpc"},
+		nil,
+	)
+	ignoreDirs := []string{"legacy"}
+
+	stats, err := ScanTree(tmpDir, "", TreeOptions{IgnoreDirs: ignoreDirs})
+	require.NoError(t, err)
+
+	tree, maxDepth, err := FindAndBuildTreeWithIgnore(tmpDir, "", ignoreDirs)
+	require.NoError(t, err)
+
+	assert.Equal(t, countTreeStacks(tree), stats.Stacks)
+	assert.Equal(t, 1, stats.Stacks)
+	assert.Equal(t, maxDepth, stats.MaxDepth)
+	assert.Equal(t, 2, stats.MaxDepth)
+	assert.Equal(t, 1, stats.Skipped)
+}
+
+func TestScanTree_MonorepoOfProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeScanFixture(t, tmpDir,
+		[]string{"team-a/network/dev/vpc", "team-b/dev/rds", "team-b"},
+		[]string{"team-c/empty"},
+	)
+	for _, project := range []string{"team-a/network", "team-b", "team-c"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, project, "root.hcl"), []byte(""), 0644))
+	}
+
+	stats, err := ScanTree(tmpDir, "root.hcl", TreeOptions{})
+	require.NoError(t, err)
+
+	tree, maxDepth, err := FindAndBuildTree(tmpDir, "root.hcl")
+	require.NoError(t, err)
+
+	assert.Equal(t, countTreeStacks(tree), stats.Stacks)
+	assert.Equal(t, 3, stats.Stacks)
+	assert.Equal(t, maxDepth, stats.MaxDepth)
+}
+
+func TestScanTree_InvalidPath(t *testing.T) {
+	_, err := ScanTree("", "", TreeOptions{})
+	assert.Error(t, err)
+
+	_, err = ScanTree(filepath.Join(t.TempDir(), "missing"), "", TreeOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveTree writes the tree rooted at root to filePath as indented JSON.
+// The file can be read back with LoadTree to skip filesystem scanning.
+func SaveTree(root *Node, filePath string) error {
+	if root == nil {
+		return fmt.Errorf("tree cannot be nil")
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize tree: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write tree file: %w", err)
+	}
+	return nil
+}
+
+// LoadTree reads a tree previously written by SaveTree and validates its shape.
+// It returns the root node and maximum depth, matching FindAndBuildTree.
+func LoadTree(filePath string) (*Node, int, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read tree file: %w", err)
+	}
+
+	var root *Node
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse tree file: %w", err)
+	}
+	if root == nil {
+		return nil, 0, fmt.Errorf("tree file %s is empty", filePath)
+	}
+
+	maxDepth := 0
+	if err := validateTree(root, 0, &maxDepth); err != nil {
+		return nil, 0, fmt.Errorf("invalid tree file %s: %w", filePath, err)
+	}
+	return root, maxDepth, nil
+}
+
+// validateTree checks that every node has a name and path and that depths increase by
+// exactly one from parent to child, tracking the deepest level in maxDepth.
+func validateTree(node *Node, depth int, maxDepth *int) error {
+	if node.Path == "" {
+		return fmt.Errorf("node at depth %d has no path", depth)
+	}
+	if depth > 0 && node.Name == "" {
+		return fmt.Errorf("node %s has no name", node.Path)
+	}
+	if node.Depth != depth {
+		return fmt.Errorf("node %s has depth %d, expected %d", node.Path, node.Depth, depth)
+	}
+	if depth > *maxDepth {
+		*maxDepth = depth
+	}
+
+	for _, child := range node.Children {
+		if child == nil {
+			return fmt.Errorf("node %s has a null child", node.Path)
+		}
+		if err := validateTree(child, depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
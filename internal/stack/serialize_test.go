@@ -0,0 +1,86 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveTreeLoadTree_RoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "env", "dev", "vpc"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "env", "dev", "rds"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "root.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "env", "dev", "vpc", "terragrunt.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "env", "dev", "rds", "terragrunt.hcl"),
+		[]byte("dependency \"vpc\" {\n  config_path = \"../vpc\"\n}\n"), 0644))
+
+	tree, maxDepth, err := FindAndBuildTree(tmpDir, "root.hcl")
+	require.NoError(t, err)
+
+	treeFile := filepath.Join(t.TempDir(), "tree.json")
+	require.NoError(t, SaveTree(tree, treeFile))
+
+	loaded, loadedDepth, err := LoadTree(treeFile)
+	require.NoError(t, err)
+	assert.Equal(t, maxDepth, loadedDepth)
+	assert.True(t, tree.Equal(loaded), "loaded tree should equal the saved tree")
+
+	// Equal must detect differences deep in the tree.
+	loaded.Children[0].Children[0].Children[0].IsStack = false
+	assert.False(t, tree.Equal(loaded))
+}
+
+func TestLoadTree_InvalidShape(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{
+			name:    "not json",
+			content: "not json",
+			errMsg:  "failed to parse tree file",
+		},
+		{
+			name:    "null tree",
+			content: "null",
+			errMsg:  "is empty",
+		},
+		{
+			name:    "missing path",
+			content: `{"name":"root","depth":0,"children":[{"name":"env","depth":1}]}`,
+			errMsg:  "has no path",
+		},
+		{
+			name:    "inconsistent depth",
+			content: `{"name":"root","path":"/r","depth":0,"children":[{"name":"env","path":"/r/env","depth":2}]}`,
+			errMsg:  "has depth 2, expected 1",
+		},
+		{
+			name:    "null child",
+			content: `{"name":"root","path":"/r","depth":0,"children":[null]}`,
+			errMsg:  "null child",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			treeFile := filepath.Join(t.TempDir(), "tree.json")
+			require.NoError(t, os.WriteFile(treeFile, []byte(tt.content), 0644))
+
+			_, _, err := LoadTree(treeFile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestLoadTree_MissingFile(t *testing.T) {
+	_, _, err := LoadTree(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read tree file")
+}
@@ -0,0 +1,35 @@
+package stack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// SettingsFileName is the optional file in a stack directory holding stack-local settings.
+const SettingsFileName = ".terrax-stack.yaml"
+
+// Settings holds the stack-local settings read from SettingsFileName.
+type Settings struct {
+	AllowedCommands []string `yaml:"allowed_commands"` // Commands offered for the stack (nil = all)
+}
+
+// LoadSettings reads the settings file in stackPath. A missing file yields empty settings.
+func LoadSettings(stackPath string) (Settings, error) {
+	filePath := filepath.Join(stackPath, SettingsFileName)
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+
+	var settings Settings
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+	return settings, nil
+}
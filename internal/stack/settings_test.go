@@ -0,0 +1,28 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	settings, err := LoadSettings(tmpDir)
+	require.NoError(t, err)
+	assert.Nil(t, settings.AllowedCommands, "a missing file allows all commands")
+
+	content := "allowed_commands:\n  - plan\n  - validate\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, SettingsFileName), []byte(content), 0644))
+	settings, err = LoadSettings(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plan", "validate"}, settings.AllowedCommands)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, SettingsFileName), []byte("allowed_commands: [plan"), 0644))
+	_, err = LoadSettings(tmpDir)
+	assert.ErrorContains(t, err, "failed to parse")
+}
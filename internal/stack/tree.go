@@ -5,6 +5,15 @@
 // designed to be UI-agnostic and testable without any framework dependencies.
 package stack
 
+import (
+	"slices"
+
+	"github.com/israoo/terrax/internal/bounds"
+)
+
+// StackMarker is appended to the names of stacks listed by GetChildNames.
+const StackMarker = " 📦"
+
 // Node represents a directory node in the stack tree.
 type Node struct {
 	Name         string   `json:"name"`
@@ -15,6 +24,7 @@ type Node struct {
 	Dependencies []string `json:"dependencies"`
 	Dependents   []string `json:"dependents"`
 	InCycle      bool     `json:"inCycle"`
+	IsProject    bool     `json:"isProject"`
 }
 
 func (n *Node) GetChildren() []*Node {
@@ -37,7 +47,7 @@ func (n *Node) GetChildNames() []string {
 	for i, child := range n.Children {
 		marker := ""
 		if child.IsStack {
-			marker = " 📦"
+			marker = StackMarker
 		}
 		names[i] = child.Name + marker
 	}
@@ -45,8 +55,53 @@ func (n *Node) GetChildNames() []string {
 }
 
 func (n *Node) FindChildByIndex(index int) *Node {
-	if !n.HasChildren() || index < 0 || index >= len(n.Children) {
+	if !n.HasChildren() || !bounds.InRange(index, len(n.Children)) {
 		return nil
 	}
 	return n.Children[index]
 }
+
+// GroupStacksFirst reorders the children of n and all its descendants so stack nodes
+// come before plain directories, keeping the existing order within each group.
+func (n *Node) GroupStacksFirst() {
+	if n == nil {
+		return
+	}
+	slices.SortStableFunc(n.Children, func(a, b *Node) int {
+		switch {
+		case a.IsStack == b.IsStack:
+			return 0
+		case a.IsStack:
+			return -1
+		default:
+			return 1
+		}
+	})
+	for _, child := range n.Children {
+		child.GroupStacksFirst()
+	}
+}
+
+// Equal reports whether n and other describe the same tree: same node fields and
+// pairwise-equal children in the same order. Nil and empty slices are treated as equal.
+func (n *Node) Equal(other *Node) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	if n.Name != other.Name || n.Path != other.Path || n.IsStack != other.IsStack ||
+		n.Depth != other.Depth || n.InCycle != other.InCycle || n.IsProject != other.IsProject {
+		return false
+	}
+	if !slices.Equal(n.Dependencies, other.Dependencies) || !slices.Equal(n.Dependents, other.Dependents) {
+		return false
+	}
+	if len(n.Children) != len(other.Children) {
+		return false
+	}
+	for i := range n.Children {
+		if !n.Children[i].Equal(other.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
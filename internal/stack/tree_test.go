@@ -2,6 +2,7 @@ package stack
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/afero"
@@ -620,6 +621,118 @@ func TestFindAndBuildTree_RealFilesystem(t *testing.T) {
 	assert.GreaterOrEqual(t, maxDepth, 0, "max depth should be non-negative")
 }
 
+// TestFindAndBuildTreeWithOptions_StackMarkers tests that configured marker files decide which directories are stacks.
+func TestFindAndBuildTreeWithOptions_StackMarkers(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"live/vpc":  "terragrunt.hcl",
+		"tf/bucket": "main.tf",
+		"docs":      "README.md",
+	}
+	for dir, file := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, file), []byte(""), 0644))
+	}
+
+	// The default marker is terragrunt.hcl alone.
+	tree, _, err := FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"live"}, tree.GetChildNames())
+
+	tree, _, err = FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{StackMarkers: []string{"terragrunt.hcl", "main.tf"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"live", "tf"}, tree.GetChildNames())
+
+	bucket := tree.Children[1].Children[0]
+	assert.True(t, bucket.IsStack)
+	assert.Equal(t, []string{"bucket 📦"}, tree.Children[1].GetChildNames())
+
+	// Only the configured markers count once any are set.
+	tree, _, err = FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{StackMarkers: []string{"main.tf"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tf"}, tree.GetChildNames())
+}
+
+// TestFindAndBuildTreeWithOptions_SkipDirectories tests that configured skip directories are
+// merged with the defaults or replace them.
+func TestFindAndBuildTreeWithOptions_SkipDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"live/vpc", "node_modules/pkg", "vendor/mod"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+
+	tests := []struct {
+		name     string
+		opts     TreeOptions
+		expected []string
+	}{
+		{name: "defaults", opts: TreeOptions{}, expected: []string{"live", "node_modules"}},
+		{
+			name:     "merged with defaults",
+			opts:     TreeOptions{SkipDirectories: []string{"node_modules"}},
+			expected: []string{"live"},
+		},
+		{
+			name:     "replacing defaults",
+			opts:     TreeOptions{SkipDirectories: []string{"node_modules"}, ReplaceSkipDirectories: true},
+			expected: []string{"live", "vendor"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, _, err := FindAndBuildTreeWithOptions(tmpDir, "", tt.opts)
+			require.NoError(t, err)
+			var names []string
+			for _, child := range tree.Children {
+				names = append(names, child.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+
+			stats, err := ScanTree(tmpDir, "", tt.opts)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.expected), stats.Stacks, "ScanTree should apply the same skip list")
+		})
+	}
+}
+
+// TestFindAndBuildTreeWithOptions_Parallelism tests that building subtrees concurrently gives
+// the same tree, in the same order, as a sequential scan.
+func TestFindAndBuildTreeWithOptions_Parallelism(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, env := range []string{"dev", "prod", "staging"} {
+		for _, svc := range []string{"app", "db", "dns", "vpc"} {
+			dir := filepath.Join(tmpDir, env, "us-east-1", svc)
+			require.NoError(t, os.MkdirAll(dir, 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "terragrunt.hcl"), []byte(""), 0644))
+		}
+		// Directories without stacks are still pruned.
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, env, "docs", "img"), 0755))
+	}
+
+	var paths func(node *Node) []string
+	paths = func(node *Node) []string {
+		result := []string{node.Path}
+		for _, child := range node.Children {
+			result = append(result, paths(child)...)
+		}
+		return result
+	}
+
+	sequential, sequentialDepth, err := FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{Parallelism: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, sequentialDepth)
+	assert.Len(t, paths(sequential), 1+3*(1+1+4))
+
+	for _, parallelism := range []int{0, 2, 16} {
+		tree, maxDepth, err := FindAndBuildTreeWithOptions(tmpDir, "", TreeOptions{Parallelism: parallelism})
+		require.NoError(t, err)
+		assert.Equal(t, sequentialDepth, maxDepth, "parallelism %d", parallelism)
+		assert.Equal(t, paths(sequential), paths(tree), "parallelism %d", parallelism)
+	}
+}
+
 // TestFindAndBuildTree_InvalidPath tests error handling for invalid paths.
 func TestFindAndBuildTree_InvalidPath(t *testing.T) {
 	tests := []struct {
@@ -884,7 +997,7 @@ func TestBuildTreeRecursive_RealFilesystem(t *testing.T) {
 	maxDepth := 0
 
 	// Call the production buildTreeRecursive (uses os.ReadDir).
-	err := buildTreeRecursive(root, &maxDepth, "")
+	err := buildTreeRecursive(root, &maxDepth, "", scanRules{})
 
 	// Assertions.
 	require.NoError(t, err, "should build tree without error")
@@ -922,7 +1035,7 @@ func TestBuildTreeRecursive_ErrorOnNonexistentPath(t *testing.T) {
 	maxDepth := 0
 
 	// Call buildTreeRecursive with a nonexistent path.
-	err := buildTreeRecursive(root, &maxDepth, "")
+	err := buildTreeRecursive(root, &maxDepth, "", scanRules{})
 
 	// Should not return an error (errors are swallowed in buildTreeRecursive).
 	assert.NoError(t, err, "buildTreeRecursive swallows ReadDir errors")
@@ -930,3 +1043,77 @@ func TestBuildTreeRecursive_ErrorOnNonexistentPath(t *testing.T) {
 	// Should have no children since the directory doesn't exist.
 	assert.Empty(t, root.Children)
 }
+
+func TestFindProjectRoots_SiblingProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(tmpDir+"/team-a/network/dev/vpc", 0755))
+	require.NoError(t, os.MkdirAll(tmpDir+"/team-b/dev/rds", 0755))
+	require.NoError(t, os.MkdirAll(tmpDir+"/.hidden", 0755))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-a/network/root.hcl", []byte(""), 0644))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-b/root.hcl", []byte(""), 0644))
+	require.NoError(t, os.WriteFile(tmpDir+"/.hidden/root.hcl", []byte(""), 0644))
+	// A root config below a project root is not a separate project.
+	require.NoError(t, os.WriteFile(tmpDir+"/team-b/dev/root.hcl", []byte(""), 0644))
+
+	roots, err := FindProjectRoots(tmpDir, "root.hcl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{tmpDir + "/team-a/network", tmpDir + "/team-b"}, roots)
+
+	// Scanning from inside a project returns only that project.
+	roots, err = FindProjectRoots(tmpDir+"/team-b", "root.hcl")
+	require.NoError(t, err)
+	assert.Equal(t, []string{tmpDir + "/team-b"}, roots)
+}
+
+func TestFindAndBuildTree_MonorepoOfProjects(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(tmpDir+"/team-a/network/dev/vpc", 0755))
+	require.NoError(t, os.MkdirAll(tmpDir+"/team-b/dev/rds", 0755))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-a/network/root.hcl", []byte(""), 0644))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-b/root.hcl", []byte(""), 0644))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-a/network/dev/vpc/terragrunt.hcl", []byte(""), 0644))
+	require.NoError(t, os.WriteFile(tmpDir+"/team-b/dev/rds/terragrunt.hcl", []byte(""), 0644))
+
+	tree, maxDepth, err := FindAndBuildTree(tmpDir, "root.hcl")
+	require.NoError(t, err)
+
+	require.Len(t, tree.Children, 2, "first level should hold one node per project")
+	assert.Equal(t, "team-a/network", tree.Children[0].Name)
+	assert.Equal(t, tmpDir+"/team-a/network", tree.Children[0].Path)
+	assert.True(t, tree.Children[0].IsProject)
+	assert.Equal(t, "team-b", tree.Children[1].Name)
+	assert.True(t, tree.Children[1].IsProject)
+	assert.Equal(t, 3, maxDepth, "project -> dev -> stack")
+
+	// A single project keeps the plain directory tree.
+	tree, _, err = FindAndBuildTree(tmpDir+"/team-b", "root.hcl")
+	require.NoError(t, err)
+	require.Len(t, tree.Children, 1)
+	assert.Equal(t, "dev", tree.Children[0].Name)
+	assert.False(t, tree.Children[0].IsProject)
+}
+
+func TestNode_GroupStacksFirst(t *testing.T) {
+	root := &Node{
+		Name: "root",
+		Children: []*Node{
+			{Name: "modules"},
+			{Name: "dev", IsStack: true, Children: []*Node{
+				{Name: "shared"},
+				{Name: "vpc", IsStack: true},
+			}},
+			{Name: "docs"},
+			{Name: "prod", IsStack: true},
+		},
+	}
+
+	root.GroupStacksFirst()
+
+	assert.Equal(t, []string{"dev 📦", "prod 📦", "modules", "docs"}, root.GetChildNames())
+	assert.Equal(t, []string{"vpc 📦", "shared"}, root.Children[0].GetChildNames())
+
+	var nilNode *Node
+	assert.NotPanics(t, func() { nilNode.GroupStacksFirst() })
+}
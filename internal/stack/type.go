@@ -0,0 +1,39 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Stack types reported by DetectType.
+const (
+	TypeTerragrunt = "terragrunt" // The directory holds a terragrunt.hcl.
+	TypeTerraform  = "terraform"  // The directory holds Terraform/OpenTofu files but no terragrunt.hcl.
+)
+
+// DetectType returns the type of the stack in dirPath: TypeTerragrunt when it holds a
+// terragrunt.hcl, TypeTerraform when it only holds .tf or .tofu files, or "" otherwise.
+func DetectType(dirPath string) string {
+	if isStackDirectory(dirPath) {
+		return TypeTerragrunt
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".tf", ".tofu":
+			return TypeTerraform
+		}
+		if strings.HasSuffix(entry.Name(), ".tf.json") {
+			return TypeTerraform
+		}
+	}
+	return ""
+}
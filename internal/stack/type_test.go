@@ -0,0 +1,37 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		expected string
+	}{
+		{name: "terragrunt stack", files: []string{"terragrunt.hcl"}, expected: TypeTerragrunt},
+		{name: "terragrunt wins over terraform files", files: []string{"terragrunt.hcl", "main.tf"}, expected: TypeTerragrunt},
+		{name: "plain terraform", files: []string{"main.tf", "variables.tf"}, expected: TypeTerraform},
+		{name: "opentofu files", files: []string{"main.tofu"}, expected: TypeTerraform},
+		{name: "terraform json", files: []string{"main.tf.json"}, expected: TypeTerraform},
+		{name: "no stack files", files: []string{"README.md"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, file := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(""), 0644))
+			}
+			assert.Equal(t, tt.expected, DetectType(dir))
+		})
+	}
+
+	assert.Empty(t, DetectType(filepath.Join(t.TempDir(), "missing")))
+}
@@ -0,0 +1,41 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// IsBreadcrumbMode reports whether the breadcrumb bar has focus.
+func (m Model) IsBreadcrumbMode() bool {
+	return m.breadcrumbMode
+}
+
+// openBreadcrumbMode gives the breadcrumb bar focus with the focused level selected.
+// Nothing happens while the commands column has focus, as the bar shows no levels then.
+func (m Model) openBreadcrumbMode() Model {
+	depth := m.getNavigationDepth()
+	if m.navigator == nil || depth < 0 {
+		return m
+	}
+	m = m.leaveFilterEditing()
+	m.breadcrumbMode = true
+	m.breadcrumbCursor = depth
+	return m
+}
+
+// handleBreadcrumbInput handles keys while the breadcrumb bar has focus: left/right select
+// an ancestor level of the focused column, enter focuses the column of that level and
+// esc (or the mode key again) returns to the columns unchanged.
+func (m Model) handleBreadcrumbInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case KeyCtrlC:
+		return m, tea.Quit
+	case KeyEsc, KeyBreadcrumb:
+		m.breadcrumbMode = false
+	case KeyLeft, KeyVimLeft:
+		m.breadcrumbCursor = max(m.breadcrumbCursor-1, 0)
+	case KeyRight, KeyVimRight:
+		m.breadcrumbCursor = min(m.breadcrumbCursor+1, max(m.getNavigationDepth(), 0))
+	case KeyEnter:
+		m.breadcrumbMode = false
+		return m.focusDepth(m.breadcrumbCursor), nil
+	}
+	return m, nil
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/atotto/clipboard"
+)
+
+// ClipboardWriter copies text to the system clipboard.
+type ClipboardWriter interface {
+	WriteAll(text string) error
+}
+
+// errNoClipboard is returned by the system clipboard when the platform offers none
+// (e.g. a headless Linux box without xclip, xsel or wl-copy).
+var errNoClipboard = errors.New("no clipboard available")
+
+// systemClipboard writes to the operating system's clipboard.
+type systemClipboard struct{}
+
+// WriteAll copies text to the system clipboard.
+func (systemClipboard) WriteAll(text string) error {
+	if clipboard.Unsupported {
+		return errNoClipboard
+	}
+	return clipboard.WriteAll(text)
+}
+
+// clipboardFallback receives the text to copy when no clipboard is available
+// (can be overridden in tests).
+var clipboardFallback io.Writer = os.Stderr
+
+// WithClipboard returns a copy of the model that copies the selected stack path with
+// cb when y is pressed, instead of the system clipboard.
+func (m Model) WithClipboard(cb ClipboardWriter) Model {
+	m.clipboard = cb
+	return m
+}
+
+// handleCopyPath copies the selected stack path to the clipboard and flashes a notice in
+// the footer. Without a usable clipboard the path is printed to stderr instead.
+func (m Model) handleCopyPath() Model {
+	path := m.GetSelectedStackPath()
+	if path == NoItemSelected {
+		return m
+	}
+
+	cb := m.clipboard
+	if cb == nil {
+		cb = systemClipboard{}
+	}
+	if err := cb.WriteAll(path); err != nil {
+		fmt.Fprintln(clipboardFallback, path)
+		m.notice = m.Text(MsgPathPrinted)
+		return m
+	}
+	m.notice = m.Text(MsgPathCopied)
+	return m
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// fakeClipboard records the text written to it, or fails with err.
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+func (c *fakeClipboard) WriteAll(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.text = text
+	return nil
+}
+
+// TestModel_CopyPath tests that y copies the focused stack path to the clipboard and
+// falls back to stderr when no clipboard is available.
+func TestModel_CopyPath(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/repo",
+		Children: []*stack.Node{
+			{Name: "dev", Path: "/repo/dev", IsStack: true},
+			{Name: "prod", Path: "/repo/prod", IsStack: true},
+		},
+	}
+
+	newModel := func(cb ClipboardWriter) Model {
+		m := NewModel(root, 1, testCommands, 3).WithClipboard(cb)
+		m.focusedColumn = 1
+		m.navState.SelectedIndices[0] = 1
+		m.navigator.PropagateSelection(m.navState)
+		return m
+	}
+
+	t.Run("copies the selected stack path", func(t *testing.T) {
+		cb := &fakeClipboard{}
+		updated, cmd := newModel(cb).Update(runesMsg(KeyCopy))
+		m := updated.(Model)
+
+		assert.Nil(t, cmd)
+		assert.Equal(t, "/repo/prod", cb.text)
+		assert.Equal(t, m.Text(MsgPathCopied), m.notice)
+
+		// The notice is a flash: the next key clears it.
+		updated, _ = m.Update(runesMsg(KeyVimDown))
+		assert.Empty(t, updated.(Model).notice)
+	})
+
+	t.Run("no clipboard prints the path to stderr", func(t *testing.T) {
+		var stderr bytes.Buffer
+		original := clipboardFallback
+		clipboardFallback = &stderr
+		t.Cleanup(func() { clipboardFallback = original })
+
+		updated, cmd := newModel(&fakeClipboard{err: errors.New("no xclip")}).Update(runesMsg(KeyCopy))
+		m := updated.(Model)
+
+		require.Nil(t, cmd, "a missing clipboard is not an error")
+		assert.Equal(t, "/repo/prod\n", stderr.String())
+		assert.Equal(t, m.Text(MsgPathPrinted), m.notice)
+	})
+}
@@ -3,11 +3,19 @@ package tui
 // UI Layout Constants
 const (
 	// Column layout
-	ColumnOverhead      = 2  // External overhead per column: left + right margin from Margin(0,1).
+	ColumnOverhead      = 2  // Default gap per column: left + right margin of 1 (column_gap).
 	ArrowIndicatorWidth = 3  // Width of each overflow arrow indicator: 1 char + Padding(0,1) = 2.
 	ColumnPadding       = 4  // Padding within each column.
 	ColumnBorderWidth   = 2  // Border width for each column.
 	MinColumnWidth      = 20 // Minimum width for a column.
+	MaxColumnWidth      = 80 // Maximum width for a column when resized with +/-.
+	ColumnWidthStep     = 2  // Width change per +/- key press.
+	CollapsedBarWidth   = 18 // Width of the commands column when collapsed to a bar.
+
+	// Preview pane
+	PreviewWidthRatio = 3  // The preview pane takes 1/3 of the screen width
+	MinPreviewWidth   = 30 // Minimum width of the preview pane
+	PreviewFrameWidth = 4  // Horizontal border (2) and padding (2) of the preview pane
 
 	// Header
 	HeaderHeight    = 1
@@ -26,8 +34,10 @@ const (
 	EllipsisWidth           = 3  // Width of truncation ellipsis "..."
 	MinItemTextWidth        = 10 // Minimum width for item text
 	MarkerWidth             = 4  // Width of selection marker prefix "● " rendered by Lipgloss
+	OutcomeIndicatorWidth   = 1  // Width of the last-run outcome icon after a navigation item
 	BreadcrumbLineCount     = 1  // Number of lines for breadcrumb bar.
 	DepthIndicatorLineCount = 1  // Number of lines for the depth dots indicator.
+	ColumnItemsOffset       = 4  // Rows from a column's top to its first item: border or padding (2), title and blank line (2).
 
 	// Plan Review Layout
 	PlanMasterWidthRatio = 3  // 1/3 of screen width
@@ -45,15 +55,71 @@ const (
 
 // Key bindings
 const (
-	KeyUp    = "up"
-	KeyDown  = "down"
-	KeyLeft  = "left"
-	KeyRight = "right"
-	KeyEnter = "enter"
-	KeyCtrlC = "ctrl+c"
-	KeyQ     = "q"
-	KeyEsc   = "esc"
-	KeySlash = "/"
+	KeyUp         = "up"
+	KeyDown       = "down"
+	KeyLeft       = "left"
+	KeyRight      = "right"
+	KeyEnter      = "enter"
+	KeyAltEnter   = "alt+enter"
+	KeyCtrlC      = "ctrl+c"
+	KeyQ          = "q"
+	KeyYes        = "y"
+	KeyNo         = "n"
+	KeyEsc        = "esc"
+	KeySlash      = "/"
+	KeyPlus       = "+"
+	KeyMinus      = "-"
+	KeyRoot       = "r"
+	KeyCollapse   = "c"
+	KeyRecenter   = "z"
+	KeyGroup      = "g"
+	KeyFailures   = "f"
+	KeyPreview    = "p"
+	KeyArgs       = "a"
+	KeyCopy       = "y"
+	KeyStacksOnly = "S"
+	KeyBreadcrumb = "B"
+
+	// Vim-style movement, active only while no filter is being edited.
+	KeyVimLeft  = "h"
+	KeyVimDown  = "j"
+	KeyVimUp    = "k"
+	KeyVimRight = "l"
+	KeyVimFirst = "g"
+	KeyVimLast  = "G"
+
+	// Two-key sequence toggling the debug overlay: KeyDebugPrefix then KeyDebug.
+	KeyDebugPrefix = "g"
+	KeyDebug       = "d"
+)
+
+// MaxFavorites is the number of command presets that can be mapped to function keys (F1–F9).
+const MaxFavorites = 9
+
+// Behaviors of enter on a node that is both a stack and a parent of other stacks.
+const (
+	EnterParentStackConfirm = "confirm" // Enter runs the stack itself (default).
+	EnterParentStackDrill   = "drill"   // Enter moves into the children; alt+enter runs the stack.
+)
+
+// Modes of type_to_filter, where typing a printable key opens the focused column's filter
+// pre-filled with it.
+const (
+	TypeToFilterOff     = "off"     // Filters are opened with the filter key only (default).
+	TypeToFilterUnbound = "unbound" // Keys without a shortcut open the filter; shortcuts win.
+	TypeToFilterAll     = "all"     // Every printable key opens the filter; ctrl+c and esc still quit.
+)
+
+// Scopes of filter_scope, which decides which columns a filter narrows.
+const (
+	FilterScopeColumn = "column" // Each column keeps its own filter (default).
+	FilterScopeGlobal = "global" // One filter follows focus and narrows whichever column has it.
+)
+
+// Built-in color themes, selected with the preset of the theme section.
+const (
+	ThemeDefault      = "default"       // Colors for dark terminals (default).
+	ThemeHighContrast = "high-contrast" // Bright, saturated colors for accessibility.
 )
 
 // UI Text
@@ -61,10 +127,20 @@ const (
 	AppTitle          = "TerraX - Terragrunt eXecutor"
 	CommandsTitle     = "Commands"
 	StacksTitle       = "Stacks"
+	IncludeRootLabel  = "(include root)"
 	HelpText          = "↑↓: navigate | ←→: change column | enter: select/confirm | q/esc: quit"
 	HelpTextWithMarks = "space: mark/unmark | ↑↓: navigate | enter: run on marked (%d) | esc: clear all | q: quit"
 	PlanHelpText      = "↑↓: navigate | ←→: change column | PgUp/PgDn: scroll | q/esc: quit"
+	ConfirmTitle      = "Execution summary"
+	ConfirmHelpText   = "enter: run | q/esc: cancel"
+	ConfirmYesNoHelp  = "y: run | n/esc: cancel"
 	NoItemSelected    = "None"
+	ArgsPlaceholder   = "extra args, e.g. -target=module.vpc"
+	SearchPlaceholder = "Search stacks..."
+	SearchHelpText    = "type: search | ↑↓: select | enter: jump | esc: close"
+	BreadcrumbHelp    = "←→: select level | enter: focus level | esc: close"
+	HistoryFilterHint = "Filter by command, stack path or exit status..."
 	Initializing      = "Initializing..."
 	ScanningStacks    = "Scanning stacks..."
+	ASCIICommandIcon  = "*" // Replaces non-ASCII command icons when emoji are disabled
 )
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WithLastArgs returns a copy of the model that pre-fills the extra args input with the
+// args a command was last run with. lastArgs maps a project root to the args of each
+// command run in that project; the "" key is used when no project is selected.
+func (m Model) WithLastArgs(lastArgs map[string]map[string][]string) Model {
+	m.lastArgs = lastArgs
+	return m
+}
+
+// GetExtraArgs returns the extra args typed for the selected command, which are appended
+// after the command name when it runs.
+func (m Model) GetExtraArgs() []string {
+	return m.extraArgs[m.GetSelectedCommand()]
+}
+
+// IsEditingArgs reports whether the extra args input has focus.
+func (m Model) IsEditingArgs() bool {
+	return m.editingArgs
+}
+
+// openArgsInput focuses the extra args input for the selected command, pre-filled with the
+// args already typed for it or, failing that, the args it was last run with in the
+// selected project.
+func (m Model) openArgsInput() (tea.Model, tea.Cmd) {
+	command := m.GetSelectedCommand()
+	args, typed := m.extraArgs[command]
+	if !typed {
+		args = m.lastArgs[m.GetSelectedProjectRoot()][command]
+	}
+
+	ti := textinput.New()
+	ti.Prompt = command + " "
+	ti.Placeholder = ArgsPlaceholder
+	ti.SetValue(strings.Join(args, " "))
+	ti.Focus()
+
+	m.argsInput = ti
+	m.editingArgs = true
+	return m, textinput.Blink
+}
+
+// handleArgsInput handles keys while the extra args input has focus: enter keeps the typed
+// args (split on whitespace) for the selected command, esc discards the edit.
+func (m Model) handleArgsInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editingArgs = false
+		return m, nil
+	case tea.KeyEnter:
+		if m.extraArgs == nil {
+			m.extraArgs = make(map[string][]string)
+		}
+		// An emptied input is kept as no args, so the last-run args are not offered again.
+		m.extraArgs[m.GetSelectedCommand()] = strings.Fields(m.argsInput.Value())
+		m.editingArgs = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.argsInput, cmd = m.argsInput.Update(msg)
+	return m, cmd
+}
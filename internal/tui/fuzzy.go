@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Fuzzy match scoring: every matched rune scores fuzzyMatchScore, plus a bonus when it
+// directly follows the previous match or starts a word; each rune skipped between two
+// matches costs fuzzyGapPenalty.
+const (
+	fuzzyMatchScore       = 1
+	fuzzyConsecutiveBonus = 5
+	fuzzyWordStartBonus   = 3
+	fuzzyGapPenalty       = 1
+)
+
+// fuzzyScore reports whether the runes of pattern appear in text in order (ignoring case),
+// like fzf, and scores the match: higher scores mean tighter matches at word starts.
+func fuzzyScore(text, pattern string) (int, bool) {
+	textRunes := []rune(strings.ToLower(text))
+	patternRunes := []rune(strings.ToLower(pattern))
+
+	score := 0
+	lastMatch := -1
+	p := 0
+	for i, r := range textRunes {
+		if p == len(patternRunes) {
+			break
+		}
+		if r != patternRunes[p] {
+			continue
+		}
+
+		score += fuzzyMatchScore
+		switch {
+		case lastMatch >= 0 && i == lastMatch+1:
+			score += fuzzyConsecutiveBonus
+		case i == 0 || isWordSeparator(textRunes[i-1]):
+			score += fuzzyWordStartBonus
+		}
+		if lastMatch >= 0 {
+			score -= (i - lastMatch - 1) * fuzzyGapPenalty
+		}
+		lastMatch = i
+		p++
+	}
+	return score, p == len(patternRunes)
+}
+
+// isWordSeparator reports whether r separates words in stack names and paths.
+func isWordSeparator(r rune) bool {
+	return r == '/' || r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+}
+
+// fuzzyFilterItems returns the items matching pattern as a subsequence, best match first.
+// Items scoring the same keep their original order.
+func fuzzyFilterItems(items []string, pattern string) []string {
+	if pattern == "" {
+		return items
+	}
+
+	type scoredItem struct {
+		item  string
+		score int
+	}
+	var matches []scoredItem
+	for _, item := range items {
+		if score, ok := fuzzyScore(item, pattern); ok {
+			matches = append(matches, scoredItem{item: item, score: score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b scoredItem) int {
+		return b.score - a.score
+	})
+
+	filtered := make([]string, len(matches))
+	for i, match := range matches {
+		filtered[i] = match.item
+	}
+	return filtered
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// matchMask returns, for each byte of text, whether it belongs to a rune matched by filter:
+// the first case-insensitive occurrence of filter as a substring or, when fuzzy is set, the
+// runes fuzzyScore matched as a subsequence. It returns nil when filter does not match.
+func matchMask(text, filter string, fuzzy bool) []bool {
+	if filter == "" {
+		return nil
+	}
+	pattern := []rune(strings.ToLower(filter))
+	if fuzzy {
+		return fuzzyMatchMask(text, pattern)
+	}
+	return substringMatchMask(text, pattern)
+}
+
+// fuzzyMatchMask marks the runes of text matching pattern as an ordered subsequence,
+// taking the first occurrence of each pattern rune like fuzzyScore does.
+func fuzzyMatchMask(text string, pattern []rune) []bool {
+	mask := make([]bool, len(text))
+	p := 0
+	for i, r := range text {
+		if p == len(pattern) {
+			break
+		}
+		if unicode.ToLower(r) != pattern[p] {
+			continue
+		}
+		markRune(mask, i, r)
+		p++
+	}
+	if p < len(pattern) {
+		return nil
+	}
+	return mask
+}
+
+// substringMatchMask marks the runes of the first occurrence of pattern in text.
+func substringMatchMask(text string, pattern []rune) []bool {
+	for start := range text {
+		end, ok := matchRunesAt(text, start, pattern)
+		if !ok {
+			continue
+		}
+		mask := make([]bool, len(text))
+		for i := start; i < end; i++ {
+			mask[i] = true
+		}
+		return mask
+	}
+	return nil
+}
+
+// matchRunesAt reports whether pattern matches text starting at byte offset start (ignoring
+// case) and returns the byte offset just past the match.
+func matchRunesAt(text string, start int, pattern []rune) (int, bool) {
+	pos := start
+	for _, want := range pattern {
+		if pos >= len(text) {
+			return 0, false
+		}
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		if unicode.ToLower(r) != want {
+			return 0, false
+		}
+		pos += size
+	}
+	return pos, true
+}
+
+// markRune marks the bytes of the rune r found at byte offset i.
+func markRune(mask []bool, i int, r rune) {
+	for j := i; j < i+utf8.RuneLen(r) && j < len(mask); j++ {
+		mask[j] = true
+	}
+}
+
+// filterHighlights returns the match mask of each item against the filter of columnID, or
+// nil when highlighting is disabled or the column has no filter text.
+func (m Model) filterHighlights(columnID int, items []string) [][]bool {
+	if !m.highlightMatches {
+		return nil
+	}
+	filter, exists := m.columnFilters[columnID]
+	if !exists || filter.Value() == "" {
+		return nil
+	}
+
+	highlights := make([][]bool, len(items))
+	for i, item := range items {
+		highlights[i] = matchMask(item, filter.Value(), m.fuzzyFilter)
+	}
+	return highlights
+}
+
+// alignHighlights shifts each mask to the end of its label, for labels that decorate the
+// matched item with a prefix (such as a command icon).
+func alignHighlights(highlights [][]bool, labels []string) [][]bool {
+	if highlights == nil {
+		return nil
+	}
+	aligned := make([][]bool, len(highlights))
+	for i, mask := range highlights {
+		if mask == nil || i >= len(labels) || len(labels[i]) < len(mask) {
+			continue
+		}
+		aligned[i] = make([]bool, len(labels[i]))
+		copy(aligned[i][len(labels[i])-len(mask):], mask)
+	}
+	return aligned
+}
+
+// renderHighlightedText renders displayText (text, possibly truncated by truncateText) with
+// style, making the bytes set in mask bold and underlined. The style's padding wraps the
+// whole text so highlighted and plain segments line up as one item.
+func renderHighlightedText(style lipgloss.Style, text, displayText string, mask []bool) string {
+	visible := len(displayText)
+	if displayText != text && strings.HasSuffix(displayText, "...") {
+		visible -= EllipsisWidth // The ellipsis is never part of a match.
+	}
+	visible = min(visible, len(mask))
+
+	plain := style.UnsetPadding()
+	matched := plain.Bold(true).Underline(true)
+
+	_, right, _, left := style.GetPadding()
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", left))
+	for start := 0; start < len(displayText); {
+		end := start + 1
+		isMatch := start < visible && mask[start]
+		for end < len(displayText) && (end < visible && mask[end]) == isMatch {
+			end++
+		}
+		if isMatch {
+			b.WriteString(matched.Render(displayText[start:end]))
+		} else {
+			b.WriteString(plain.Render(displayText[start:end]))
+		}
+		start = end
+	}
+	b.WriteString(strings.Repeat(" ", right))
+	return b.String()
+}
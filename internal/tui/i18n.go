@@ -0,0 +1,161 @@
+package tui
+
+import "strings"
+
+// MessageKey identifies a translatable UI string.
+type MessageKey string
+
+// Translatable UI strings.
+const (
+	MsgAppTitle           MessageKey = "app_title"
+	MsgCommandsTitle      MessageKey = "commands_title"
+	MsgHelpText           MessageKey = "help_text"
+	MsgHelpTextWithMarks  MessageKey = "help_text_with_marks" // Takes the number of marked stacks (%d).
+	MsgPlanHelpText       MessageKey = "plan_help_text"
+	MsgInitializing       MessageKey = "initializing"
+	MsgScanningStacks     MessageKey = "scanning_stacks"
+	MsgHistoryTitle       MessageKey = "history_title"
+	MsgHistoryTimestamp   MessageKey = "history_timestamp"
+	MsgHistoryCommand     MessageKey = "history_command"
+	MsgHistoryStackPath   MessageKey = "history_stack_path"
+	MsgHistoryExitCode    MessageKey = "history_exit_code"
+	MsgHistoryDuration    MessageKey = "history_duration"
+	MsgHistoryChanges     MessageKey = "history_changes"
+	MsgHistoryFailedOnly  MessageKey = "history_failed_only"
+	MsgSelectionConfirmed MessageKey = "selection_confirmed"
+	MsgSelectionCancelled MessageKey = "selection_cancelled"
+	MsgConfigReloaded     MessageKey = "config_reloaded"
+	MsgConfigReloadFailed MessageKey = "config_reload_failed" // Takes the error (%v).
+	MsgCommandNotAllowed  MessageKey = "command_not_allowed"  // Takes the command (%s).
+	MsgPathCopied         MessageKey = "path_copied"
+	MsgPathPrinted        MessageKey = "path_printed"
+	MsgStacksOnly         MessageKey = "stacks_only"
+	MsgAllDirectories     MessageKey = "all_directories"
+	MsgConfirmTitle       MessageKey = "confirm_title"
+	MsgConfirmHelpText    MessageKey = "confirm_help_text"
+	MsgConfirmPrompt      MessageKey = "confirm_prompt" // Takes the command (%s) and target stacks (%s).
+	MsgConfirmYesNoHelp   MessageKey = "confirm_yes_no_help"
+	MsgIdleQuit           MessageKey = "idle_quit" // Takes the idle timeout (%s).
+	MsgPreviewNoConfig    MessageKey = "preview_no_config"
+	MsgPreviewMoreLines   MessageKey = "preview_more_lines" // Takes the number of lines not shown (%d).
+	MsgStackOwner         MessageKey = "stack_owner"        // Takes the owner from the stack metadata (%s).
+	MsgSearchHelpText     MessageKey = "search_help_text"
+	MsgSearchNoMatches    MessageKey = "search_no_matches"
+	MsgStatusFilter       MessageKey = "status_filter" // Takes the focused column's filter text (%s).
+	MsgBreadcrumbHelpText MessageKey = "breadcrumb_help_text"
+)
+
+// DefaultLocale is the locale used when none is configured or detected.
+const DefaultLocale = "en"
+
+// Catalog maps message keys to their text in one locale.
+type Catalog map[MessageKey]string
+
+// catalogs holds the message catalogs keyed by locale. The DefaultLocale catalog must
+// define every key because it is the fallback for keys missing from other catalogs.
+var catalogs = map[string]Catalog{
+	DefaultLocale: {
+		MsgAppTitle:           AppTitle,
+		MsgCommandsTitle:      CommandsTitle,
+		MsgHelpText:           HelpText,
+		MsgHelpTextWithMarks:  HelpTextWithMarks,
+		MsgPlanHelpText:       PlanHelpText,
+		MsgInitializing:       Initializing,
+		MsgScanningStacks:     ScanningStacks,
+		MsgHistoryTitle:       "Execution History",
+		MsgHistoryTimestamp:   "Timestamp",
+		MsgHistoryCommand:     "Command",
+		MsgHistoryStackPath:   "Stack Path",
+		MsgHistoryExitCode:    "Exit Code",
+		MsgHistoryDuration:    "Duration",
+		MsgHistoryChanges:     "Changes",
+		MsgHistoryFailedOnly:  "failures only",
+		MsgSelectionConfirmed: "Selection confirmed",
+		MsgSelectionCancelled: "Selection cancelled",
+		MsgConfigReloaded:     "Configuration reloaded",
+		MsgConfigReloadFailed: "Configuration reload failed: %v",
+		MsgCommandNotAllowed:  "%s is not allowed for this stack",
+		MsgPathCopied:         "Stack path copied to the clipboard",
+		MsgPathPrinted:        "No clipboard available, stack path printed to stderr",
+		MsgStacksOnly:         "Showing only stacks (S shows all directories)",
+		MsgAllDirectories:     "Showing all directories",
+		MsgConfirmTitle:       ConfirmTitle,
+		MsgConfirmHelpText:    ConfirmHelpText,
+		MsgConfirmPrompt:      "Run %s on %s?",
+		MsgConfirmYesNoHelp:   ConfirmYesNoHelp,
+		MsgIdleQuit:           "Quit after %s without input",
+		MsgPreviewNoConfig:    "No config",
+		MsgPreviewMoreLines:   "… %d more lines",
+		MsgStackOwner:         "owner: %s",
+		MsgSearchHelpText:     SearchHelpText,
+		MsgSearchNoMatches:    "No stacks match",
+		MsgStatusFilter:       "filter: %s",
+		MsgBreadcrumbHelpText: BreadcrumbHelp,
+	},
+	"es": {
+		MsgCommandsTitle:      "Comandos",
+		MsgHelpText:           "↑↓: navegar | ←→: cambiar columna | enter: seleccionar/confirmar | q/esc: salir",
+		MsgHelpTextWithMarks:  "space: marcar/desmarcar | ↑↓: navegar | enter: ejecutar en marcados (%d) | esc: limpiar | q: salir",
+		MsgPlanHelpText:       "↑↓: navegar | ←→: cambiar columna | PgUp/PgDn: desplazar | q/esc: salir",
+		MsgInitializing:       "Iniciando...",
+		MsgScanningStacks:     "Buscando stacks...",
+		MsgHistoryTitle:       "Historial de ejecuciones",
+		MsgHistoryTimestamp:   "Fecha",
+		MsgHistoryCommand:     "Comando",
+		MsgHistoryStackPath:   "Ruta del stack",
+		MsgHistoryExitCode:    "Código",
+		MsgHistoryDuration:    "Duración",
+		MsgHistoryChanges:     "Cambios",
+		MsgHistoryFailedOnly:  "solo fallos",
+		MsgSelectionConfirmed: "Selección confirmada",
+		MsgSelectionCancelled: "Selección cancelada",
+		MsgConfigReloaded:     "Configuración recargada",
+		MsgConfigReloadFailed: "Error al recargar la configuración: %v",
+		MsgCommandNotAllowed:  "%s no está permitido en este stack",
+		MsgPathCopied:         "Ruta del stack copiada al portapapeles",
+		MsgPathPrinted:        "Sin portapapeles, ruta del stack impresa en stderr",
+		MsgStacksOnly:         "Solo stacks (S muestra todos los directorios)",
+		MsgAllDirectories:     "Todos los directorios",
+		MsgConfirmTitle:       "Resumen de la ejecución",
+		MsgConfirmHelpText:    "enter: ejecutar | q/esc: cancelar",
+		MsgConfirmPrompt:      "¿Ejecutar %s en %s?",
+		MsgConfirmYesNoHelp:   "y: ejecutar | n/esc: cancelar",
+		MsgIdleQuit:           "Salida tras %s sin actividad",
+		MsgPreviewNoConfig:    "Sin configuración",
+		MsgPreviewMoreLines:   "… %d líneas más",
+		MsgStackOwner:         "responsable: %s",
+		MsgSearchHelpText:     "escribir: buscar | ↑↓: seleccionar | enter: ir | esc: cerrar",
+		MsgSearchNoMatches:    "Ningún stack coincide",
+		MsgStatusFilter:       "filtro: %s",
+		MsgBreadcrumbHelpText: "←→: seleccionar nivel | enter: enfocar nivel | esc: cerrar",
+	},
+}
+
+// ResolveLocale returns the language of the configured locale, or of lang (a LANG-style
+// value such as "es_ES.UTF-8") when none is configured, falling back to DefaultLocale.
+// Locales without a catalog are returned as-is; their lookups fall back to English.
+func ResolveLocale(configured, lang string) string {
+	for _, candidate := range []string{configured, lang} {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if i := strings.IndexAny(candidate, ".@_-"); i >= 0 {
+			candidate = candidate[:i]
+		}
+		if candidate != "" && candidate != "c" && candidate != "posix" {
+			return candidate
+		}
+	}
+	return DefaultLocale
+}
+
+// lookupMessage returns the text for key in locale, falling back to the DefaultLocale catalog.
+func lookupMessage(locale string, key MessageKey) string {
+	if text, ok := catalogs[locale][key]; ok {
+		return text
+	}
+	return catalogs[DefaultLocale][key]
+}
+
+// Text returns the text for key in the model's locale, falling back to English.
+func (m Model) Text(key MessageKey) string {
+	return lookupMessage(m.locale, key)
+}
@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/israoo/terrax/internal/history"
+	"github.com/israoo/terrax/internal/stack"
+	"github.com/stretchr/testify/assert"
+)
+
+// withTestCatalog registers a partial catalog for the "xx" locale for the duration of the test.
+func withTestCatalog(t *testing.T) {
+	t.Helper()
+	catalogs["xx"] = Catalog{
+		MsgCommandsTitle:    "XX-Commands",
+		MsgHelpText:         "XX-Help",
+		MsgHistoryTitle:     "XX-History",
+		MsgHistoryTimestamp: "XX-When",
+	}
+	t.Cleanup(func() { delete(catalogs, "xx") })
+}
+
+// TestResolveLocale tests locale selection from config and LANG.
+func TestResolveLocale(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		lang       string
+		expected   string
+	}{
+		{name: "nothing set", expected: DefaultLocale},
+		{name: "configured wins over LANG", configured: "es", lang: "fr_FR.UTF-8", expected: "es"},
+		{name: "LANG with region and encoding", lang: "es_ES.UTF-8", expected: "es"},
+		{name: "configured with region", configured: "pt-BR", expected: "pt"},
+		{name: "C locale", lang: "C", expected: DefaultLocale},
+		{name: "POSIX locale", lang: "POSIX", expected: DefaultLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveLocale(tt.configured, tt.lang))
+		})
+	}
+}
+
+// TestText_FallsBackToEnglish tests that missing keys and unknown locales use English.
+func TestText_FallsBackToEnglish(t *testing.T) {
+	withTestCatalog(t)
+
+	m := Model{}.WithLocale("xx")
+	assert.Equal(t, "XX-Commands", m.Text(MsgCommandsTitle))
+	assert.Equal(t, AppTitle, m.Text(MsgAppTitle), "missing key should fall back to English")
+
+	unknown := Model{}.WithLocale("zz")
+	assert.Equal(t, CommandsTitle, unknown.Text(MsgCommandsTitle))
+
+	// Every key in every catalog must also exist in English.
+	for locale, catalog := range catalogs {
+		for key := range catalog {
+			assert.Contains(t, catalogs[DefaultLocale], key, "locale %s key %s has no English text", locale, key)
+		}
+	}
+}
+
+// TestView_Locale tests that switching the locale changes the rendered strings.
+func TestView_Locale(t *testing.T) {
+	withTestCatalog(t)
+
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	m := NewModel(root, 1, testCommands, 3).WithLocale("xx")
+	m.width = 200
+	m.height = 30
+	m.columnWidth = 30
+	m.ready = true
+
+	view := m.View()
+	assert.Contains(t, view, "XX-Commands")
+	assert.Contains(t, view, "XX-Help")
+	assert.NotContains(t, view, HelpText)
+	assert.Contains(t, view, AppTitle)
+
+	h := NewHistoryModel([]history.ExecutionLogEntry{
+		{ID: 1, Timestamp: time.Now(), Command: "plan", StackPath: "dev/vpc"},
+	}).WithLocale("xx")
+	h.width = 200
+	h.height = 30
+	h.ready = true
+
+	view = h.View()
+	assert.Contains(t, view, "XX-History")
+	assert.Contains(t, view, "XX-When")
+	assert.Contains(t, view, "Stack Path", "missing header should fall back to English")
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/israoo/terrax/internal/clock"
+)
+
+// currentClock is the time source for the idle timeout (can be overridden in tests).
+var currentClock clock.Clock = clock.Real{}
+
+// setClock allows tests to inject a fake clock.
+// Returns a cleanup function to restore the original clock.
+func setClock(c clock.Clock) func() {
+	original := currentClock
+	currentClock = c
+	return func() {
+		currentClock = original
+	}
+}
+
+// idleTickMsg is delivered when the idle timeout may have elapsed.
+type idleTickMsg struct{}
+
+// idleTick schedules an idleTickMsg once wait has passed.
+func idleTick(wait time.Duration) tea.Cmd {
+	return tea.Tick(wait, func(time.Time) tea.Msg {
+		return idleTickMsg{}
+	})
+}
+
+// WithIdleTimeout returns a copy of the model that quits without executing anything once
+// no key or mouse event has arrived for timeout, freeing shared terminals. Any input
+// restarts the wait. A timeout of 0 or less never quits.
+func (m Model) WithIdleTimeout(timeout time.Duration) Model {
+	m.idleTimeout = max(timeout, 0)
+	m.lastInput = currentClock.Now()
+	return m
+}
+
+// IdledOut reports whether the session ended because of the idle timeout.
+func (m Model) IdledOut() bool {
+	return m.idledOut
+}
+
+// IdleTimeout returns the configured idle timeout (0 = never).
+func (m Model) IdleTimeout() time.Duration {
+	return m.idleTimeout
+}
+
+// handleIdleTick quits once the idle timeout has passed since the last input. Input that
+// arrived meanwhile pushes the next check back by the time it bought.
+func (m Model) handleIdleTick() (tea.Model, tea.Cmd) {
+	if m.idleTimeout <= 0 {
+		return m, nil
+	}
+	if remaining := m.idleTimeout - currentClock.Now().Sub(m.lastInput); remaining > 0 {
+		return m, idleTick(remaining)
+	}
+
+	m.idledOut = true
+	m.confirmed = false
+	return m, tea.Quit
+}
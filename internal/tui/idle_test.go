@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/clock"
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// TestIdleTimeout tests that the TUI quits without executing once no input arrived for
+// the idle timeout, and that input restarts the wait.
+func TestIdleTimeout(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(setClock(fake))
+
+	root := &stack.Node{Name: "root", Children: []*stack.Node{{Name: "dev"}, {Name: "prod"}}}
+	update := func(m Model, msg tea.Msg) (Model, tea.Cmd) {
+		updated, cmd := m.Update(msg)
+		return updated.(Model), cmd
+	}
+
+	t.Run("no input for the timeout quits", func(t *testing.T) {
+		m := NewModel(root, 1, testCommands, 3).WithIdleTimeout(time.Minute)
+		require.NotNil(t, m.Init(), "the first check is scheduled on start")
+
+		fake.Advance(30 * time.Second)
+		m, cmd := update(m, idleTickMsg{})
+		assert.False(t, m.IdledOut())
+		assert.NotNil(t, cmd, "the next check is scheduled")
+
+		fake.Advance(30 * time.Second)
+		m, cmd = update(m, idleTickMsg{})
+		assert.True(t, m.IdledOut())
+		assert.False(t, m.IsConfirmed(), "nothing is executed")
+		require.NotNil(t, cmd)
+		assert.IsType(t, tea.QuitMsg{}, cmd())
+	})
+
+	t.Run("input resets the timer", func(t *testing.T) {
+		m := NewModel(root, 1, testCommands, 3).WithIdleTimeout(time.Minute)
+
+		fake.Advance(50 * time.Second)
+		m, _ = update(m, tea.KeyMsg{Type: tea.KeyDown})
+
+		fake.Advance(20 * time.Second)
+		m, cmd := update(m, idleTickMsg{})
+		assert.False(t, m.IdledOut(), "only 20s passed since the key")
+		assert.NotNil(t, cmd)
+
+		fake.Advance(40 * time.Second)
+		m, _ = update(m, idleTickMsg{})
+		assert.True(t, m.IdledOut())
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		m := NewModel(root, 1, testCommands, 3)
+		assert.Nil(t, m.Init())
+
+		fake.Advance(24 * time.Hour)
+		m, cmd := update(m, idleTickMsg{})
+		assert.False(t, m.IdledOut())
+		assert.Nil(t, cmd)
+	})
+}
@@ -0,0 +1,19 @@
+package tui
+
+// Messages holds the status text shown while the TUI has nothing else to render.
+// Empty fields fall back to the locale's catalog, so users can override only some of them.
+type Messages struct {
+	Initializing   string // Shown until the first window size is received.
+	ScanningStacks string // Shown when no stack levels were found to navigate.
+}
+
+// withDefaults returns a copy of msgs with empty fields replaced by the locale's text.
+func (msgs Messages) withDefaults(locale string) Messages {
+	if msgs.Initializing == "" {
+		msgs.Initializing = lookupMessage(locale, MsgInitializing)
+	}
+	if msgs.ScanningStacks == "" {
+		msgs.ScanningStacks = lookupMessage(locale, MsgScanningStacks)
+	}
+	return msgs
+}
@@ -7,13 +7,20 @@
 package tui
 
 import (
+	"fmt"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
+	"github.com/israoo/terrax/internal/bounds"
+	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/history"
 	"github.com/israoo/terrax/internal/plan"
 	"github.com/israoo/terrax/internal/stack"
@@ -29,6 +36,8 @@ const (
 	StateHistory
 	// StatePlanReview is the state for analyzing plan results.
 	StatePlanReview
+	// StateConfirm is the state for reviewing the execution summary before running it.
+	StateConfirm
 )
 
 // ColumnType represents the type of column being focused.
@@ -52,14 +61,34 @@ type Model struct {
 	navState  *stack.NavigationState
 
 	// Commands
-	commands        []string
-	selectedCommand int
+	commands          []string
+	selectedCommand   int
+	dangerousCommands map[string]bool     // Commands rendered with a warning style (e.g. apply, destroy)
+	commandIcons      map[string]string   // Icon shown before each command (e.g. plan -> 🔍)
+	commandDescs      map[string]string   // Dimmed description shown beside each command
+	commandAliases    map[string]Favorite // Command and args run for a listed label (e.g. aa -> apply -auto-approve)
+	commandGroups     []CommandGroup      // Groups listed under a header, in order (nil = no headers)
+	lastCommands      map[string]string   // Last command run per absolute stack path (nil = not remembered)
+	rememberedFor     string              // Stack path whose remembered command was last applied
 
 	// History
 	history              []history.ExecutionLogEntry
 	historyCursor        int
-	selectedHistoryEntry *history.ExecutionLogEntry // Entry selected for re-execution
-	reExecuteFromHistory bool                       // Flag to indicate re-execution from history
+	selectedHistoryEntry *history.ExecutionLogEntry  // Entry selected for re-execution
+	reExecuteFromHistory bool                        // Flag to indicate re-execution from history
+	historyTableStyle    HistoryTableStyle           // Striping and cursor colors for the history table
+	previousProjectRoot  string                      // Project of the last run when it differs from the current one
+	historyGrouped       bool                        // History is shown as one row per stack instead of one per run
+	historyGroups        []history.StackGroup        // history grouped by stack path (set while grouped)
+	historyExpanded      map[string]bool             // Stack paths whose runs are listed under their group row
+	historyAll           []history.ExecutionLogEntry // Every loaded entry; history holds those passing the filter
+	historyFilter        textinput.Model             // Narrows history by command, stack path or exit status
+	historyFiltering     bool                        // The history filter input has focus
+	historyFailedOnly    bool                        // Only entries with a non-zero exit code are shown
+
+	// Latest run per absolute stack path, shown as an outcome icon next to navigation
+	// items (nil = no indicators).
+	lastRuns map[string]history.ExecutionLogEntry
 
 	// Plan Review
 	planReport               *plan.PlanReport
@@ -75,25 +104,116 @@ type Model struct {
 	focusedColumn    int  // 0 = commands, 1+ = navigation columns
 	navigationOffset int  // First visible navigation level (sliding window)
 	confirmed        bool // Whether user confirmed selection
+	rightConfirms    bool // Right-arrow on a leaf stack confirms like enter
+	enterDrills      bool // Enter on a stack with children drills in instead of confirming
+	groupStacks      bool // Stacks are listed before plain directories, with a divider between them
+	breadcrumbCmd    bool // Breadcrumb shows the selected command before the path
+
+	// VCS branch checked out in the working directory ("" = not a repository)
+	branch string
+
+	// Include root (directory holding root_config_file)
+	includeRoot       string // Empty when no root config file was found above the stack tree
+	targetIncludeRoot bool   // Commands column runs against includeRoot instead of the tree root
+
+	// Text
+	locale   string   // Message catalog locale ("" = DefaultLocale)
+	messages Messages // Configured status text; empty fields use the locale's text
+	notice   string   // One-off footer message (e.g. config reloaded), cleared on the next key
+
+	// Colors
+	theme Theme // Colors of the semantic roles (borders, selection, status icons, header)
+
+	// Clipboard
+	clipboard ClipboardWriter // Copies the selected stack path on y (nil = system clipboard)
+
+	// Config reload
+	configReloader ConfigReloader // Re-reads the configuration on ctrl+r (nil = disabled)
+
+	// Confirmation summary
+	confirmSummary bool               // Show the execution summary before confirming
+	previewer      ExecutionPreviewer // Fills in binary, extra args and env var names (nil = omitted)
+	confirmDetails ExecutionDetails   // Summary shown in StateConfirm
+
+	// Destructive command prompt
+	requireConfirmation []string // Commands run only after an explicit y
+	awaitingYes         bool     // StateConfirm asks yes/no for a command in requireConfirmation
+
+	// Favorites
+	favorites []Favorite // Presets selected with F1–F9
+	favorite  *Favorite  // Preset chosen with a function key; overrides the commands column
 
 	// Layout
 	width                int
 	height               int
 	columnWidth          int // Pre-calculated static column width
+	columnWidthOverride  int // User-chosen column width via +/- or column_width config (0 = auto)
 	maxNavigationColumns int // Maximum navigation columns visible (sliding window)
 
+	// Column spacing
+	columnGap       int    // Spaces between adjacent columns
+	columnSeparator string // Drawn between navigation columns ("" = none)
+
 	// Filtering (per-column)
 	columnFilters      map[int]textinput.Model // Filter inputs per column (0=commands, 1+=navigation)
 	activeFilterColumn int                     // Which column's filter is currently being edited (-1 = none)
+	fuzzyFilter        bool                    // Match filters as subsequences ranked best-first instead of substrings
+	typeToFilter       string                  // Which printable keys open the filter (TypeToFilter*; "" = off)
+	highlightMatches   bool                    // Style the characters a column filter matched in each item
+	globalFilter       bool                    // One filter follows focus instead of one per column (FilterScopeGlobal)
 
 	// Scrolling (per-column vertical viewport)
 	scrollOffsets map[int]int // Scroll offset per column (0=commands, 1+=navigation)
 
 	// State flags
-	ready bool
+	ready             bool
+	commandsCollapsed bool // Commands column shown as a bar with only the selected command
+	skipSingleCommand bool // Hide the commands column when it holds a single command
+	noAltScreen       bool // Render in the main screen buffer instead of the alternate one
+
+	// Key bindings
+	keyActions map[string]string // Action bound to each key (config.Action*)
+
+	// Preview pane
+	previewPane  bool                    // Show the focused stack's terragrunt.hcl beside the columns
+	previewLines int                     // Maximum terragrunt.hcl lines read per stack
+	previews     map[string]stackPreview // terragrunt.hcl preview per stack path, read on first preview
+
+	// Extra arguments
+	extraArgs   map[string][]string            // Args typed for each command, appended after it when run
+	lastArgs    map[string]map[string][]string // Args each command was last run with per project root, from history
+	argsInput   textinput.Model                // Input where the extra args are typed
+	editingArgs bool                           // The extra args input has focus
+
+	// Tree search
+	searchInput  textinput.Model // Query matched against the paths of every stack
+	searchCursor int             // Selected match in the search overlay
+	searching    bool            // The search overlay has focus
+
+	// Breadcrumb mode
+	breadcrumbMode   bool // The breadcrumb bar has focus
+	breadcrumbCursor int  // Navigation level selected in the breadcrumb bar
+
+	// Idle auto-quit
+	idleTimeout time.Duration // Quit without executing after this long without input (0 = never)
+	lastInput   time.Time     // When the last key or mouse event arrived
+	idledOut    bool          // The session ended because of the idle timeout
+
+	// Debugging
+	pendingKey    string // First key of a two-key sequence awaiting its second key
+	pendingKeySeq int    // Counts started sequences so stale timeouts can be told apart
+	debugOverlay  bool   // Show the internal navigation state in place of the columns
 
 	// Multi-stack selection
 	selectedPaths map[string]bool // absolute paths of explicitly marked nodes
+
+	// Stack-local settings
+	stackAllowlists map[string][]string // allowed_commands per stack path, read on first focus (nil = all)
+	cmdStackTypes   map[string][]string // Stack types each command applies to (missing = every type)
+	stackTypes      map[string]string   // Detected stack type per path, detected on first focus
+
+	// Stack metadata
+	stackMetadata map[string]stack.Metadata // .terrax-meta.yaml per stack path, read on first focus
 }
 
 // NewModel creates a new TUI model instance.
@@ -114,6 +234,7 @@ func NewModel(stackRoot *stack.Node, maxDepth int, commands []string, maxNavigat
 		confirmed:            false,
 		ready:                false,
 		maxNavigationColumns: maxNavigationColumns,
+		columnGap:            ColumnOverhead,
 		columnFilters:        make(map[int]textinput.Model),
 		activeFilterColumn:   -1,
 		scrollOffsets:        make(map[int]int),
@@ -122,6 +243,15 @@ func NewModel(stackRoot *stack.Node, maxDepth int, commands []string, maxNavigat
 		selectedHistoryEntry: nil,
 		reExecuteFromHistory: false,
 		selectedPaths:        make(map[string]bool),
+		stackAllowlists:      make(map[string][]string),
+		stackTypes:           make(map[string]string),
+		stackMetadata:        make(map[string]stack.Metadata),
+		keyActions:           keyActions(nil),
+		previewLines:         config.DefaultPreviewLines,
+		highlightMatches:     config.DefaultHighlightMatches,
+		previews:             make(map[string]stackPreview),
+		extraArgs:            make(map[string][]string),
+		theme:                DefaultTheme(),
 	}
 
 	navigator.PropagateSelection(navState)
@@ -134,15 +264,263 @@ func NewHistoryModel(historyEntries []history.ExecutionLogEntry) Model {
 	m := Model{
 		state:                StateHistory,
 		history:              historyEntries,
+		historyAll:           historyEntries,
 		historyCursor:        0,
 		ready:                false,
 		selectedHistoryEntry: nil,
 		reExecuteFromHistory: false,
 		selectedPaths:        make(map[string]bool),
+		theme:                DefaultTheme(),
+	}
+	return m
+}
+
+// WithDangerousCommands returns a copy of the model that highlights the given
+// commands with a warning style in the commands column.
+func (m Model) WithDangerousCommands(commands []string) Model {
+	m.dangerousCommands = make(map[string]bool, len(commands))
+	for _, c := range commands {
+		m.dangerousCommands[c] = true
+	}
+	return m
+}
+
+// WithRightArrowConfirm returns a copy of the model where right-arrow on a leaf stack
+// confirms the selection like enter instead of wrapping to the commands column.
+func (m Model) WithRightArrowConfirm(enabled bool) Model {
+	m.rightConfirms = enabled
+	return m
+}
+
+// WithCyclicNavigation returns a copy of the model where up/down past either end of the
+// commands and navigation columns wraps around (the default), or stops at the first and
+// last item when enabled is false.
+func (m Model) WithCyclicNavigation(enabled bool) Model {
+	if m.navigator != nil {
+		navigator := *m.navigator
+		navigator.SetCyclic(enabled)
+		m.navigator = &navigator
 	}
 	return m
 }
 
+// WithBranch returns a copy of the model that shows branch in the header.
+// An empty branch (not a repository) is omitted.
+func (m Model) WithBranch(branch string) Model {
+	m.branch = branch
+	return m
+}
+
+// WithAltScreen returns a copy of the model that runs in the terminal's alternate screen
+// buffer (the default), keeping the user's scrollback intact, or in the main buffer when
+// enabled is false, which leaves the last frame on screen for debugging.
+func (m Model) WithAltScreen(enabled bool) Model {
+	m.noAltScreen = !enabled
+	return m
+}
+
+// WithDebugOverlay returns a copy of the model that starts with the debug overlay shown
+// (toggled with g then d), listing the internal navigation state for bug reports.
+func (m Model) WithDebugOverlay(enabled bool) Model {
+	m.debugOverlay = enabled
+	return m
+}
+
+// GetBranch returns the VCS branch shown in the header.
+func (m Model) GetBranch() string {
+	return m.branch
+}
+
+// WithBreadcrumbCommand returns a copy of the model whose breadcrumb bar shows the
+// selected command before the path, so the whole intended action is visible.
+func (m Model) WithBreadcrumbCommand(enabled bool) Model {
+	m.breadcrumbCmd = enabled
+	return m
+}
+
+// WithEnterOnParentStack returns a copy of the model where enter on a node that is both
+// a stack and a parent either confirms it (EnterParentStackConfirm) or drills into its
+// children (EnterParentStackDrill), leaving alt+enter to confirm. Unknown modes confirm.
+func (m Model) WithEnterOnParentStack(mode string) Model {
+	m.enterDrills = mode == EnterParentStackDrill
+	return m
+}
+
+// WithLastCommands returns a copy of the model that, whenever a different stack gains focus,
+// pre-selects the command last run against it. lastCommands maps absolute stack paths to
+// command names; stacks without an entry select the default (first) command.
+func (m Model) WithLastCommands(lastCommands map[string]string) Model {
+	m.lastCommands = lastCommands
+	if m.lastCommands == nil {
+		m.lastCommands = make(map[string]string)
+	}
+	return m
+}
+
+// WithLastSelection returns a copy of the model that starts with command selected and the
+// columns positioned at stackPath, as they were when the TUI last closed in this project.
+// A command no longer configured or a path no longer in the tree is ignored.
+func (m Model) WithLastSelection(command, stackPath string) Model {
+	if index := m.commandIndex(command); index >= 0 {
+		m.selectedCommand = index
+		maxVisibleItems := m.getMaxVisibleItems(0)
+		m.scrollOffsets[0] = (index / maxVisibleItems) * maxVisibleItems
+	}
+	if m.navigator == nil || stackPath == "" {
+		return m
+	}
+
+	m = m.jumpToPath(stackPath)
+	if !m.isCommandsColumnFocused() {
+		// The restored command stays selected until another stack gains focus.
+		m.rememberedFor = m.GetSelectedStackPath()
+	}
+	return m
+}
+
+// WithLastRuns returns a copy of the model that marks each navigation item with the outcome
+// of the latest run against it (✓, ✗, ± or =), styled like the history table. lastRuns maps
+// absolute stack paths to their latest entry; items without an entry show no indicator.
+func (m Model) WithLastRuns(lastRuns map[string]history.ExecutionLogEntry) Model {
+	m.lastRuns = lastRuns
+	if m.lastRuns == nil {
+		m.lastRuns = make(map[string]history.ExecutionLogEntry)
+	}
+	return m
+}
+
+// WithStackGrouping returns a copy of the model that lists stacks before plain directories
+// in every navigation column, separated by a non-selectable divider.
+// The stack tree is reordered in place.
+func (m Model) WithStackGrouping(enabled bool) Model {
+	m.groupStacks = enabled
+	if enabled && m.navigator != nil {
+		m.navigator.GetRoot().GroupStacksFirst()
+		m.navigator.PropagateSelection(m.navState)
+	}
+	return m
+}
+
+// WithIncludeRoot returns a copy of the model that can target the given include root
+// (the directory holding the root config file) from the commands column, so run-all
+// commands can be executed for the whole project even when TerraX was opened deeper.
+func (m Model) WithIncludeRoot(root string) Model {
+	m.includeRoot = root
+	m.targetIncludeRoot = false
+	return m
+}
+
+// WithCommandIcons returns a copy of the model that prefixes commands with the given icons.
+// When emoji is false, icons that are not plain ASCII are replaced by ASCIICommandIcon.
+func (m Model) WithCommandIcons(icons map[string]string, emoji bool) Model {
+	m.commandIcons = make(map[string]string, len(icons))
+	for command, icon := range icons {
+		if icon == "" {
+			continue
+		}
+		if !emoji && !isASCII(icon) {
+			icon = ASCIICommandIcon
+		}
+		m.commandIcons[command] = icon
+	}
+	return m
+}
+
+// WithCommandDescriptions returns a copy of the model that shows a dimmed one-line
+// description beside each command in the commands column (e.g. plan -> "preview changes").
+func (m Model) WithCommandDescriptions(descriptions map[string]string) Model {
+	m.commandDescs = descriptions
+	return m
+}
+
+// commandDescriptions returns the description configured for each command, or nil when
+// no descriptions are configured.
+func (m Model) commandDescriptions(commands []string) []string {
+	if len(m.commandDescs) == 0 {
+		return nil
+	}
+	descriptions := make([]string, len(commands))
+	for i, c := range commands {
+		descriptions[i] = m.commandDescs[c]
+	}
+	return descriptions
+}
+
+// commandLabels returns the display label for each command, prefixed with its icon if configured.
+func (m Model) commandLabels(commands []string) []string {
+	if len(m.commandIcons) == 0 {
+		return commands
+	}
+	labels := make([]string, len(commands))
+	for i, c := range commands {
+		if icon, ok := m.commandIcons[c]; ok {
+			labels[i] = icon + " " + c
+		} else {
+			labels[i] = c
+		}
+	}
+	return labels
+}
+
+// isASCII reports whether s contains only ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// dangerousFlags reports, for each command in commands, whether it or the command of its
+// alias is configured as dangerous.
+// Returns nil when no dangerous commands are configured.
+func (m Model) dangerousFlags(commands []string) []bool {
+	if len(m.dangerousCommands) == 0 {
+		return nil
+	}
+	flags := make([]bool, len(commands))
+	for i, c := range commands {
+		flags[i] = m.dangerousCommands[c] || m.dangerousCommands[m.resolveCommand(c)]
+	}
+	return flags
+}
+
+// WithLocale returns a copy of the model that renders UI strings from the locale's
+// message catalog. Keys missing from the catalog fall back to English.
+func (m Model) WithLocale(locale string) Model {
+	m.locale = locale
+	return m
+}
+
+// WithMessages returns a copy of the model that renders the given status text.
+// Empty fields keep the built-in text.
+func (m Model) WithMessages(msgs Messages) Model {
+	m.messages = msgs
+	return m
+}
+
+// WithHistoryTableStyle returns a copy of the model that renders the history table
+// with the given striping and cursor configuration.
+func (m Model) WithHistoryTableStyle(style HistoryTableStyle) Model {
+	m.historyTableStyle = style
+	return m
+}
+
+// WithProjectSwitchNotice returns a copy of the model whose history view shows a banner
+// noting that the most recent run happened in previousProjectRoot, a different project.
+// An empty previousProjectRoot shows no banner.
+func (m Model) WithProjectSwitchNotice(previousProjectRoot string) Model {
+	m.previousProjectRoot = previousProjectRoot
+	return m
+}
+
+// WithHistoryGrouping returns a copy of the model whose history view starts grouped by
+// stack, one row per stack showing its latest run. Groups expand to list every run.
+func (m Model) WithHistoryGrouping(grouped bool) Model {
+	return m.setHistoryGrouping(grouped)
+}
+
 // NewPlanReviewModel creates a model initialized in plan review mode.
 func NewPlanReviewModel(report *plan.PlanReport) Model {
 	// Filter stacks to only show those with changes
@@ -208,11 +586,23 @@ func flattenTree(nodes []*plan.TreeNode) []*plan.TreeNode {
 
 // Init initializes the model (BubbleTea interface).
 func (m Model) Init() tea.Cmd {
+	if m.idleTimeout > 0 {
+		return idleTick(m.idleTimeout)
+	}
 	return nil
 }
 
 // Update handles messages and updates the model (BubbleTea interface).
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case idleTickMsg:
+		return m.handleIdleTick()
+	case keySequenceTimeoutMsg:
+		return m.handleKeySequenceTimeout(msg)
+	case tea.KeyMsg, tea.MouseMsg:
+		m.lastInput = currentClock.Now()
+	}
+
 	switch m.state {
 	case StateNavigation:
 		return m.handleNavigationUpdate(msg)
@@ -220,6 +610,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleHistoryUpdate(msg)
 	case StatePlanReview:
 		return m.handlePlanReviewUpdate(msg)
+	case StateConfirm:
+		return m.handleConfirmUpdate(msg)
 	}
 	return m, nil
 }
@@ -233,6 +625,8 @@ func (m Model) View() string {
 		return m.renderHistoryView()
 	case StatePlanReview:
 		return m.renderPlanReviewView()
+	case StateConfirm:
+		return m.renderConfirmView()
 	}
 	return "Unknown state"
 }
@@ -240,7 +634,12 @@ func (m Model) View() string {
 // calculateColumnWidth computes the static width for all columns.
 // Uses actual visible columns (capped at maxNavigationColumns) so shallow trees
 // expand to fill the terminal instead of leaving a gap on the right.
+// A user-chosen width (columnWidthOverride) takes precedence and is only clamped.
 func (m Model) calculateColumnWidth() int {
+	if m.columnWidthOverride > 0 {
+		return m.clampColumnWidth(m.columnWidthOverride)
+	}
+
 	maxDepth := m.navigator.GetMaxDepth()
 	if maxDepth == 0 {
 		return MinColumnWidth
@@ -250,7 +649,18 @@ func (m Model) calculateColumnWidth() int {
 	actualNavCols := min(maxDepth, m.maxNavigationColumns)
 	actualVisibleColumns := 1 + actualNavCols
 
-	// Each column consumes colWidth + ColumnOverhead chars (left + right margin).
+	// A collapsed commands column is a fixed-width bar and a hidden one takes no room;
+	// its share goes to navigation. The preview pane keeps its width.
+	reservedWidth := m.previewPaneWidth()
+	if m.commandsHidden() {
+		actualVisibleColumns = actualNavCols
+	} else if m.commandsCollapsed {
+		actualVisibleColumns = actualNavCols
+		reservedWidth += CollapsedBarWidth + m.columnGap
+	}
+
+	// Each column consumes colWidth + columnGap chars (left + right margin), and a
+	// configured separator is drawn between each pair of navigation columns.
 	// When the tree is deeper than the sliding window, a right-overflow arrow is
 	// rendered alongside the columns and must be included in the budget.
 	arrowOverhead := 0
@@ -258,7 +668,9 @@ func (m Model) calculateColumnWidth() int {
 		arrowOverhead = ArrowIndicatorWidth
 	}
 
-	colWidth := (m.width - ColumnOverhead*actualVisibleColumns - arrowOverhead) / actualVisibleColumns
+	separators := (actualNavCols - 1) * m.separatorWidth()
+
+	colWidth := (m.width - reservedWidth - separators - m.columnGap*actualVisibleColumns - arrowOverhead) / actualVisibleColumns
 
 	if colWidth < MinColumnWidth {
 		return MinColumnWidth
@@ -266,6 +678,289 @@ func (m Model) calculateColumnWidth() int {
 	return colWidth
 }
 
+// clampColumnWidth bounds width between MinColumnWidth and the widest column that still
+// fits the terminal (capped at MaxColumnWidth).
+func (m Model) clampColumnWidth(width int) int {
+	maxWidth := min(MaxColumnWidth, m.width-m.columnGap)
+	if maxWidth < MinColumnWidth {
+		maxWidth = MinColumnWidth
+	}
+	return max(MinColumnWidth, min(width, maxWidth))
+}
+
+// WithColumnWidth returns a copy of the model that uses a fixed column width
+// instead of the auto-calculated one. A width of 0 uses the automatic layout.
+func (m Model) WithColumnWidth(width int) Model {
+	m.columnWidthOverride = max(width, 0)
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// WithCommands returns a copy of the model listing the given commands.
+// The selection is kept in range of the new list.
+func (m Model) WithCommands(commands []string) Model {
+	m.commands = commands
+	m.selectedCommand = bounds.ClampIndex(m.selectedCommand, len(commands))
+	if m.scrollOffsets != nil {
+		m.scrollOffsets[0] = 0
+	}
+	if m.commandGroups != nil {
+		return m.WithCommandGroups(m.commandGroups)
+	}
+	return m
+}
+
+// CommandGroup is a labeled set of commands listed together under a header in the
+// commands column.
+type CommandGroup struct {
+	Name     string   // Header shown above the group's commands
+	Commands []string // Commands of the group, in order
+}
+
+// WithCommandGroups returns a copy of the model that lists the commands of each group
+// together under a non-selectable header. Commands in no group are listed first, without
+// a header. Commands not in the commands list, or already in an earlier group, are
+// dropped from a group, as are groups left without commands. The same command stays
+// selected.
+func (m Model) WithCommandGroups(groups []CommandGroup) Model {
+	selected := m.GetSelectedCommandLabel()
+	grouped := make(map[string]bool)
+	m.commandGroups = nil
+	for _, group := range groups {
+		var commands []string
+		for _, command := range group.Commands {
+			if slices.Contains(m.commands, command) && !grouped[command] {
+				grouped[command] = true
+				commands = append(commands, command)
+			}
+		}
+		if len(commands) > 0 {
+			m.commandGroups = append(m.commandGroups, CommandGroup{Name: group.Name, Commands: commands})
+		}
+	}
+
+	ordered := make([]string, 0, len(m.commands))
+	for _, command := range m.commands {
+		if !grouped[command] {
+			ordered = append(ordered, command)
+		}
+	}
+	for _, group := range m.commandGroups {
+		ordered = append(ordered, group.Commands...)
+	}
+	m.commands = ordered
+	if index := slices.Index(ordered, selected); index >= 0 {
+		m.selectedCommand = index
+	}
+	return m
+}
+
+// commandGroupHeaders returns the group header drawn before each item of commands (the
+// possibly filtered commands column) that starts a group, keyed by the item's index.
+// Groups with no command in commands get no header. Returns nil without groups.
+func (m Model) commandGroupHeaders(commands []string) map[int]string {
+	if len(m.commandGroups) == 0 {
+		return nil
+	}
+	if filter, exists := m.columnFilters[0]; exists && m.fuzzyFilter && filter.Value() != "" {
+		return nil // Ranked matches no longer list each group's commands together.
+	}
+	headers := make(map[int]string)
+	previous := ""
+	for i, command := range commands {
+		name := m.commandGroupName(command)
+		if name != "" && (i == 0 || name != previous) {
+			headers[i] = name
+		}
+		previous = name
+	}
+	return headers
+}
+
+// commandGroupName returns the name of the group command belongs to, or "" when it is
+// in no group.
+func (m Model) commandGroupName(command string) string {
+	for _, group := range m.commandGroups {
+		if slices.Contains(group.Commands, command) {
+			return group.Name
+		}
+	}
+	return ""
+}
+
+// WithMaxNavigationColumns returns a copy of the model showing at most n navigation
+// columns at once (must be validated before calling). The sliding window is moved so
+// the focused column stays visible.
+func (m Model) WithMaxNavigationColumns(n int) Model {
+	m.maxNavigationColumns = n
+	if depth := m.getNavigationDepth(); depth >= 0 {
+		if depth < m.navigationOffset {
+			m.navigationOffset = depth
+		} else if depth > m.navigationOffset+n-1 {
+			m.navigationOffset = depth - n + 1
+		}
+	}
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// WithColumnGap returns a copy of the model with gap spaces between adjacent columns.
+// A gap of 0 places columns side by side; a negative gap uses the default spacing.
+func (m Model) WithColumnGap(gap int) Model {
+	if gap < 0 {
+		gap = ColumnOverhead
+	}
+	m.columnGap = gap
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// WithColumnSeparator returns a copy of the model that draws sep between navigation
+// columns. An empty sep draws no separator.
+func (m Model) WithColumnSeparator(sep string) Model {
+	m.columnSeparator = sep
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// columnMargins splits the column gap into the left and right margin of each column.
+func (m Model) columnMargins() (left, right int) {
+	return m.columnGap / 2, m.columnGap - m.columnGap/2
+}
+
+// separatorWidth returns the terminal width of the column separator (0 when unset).
+func (m Model) separatorWidth() int {
+	return lipgloss.Width(m.columnSeparator)
+}
+
+// ConfigReloader re-reads the configuration and returns the model with it applied.
+type ConfigReloader func(m Model) (Model, error)
+
+// WithConfigReloader returns a copy of the model that reloads its configuration with
+// reload when ctrl+r is pressed, so edits to the config file apply without a restart.
+func (m Model) WithConfigReloader(reload ConfigReloader) Model {
+	m.configReloader = reload
+	return m
+}
+
+// Favorite is a command preset with extra args, selected with a function key.
+type Favorite struct {
+	Command string   // Command to run
+	Args    []string // Extra args passed to the command
+}
+
+// ParseFavorite splits a preset such as "plan -refresh=false" into its command and args.
+// It reports false for a blank preset.
+func ParseFavorite(preset string) (Favorite, bool) {
+	fields := strings.Fields(preset)
+	if len(fields) == 0 {
+		return Favorite{}, false
+	}
+	return Favorite{Command: fields[0], Args: fields[1:]}, true
+}
+
+// WithFavorites returns a copy of the model with presets mapped to F1–F9 in order.
+// Blank presets are skipped and presets beyond MaxFavorites are ignored.
+func (m Model) WithFavorites(presets []string) Model {
+	m.favorites = nil
+	for _, preset := range presets {
+		if favorite, ok := ParseFavorite(preset); ok && len(m.favorites) < MaxFavorites {
+			m.favorites = append(m.favorites, favorite)
+		}
+	}
+	return m
+}
+
+// WithCommandAliases returns a copy of the model that runs a command line in place of a
+// listed command label, e.g. aa -> "apply -auto-approve". The commands column shows the
+// label; the first word of the command line runs with the rest as extra args. Blank
+// command lines are skipped.
+func (m Model) WithCommandAliases(aliases map[string]string) Model {
+	m.commandAliases = make(map[string]Favorite, len(aliases))
+	for label, commandLine := range aliases {
+		if alias, ok := ParseFavorite(commandLine); ok {
+			m.commandAliases[label] = alias
+		}
+	}
+	return m
+}
+
+// resolveCommand returns the command run for a label of the commands column: the
+// command of its alias, or the label itself.
+func (m Model) resolveCommand(label string) string {
+	if alias, ok := m.commandAliases[label]; ok {
+		return alias.Command
+	}
+	return label
+}
+
+// commandIndex returns the index in the commands column of command, listed as is or as
+// the command of an alias, or -1 when it is not listed.
+func (m Model) commandIndex(command string) int {
+	if index := slices.Index(m.commands, command); index >= 0 {
+		return index
+	}
+	return slices.IndexFunc(m.commands, func(label string) bool {
+		return m.resolveCommand(label) == command
+	})
+}
+
+// WithCommandStackTypes returns a copy of the model that only offers a command for the
+// stack types it maps to (see stack.DetectType). Commands missing from the map, and
+// directories of no detected type, are not filtered.
+func (m Model) WithCommandStackTypes(commandTypes map[string][]string) Model {
+	m.cmdStackTypes = commandTypes
+	return m
+}
+
+// ExecutionDetails is the context of a confirmed selection shown in the confirmation summary.
+type ExecutionDetails struct {
+	Binary      string   // Executable that will run the command
+	Command     string   // Selected command
+	StackPaths  []string // Stacks the command runs against
+	ExtraArgs   []string // Configured extra flags added to the command
+	EnvVarNames []string // Names of the environment variables injected for the run
+	Branch      string   // VCS branch of the working directory
+}
+
+// ExecutionPreviewer completes details with the execution context the TUI does not know,
+// such as the resolved binary, extra args and environment variable names.
+type ExecutionPreviewer func(details ExecutionDetails) ExecutionDetails
+
+// WithConfirmSummary returns a copy of the model that shows a summary of the execution
+// after confirming, requiring enter to proceed.
+func (m Model) WithConfirmSummary(enabled bool) Model {
+	m.confirmSummary = enabled
+	return m
+}
+
+// WithRequireConfirmation returns a copy of the model that, after confirming one of commands,
+// shows the execution summary with a yes/no prompt and runs the command only on y.
+func (m Model) WithRequireConfirmation(commands []string) Model {
+	m.requireConfirmation = commands
+	return m
+}
+
+// WithExecutionPreviewer returns a copy of the model that completes the confirmation
+// summary with preview.
+func (m Model) WithExecutionPreviewer(preview ExecutionPreviewer) Model {
+	m.previewer = preview
+	return m
+}
+
+// GetExecutionDetails returns the summary shown while confirming an execution.
+func (m Model) GetExecutionDetails() ExecutionDetails {
+	return m.confirmDetails
+}
+
 // isCommandsColumnFocused returns true if the commands column is focused.
 func (m Model) isCommandsColumnFocused() bool {
 	return m.focusedColumn == 0
@@ -291,10 +986,32 @@ func (m Model) getAvailableHeight() int {
 	return availableHeight
 }
 
+// getListLineCount returns the number of lines a column list occupies: the visible items
+// plus the lines reserved for the stack grouping divider or the command group headers.
+// Every column is padded to the tallest, so all columns share one height.
+func (m Model) getListLineCount() int {
+	commandLines := m.getMaxVisibleItems(0) + m.getReservedListLines(0)
+	navigationLines := m.getMaxVisibleItems(1) + m.getReservedListLines(1)
+	return max(commandLines, navigationLines)
+}
+
+// getReservedListLines returns the lines the list of a column reserves besides its items:
+// one per command group for their headers in the commands column (columnID 0), and one for
+// the stack grouping divider in the navigation columns.
+func (m Model) getReservedListLines(columnID int) int {
+	if columnID == 0 {
+		return len(m.commandGroups)
+	}
+	if m.groupStacks {
+		return 1
+	}
+	return 0
+}
+
 // getMaxVisibleItems returns the maximum number of items that can be displayed
-// in a column given the current terminal height.
+// in the column given the current terminal height.
 // Reserves 1 line for pagination indicators to ensure consistent column heights.
-func (m Model) getMaxVisibleItems() int {
+func (m Model) getMaxVisibleItems(columnID int) int {
 	availableHeight := m.getAvailableHeight()
 
 	// Reserve 1 line for pagination indicators to ensure all columns have same height.
@@ -302,6 +1019,10 @@ func (m Model) getMaxVisibleItems() int {
 	reservedForPagination := 1
 	maxItems := availableHeight - reservedForPagination
 
+	// Reserve lines for the command group headers or the divider between stacks and plain
+	// directories.
+	maxItems -= m.getReservedListLines(columnID)
+
 	if maxItems < 1 {
 		return 1
 	}
@@ -309,9 +1030,9 @@ func (m Model) getMaxVisibleItems() int {
 	return maxItems
 }
 
-// getTotalPages calculates the total number of pages for a list.
-func (m Model) getTotalPages(totalItems int) int {
-	maxVisibleItems := m.getMaxVisibleItems()
+// getTotalPages calculates the total number of pages for a list in the column.
+func (m Model) getTotalPages(columnID, totalItems int) int {
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
 	if maxVisibleItems <= 0 {
 		return 1
 	}
@@ -324,7 +1045,7 @@ func (m Model) getTotalPages(totalItems int) int {
 
 // getCurrentPage calculates the current page number (1-indexed) based on scroll offset.
 func (m Model) getCurrentPage(columnID int) int {
-	maxVisibleItems := m.getMaxVisibleItems()
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
 	if maxVisibleItems <= 0 {
 		return 1
 	}
@@ -333,9 +1054,9 @@ func (m Model) getCurrentPage(columnID int) int {
 	return currentPage
 }
 
-// getPageStartIndex returns the start index for a given page number (1-indexed).
-func (m Model) getPageStartIndex(pageNumber int) int {
-	maxVisibleItems := m.getMaxVisibleItems()
+// getPageStartIndex returns the start index for a given page number (1-indexed) of the column.
+func (m Model) getPageStartIndex(columnID, pageNumber int) int {
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
 	if pageNumber <= 1 {
 		return 0
 	}
@@ -352,18 +1073,56 @@ func (m Model) getNavigationDepth() int {
 }
 
 // GetSelectedCommand returns the currently selected command name.
+// A preset chosen with a function key takes precedence over the commands column.
 func (m Model) GetSelectedCommand() string {
-	if m.selectedCommand >= 0 && m.selectedCommand < len(m.commands) {
+	if m.favorite != nil {
+		return m.favorite.Command
+	}
+	return m.resolveCommand(m.GetSelectedCommandLabel())
+}
+
+// GetSelectedCommandLabel returns the selected command as listed in the commands column,
+// before expanding a command alias.
+func (m Model) GetSelectedCommandLabel() string {
+	if m.favorite != nil {
+		return m.favorite.Command
+	}
+	if bounds.InRange(m.selectedCommand, len(m.commands)) {
 		return m.commands[m.selectedCommand]
 	}
 	return NoItemSelected
 }
 
+// GetSelectedArgs returns the extra args of the preset chosen with a function key or of
+// the selected command alias, if any.
+func (m Model) GetSelectedArgs() []string {
+	if m.favorite != nil {
+		return m.favorite.Args
+	}
+	if alias, ok := m.commandAliases[m.GetSelectedCommandLabel()]; ok {
+		return alias.Args
+	}
+	return nil
+}
+
+// GetCommands returns the commands listed in the commands column.
+func (m Model) GetCommands() []string {
+	return m.commands
+}
+
+// GetMaxNavigationColumns returns the maximum number of navigation columns visible at once.
+func (m Model) GetMaxNavigationColumns() int {
+	return m.maxNavigationColumns
+}
+
 // GetSelectedStackPath returns the selected stack path.
 func (m Model) GetSelectedStackPath() string {
 	var targetNode *stack.Node
 
 	if m.isCommandsColumnFocused() {
+		if m.isTargetingIncludeRoot() {
+			return m.includeRoot
+		}
 		targetNode = m.navigator.GetRoot()
 	} else {
 		depth := m.getNavigationDepth()
@@ -376,6 +1135,26 @@ func (m Model) GetSelectedStackPath() string {
 	return NoItemSelected
 }
 
+// GetSelectedProjectRoot returns the path of the project selected in the first navigation
+// level when the tree groups stacks by project (monorepo of projects).
+// Returns an empty string when the tree has no project level or nothing is selected.
+func (m Model) GetSelectedProjectRoot() string {
+	if m.navigator == nil || m.navState == nil {
+		return ""
+	}
+	for _, node := range m.navState.CurrentNodes {
+		if node != nil && node.IsProject {
+			return node.Path
+		}
+	}
+	return ""
+}
+
+// isTargetingIncludeRoot reports whether the commands column execution target is the include root.
+func (m Model) isTargetingIncludeRoot() bool {
+	return m.targetIncludeRoot && m.includeRoot != ""
+}
+
 // IsConfirmed returns whether the user confirmed the selection.
 func (m Model) IsConfirmed() bool {
 	return m.confirmed
@@ -384,6 +1163,9 @@ func (m Model) IsConfirmed() bool {
 // getCurrentNavigationPath returns the current navigation path as a string.
 // Delegates to Navigator for path construction business logic.
 func (m Model) getCurrentNavigationPath() string {
+	if m.isCommandsColumnFocused() && m.isTargetingIncludeRoot() {
+		return m.includeRoot + " " + IncludeRootLabel
+	}
 	depth := m.getNavigationDepth()
 	return m.navigator.GetNavigationPath(m.navState, depth)
 }
@@ -401,7 +1183,7 @@ func (m Model) canAdvanceFurther() bool {
 	}
 
 	depth := m.getNavigationDepth()
-	if depth < 0 || depth >= len(m.navState.CurrentNodes) {
+	if !bounds.InRange(depth, len(m.navState.CurrentNodes)) {
 		return false
 	}
 
@@ -413,6 +1195,26 @@ func (m Model) canAdvanceFurther() bool {
 	return currentNode.HasChildren()
 }
 
+// isOnLeafStack returns true if the focused node is a stack with no children.
+func (m Model) isOnLeafStack() bool {
+	if m.isCommandsColumnFocused() {
+		return false
+	}
+
+	node := m.navigator.GetNodeAtDepth(m.navState, m.getNavigationDepth())
+	return node != nil && node.IsStack && !node.HasChildren()
+}
+
+// isOnParentStack returns true if the focused node is a stack that also has children.
+func (m Model) isOnParentStack() bool {
+	if m.isCommandsColumnFocused() {
+		return false
+	}
+
+	node := m.navigator.GetNodeAtDepth(m.navState, m.getNavigationDepth())
+	return node != nil && node.IsStack && node.HasChildren()
+}
+
 // hasRightOverflow returns true if there are navigation columns to the right.
 // Shows indicator if: 1) sliding window doesn't cover last levels AND 2) current node has children.
 func (m Model) hasRightOverflow() bool {
@@ -430,6 +1232,93 @@ func (m Model) hasRightOverflow() bool {
 	return true
 }
 
+// WithFuzzyFilter returns a copy of the model whose column filters match items fuzzily:
+// the typed characters must appear in order but not together ("dvus" matches
+// "dev/us-east-1"), and matches are listed best first. By default filters match substrings.
+func (m Model) WithFuzzyFilter(enabled bool) Model {
+	m.fuzzyFilter = enabled
+	return m
+}
+
+// WithHighlightMatches returns a copy of the model that styles the characters a column
+// filter matched in each listed item (bold and underlined). It is enabled by default.
+func (m Model) WithHighlightMatches(enabled bool) Model {
+	m.highlightMatches = enabled
+	return m
+}
+
+// WithKeyBindings returns a copy of the model whose actions are triggered by the keys in
+// bindings (action -> key, validated by config.TUI.ResolveKeyBindings). Actions left out
+// keep their default key.
+func (m Model) WithKeyBindings(bindings map[string]string) Model {
+	m.keyActions = keyActions(bindings)
+	return m
+}
+
+// keyActions inverts the default key bindings overridden by bindings into a key -> action map.
+func keyActions(bindings map[string]string) map[string]string {
+	actions := make(map[string]string, len(config.DefaultKeyBindings))
+	for action, key := range config.DefaultKeyBindings {
+		if override, ok := bindings[action]; ok {
+			key = override
+		}
+		actions[key] = action
+	}
+	return actions
+}
+
+// WithSkipSingleCommand returns a copy of the model that hides the commands column while
+// exactly one command is configured: navigation is pure stack selection and enter runs
+// that command against the selected stack. Focus moves off the hidden column.
+func (m Model) WithSkipSingleCommand(enabled bool) Model {
+	m.skipSingleCommand = enabled
+	if m.commandsHidden() && m.isCommandsColumnFocused() {
+		m.focusedColumn = 1
+	}
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// commandsHidden reports whether the commands column is skipped because it holds the only
+// configured command and there is at least one navigation column to focus instead.
+func (m Model) commandsHidden() bool {
+	return m.skipSingleCommand && len(m.commands) == 1 &&
+		m.navigator != nil && m.navigator.GetMaxDepth() > 0
+}
+
+// WithTypeToFilter returns a copy of the model where typing a printable key in a column
+// opens its filter pre-filled with that key: TypeToFilterUnbound does so for keys without a
+// shortcut, TypeToFilterAll for every printable key. Other modes turn it off.
+func (m Model) WithTypeToFilter(mode string) Model {
+	switch mode {
+	case TypeToFilterUnbound, TypeToFilterAll:
+		m.typeToFilter = mode
+	default:
+		m.typeToFilter = ""
+	}
+	return m
+}
+
+// WithFilterScope returns a copy of the model whose filter follows focus when scope is
+// FilterScopeGlobal: the text typed in one column narrows whichever column gains focus next.
+// Other scopes keep one filter per column.
+func (m Model) WithFilterScope(scope string) Model {
+	m.globalFilter = scope == FilterScopeGlobal
+	return m
+}
+
+// applyFilter filters items by filterText with the model's matching mode.
+// Selection is tracked by original index, so callers map indices with
+// findOriginalIndex/findFilteredIndex, which do not depend on the order of the result.
+func (m Model) applyFilter(items []string, filterText string) []string {
+	if m.fuzzyFilter {
+		return fuzzyFilterItems(items, filterText)
+	}
+	return filterItems(items, filterText)
+}
+
 // filterItems filters a list of items based on the filter text (case-insensitive).
 func filterItems(items []string, filterText string) []string {
 	if filterText == "" {
@@ -448,38 +1337,153 @@ func filterItems(items []string, filterText string) []string {
 	return filtered
 }
 
-// getFilteredCommands returns the commands list with active filter applied.
+// getFilteredCommands returns the commands list with the focused stack's allowlist
+// and the active filter applied.
 func (m *Model) getFilteredCommands() []string {
+	commands := m.getAllowedCommands()
 	if filter, exists := m.columnFilters[0]; exists {
 		filterValue := filter.Value()
 		if filterValue != "" {
-			return filterItems(m.commands, filterValue)
+			return m.applyFilter(commands, filterValue)
 		}
 	}
-	return m.commands
+	return commands
 }
 
-// getFilteredNavigationItems returns the navigation items for a depth with active filter applied.
+// getAllowedCommands returns the commands allowed for the focused stack, keeping the
+// configured order. All commands are allowed when the commands column is focused, or the
+// focused stack has no allowlist and no detected type limits its commands.
+func (m *Model) getAllowedCommands() []string {
+	allowlist := m.focusedStackAllowlist()
+	stackType := m.focusedStackType()
+	if allowlist == nil && stackType == "" {
+		return m.commands
+	}
+
+	allowed := make([]string, 0, len(m.commands))
+	for _, label := range m.commands {
+		command := m.resolveCommand(label)
+		if allowlist != nil && !slices.Contains(allowlist, command) {
+			continue
+		}
+		if types, limited := m.cmdStackTypes[command]; limited && stackType != "" && !slices.Contains(types, stackType) {
+			continue
+		}
+		allowed = append(allowed, label)
+	}
+	return allowed
+}
+
+// focusedStackType returns the detected type of the focused node, or "" when no command
+// is limited to stack types or the type is unknown. Detection runs the first time a path
+// is focused and is cached.
+func (m *Model) focusedStackType() string {
+	if len(m.cmdStackTypes) == 0 || m.isCommandsColumnFocused() || m.navigator == nil {
+		return ""
+	}
+
+	node := m.navigator.GetNodeAtDepth(m.navState, m.getNavigationDepth())
+	if node == nil {
+		return ""
+	}
+
+	if stackType, cached := m.stackTypes[node.Path]; cached {
+		return stackType
+	}
+	stackType := stack.DetectType(node.Path)
+	if m.stackTypes != nil {
+		m.stackTypes[node.Path] = stackType
+	}
+	return stackType
+}
+
+// focusedStackAllowlist returns the allowed_commands of the focused stack's settings file,
+// or nil when there is none. The file is read the first time the stack is focused and
+// cached; an unreadable or invalid file allows all commands.
+func (m *Model) focusedStackAllowlist() []string {
+	if m.isCommandsColumnFocused() || m.navigator == nil {
+		return nil
+	}
+
+	node := m.navigator.GetNodeAtDepth(m.navState, m.getNavigationDepth())
+	if node == nil || !node.IsStack {
+		return nil
+	}
+
+	if allowlist, cached := m.stackAllowlists[node.Path]; cached {
+		return allowlist
+	}
+	settings, err := stack.LoadSettings(node.Path)
+	if err != nil {
+		settings = stack.Settings{}
+	}
+	if m.stackAllowlists != nil {
+		m.stackAllowlists[node.Path] = settings.AllowedCommands
+	}
+	return settings.AllowedCommands
+}
+
+// focusedStackInfo returns the description and owner from the focused stack's metadata
+// file joined into one line, or "" when there is none. The file is read the first time the
+// stack is focused and cached; an unreadable or invalid file counts as no metadata.
+func (m *Model) focusedStackInfo() string {
+	node := m.focusedNode()
+	if node == nil || !node.IsStack {
+		return ""
+	}
+
+	metadata, cached := m.stackMetadata[node.Path]
+	if !cached {
+		var err error
+		if metadata, err = stack.LoadMetadata(node.Path); err != nil {
+			metadata = stack.Metadata{}
+		}
+		if m.stackMetadata != nil {
+			m.stackMetadata[node.Path] = metadata
+		}
+	}
+
+	var parts []string
+	if description := strings.Join(strings.Fields(metadata.Description), " "); description != "" {
+		parts = append(parts, description)
+	}
+	if owner := strings.TrimSpace(metadata.Owner); owner != "" {
+		parts = append(parts, fmt.Sprintf(m.Text(MsgStackOwner), owner))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// getFilteredNavigationItems returns the navigation items for a depth with the stacks-only
+// toggle and the active filter applied.
 func (m *Model) getFilteredNavigationItems(depth int) []string {
-	if depth < 0 || depth >= len(m.navState.Columns) {
+	if !bounds.InRange(depth, len(m.navState.Columns)) {
 		return []string{}
 	}
 
-	items := m.navState.Columns[depth]
+	items := m.navigator.ShownItems(m.navState, depth)
 	columnID := depth + 1
 
 	if filter, exists := m.columnFilters[columnID]; exists {
 		filterValue := filter.Value()
 		if filterValue != "" {
-			return filterItems(items, filterValue)
+			return m.applyFilter(items, filterValue)
 		}
 	}
 	return items
 }
 
+// isNavigationColumnFiltered reports whether the navigation column at depth lists only some
+// of its items, through its filter or the stacks-only toggle.
+func (m *Model) isNavigationColumnFiltered(depth int) bool {
+	if filter, exists := m.columnFilters[depth+1]; exists && filter.Value() != "" {
+		return true
+	}
+	return m.navigator.IsStacksOnly()
+}
+
 // findOriginalIndex maps a filtered index back to the original unfiltered index.
 func findOriginalIndex(originalItems []string, filteredItems []string, filteredIndex int) int {
-	if filteredIndex < 0 || filteredIndex >= len(filteredItems) {
+	if !bounds.InRange(filteredIndex, len(filteredItems)) {
 		return -1
 	}
 
@@ -494,7 +1498,7 @@ func findOriginalIndex(originalItems []string, filteredItems []string, filteredI
 
 // findFilteredIndex maps an original index to the filtered index.
 func findFilteredIndex(originalItems []string, filteredItems []string, originalIndex int) int {
-	if originalIndex < 0 || originalIndex >= len(originalItems) {
+	if !bounds.InRange(originalIndex, len(originalItems)) {
 		return -1
 	}
 
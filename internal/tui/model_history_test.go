@@ -545,3 +545,218 @@ func TestModel_HistoryReExecution(t *testing.T) {
 	assert.Equal(t, "/test/prod/rds", finalModel.GetSelectedHistoryEntry().AbsolutePath)
 	assert.NotNil(t, cmd, "should quit to execute command")
 }
+
+// TestModel_HistoryGrouping tests grouping the history by stack, expanding a group to
+// list its runs and re-executing from group and run rows.
+func TestModel_HistoryGrouping(t *testing.T) {
+	entries := []history.ExecutionLogEntry{
+		{ID: 3, Command: "apply", StackPath: "dev/vpc", ExitCode: 1},
+		{ID: 2, Command: "plan", StackPath: "prod/rds"},
+		{ID: 1, Command: "plan", StackPath: "dev/vpc"},
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.handleHistoryUpdate(msg)
+		return updated.(Model)
+	}
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+	down := tea.KeyMsg{Type: tea.KeyDown}
+
+	m := NewHistoryModel(entries)
+	m.historyCursor = 2
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyGroup)})
+	assert.True(t, m.historyGrouped)
+	assert.Equal(t, 0, m.historyCursor, "toggling grouping resets the cursor")
+	assert.Equal(t, 2, m.historyRowCount(), "one row per stack while collapsed")
+	assert.Equal(t, 3, m.historyEntryAtCursor().ID, "a group row stands for its latest run")
+
+	// Right expands the group under the cursor into its runs.
+	m = press(m, right)
+	assert.Equal(t, []historyRow{{0, -1}, {0, 0}, {0, 1}, {1, -1}}, m.historyRows())
+
+	m = press(press(m, down), down)
+	assert.Equal(t, 1, m.historyEntryAtCursor().ID)
+
+	// Left from a run collapses its group and returns to the group row.
+	m = press(m, left)
+	assert.Equal(t, 0, m.historyCursor)
+	assert.Equal(t, 2, m.historyRowCount())
+
+	// Enter on a group row re-executes the stack's latest run.
+	m = press(m, down)
+	updated, cmd := m.handleHistoryUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	final := updated.(Model)
+	assert.True(t, final.ShouldReExecuteFromHistory())
+	assert.Equal(t, 2, final.GetSelectedHistoryEntry().ID)
+	assert.NotNil(t, cmd)
+
+	// Toggling again returns to one row per run.
+	m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyGroup)})
+	assert.False(t, m.historyGrouped)
+	assert.Equal(t, 3, m.historyRowCount())
+}
+
+// TestWithHistoryGrouping tests starting the history viewer grouped by stack.
+func TestWithHistoryGrouping(t *testing.T) {
+	entries := []history.ExecutionLogEntry{
+		{ID: 2, Command: "apply", StackPath: "dev/vpc"},
+		{ID: 1, Command: "plan", StackPath: "dev/vpc"},
+	}
+
+	m := NewHistoryModel(entries).WithHistoryGrouping(true)
+
+	assert.True(t, m.historyGrouped)
+	assert.Len(t, m.historyGroups, 1)
+	assert.Equal(t, 1, m.historyRowCount())
+
+	// Left/right do nothing in the flat view.
+	flat := NewHistoryModel(entries)
+	updated, _ := flat.handleHistoryUpdate(tea.KeyMsg{Type: tea.KeyRight})
+	assert.Equal(t, 2, updated.(Model).historyRowCount())
+}
+
+// TestModel_HistoryFilter tests narrowing the history view with the '/' filter and
+// clearing it with esc.
+func TestModel_HistoryFilter(t *testing.T) {
+	entries := []history.ExecutionLogEntry{
+		{ID: 4, Command: "apply", StackPath: "prod/rds", ExitCode: 1},
+		{ID: 3, Command: "plan", StackPath: "prod/vpc"},
+		{ID: 2, Command: "apply", StackPath: "dev/vpc"},
+		{ID: 1, Command: "plan", StackPath: "dev/rds"},
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.handleHistoryUpdate(msg)
+		return updated.(Model)
+	}
+	typeText := func(m Model, text string) Model {
+		for _, r := range text {
+			m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+		return m
+	}
+	ids := func(m Model) []int {
+		var got []int
+		for _, entry := range m.history {
+			got = append(got, entry.ID)
+		}
+		return got
+	}
+
+	t.Run("by command", func(t *testing.T) {
+		m := NewHistoryModel(entries)
+		m.historyCursor = 3
+		m = press(m, runesMsg(KeySlash))
+		assert.True(t, m.historyFiltering)
+
+		m = typeText(m, "APPLY")
+		assert.Equal(t, []int{4, 2}, ids(m))
+		assert.Equal(t, 0, m.historyCursor, "the cursor moves over the filtered rows")
+		assert.Equal(t, 2, m.historyRowCount())
+
+		// Enter returns to the table; navigation wraps within the filtered rows.
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.False(t, m.historyFiltering)
+		m = press(m, tea.KeyMsg{Type: tea.KeyUp})
+		assert.Equal(t, 2, m.historyEntryAtCursor().ID)
+
+		// Esc clears the filter before exiting.
+		updated, cmd := m.handleHistoryUpdate(tea.KeyMsg{Type: tea.KeyEsc})
+		m = updated.(Model)
+		assert.Nil(t, cmd, "esc with an active filter does not quit")
+		assert.Equal(t, []int{4, 3, 2, 1}, ids(m))
+		assert.False(t, m.historyFiltered())
+	})
+
+	t.Run("by path substring", func(t *testing.T) {
+		m := typeText(press(NewHistoryModel(entries), runesMsg(KeySlash)), "vpc")
+		assert.Equal(t, []int{3, 2}, ids(m))
+
+		m = typeText(m, "x")
+		assert.Empty(t, m.history)
+		assert.Nil(t, m.historyEntryAtCursor())
+
+		// Esc while typing clears the filter and restores every entry.
+		m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+		assert.False(t, m.historyFiltering)
+		assert.Equal(t, []int{4, 3, 2, 1}, ids(m))
+	})
+
+	t.Run("by exit status", func(t *testing.T) {
+		m := typeText(press(NewHistoryModel(entries), runesMsg(KeySlash)), "failed")
+		assert.Equal(t, []int{4}, ids(m))
+	})
+
+	t.Run("grouped", func(t *testing.T) {
+		m := NewHistoryModel(entries).WithHistoryGrouping(true)
+		m = typeText(press(m, runesMsg(KeySlash)), "rds")
+		assert.Len(t, m.historyGroups, 2)
+		assert.Equal(t, "prod/rds", m.historyGroups[0].StackPath)
+	})
+}
+
+// TestModel_HistoryFailedOnly tests toggling the history view between failed runs and
+// every run, alone and combined with the text filter.
+func TestModel_HistoryFailedOnly(t *testing.T) {
+	entries := []history.ExecutionLogEntry{
+		{ID: 4, Command: "apply", StackPath: "prod/rds", ExitCode: 1},
+		{ID: 3, Command: "plan", StackPath: "prod/vpc"},
+		{ID: 2, Command: "plan", StackPath: "dev/vpc", ExitCode: 2},
+		{ID: 1, Command: "plan", StackPath: "dev/rds"},
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.handleHistoryUpdate(msg)
+		return updated.(Model)
+	}
+	ids := func(m Model) []int {
+		var got []int
+		for _, entry := range m.history {
+			got = append(got, entry.ID)
+		}
+		return got
+	}
+	toggle := runesMsg(KeyFailures)
+
+	m := NewHistoryModel(entries)
+	m.historyCursor = 3
+	m = press(m, toggle)
+	assert.True(t, m.historyFailedOnly)
+	assert.Equal(t, []int{4, 2}, ids(m))
+	assert.Equal(t, 0, m.historyCursor, "the cursor is clamped to the failed rows")
+	assert.Equal(t, 2, m.historyRowCount())
+
+	m = press(m, toggle)
+	assert.False(t, m.historyFailedOnly)
+	assert.Equal(t, []int{4, 3, 2, 1}, ids(m), "toggling off restores every row")
+
+	// Composes with the text filter.
+	m = press(press(m, toggle), runesMsg(KeySlash))
+	for _, r := range "plan" {
+		m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	assert.Equal(t, []int{2}, ids(m))
+	m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = press(m, toggle)
+	assert.Equal(t, []int{3, 2, 1}, ids(m), "the text filter still applies")
+
+	// Esc clears both filters.
+	m = press(press(m, toggle), tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.historyFailedOnly)
+	assert.Equal(t, []int{4, 3, 2, 1}, ids(m))
+}
+
+// TestRenderHistoryView_FailedOnly tests that the header names the failures-only filter.
+func TestRenderHistoryView_FailedOnly(t *testing.T) {
+	m := NewHistoryModel([]history.ExecutionLogEntry{
+		{ID: 2, Command: "apply", StackPath: "dev/vpc", ExitCode: 1},
+		{ID: 1, Command: "plan", StackPath: "dev/vpc"},
+	})
+	m.width = 120
+	m.height = 30
+	m.ready = true
+
+	assert.NotContains(t, m.renderHistoryView(), "(failures only)")
+	m = m.setHistoryFailedOnly(true)
+	view := m.renderHistoryView()
+	assert.Contains(t, view, "Execution History (failures only)")
+	assert.Contains(t, view, "1 of 2 entries match")
+}
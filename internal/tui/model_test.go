@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/israoo/terrax/internal/stack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestModel_Init tests the Bubble Tea Init method.
@@ -361,6 +364,69 @@ func TestModel_GetSelectedStackPath_NilNode(t *testing.T) {
 	assert.Equal(t, NoItemSelected, path)
 }
 
+// TestModel_GetSelectedProjectRoot tests project detection from the first navigation level.
+func TestModel_GetSelectedProjectRoot(t *testing.T) {
+	root := &stack.Node{
+		Name: "monorepo",
+		Path: "/test/monorepo",
+		Children: []*stack.Node{
+			{Name: "team-a", Path: "/test/monorepo/team-a", IsProject: true, Depth: 1, Children: []*stack.Node{
+				{Name: "vpc", Path: "/test/monorepo/team-a/vpc", IsStack: true, Depth: 2},
+			}},
+			{Name: "team-b", Path: "/test/monorepo/team-b", IsProject: true, Depth: 1, Children: []*stack.Node{
+				{Name: "rds", Path: "/test/monorepo/team-b/rds", IsStack: true, Depth: 2},
+			}},
+		},
+	}
+
+	m := NewModel(root, 2, []string{"plan"}, 3)
+	assert.Equal(t, "/test/monorepo/team-a", m.GetSelectedProjectRoot())
+
+	m.navigator.MoveDown(m.navState, 0)
+	m.navigator.PropagateSelection(m.navState)
+	assert.Equal(t, "/test/monorepo/team-b", m.GetSelectedProjectRoot())
+
+	plain := NewModel(&stack.Node{
+		Name:     "root",
+		Path:     "/test/root",
+		Children: []*stack.Node{{Name: "env", Path: "/test/root/env"}},
+	}, 1, []string{"plan"}, 3)
+	assert.Empty(t, plain.GetSelectedProjectRoot(), "tree without project level has no selected project")
+}
+
+// TestModel_LastArgs_SelectedProject tests that the extra args input is pre-filled from the
+// history of the selected project only.
+func TestModel_LastArgs_SelectedProject(t *testing.T) {
+	root := &stack.Node{
+		Name: "monorepo",
+		Path: "/test/monorepo",
+		Children: []*stack.Node{
+			{Name: "team-a", Path: "/test/monorepo/team-a", IsProject: true, Depth: 1, Children: []*stack.Node{
+				{Name: "vpc", Path: "/test/monorepo/team-a/vpc", IsStack: true, Depth: 2},
+			}},
+			{Name: "team-b", Path: "/test/monorepo/team-b", IsProject: true, Depth: 1, Children: []*stack.Node{
+				{Name: "rds", Path: "/test/monorepo/team-b/rds", IsStack: true, Depth: 2},
+			}},
+		},
+	}
+	lastArgs := map[string]map[string][]string{
+		"":                      {"plan": {"-var-file=b.tfvars"}},
+		"/test/monorepo/team-a": {"plan": {"-var-file=a.tfvars"}},
+		"/test/monorepo/team-b": {},
+	}
+	openArgs := func(m Model) string {
+		updated, _ := m.openArgsInput()
+		return updated.(Model).argsInput.Value()
+	}
+
+	m := NewModel(root, 2, []string{"plan"}, 3).WithLastArgs(lastArgs)
+	assert.Equal(t, "-var-file=a.tfvars", openArgs(m))
+
+	m.navigator.MoveDown(m.navState, 0)
+	m.navigator.PropagateSelection(m.navState)
+	assert.Empty(t, openArgs(m), "args run in another project are not offered")
+}
+
 // TestModel_GetSelectedCommand tests command retrieval.
 func TestModel_GetSelectedCommand(t *testing.T) {
 	commands := []string{"plan", "apply", "destroy"}
@@ -582,3 +648,85 @@ func TestModel_ClearSelectedPaths(t *testing.T) {
 	m.clearSelectedPaths()
 	assert.Empty(t, m.selectedPaths)
 }
+
+func TestModel_StackCommandAllowlist(t *testing.T) {
+	tmpDir := t.TempDir()
+	restricted := filepath.Join(tmpDir, "prod")
+	unrestricted := filepath.Join(tmpDir, "dev")
+	require.NoError(t, os.MkdirAll(restricted, 0755))
+	require.NoError(t, os.MkdirAll(unrestricted, 0755))
+	content := "allowed_commands:\n  - validate\n  - plan\n"
+	require.NoError(t, os.WriteFile(filepath.Join(restricted, stack.SettingsFileName), []byte(content), 0644))
+
+	root := &stack.Node{
+		Name: "root",
+		Path: tmpDir,
+		Children: []*stack.Node{
+			{Name: "dev", Path: unrestricted, IsStack: true, Depth: 1},
+			{Name: "prod", Path: restricted, IsStack: true, Depth: 1},
+		},
+	}
+	m := NewModel(root, 1, testCommands, 3)
+	m.selectedCommand = 1 // apply
+
+	// The commands column itself always lists every command.
+	assert.Equal(t, testCommands, m.getFilteredCommands())
+
+	// A stack without the file uses the global list.
+	m.focusedColumn = 1
+	assert.Equal(t, testCommands, m.getFilteredCommands())
+
+	// A stack with an allowlist keeps the configured order of the allowed commands.
+	m = m.handleVerticalMove(false)
+	require.Equal(t, restricted, m.GetSelectedStackPath())
+	assert.Equal(t, []string{"plan", "validate"}, m.getFilteredCommands())
+	assert.Contains(t, m.stackAllowlists, restricted, "allowlist should be cached")
+
+	// Confirming a command outside the allowlist is refused.
+	updated, cmd := m.confirmSelection()
+	result := updated.(Model)
+	assert.Nil(t, cmd)
+	assert.False(t, result.IsConfirmed())
+	assert.Contains(t, result.notice, "apply")
+
+	m.selectedCommand = 0 // plan
+	updated, _ = m.confirmSelection()
+	assert.True(t, updated.(Model).IsConfirmed())
+}
+
+func TestModel_CommandStackTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+	terragruntStack := filepath.Join(tmpDir, "live")
+	terraformStack := filepath.Join(tmpDir, "modules")
+	require.NoError(t, os.MkdirAll(terragruntStack, 0755))
+	require.NoError(t, os.MkdirAll(terraformStack, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(terragruntStack, "terragrunt.hcl"), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(terraformStack, "main.tf"), []byte(""), 0644))
+
+	root := &stack.Node{
+		Name: "root",
+		Path: tmpDir,
+		Children: []*stack.Node{
+			{Name: "live", Path: terragruntStack, IsStack: true, Depth: 1},
+			{Name: "modules", Path: terraformStack, Depth: 1},
+		},
+	}
+	commands := []string{"plan", "run-all", "validate"}
+	m := NewModel(root, 1, commands, 3).
+		WithCommandStackTypes(map[string][]string{"run-all": {stack.TypeTerragrunt}})
+	m.focusedColumn = 1
+
+	// A Terragrunt stack offers the Terragrunt-only command.
+	require.Equal(t, terragruntStack, m.GetSelectedStackPath())
+	assert.Equal(t, commands, m.getFilteredCommands())
+
+	// A plain Terraform directory hides it; unmapped commands stay.
+	m = m.handleVerticalMove(false)
+	require.Equal(t, terraformStack, m.GetSelectedStackPath())
+	assert.Equal(t, []string{"plan", "validate"}, m.getFilteredCommands())
+	assert.Equal(t, stack.TypeTerraform, m.stackTypes[terraformStack], "detected type should be cached")
+
+	// Without a mapping nothing is filtered.
+	m = m.WithCommandStackTypes(nil)
+	assert.Equal(t, commands, m.getFilteredCommands())
+}
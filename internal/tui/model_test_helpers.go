@@ -38,6 +38,7 @@ func NewTestModel(stackRoot *stack.Node, maxDepth int, commands []string, maxNav
 		height:               30,
 		columnWidth:          25,
 		selectedPaths:        make(map[string]bool),
+		theme:                DefaultTheme(),
 	}
 
 	// Initialize navigation state.
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// Config holds the settings for running the TUI through Run. Settings are taken as given,
+// so zero values mean what they mean in the configuration file: a ColumnGap of 0 places
+// columns side by side; set it to config.DefaultColumnGap (or -1) for the usual spacing.
+type Config struct {
+	MaxDepth int        // Deepest level of the stack tree (as returned with the tree)
+	Settings config.TUI // Commands, columns and display settings; no commands or columns use the defaults
+}
+
+// Selection is the outcome of a TUI session.
+type Selection struct {
+	Confirmed  bool     // False when the user quit without confirming
+	Command    string   // Selected command
+	StackPath  string   // Path of the focused stack (or the root from the commands column)
+	StackPaths []string // Paths to run against: the marked stacks, or StackPath alone
+	Args       []string // Extra args of the chosen favorite preset, then those typed for the command
+}
+
+// Runner runs a TUI program and returns the final model.
+type Runner func(initialModel Model) (Model, error)
+
+// currentRunner holds the runner used by Run (can be overridden in tests).
+var currentRunner Runner = RunProgram
+
+// setRunner allows tests to inject a custom runner.
+// Returns a cleanup function to restore the original runner.
+func setRunner(runner Runner) func() {
+	original := currentRunner
+	currentRunner = runner
+	return func() {
+		currentRunner = original
+	}
+}
+
+// Run shows the navigation TUI for root and returns what the user selected.
+// It never exits the process; a cancelled session returns a Selection with Confirmed unset.
+func Run(root *stack.Node, cfg Config) (Selection, error) {
+	settings := cfg.Settings
+	settings.Normalize()
+
+	initialModel := NewModel(root, cfg.MaxDepth, settings.Commands, settings.MaxNavigationColumns).WithConfig(settings)
+	model, err := currentRunner(initialModel)
+	if err != nil {
+		return Selection{}, err
+	}
+	return model.Selection(), nil
+}
+
+// ProgramOptions returns the Bubble Tea options that set up the screen for model: the
+// alternate screen buffer unless it was disabled with WithAltScreen. Bubble Tea restores
+// the terminal when the program exits, fails or panics.
+func (m Model) ProgramOptions() []tea.ProgramOption {
+	if m.noAltScreen {
+		return nil
+	}
+	return []tea.ProgramOption{tea.WithAltScreen()}
+}
+
+// RunProgram runs a Bubble Tea program for model with the standard options
+// and returns the final model.
+func RunProgram(model Model) (Model, error) {
+	p := tea.NewProgram(
+		model,
+		append(model.ProgramOptions(), tea.WithMouseCellMotion())...,
+	)
+
+	finalModel, err := p.Run()
+	if err != nil {
+		return Model{}, err
+	}
+
+	resultModel, ok := finalModel.(Model)
+	if !ok {
+		return Model{}, fmt.Errorf("unexpected model type")
+	}
+
+	return resultModel, nil
+}
+
+// WithConfig returns a copy of the model with the TUI settings in cfg applied.
+// The locale falls back to LANG when cfg does not set one.
+func (m Model) WithConfig(cfg config.TUI) Model {
+	return m.
+		WithCommands(cfg.Commands).
+		WithMaxNavigationColumns(cfg.MaxNavigationColumns).
+		WithDangerousCommands(cfg.DangerousCommands).
+		WithCommandIcons(cfg.CommandIcons, cfg.Emoji).
+		WithCommandDescriptions(cfg.CommandDescriptions).
+		WithCommandAliases(cfg.CommandAliases).
+		WithCommandGroups(commandGroupsFromConfig(cfg.CommandGroups)).
+		WithColumnWidth(cfg.ColumnWidth).
+		WithColumnGap(cfg.ColumnGap).
+		WithColumnSeparator(cfg.ColumnSeparator).
+		WithRightArrowConfirm(cfg.RightArrowConfirm).
+		WithCyclicNavigation(cfg.CyclicNavigation).
+		WithBreadcrumbCommand(cfg.BreadcrumbCommand).
+		WithConfirmSummary(cfg.ConfirmSummary).
+		WithRequireConfirmation(cfg.RequireConfirmation).
+		WithFavorites(cfg.Favorites).
+		WithCommandStackTypes(cfg.CommandStackTypes).
+		WithEnterOnParentStack(cfg.EnterOnParentStack).
+		WithAltScreen(!cfg.DisableAltScreen).
+		WithFuzzyFilter(cfg.FuzzyFilter).
+		WithHighlightMatches(cfg.HighlightMatches).
+		WithTypeToFilter(cfg.TypeToFilter).
+		WithFilterScope(cfg.FilterScope).
+		WithIdleTimeout(cfg.IdleTimeout).
+		WithPreviewLines(cfg.PreviewLines).
+		WithSkipSingleCommand(cfg.SkipSingleCommand).
+		WithKeyBindings(cfg.KeyBindings).
+		WithTheme(ThemeFromConfig(cfg.Theme)).
+		WithLocale(ResolveLocale(cfg.Locale, os.Getenv("LANG"))).
+		WithMessages(Messages{
+			Initializing:   cfg.Messages.Initializing,
+			ScanningStacks: cfg.Messages.ScanningStacks,
+		})
+}
+
+// commandGroupsFromConfig converts the configured command groups (nil when none).
+func commandGroupsFromConfig(groups []config.TUICommandGroup) []CommandGroup {
+	var converted []CommandGroup
+	for _, group := range groups {
+		converted = append(converted, CommandGroup{Name: group.Name, Commands: group.Commands})
+	}
+	return converted
+}
+
+// Selection returns the outcome of the session represented by the model.
+func (m Model) Selection() Selection {
+	if !m.IsConfirmed() {
+		return Selection{}
+	}
+
+	sel := m.pendingSelection()
+	sel.Confirmed = true
+	return sel
+}
+
+// pendingSelection returns what confirming the session now would select, leaving
+// Confirmed unset.
+func (m Model) pendingSelection() Selection {
+	stackPath := m.GetSelectedStackPath()
+	stackPaths := []string{stackPath}
+	if m.HasSelectedPaths() {
+		stackPaths = m.GetSelectedStackPaths()
+	}
+
+	return Selection{
+		Command:    m.GetSelectedCommand(),
+		StackPath:  stackPath,
+		StackPaths: stackPaths,
+		Args:       append(slices.Clone(m.GetSelectedArgs()), m.GetExtraArgs()...),
+	}
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/stack"
+)
+
+func runTestTree() *stack.Node {
+	return &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+			{Name: "rds", Path: "/root/rds", IsStack: true, Depth: 1},
+		},
+	}
+}
+
+func TestRun_Confirmed(t *testing.T) {
+	var initial Model
+	defer setRunner(func(m Model) (Model, error) {
+		initial = m
+		updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+		updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRight})
+		m = updated.(Model)
+		updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+		return updated.(Model), nil
+	})()
+
+	sel, err := Run(runTestTree(), Config{
+		MaxDepth: 1,
+		Settings: config.TUI{Commands: []string{"plan", "apply"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plan", "apply"}, initial.GetCommands())
+	assert.Equal(t, config.DefaultMaxNavigationColumns, initial.GetMaxNavigationColumns())
+	assert.Equal(t, 0, initial.columnGap, "a zero-value gap means no gap")
+	assert.Equal(t, Selection{
+		Confirmed:  true,
+		Command:    "apply",
+		StackPath:  "/root/vpc",
+		StackPaths: []string{"/root/vpc"},
+	}, sel)
+}
+
+func TestRun_Cancelled(t *testing.T) {
+	defer setRunner(func(m Model) (Model, error) {
+		updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		return updated.(Model), nil
+	})()
+
+	sel, err := Run(runTestTree(), Config{MaxDepth: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, Selection{}, sel)
+}
+
+func TestRun_RunnerError(t *testing.T) {
+	defer setRunner(func(m Model) (Model, error) {
+		return Model{}, errors.New("no terminal")
+	})()
+
+	_, err := Run(runTestTree(), Config{MaxDepth: 1})
+
+	assert.EqualError(t, err, "no terminal")
+}
+
+func TestModel_Selection_MarkedStacks(t *testing.T) {
+	m := NewModel(runTestTree(), 1, testCommands, 3)
+	m.focusedColumn = 1
+	m = m.handleSpaceKey()
+	m = m.handleVerticalMove(false)
+	m = m.handleSpaceKey()
+	m.confirmed = true
+
+	sel := m.Selection()
+
+	assert.True(t, sel.Confirmed)
+	assert.Equal(t, "plan", sel.Command)
+	assert.Equal(t, "/root/rds", sel.StackPath)
+	assert.ElementsMatch(t, []string{"/root/vpc", "/root/rds"}, sel.StackPaths)
+}
+
+// hasProgramOption reports whether opts contains an option built by the same constructor as want.
+func hasProgramOption(opts []tea.ProgramOption, want tea.ProgramOption) bool {
+	for _, opt := range opts {
+		if reflect.ValueOf(opt).Pointer() == reflect.ValueOf(want).Pointer() {
+			return true
+		}
+	}
+	return false
+}
+
+func TestProgramOptions_AltScreen(t *testing.T) {
+	m := NewModel(runTestTree(), 1, []string{"plan"}, 3)
+	assert.True(t, hasProgramOption(m.ProgramOptions(), tea.WithAltScreen()), "alternate screen is on by default")
+
+	disabled := m.WithAltScreen(false)
+	assert.False(t, hasProgramOption(disabled.ProgramOptions(), tea.WithAltScreen()))
+	assert.True(t, hasProgramOption(disabled.WithAltScreen(true).ProgramOptions(), tea.WithAltScreen()))
+
+	fromConfig := m.WithConfig(config.TUI{DisableAltScreen: true})
+	assert.Empty(t, fromConfig.ProgramOptions())
+	assert.True(t, hasProgramOption(NewHistoryModel(nil).ProgramOptions(), tea.WithAltScreen()))
+}
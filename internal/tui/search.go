@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// IsSearching reports whether the tree search overlay has focus.
+func (m Model) IsSearching() bool {
+	return m.searching
+}
+
+// openSearch shows the tree search overlay with an empty query, which lists every stack.
+func (m Model) openSearch() (tea.Model, tea.Cmd) {
+	ti := textinput.New()
+	ti.Placeholder = SearchPlaceholder
+	ti.Focus()
+
+	m.searchInput = ti
+	m.searchCursor = 0
+	m.searching = true
+	return m, textinput.Blink
+}
+
+// handleSearchInput handles keys while the tree search overlay has focus: up/down move
+// through the matches, enter jumps to the selected one and esc closes the overlay.
+func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.searching = false
+		return m, nil
+	case tea.KeyUp:
+		m.searchCursor = max(m.searchCursor-1, 0)
+		return m, nil
+	case tea.KeyDown:
+		m.searchCursor = min(m.searchCursor+1, max(len(m.searchMatches())-1, 0))
+		return m, nil
+	case tea.KeyEnter:
+		matches := m.searchMatches()
+		if len(matches) == 0 {
+			return m, nil
+		}
+		m.searching = false
+		return m.jumpToPath(m.searchRoot() + matches[m.searchCursor]), nil
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	m.searchCursor = 0
+	return m, cmd
+}
+
+// searchRoot returns the prefix stripped from stack paths listed in the search overlay.
+func (m Model) searchRoot() string {
+	return m.navigator.GetRoot().Path + "/"
+}
+
+// searchMatches returns the paths, relative to the tree root, of the stacks matching the
+// search query with the model's filter mode. An empty query matches every stack.
+func (m Model) searchMatches() []string {
+	if m.navigator == nil || m.navigator.GetRoot() == nil {
+		return nil
+	}
+
+	root := m.searchRoot()
+	var paths []string
+	for _, path := range m.navigator.StackPaths() {
+		paths = append(paths, strings.TrimPrefix(path, root))
+	}
+	return m.applyFilter(paths, m.searchInput.Value())
+}
+
+// jumpToPath selects the node at path in every navigation column and focuses its column.
+// Navigation filters (and a global filter) are cleared so the selection cannot be hidden by
+// them. Paths outside the navigable tree leave the model unchanged.
+func (m Model) jumpToPath(path string) Model {
+	chain := m.navigator.FindPath(path)
+	if chain == nil || !m.navigator.SelectPath(m.navState, path) {
+		return m
+	}
+
+	for columnID := range m.columnFilters {
+		if columnID > 0 || m.globalFilter {
+			delete(m.columnFilters, columnID)
+		}
+	}
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+
+	for depth, index := range m.navState.SelectedIndices {
+		maxVisibleItems := m.getMaxVisibleItems(depth + 1)
+		m.scrollOffsets[depth+1] = (index / maxVisibleItems) * maxVisibleItems
+	}
+	return m.focusDepth(len(chain) - 1)
+}
+
+// renderSearchOverlay renders the search query and the matching stacks in place of the
+// columns, scrolled so the selected match is visible.
+func (r *Renderer) renderSearchOverlay() string {
+	m := r.model
+	height := max(r.layout.GetContentHeight()-2, 0) // Inside the panel border.
+	width := max(m.width-2, 0)
+
+	lines := []string{m.searchInput.View(), ""}
+	matches := m.searchMatches()
+	if len(matches) == 0 {
+		lines = append(lines, previewNoteStyle.Render(m.Text(MsgSearchNoMatches)))
+	}
+
+	visible := max(height-len(lines), 1)
+	start := max(m.searchCursor-visible+1, 0)
+	maxTextWidth := max(width-CursorWidth-ItemStylePadding-ColumnStylePadding, MinItemTextWidth)
+	for i := start; i < len(matches) && i < start+visible; i++ {
+		cursor := " "
+		isSelected := i == m.searchCursor
+		if isSelected {
+			cursor = "►"
+		}
+		style := m.theme.listItemStyle(isSelected, false)
+
+		text := truncateText(matches[i], maxTextWidth)
+		rendered := style.Render(text)
+		if m.highlightMatches {
+			if mask := matchMask(matches[i], m.searchInput.Value(), m.fuzzyFilter); mask != nil {
+				rendered = renderHighlightedText(style, matches[i], text, mask)
+			}
+		}
+		lines = append(lines, cursor+" "+rendered)
+	}
+
+	return searchOverlayStyle.
+		Width(width).
+		Height(height).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
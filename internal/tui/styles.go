@@ -10,6 +10,10 @@ var (
 	accentColor    = lipgloss.Color("#FF6B9D")
 	textColor      = lipgloss.Color("#FFFFFF")
 	dimColor       = lipgloss.Color("#888888")
+	dangerColor    = lipgloss.Color("#FF4444")
+
+	// Default background for striped history rows; subtle enough to keep text readable.
+	historyStripeColor = lipgloss.Color("#262626")
 
 	// Column styles
 	focusedBorder = lipgloss.RoundedBorder()
@@ -22,6 +26,12 @@ var (
 			Padding(0, 1).
 			Align(lipgloss.Center)
 
+	// Banner shown in the history view when the last run was in another project
+	projectSwitchStyle = lipgloss.NewStyle().
+				Foreground(dimColor).
+				Padding(0, 1).
+				Italic(true)
+
 	// Footer style
 	footerStyle = lipgloss.NewStyle().
 			Foreground(dimColor).
@@ -45,6 +55,17 @@ var (
 				Foreground(accentColor).
 				Padding(0, 1)
 
+	// Dangerous command styles (e.g. apply/destroy), shown in a warning color.
+	dangerousItemStyle = lipgloss.NewStyle().
+				Foreground(dangerColor).
+				Padding(0, 1)
+
+	selectedDangerousItemStyle = lipgloss.NewStyle().
+					Bold(true).
+					Underline(true).
+					Foreground(dangerColor).
+					Padding(0, 1)
+
 	// Arrow indicator style
 	arrowStyle = lipgloss.NewStyle().
 			Bold(true).
@@ -59,6 +80,11 @@ var (
 				Padding(0, 2).
 				Margin(0, 0)
 
+	// Level selected in breadcrumb mode, on the breadcrumb bar's background.
+	breadcrumbSelectedStyle = lipgloss.NewStyle().
+				Bold(true).
+				Reverse(true)
+
 	// Page indicator styles
 	pageIndicatorStyle = lipgloss.NewStyle().
 				Foreground(dimColor).
@@ -69,6 +95,15 @@ var (
 					Bold(true).
 					Padding(0, 1)
 
+	// Description shown beside a command in the commands column.
+	commandDescriptionStyle = lipgloss.NewStyle().Foreground(dimColor)
+
+	// Header above each group of commands when command groups are configured.
+	commandGroupStyle = lipgloss.NewStyle().Foreground(dimColor).Bold(true)
+
+	// Divider between stacks and plain directories when stack grouping is enabled.
+	dividerStyle = lipgloss.NewStyle().Foreground(dimColor)
+
 	// Marker styles for multi-stack selection.
 	markedStyle   = lipgloss.NewStyle().Foreground(accentColor).Bold(true)
 	unmarkedStyle = lipgloss.NewStyle().Foreground(dimColor)
@@ -77,4 +112,27 @@ var (
 	depthDotVisibleStyle     = lipgloss.NewStyle().Foreground(secondaryColor).Bold(true)
 	depthDotReachableStyle   = lipgloss.NewStyle().Foreground(dimColor)
 	depthDotUnreachableStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#3A3A3A"))
+
+	// Preview pane showing the focused stack's terragrunt.hcl, and its syntax highlighting.
+	previewPaneStyle = lipgloss.NewStyle().
+				Border(focusedBorder).
+				BorderForeground(dimColor).
+				Padding(0, 1)
+	previewNoteStyle    = lipgloss.NewStyle().Foreground(dimColor).Italic(true)
+	hclCommentStyle     = lipgloss.NewStyle().Foreground(dimColor).Italic(true)
+	hclStringStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("#A6E22E"))
+	hclIdentifierStyle  = lipgloss.NewStyle().Foreground(secondaryColor)
+	hclPunctuationStyle = lipgloss.NewStyle().Foreground(accentColor)
+
+	// Tree search overlay panel listing the stacks that match the query.
+	searchOverlayStyle = lipgloss.NewStyle().
+				Border(focusedBorder).
+				BorderForeground(accentColor).
+				Padding(0, 1)
+
+	// Debug overlay panel showing the internal navigation state.
+	debugOverlayStyle = lipgloss.NewStyle().
+				Border(focusedBorder).
+				BorderForeground(dimColor).
+				Padding(0, 1)
 )
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/israoo/terrax/internal/config"
+)
+
+// Theme holds the colors of the TUI's semantic roles.
+type Theme struct {
+	FocusedBorder lipgloss.Color // Border of the focused column
+	SelectedItem  lipgloss.Color // Selected list item and history cursor row
+	StackMarker   lipgloss.Color // Stacks listed in the navigation columns
+	Success       lipgloss.Color // Success icons and resources to add
+	Error         lipgloss.Color // Failure icons and resources to destroy
+	Header        lipgloss.Color // Background of the header bar
+}
+
+// DefaultTheme returns the built-in theme, designed for dark terminals.
+func DefaultTheme() Theme {
+	return Theme{
+		FocusedBorder: primaryColor,
+		SelectedItem:  accentColor,
+		StackMarker:   textColor,
+		Success:       lipgloss.Color("#00FF00"),
+		Error:         lipgloss.Color("#FF0000"),
+		Header:        primaryColor,
+	}
+}
+
+// HighContrastTheme returns a theme of bright, saturated colors that stay legible for
+// people with low vision or color vision deficiencies.
+func HighContrastTheme() Theme {
+	return Theme{
+		FocusedBorder: lipgloss.Color("#FFFF00"),
+		SelectedItem:  lipgloss.Color("#00FFFF"),
+		StackMarker:   lipgloss.Color("#FFFFFF"),
+		Success:       lipgloss.Color("#00FF00"),
+		Error:         lipgloss.Color("#FF5555"),
+		Header:        lipgloss.Color("#0000AA"),
+	}
+}
+
+// ThemeFromConfig returns the theme described by cfg: its preset, ThemeDefault when the
+// preset is unknown, with the colors cfg sets replacing the preset's.
+func ThemeFromConfig(cfg config.TUITheme) Theme {
+	theme := DefaultTheme()
+	if cfg.Preset == ThemeHighContrast {
+		theme = HighContrastTheme()
+	}
+
+	override := func(color *lipgloss.Color, value string) {
+		if value != "" {
+			*color = lipgloss.Color(value)
+		}
+	}
+	override(&theme.FocusedBorder, cfg.FocusedBorder)
+	override(&theme.SelectedItem, cfg.SelectedItem)
+	override(&theme.StackMarker, cfg.StackMarker)
+	override(&theme.Success, cfg.Success)
+	override(&theme.Error, cfg.Error)
+	override(&theme.Header, cfg.Header)
+	return theme
+}
+
+// WithTheme returns a copy of the model rendered with the colors of theme.
+func (m Model) WithTheme(theme Theme) Model {
+	m.theme = theme
+	return m
+}
+
+// header returns the style of the header bar.
+func (t Theme) header() lipgloss.Style {
+	return headerStyle.Background(t.Header)
+}
+
+// listItemStyle returns the style for a list item. Dangerous items keep their warning
+// color whether or not they are selected, so the cursor never hides the warning.
+func (t Theme) listItemStyle(isSelected, isDangerous bool) lipgloss.Style {
+	switch {
+	case isDangerous && isSelected:
+		return selectedDangerousItemStyle
+	case isDangerous:
+		return dangerousItemStyle
+	case isSelected:
+		return selectedItemStyle.Foreground(t.SelectedItem)
+	default:
+		return itemStyle
+	}
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// TestThemeFromConfig tests that the preset picks the base theme and that set colors
+// replace the preset's.
+func TestThemeFromConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.TUITheme
+		expected Theme
+	}{
+		{name: "empty uses the default theme", cfg: config.TUITheme{}, expected: DefaultTheme()},
+		{name: "default preset", cfg: config.TUITheme{Preset: ThemeDefault}, expected: DefaultTheme()},
+		{name: "unknown preset falls back to the default", cfg: config.TUITheme{Preset: "solarized"}, expected: DefaultTheme()},
+		{name: "high-contrast preset", cfg: config.TUITheme{Preset: ThemeHighContrast}, expected: HighContrastTheme()},
+		{
+			name: "overrides replace the preset's colors",
+			cfg: config.TUITheme{
+				Preset:        ThemeHighContrast,
+				FocusedBorder: "#111111",
+				SelectedItem:  "#222222",
+				StackMarker:   "#333333",
+				Success:       "#444444",
+				Error:         "#555555",
+				Header:        "21",
+			},
+			expected: Theme{
+				FocusedBorder: "#111111",
+				SelectedItem:  "#222222",
+				StackMarker:   "#333333",
+				Success:       "#444444",
+				Error:         "#555555",
+				Header:        "21",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ThemeFromConfig(tt.cfg))
+		})
+	}
+}
+
+// TestTheme_Styles tests that the colors of a custom theme are reflected in the styles the
+// renderer produces.
+func TestTheme_Styles(t *testing.T) {
+	theme := ThemeFromConfig(config.TUITheme{
+		FocusedBorder: "#111111",
+		SelectedItem:  "#222222",
+		StackMarker:   "#333333",
+		Success:       "#444444",
+		Error:         "#555555",
+		Header:        "#666666",
+	})
+
+	t.Run("focused column border", func(t *testing.T) {
+		assert.Equal(t, lipgloss.Color("#111111"), columnStyle(true, theme).GetBorderTopForeground())
+	})
+
+	t.Run("selected item", func(t *testing.T) {
+		assert.Equal(t, lipgloss.Color("#222222"), theme.listItemStyle(true, false).GetForeground())
+		assert.Equal(t, dangerColor, theme.listItemStyle(true, true).GetForeground(), "dangerous items keep the warning color")
+	})
+
+	t.Run("header", func(t *testing.T) {
+		assert.Equal(t, lipgloss.Color("#666666"), theme.header().GetBackground())
+	})
+
+	t.Run("history table", func(t *testing.T) {
+		styles := newHistoryTableStyles(HistoryTableStyle{}, theme)
+		assert.Equal(t, lipgloss.Color("#444444"), styles.successIcon.GetForeground())
+		assert.Equal(t, lipgloss.Color("#555555"), styles.errorIcon.GetForeground())
+		assert.Equal(t, lipgloss.Color("#222222"), styles.cursor.GetForeground())
+
+		styles = newHistoryTableStyles(HistoryTableStyle{CursorForeground: "#777777"}, theme)
+		assert.Equal(t, lipgloss.Color("#777777"), styles.cursor.GetForeground(), "history.table colors win over the theme")
+	})
+
+	t.Run("stack items", func(t *testing.T) {
+		original := lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.ANSI256)
+		t.Cleanup(func() { lipgloss.SetColorProfile(original) })
+
+		items := []string{"modules", "app" + stack.StackMarker}
+		rendered := renderItemList(theme, itemList{items: items, end: 2, selected: -1, lineCount: 2, maxTextWidth: 40, totalPages: 1, currentPage: 1})
+		stackStyle := itemStyle.Foreground(theme.StackMarker)
+		assert.Contains(t, rendered, stackStyle.Render("app"+stack.StackMarker))
+		assert.Contains(t, rendered, itemStyle.Render("modules"), "plain directories keep the item color")
+	})
+
+	t.Run("model", func(t *testing.T) {
+		m := NewModel(&stack.Node{Name: "root"}, 1, testCommands, 3)
+		assert.Equal(t, DefaultTheme(), m.theme)
+		assert.Equal(t, theme, m.WithTheme(theme).theme)
+		configured := m.WithConfig(config.TUI{Theme: config.TUITheme{Preset: ThemeHighContrast}})
+		assert.Equal(t, HighContrastTheme(), configured.theme)
+	})
+}
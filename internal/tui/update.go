@@ -1,8 +1,15 @@
 package tui
 
 import (
+	"fmt"
+	"slices"
+	"time"
+	"unicode"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/israoo/terrax/internal/bounds"
+	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/stack"
 )
 
@@ -11,13 +18,263 @@ import (
 func (m Model) handleNavigationUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		updated, cmd := m.handleKeyPress(msg)
+		if next, ok := updated.(Model); ok && next.lastCommands != nil {
+			return next.selectRememberedCommand(), cmd
+		}
+		return updated, cmd
 	case tea.WindowSizeMsg:
 		return m.handleWindowResize(msg), nil
+	case tea.MouseMsg:
+		updated, cmd := m.handleMouse(msg)
+		if next, ok := updated.(Model); ok && next.lastCommands != nil {
+			return next.selectRememberedCommand(), cmd
+		}
+		return updated, cmd
 	}
 	return m, nil
 }
 
+// handleMouse handles clicks and the scroll wheel. A click on a level name in the breadcrumb
+// bar focuses that level; a click in a column focuses it and selects the item under the
+// pointer, and a click on the selected item of the focused column acts like enter. The
+// wheel moves the selection of the focused column. Other mouse events are ignored.
+func (m Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		if m.searching || m.editingArgs {
+			return m, nil
+		}
+		return m.handleVerticalMove(msg.Button == tea.MouseButtonWheelUp), nil
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	if msg.Y == HeaderHeight {
+		for _, segment := range m.breadcrumbSegments() {
+			if msg.X >= segment.start && msg.X < segment.end {
+				return m.focusDepth(segment.depth), nil
+			}
+		}
+		return m, nil
+	}
+
+	if m.searching || m.debugOverlay || m.editingArgs || m.navigator == nil {
+		return m, nil
+	}
+	columnID, ok := m.columnAt(msg.X)
+	if !ok {
+		return m, nil
+	}
+	row := msg.Y - HeaderHeight - BreadcrumbLineCount - DepthIndicatorLineCount - ColumnItemsOffset
+	return m.clickColumn(columnID, m.itemAtRow(columnID, row))
+}
+
+// columnAt returns the ID of the column rendered at screen column x, laid out like
+// renderColumnsWithArrows: the commands column, then the visible navigation columns with
+// the separator between them, each taking its width plus its margins.
+func (m Model) columnAt(x int) (int, bool) {
+	marginLeft, marginRight := m.columnMargins()
+	right := 0
+	if !m.commandsHidden() {
+		width := m.columnWidth
+		if m.commandsCollapsed {
+			width = CollapsedBarWidth
+		}
+		right += marginLeft + width + marginRight
+		if x < right {
+			return 0, true
+		}
+	}
+
+	startDepth := m.navigationOffset
+	endDepth := min(startDepth+m.maxNavigationColumns, m.navigator.GetMaxDepth())
+	for depth := startDepth; depth < endDepth; depth++ {
+		if len(m.navState.Columns[depth]) == 0 {
+			break
+		}
+		if depth > startDepth {
+			right += m.separatorWidth()
+		}
+		right += marginLeft + m.columnWidth + marginRight
+		if x < right {
+			return depth + 1, true
+		}
+	}
+	return 0, false
+}
+
+// itemAtRow returns the index in the filtered list of the column of the item rendered on
+// row, counted from the column's first item row, or -1 when no item is there. It follows
+// renderItemList: the visible page of items, with the stack group divider and command group
+// headers taking a row.
+func (m Model) itemAtRow(columnID, row int) int {
+	if row < 0 {
+		return -1
+	}
+
+	var items []string
+	var headers map[int]string
+	dividerIndex := -1
+	if columnID == 0 {
+		if m.commandsCollapsed {
+			// The collapsed bar shows only the selected command.
+			if row == 0 {
+				return max(findFilteredIndex(m.commands, m.getFilteredCommands(), m.selectedCommand), 0)
+			}
+			return -1
+		}
+		items = m.getFilteredCommands()
+		headers = m.commandGroupHeaders(items)
+	} else {
+		depth := columnID - 1
+		items = m.getFilteredNavigationItems(depth)
+		dividerIndex = m.stackGroupDivider(depth, m.navState.Columns[depth], items)
+	}
+
+	startIdx, endIdx := calculatePaginatedRange(m.scrollOffsets[columnID], m.getMaxVisibleItems(columnID), len(items))
+	line := 0
+	for i := startIdx; i < endIdx; i++ {
+		if i == dividerIndex && i > startIdx {
+			line++
+		}
+		if _, ok := headers[i]; ok {
+			line++
+		}
+		if line == row {
+			return i
+		}
+		line++
+	}
+	return -1
+}
+
+// clickColumn focuses the column and selects the item at index in its filtered list
+// (-1 = none). Clicking the selected item of the focused column acts like enter.
+func (m Model) clickColumn(columnID, index int) (tea.Model, tea.Cmd) {
+	wasFocused := m.focusedColumn == columnID
+	if columnID == 0 {
+		m = m.focusCommands()
+	} else {
+		m = m.focusDepth(columnID - 1)
+	}
+	if index < 0 {
+		return m, nil
+	}
+
+	if columnID == 0 {
+		original := findOriginalIndex(m.commands, m.getFilteredCommands(), index)
+		if original < 0 {
+			return m, nil
+		}
+		if wasFocused && original == m.selectedCommand {
+			return m.handleEnterKey()
+		}
+		m.selectedCommand = original
+		return m, nil
+	}
+
+	depth := columnID - 1
+	original := findOriginalIndex(m.navState.Columns[depth], m.getFilteredNavigationItems(depth), index)
+	if original < 0 {
+		return m, nil
+	}
+	if wasFocused && original == m.navState.SelectedIndices[depth] {
+		return m.handleEnterKey()
+	}
+	m.navState.SelectedIndices[depth] = original
+	m.navigator.PropagateSelection(m.navState)
+	return m, nil
+}
+
+// focusCommands moves focus to the commands column, leaving any filter being edited.
+func (m Model) focusCommands() Model {
+	m = m.leaveFilterEditing()
+	previousColumn := m.focusedColumn
+	m.focusedColumn = 0
+	if m.globalFilter {
+		m.carryFilter(previousColumn)
+	}
+	return m
+}
+
+// leaveFilterEditing blurs the filter being edited, if any.
+func (m Model) leaveFilterEditing() Model {
+	if m.activeFilterColumn >= 0 {
+		if filter, exists := m.columnFilters[m.activeFilterColumn]; exists {
+			filter.Blur()
+			m.columnFilters[m.activeFilterColumn] = filter
+		}
+		m.activeFilterColumn = -1
+	}
+	return m
+}
+
+// focusDepth moves focus to the navigation column at depth, sliding the window so it is
+// visible and leaving any filter being edited.
+func (m Model) focusDepth(depth int) Model {
+	m = m.leaveFilterEditing()
+	previousColumn := m.focusedColumn
+	m.focusedColumn = depth + 1
+	if m.globalFilter {
+		m.carryFilter(previousColumn)
+	}
+	if depth < m.navigationOffset {
+		m.navigationOffset = depth
+	} else if depth > m.navigationOffset+m.maxNavigationColumns-1 {
+		m.navigationOffset = depth - m.maxNavigationColumns + 1
+	}
+	return m
+}
+
+// carryFilter moves the filter of column from to the focused column, so with a global filter
+// scope the same text narrows whichever column has focus. The selection of the focused
+// column moves to a match when the filter hides it.
+func (m *Model) carryFilter(from int) {
+	filter, exists := m.columnFilters[from]
+	if !exists || from == m.focusedColumn {
+		return
+	}
+	delete(m.columnFilters, from)
+	m.columnFilters[m.focusedColumn] = filter
+
+	active := m.activeFilterColumn
+	m.activeFilterColumn = m.focusedColumn
+	m.adjustSelectionAfterFilter()
+	m.activeFilterColumn = active
+}
+
+// selectRememberedCommand pre-selects the command last run against the focused stack when
+// focus has moved to a different stack. Stacks without history select the default command.
+// Commands chosen by hand stay selected until another stack gains focus.
+func (m Model) selectRememberedCommand() Model {
+	if m.confirmed || m.isCommandsColumnFocused() {
+		return m
+	}
+	path := m.GetSelectedStackPath()
+	if path == NoItemSelected || path == m.rememberedFor {
+		return m
+	}
+	m.rememberedFor = path
+
+	m.selectedCommand = FirstItemIndex
+	if command, ok := m.lastCommands[path]; ok {
+		if idx := m.commandIndex(command); idx >= 0 {
+			m.selectedCommand = idx
+		}
+	}
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+	maxVisibleItems := m.getMaxVisibleItems(0)
+	m.scrollOffsets[0] = (m.selectedCommand / maxVisibleItems) * maxVisibleItems
+	return m
+}
+
 // handleWindowResize processes window resize events.
 func (m Model) handleWindowResize(msg tea.WindowSizeMsg) Model {
 	m.width = msg.Width
@@ -29,6 +286,98 @@ func (m Model) handleWindowResize(msg tea.WindowSizeMsg) Model {
 	return m
 }
 
+// handleColumnResize widens (delta > 0) or narrows (delta < 0) all columns.
+// The chosen width is kept as an override so it survives later window resizes;
+// item truncation follows automatically because it is derived from columnWidth.
+func (m Model) handleColumnResize(delta int) Model {
+	if m.navigator == nil {
+		return m
+	}
+	current := m.columnWidth
+	if current == 0 {
+		current = m.calculateColumnWidth()
+	}
+	m.columnWidthOverride = m.clampColumnWidth(current + delta)
+	m.columnWidth = m.columnWidthOverride
+	return m
+}
+
+// handleCommandsCollapse toggles the commands column between the full list and a
+// compact bar showing only the selected command, giving navigation columns more room.
+func (m Model) handleCommandsCollapse() Model {
+	m.commandsCollapsed = !m.commandsCollapsed
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// handlePreviewToggle shows or hides the terragrunt.hcl preview pane, resizing the columns
+// to make room for it.
+func (m Model) handlePreviewToggle() Model {
+	m.previewPane = !m.previewPane
+	if m.ready && m.navigator != nil {
+		m.columnWidth = m.calculateColumnWidth()
+	}
+	return m
+}
+
+// favoriteKeys are the function keys mapped to the favorites, in order.
+var favoriteKeys = []tea.KeyType{tea.KeyF1, tea.KeyF2, tea.KeyF3, tea.KeyF4, tea.KeyF5, tea.KeyF6, tea.KeyF7, tea.KeyF8, tea.KeyF9}
+
+// handleFavoriteKey runs the preset at index against the focused stack (or the root from
+// the commands column), bypassing the commands column. Unmapped keys do nothing.
+func (m Model) handleFavoriteKey(index int) (tea.Model, tea.Cmd) {
+	if !bounds.InRange(index, len(m.favorites)) {
+		return m, nil
+	}
+
+	favorite := m.favorites[index]
+	m.favorite = &favorite
+	updated, cmd := m.confirmSelection()
+	result := updated.(Model)
+	if !result.confirmed && result.state != StateConfirm {
+		// Not confirmed (e.g. refused by the stack's allowlist); back to the commands column.
+		result.favorite = nil
+	}
+	return result, cmd
+}
+
+// handleRecenter scrolls the focused column so the selection sits in the middle of the
+// visible window, like vim's zz. The window is clamped to stay within the list.
+func (m Model) handleRecenter() Model {
+	columnID, index, total := m.focusedListPosition()
+	if index < 0 {
+		return m
+	}
+
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
+	offset := min(index-maxVisibleItems/2, total-maxVisibleItems)
+	m.scrollOffsets[columnID] = max(offset, 0)
+	return m
+}
+
+// focusedListPosition returns the ID of the focused column, the position of its selection
+// in the filtered list and the length of that list. The position is -1 when nothing is selected.
+func (m *Model) focusedListPosition() (columnID, index, total int) {
+	if m.isCommandsColumnFocused() {
+		filteredCommands := m.getFilteredCommands()
+		return 0, findFilteredIndex(m.commands, filteredCommands, m.selectedCommand), len(filteredCommands)
+	}
+
+	depth := m.getNavigationDepth()
+	if m.navState == nil || !bounds.InRange(depth, len(m.navState.Columns)) {
+		return depth + 1, -1, 0
+	}
+	filteredItems := m.getFilteredNavigationItems(depth)
+	index = findFilteredIndex(m.navState.Columns[depth], filteredItems, m.navState.SelectedIndices[depth])
+	return depth + 1, index, len(filteredItems)
+}
+
 // handleHistoryUpdate handles updates when in StateHistory mode.
 func (m Model) handleHistoryUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -39,29 +388,51 @@ func (m Model) handleHistoryUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.historyFiltering {
+			return m.handleHistoryFilterInput(msg)
+		}
+
 		switch msg.Type {
 		case tea.KeyEsc:
+			// Esc first clears the active filters, then exits
+			if m.historyFiltered() {
+				m.historyFailedOnly = false
+				return m.clearHistoryFilter(), nil
+			}
 			return m, tea.Quit
 
 		case tea.KeyRunes:
-			if msg.String() == KeyQ {
+			switch msg.String() {
+			case KeyQ:
 				return m, tea.Quit
+			case KeyGroup:
+				return m.setHistoryGrouping(!m.historyGrouped), nil
+			case KeySlash:
+				return m.openHistoryFilter()
+			case KeyFailures:
+				return m.setHistoryFailedOnly(!m.historyFailedOnly), nil
 			}
 
+		case tea.KeyRight, tea.KeyLeft:
+			if m.historyGrouped {
+				m = m.setHistoryGroupExpanded(msg.Type == tea.KeyRight)
+			}
+			return m, nil
+
 		case tea.KeyUp:
-			if len(m.history) > 0 {
+			if total := m.historyRowCount(); total > 0 {
 				m.historyCursor--
 				if m.historyCursor < 0 {
 					// Cyclic wrap to last item
-					m.historyCursor = len(m.history) - 1
+					m.historyCursor = total - 1
 				}
 			}
 			return m, nil
 
 		case tea.KeyDown:
-			if len(m.history) > 0 {
+			if total := m.historyRowCount(); total > 0 {
 				m.historyCursor++
-				if m.historyCursor >= len(m.history) {
+				if m.historyCursor >= total {
 					// Cyclic wrap to first item
 					m.historyCursor = 0
 				}
@@ -69,7 +440,7 @@ func (m Model) handleHistoryUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case tea.KeyPgUp, tea.KeyPgDown:
-			if len(m.history) > 0 {
+			if total := m.historyRowCount(); total > 0 {
 				// Calculate content height matches view_history.go
 				// contentHeight := m.height - HeaderHeight - FooterHeight - 6
 				const historyFrameOverhead = 8 // 1+1+6
@@ -79,23 +450,17 @@ func (m Model) handleHistoryUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 
 				if msg.Type == tea.KeyPgDown {
-					m.historyCursor += visibleHeight
-					if m.historyCursor >= len(m.history) {
-						m.historyCursor = len(m.history) - 1
-					}
+					m.historyCursor = bounds.ClampIndex(m.historyCursor+visibleHeight, total)
 				} else {
-					m.historyCursor -= visibleHeight
-					if m.historyCursor < 0 {
-						m.historyCursor = 0
-					}
+					m.historyCursor = bounds.ClampIndex(m.historyCursor-visibleHeight, total)
 				}
 			}
 			return m, nil
 
 		case tea.KeyEnter:
-			// Re-execute the selected history entry
-			if len(m.history) > 0 && m.historyCursor >= 0 && m.historyCursor < len(m.history) {
-				m.selectedHistoryEntry = &m.history[m.historyCursor]
+			// Re-execute the selected history entry (a group row re-executes its latest run)
+			if entry := m.historyEntryAtCursor(); entry != nil {
+				m.selectedHistoryEntry = entry
 				m.reExecuteFromHistory = true
 			}
 			return m, tea.Quit
@@ -107,27 +472,45 @@ func (m Model) handleHistoryUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress processes keyboard input.
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editingArgs {
+		return m.handleArgsInput(msg)
+	}
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+	if m.breadcrumbMode {
+		return m.handleBreadcrumbInput(msg)
+	}
+
 	// Handle filter input editing mode
 	if m.activeFilterColumn >= 0 {
-		switch msg.String() {
-		case KeyEsc:
+		// Keys that type text go to the filter even when bound to an action.
+		action := ""
+		if msg.Type != tea.KeyRunes {
+			action = m.keyActions[msg.String()]
+		}
+		switch {
+		case msg.String() == KeyEsc:
 			// Exit filter input mode and remove the filter completely
 			delete(m.columnFilters, m.activeFilterColumn)
 			m.activeFilterColumn = -1
 			return m, nil
-		case KeyEnter:
+		case msg.String() == KeyAltEnter:
+			// Confirm even when enter would drill into a parent stack
+			return m.confirmSelection()
+		case action == config.ActionConfirm:
 			// Execute command with current selection
 			return m.handleEnterKey()
-		case KeyUp:
+		case action == config.ActionUp:
 			// Allow navigation while filtering
 			return m.handleVerticalMove(true), nil
-		case KeyDown:
+		case action == config.ActionDown:
 			// Allow navigation while filtering
 			return m.handleVerticalMove(false), nil
-		case KeyLeft:
+		case action == config.ActionLeft:
 			// Allow navigation while filtering
 			return m.handleHorizontalMove(true)
-		case KeyRight:
+		case action == config.ActionRight:
 			// Allow navigation while filtering
 			return m.handleHorizontalMove(false)
 		default:
@@ -149,7 +532,51 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Normal navigation mode (always available).
+	m.notice = ""
+	if m.typeToFilter == TypeToFilterAll && isPrintableKey(msg) {
+		return m.startTypedFilter(string(msg.Runes))
+	}
+	if prefix := m.pendingKey; prefix != "" {
+		m.pendingKey = ""
+		if prefix == KeyDebugPrefix && msg.String() == KeyDebug {
+			m.debugOverlay = !m.debugOverlay
+			return m, nil
+		}
+		// Not the debug sequence: the deferred jump happens before the key is handled.
+		m = m.handleJump(false)
+	}
+	switch m.keyActions[msg.String()] {
+	case config.ActionQuit:
+		if m.HasSelectedPaths() {
+			m.clearSelectedPaths()
+			return m, nil
+		}
+		return m, tea.Quit
+	case config.ActionFilter:
+		return m.activateFilter()
+	case config.ActionConfirm:
+		return m.handleEnterKey()
+	case config.ActionUp:
+		return m.handleVerticalMove(true), nil
+	case config.ActionDown:
+		return m.handleVerticalMove(false), nil
+	case config.ActionLeft:
+		return m.handleHorizontalMove(true)
+	case config.ActionRight:
+		return m.handleHorizontalMove(false)
+	case config.ActionPageUp:
+		return m.handlePageMove(true), nil
+	case config.ActionPageDown:
+		return m.handlePageMove(false), nil
+	}
+
 	switch msg.Type {
+	case tea.KeyF1, tea.KeyF2, tea.KeyF3, tea.KeyF4, tea.KeyF5, tea.KeyF6, tea.KeyF7, tea.KeyF8, tea.KeyF9:
+		return m.handleFavoriteKey(slices.Index(favoriteKeys, msg.Type))
+	case tea.KeyCtrlR:
+		return m.handleConfigReload(), nil
+	case tea.KeyCtrlF:
+		return m.openSearch()
 	case tea.KeyCtrlC, tea.KeyEsc:
 		if msg.Type == tea.KeyEsc && m.HasSelectedPaths() {
 			m.clearSelectedPaths()
@@ -158,53 +585,172 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case tea.KeyRunes:
-		if msg.String() == KeyQ {
-			if m.HasSelectedPaths() {
-				m.clearSelectedPaths()
-				return m, nil
-			}
-			return m, tea.Quit
+		if msg.String() == KeyPlus {
+			return m.handleColumnResize(ColumnWidthStep), nil
 		}
-		if msg.String() == KeySlash {
-			// Activate filter for current focused column
-			columnID := m.focusedColumn
-			if _, exists := m.columnFilters[columnID]; !exists {
-				// Create new filter for this column
-				ti := textinput.New()
-				ti.Placeholder = "Filter..."
-				ti.CharLimit = 50
-				ti.Width = 20
-				m.columnFilters[columnID] = ti
-			}
-			filter := m.columnFilters[columnID]
-			filter.Focus()
-			m.columnFilters[columnID] = filter
-			m.activeFilterColumn = columnID
-			return m, textinput.Blink
+		if msg.String() == KeyMinus {
+			return m.handleColumnResize(-ColumnWidthStep), nil
+		}
+		if msg.String() == KeyCollapse {
+			return m.handleCommandsCollapse(), nil
+		}
+		if msg.String() == KeyRecenter {
+			return m.handleRecenter(), nil
+		}
+		if msg.String() == KeyPreview {
+			return m.handlePreviewToggle(), nil
+		}
+		if msg.String() == KeyArgs {
+			return m.openArgsInput()
+		}
+		if msg.String() == KeyCopy {
+			return m.handleCopyPath(), nil
+		}
+		if msg.String() == KeyStacksOnly {
+			return m.handleStacksOnlyToggle(), nil
+		}
+		if msg.String() == KeyBreadcrumb {
+			return m.openBreadcrumbMode(), nil
+		}
+		switch msg.String() {
+		case KeyVimFirst:
+			// Also the first key of the debug overlay sequence (KeyDebugPrefix), so the jump
+			// waits until the next key or the sequence timeout shows it is not g d.
+			return m.startKeySequence(msg.String())
+		case KeyVimLast:
+			return m.handleJump(true), nil
+		case KeyVimUp:
+			return m.handleVerticalMove(true), nil
+		case KeyVimDown:
+			return m.handleVerticalMove(false), nil
+		case KeyVimLeft:
+			return m.handleHorizontalMove(true)
+		case KeyVimRight:
+			return m.handleHorizontalMove(false)
+		}
+		if msg.String() == KeyRoot && m.isCommandsColumnFocused() && m.includeRoot != "" {
+			// Toggle the commands column target between the tree root and the include root.
+			m.targetIncludeRoot = !m.targetIncludeRoot
+			return m, nil
+		}
+		if m.typeToFilter == TypeToFilterUnbound && isPrintableKey(msg) {
+			return m.startTypedFilter(string(msg.Runes))
 		}
 
 	case tea.KeyEnter:
-		return m.handleEnterKey()
+		if msg.Alt {
+			return m.confirmSelection()
+		}
 	case tea.KeySpace:
 		return m.handleSpaceKey(), nil
-	case tea.KeyUp:
-		return m.handleVerticalMove(true), nil
-	case tea.KeyDown:
-		return m.handleVerticalMove(false), nil
-	case tea.KeyLeft:
-		return m.handleHorizontalMove(true)
-	case tea.KeyRight:
-		return m.handleHorizontalMove(false)
-	case tea.KeyPgUp:
-		return m.handlePageMove(true), nil
-	case tea.KeyPgDown:
-		return m.handlePageMove(false), nil
+	case tea.KeyHome:
+		return m.handleJump(false), nil
+	case tea.KeyEnd:
+		return m.handleJump(true), nil
 	}
 	return m, nil
 }
 
+// isPrintableKey reports whether msg types text, as opposed to a control or alt key.
+func isPrintableKey(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes || msg.Alt || len(msg.Runes) == 0 {
+		return false
+	}
+	for _, r := range msg.Runes {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// startTypedFilter opens the focused column's filter with text as its value.
+func (m Model) startTypedFilter(text string) (tea.Model, tea.Cmd) {
+	updated, cmd := m.activateFilter()
+	m = updated.(Model)
+
+	filter := m.columnFilters[m.activeFilterColumn]
+	filter.SetValue(text)
+	filter.CursorEnd()
+	m.columnFilters[m.activeFilterColumn] = filter
+	m.adjustSelectionAfterFilter()
+	return m, cmd
+}
+
+// activateFilter starts editing the filter of the focused column, creating it if needed.
+func (m Model) activateFilter() (tea.Model, tea.Cmd) {
+	columnID := m.focusedColumn
+	if _, exists := m.columnFilters[columnID]; !exists {
+		// Create new filter for this column
+		ti := textinput.New()
+		ti.Placeholder = "Filter..."
+		ti.CharLimit = 50
+		ti.Width = 20
+		m.columnFilters[columnID] = ti
+	}
+	filter := m.columnFilters[columnID]
+	filter.Focus()
+	m.columnFilters[columnID] = filter
+	m.activeFilterColumn = columnID
+	return m, textinput.Blink
+}
+
+// handleStacksOnlyToggle shows only the stacks of each navigation column, or every
+// directory again. Selections hidden by the toggle move to the first item shown, and each
+// navigation column scrolls to the page of its selection.
+func (m Model) handleStacksOnlyToggle() Model {
+	if m.navigator == nil {
+		return m
+	}
+	navigator := *m.navigator
+	navigator.SetStacksOnly(!navigator.IsStacksOnly())
+	m.navigator = &navigator
+	m.navigator.PropagateSelection(m.navState)
+
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+	for depth := range m.navState.Columns {
+		items := m.getFilteredNavigationItems(depth)
+		index := max(findFilteredIndex(m.navState.Columns[depth], items, m.navState.SelectedIndices[depth]), 0)
+		maxVisibleItems := m.getMaxVisibleItems(depth + 1)
+		m.scrollOffsets[depth+1] = (index / maxVisibleItems) * maxVisibleItems
+	}
+
+	if m.navigator.IsStacksOnly() {
+		m.notice = m.Text(MsgStacksOnly)
+	} else {
+		m.notice = m.Text(MsgAllDirectories)
+	}
+	return m
+}
+
+// handleConfigReload re-reads the configuration through the configured reloader.
+// On failure the current settings are kept and the error is shown in the footer.
+func (m Model) handleConfigReload() Model {
+	if m.configReloader == nil {
+		return m
+	}
+	reloaded, err := m.configReloader(m)
+	if err != nil {
+		m.notice = fmt.Sprintf(m.Text(MsgConfigReloadFailed), err)
+		return m
+	}
+	reloaded.notice = reloaded.Text(MsgConfigReloaded)
+	return reloaded
+}
+
 // handleEnterKey processes the enter key with dual behavior.
+// In drill mode, enter on a stack that has children moves into them instead of confirming.
 func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
+	if m.enterDrills && m.isOnParentStack() {
+		return m.handleHorizontalMove(false)
+	}
+	return m.confirmSelection()
+}
+
+// confirmSelection confirms the focused node (or the root from the commands column) and quits.
+func (m Model) confirmSelection() (tea.Model, tea.Cmd) {
 	var targetNode *stack.Node
 
 	if m.isCommandsColumnFocused() {
@@ -216,16 +762,35 @@ func (m Model) handleEnterKey() (tea.Model, tea.Cmd) {
 		targetNode = m.navigator.GetNodeAtDepth(m.navState, depth)
 	}
 
-	if targetNode != nil {
-		m.confirmed = true
-		return m, tea.Quit
+	if targetNode == nil {
+		return m, nil
 	}
 
-	return m, nil
+	// A stack's allowlist also applies to a command picked before focusing it.
+	if allowlist := m.focusedStackAllowlist(); allowlist != nil && !slices.Contains(allowlist, m.GetSelectedCommand()) {
+		m.notice = fmt.Sprintf(m.Text(MsgCommandNotAllowed), m.GetSelectedCommand())
+		return m, nil
+	}
+
+	if m.confirmSummary || slices.Contains(m.requireConfirmation, m.GetSelectedCommand()) {
+		return m.enterConfirmState(), nil
+	}
+
+	m.confirmed = true
+	return m, tea.Quit
 }
 
 // handleVerticalMove processes up/down navigation.
 func (m Model) handleVerticalMove(isUp bool) Model {
+	// A re-centered window is not page-aligned; snap back to the selection's page so
+	// page-based movement keeps the selection visible.
+	if columnID, index, _ := m.focusedListPosition(); index >= 0 {
+		maxVisibleItems := m.getMaxVisibleItems(columnID)
+		if m.scrollOffsets[columnID]%maxVisibleItems != 0 {
+			m.scrollOffsets[columnID] = (index / maxVisibleItems) * maxVisibleItems
+		}
+	}
+
 	if m.isCommandsColumnFocused() {
 		m.moveCommandSelection(isUp)
 	} else {
@@ -235,7 +800,12 @@ func (m Model) handleVerticalMove(isUp bool) Model {
 }
 
 // handleHorizontalMove processes left/right column switching.
+// With right-arrow confirm enabled, right on a leaf stack confirms instead of wrapping.
 func (m Model) handleHorizontalMove(isLeft bool) (tea.Model, tea.Cmd) {
+	if !isLeft && m.rightConfirms && m.isOnLeafStack() {
+		return m.handleEnterKey()
+	}
+
 	// If we're editing a filter, blur it when moving to another column
 	if m.activeFilterColumn >= 0 {
 		if filter, exists := m.columnFilters[m.activeFilterColumn]; exists {
@@ -245,11 +815,15 @@ func (m Model) handleHorizontalMove(isLeft bool) (tea.Model, tea.Cmd) {
 		m.activeFilterColumn = -1
 	}
 
+	previousColumn := m.focusedColumn
 	if isLeft {
 		m.moveToPreviousColumn()
 	} else {
 		m.moveToNextColumn()
 	}
+	if m.globalFilter {
+		m.carryFilter(previousColumn)
+	}
 
 	// After moving to a new column, check if that column has a filter
 	// If it does, automatically activate it for editing
@@ -281,8 +855,8 @@ func (m *Model) moveCommandSelection(isUp bool) {
 		hasFilter = true
 	}
 
-	maxVisibleItems := m.getMaxVisibleItems()
-	totalPages := m.getTotalPages(len(filteredCommands))
+	maxVisibleItems := m.getMaxVisibleItems(0)
+	totalPages := m.getTotalPages(0, len(filteredCommands))
 	currentPage := m.getCurrentPage(0) // columnID = 0 for commands
 
 	if !hasFilter {
@@ -290,38 +864,38 @@ func (m *Model) moveCommandSelection(isUp bool) {
 		if isUp {
 			if m.selectedCommand > 0 {
 				// Check if we're at the first item of current page
-				pageStart := m.getPageStartIndex(currentPage)
+				pageStart := m.getPageStartIndex(0, currentPage)
 				if m.selectedCommand == pageStart && currentPage > 1 {
 					// Jump to last item of previous page
 					prevPage := currentPage - 1
-					prevPageStart := m.getPageStartIndex(prevPage)
+					prevPageStart := m.getPageStartIndex(0, prevPage)
 					prevPageEnd := min(prevPageStart+maxVisibleItems-1, len(m.commands)-1)
 					m.selectedCommand = prevPageEnd
 					m.scrollOffsets[0] = prevPageStart
 				} else {
 					m.selectedCommand--
 				}
-			} else {
+			} else if m.navigator.IsCyclic() {
 				// Wrap to bottom (last item of last page)
 				m.selectedCommand = len(m.commands) - 1
-				lastPage := m.getTotalPages(len(m.commands))
-				m.scrollOffsets[0] = m.getPageStartIndex(lastPage)
+				lastPage := m.getTotalPages(0, len(m.commands))
+				m.scrollOffsets[0] = m.getPageStartIndex(0, lastPage)
 			}
 		} else {
 			if m.selectedCommand < len(m.commands)-1 {
 				// Check if we're at the last item of current page
-				pageStart := m.getPageStartIndex(currentPage)
+				pageStart := m.getPageStartIndex(0, currentPage)
 				pageEnd := min(pageStart+maxVisibleItems-1, len(m.commands)-1)
 				if m.selectedCommand == pageEnd && currentPage < totalPages {
 					// Jump to first item of next page
 					nextPage := currentPage + 1
-					nextPageStart := m.getPageStartIndex(nextPage)
+					nextPageStart := m.getPageStartIndex(0, nextPage)
 					m.selectedCommand = nextPageStart
 					m.scrollOffsets[0] = nextPageStart
 				} else {
 					m.selectedCommand++
 				}
-			} else {
+			} else if m.navigator.IsCyclic() {
 				// Wrap to top (first item of first page)
 				m.selectedCommand = 0
 				m.scrollOffsets[0] = 0
@@ -343,38 +917,38 @@ func (m *Model) moveCommandSelection(isUp bool) {
 	if isUp {
 		if filteredIndex > 0 {
 			// Check if we're at the first item of current page
-			pageStart := m.getPageStartIndex(currentPage)
+			pageStart := m.getPageStartIndex(0, currentPage)
 			if filteredIndex == pageStart && currentPage > 1 {
 				// Jump to last item of previous page
 				prevPage := currentPage - 1
-				prevPageStart := m.getPageStartIndex(prevPage)
+				prevPageStart := m.getPageStartIndex(0, prevPage)
 				prevPageEnd := min(prevPageStart+maxVisibleItems-1, len(filteredCommands)-1)
 				filteredIndex = prevPageEnd
 				m.scrollOffsets[0] = prevPageStart
 			} else {
 				filteredIndex--
 			}
-		} else {
+		} else if m.navigator.IsCyclic() {
 			// Wrap to bottom
 			filteredIndex = len(filteredCommands) - 1
-			lastPage := m.getTotalPages(len(filteredCommands))
-			m.scrollOffsets[0] = m.getPageStartIndex(lastPage)
+			lastPage := m.getTotalPages(0, len(filteredCommands))
+			m.scrollOffsets[0] = m.getPageStartIndex(0, lastPage)
 		}
 	} else {
 		if filteredIndex < len(filteredCommands)-1 {
 			// Check if we're at the last item of current page
-			pageStart := m.getPageStartIndex(currentPage)
+			pageStart := m.getPageStartIndex(0, currentPage)
 			pageEnd := min(pageStart+maxVisibleItems-1, len(filteredCommands)-1)
 			if filteredIndex == pageEnd && currentPage < totalPages {
 				// Jump to first item of next page
 				nextPage := currentPage + 1
-				nextPageStart := m.getPageStartIndex(nextPage)
+				nextPageStart := m.getPageStartIndex(0, nextPage)
 				filteredIndex = nextPageStart
 				m.scrollOffsets[0] = nextPageStart
 			} else {
 				filteredIndex++
 			}
-		} else {
+		} else if m.navigator.IsCyclic() {
 			// Wrap to top
 			filteredIndex = 0
 			m.scrollOffsets[0] = 0
@@ -407,13 +981,10 @@ func (m *Model) moveNavigationSelection(isUp bool) {
 
 	// Check if filter is active for this column
 	columnID := depth + 1
-	hasFilter := false
-	if filter, exists := m.columnFilters[columnID]; exists && filter.Value() != "" {
-		hasFilter = true
-	}
+	hasFilter := m.isNavigationColumnFiltered(depth)
 
-	maxVisibleItems := m.getMaxVisibleItems()
-	totalPages := m.getTotalPages(len(originalItems))
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
+	totalPages := m.getTotalPages(columnID, len(originalItems))
 	currentPage := m.getCurrentPage(columnID)
 
 	if !hasFilter {
@@ -421,11 +992,11 @@ func (m *Model) moveNavigationSelection(isUp bool) {
 		if isUp {
 			if currentIndex > 0 {
 				// Check if we're at the first item of current page
-				pageStart := m.getPageStartIndex(currentPage)
+				pageStart := m.getPageStartIndex(columnID, currentPage)
 				if currentIndex == pageStart && currentPage > 1 {
 					// Jump to last item of previous page
 					prevPage := currentPage - 1
-					prevPageStart := m.getPageStartIndex(prevPage)
+					prevPageStart := m.getPageStartIndex(columnID, prevPage)
 					prevPageEnd := min(prevPageStart+maxVisibleItems-1, len(originalItems)-1)
 					m.navState.SelectedIndices[depth] = prevPageEnd
 					m.scrollOffsets[columnID] = prevPageStart
@@ -433,29 +1004,29 @@ func (m *Model) moveNavigationSelection(isUp bool) {
 					m.navState.SelectedIndices[depth]--
 				}
 				m.navigator.PropagateSelection(m.navState)
-			} else {
+			} else if m.navigator.IsCyclic() {
 				// Wrap to bottom (last item of last page)
 				m.navState.SelectedIndices[depth] = len(originalItems) - 1
-				lastPage := m.getTotalPages(len(originalItems))
-				m.scrollOffsets[columnID] = m.getPageStartIndex(lastPage)
+				lastPage := m.getTotalPages(columnID, len(originalItems))
+				m.scrollOffsets[columnID] = m.getPageStartIndex(columnID, lastPage)
 				m.navigator.PropagateSelection(m.navState)
 			}
 		} else {
 			if currentIndex < len(originalItems)-1 {
 				// Check if we're at the last item of current page
-				pageStart := m.getPageStartIndex(currentPage)
+				pageStart := m.getPageStartIndex(columnID, currentPage)
 				pageEnd := min(pageStart+maxVisibleItems-1, len(originalItems)-1)
 				if currentIndex == pageEnd && currentPage < totalPages {
 					// Jump to first item of next page
 					nextPage := currentPage + 1
-					nextPageStart := m.getPageStartIndex(nextPage)
+					nextPageStart := m.getPageStartIndex(columnID, nextPage)
 					m.navState.SelectedIndices[depth] = nextPageStart
 					m.scrollOffsets[columnID] = nextPageStart
 				} else {
 					m.navState.SelectedIndices[depth]++
 				}
 				m.navigator.PropagateSelection(m.navState)
-			} else {
+			} else if m.navigator.IsCyclic() {
 				// Wrap to top (first item of first page)
 				m.navState.SelectedIndices[depth] = 0
 				m.scrollOffsets[columnID] = 0
@@ -478,45 +1049,45 @@ func (m *Model) moveNavigationSelection(isUp bool) {
 		return
 	}
 
-	totalPagesFiltered := m.getTotalPages(len(filteredItems))
+	totalPagesFiltered := m.getTotalPages(columnID, len(filteredItems))
 	currentPageFiltered := m.getCurrentPage(columnID)
 
 	// Move within filtered list with page-based navigation
 	if isUp {
 		if filteredIndex > 0 {
 			// Check if we're at the first item of current page
-			pageStart := m.getPageStartIndex(currentPageFiltered)
+			pageStart := m.getPageStartIndex(columnID, currentPageFiltered)
 			if filteredIndex == pageStart && currentPageFiltered > 1 {
 				// Jump to last item of previous page
 				prevPage := currentPageFiltered - 1
-				prevPageStart := m.getPageStartIndex(prevPage)
+				prevPageStart := m.getPageStartIndex(columnID, prevPage)
 				prevPageEnd := min(prevPageStart+maxVisibleItems-1, len(filteredItems)-1)
 				filteredIndex = prevPageEnd
 				m.scrollOffsets[columnID] = prevPageStart
 			} else {
 				filteredIndex--
 			}
-		} else {
+		} else if m.navigator.IsCyclic() {
 			// Wrap to bottom
 			filteredIndex = len(filteredItems) - 1
-			lastPage := m.getTotalPages(len(filteredItems))
-			m.scrollOffsets[columnID] = m.getPageStartIndex(lastPage)
+			lastPage := m.getTotalPages(columnID, len(filteredItems))
+			m.scrollOffsets[columnID] = m.getPageStartIndex(columnID, lastPage)
 		}
 	} else {
 		if filteredIndex < len(filteredItems)-1 {
 			// Check if we're at the last item of current page
-			pageStart := m.getPageStartIndex(currentPageFiltered)
+			pageStart := m.getPageStartIndex(columnID, currentPageFiltered)
 			pageEnd := min(pageStart+maxVisibleItems-1, len(filteredItems)-1)
 			if filteredIndex == pageEnd && currentPageFiltered < totalPagesFiltered {
 				// Jump to first item of next page
 				nextPage := currentPageFiltered + 1
-				nextPageStart := m.getPageStartIndex(nextPage)
+				nextPageStart := m.getPageStartIndex(columnID, nextPage)
 				filteredIndex = nextPageStart
 				m.scrollOffsets[columnID] = nextPageStart
 			} else {
 				filteredIndex++
 			}
-		} else {
+		} else if m.navigator.IsCyclic() {
 			// Wrap to top
 			filteredIndex = 0
 			m.scrollOffsets[columnID] = 0
@@ -533,7 +1104,7 @@ func (m *Model) moveNavigationSelection(isUp bool) {
 
 // moveToPreviousColumn moves focus to the previous column with sliding window.
 func (m *Model) moveToPreviousColumn() {
-	if m.focusedColumn > 0 {
+	if m.focusedColumn > 0 && !(m.focusedColumn == 1 && m.commandsHidden()) {
 		// Move focus left
 		m.focusedColumn--
 
@@ -577,8 +1148,11 @@ func (m *Model) moveToNextColumn() {
 			}
 		}
 	} else {
-		// Wrap to commands column
+		// Wrap to commands column (or the first navigation column when it is hidden)
 		m.focusedColumn = 0
+		if m.commandsHidden() {
+			m.focusedColumn = 1
+		}
 		m.navigationOffset = 0
 	}
 }
@@ -605,7 +1179,7 @@ func (m *Model) adjustSelectionAfterFilter() {
 	} else {
 		// Navigation column
 		depth := m.activeFilterColumn - 1
-		if depth < 0 || depth >= len(m.navState.Columns) {
+		if !bounds.InRange(depth, len(m.navState.Columns)) {
 			return
 		}
 
@@ -640,6 +1214,97 @@ func (m Model) handlePageMove(isUp bool) Model {
 	return m
 }
 
+// keySequenceTimeout is how long the first key of a two-key sequence waits for its second.
+const keySequenceTimeout = 500 * time.Millisecond
+
+// keySequenceTimeoutMsg is delivered when the sequence started as seq may have timed out.
+type keySequenceTimeoutMsg struct{ seq int }
+
+// startKeySequence records key as the pending first key of a two-key sequence and
+// schedules its timeout.
+func (m Model) startKeySequence(key string) (tea.Model, tea.Cmd) {
+	m.pendingKey = key
+	m.pendingKeySeq++
+	seq := m.pendingKeySeq
+	return m, tea.Tick(keySequenceTimeout, func(time.Time) tea.Msg {
+		return keySequenceTimeoutMsg{seq: seq}
+	})
+}
+
+// handleKeySequenceTimeout gives up on a pending sequence that got no second key in time,
+// performing the jump of its first key. Timeouts of earlier sequences are ignored.
+func (m Model) handleKeySequenceTimeout(msg keySequenceTimeoutMsg) (tea.Model, tea.Cmd) {
+	if m.pendingKey == "" || msg.seq != m.pendingKeySeq || m.state != StateNavigation {
+		return m, nil
+	}
+	m.pendingKey = ""
+	return m.handleJump(false), nil
+}
+
+// handleJump moves the focused column's selection to its first item, or its last when
+// toLast is set, among the filtered items when a filter is set.
+func (m Model) handleJump(toLast bool) Model {
+	if m.isCommandsColumnFocused() {
+		m.jumpCommandSelection(toLast)
+	} else {
+		m.jumpNavigationSelection(toLast)
+	}
+	return m
+}
+
+// jumpCommandSelection selects the first or last filtered command and scrolls to its page.
+func (m *Model) jumpCommandSelection(toLast bool) {
+	filteredCommands := m.getFilteredCommands()
+	if len(filteredCommands) == 0 {
+		return
+	}
+
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+
+	targetIdx := 0
+	if toLast {
+		targetIdx = len(filteredCommands) - 1
+	}
+
+	maxVisibleItems := m.getMaxVisibleItems(0)
+	m.selectedCommand = findOriginalIndex(m.commands, filteredCommands, targetIdx)
+	m.scrollOffsets[0] = (targetIdx / maxVisibleItems) * maxVisibleItems
+}
+
+// jumpNavigationSelection selects the first or last filtered item of the focused navigation
+// column and scrolls to its page.
+func (m *Model) jumpNavigationSelection(toLast bool) {
+	depth := m.getNavigationDepth()
+	if depth < 0 {
+		return
+	}
+
+	filteredItems := m.getFilteredNavigationItems(depth)
+	if len(filteredItems) == 0 {
+		return
+	}
+
+	if m.scrollOffsets == nil {
+		m.scrollOffsets = make(map[int]int)
+	}
+
+	targetIdx := 0
+	if toLast {
+		targetIdx = len(filteredItems) - 1
+	}
+
+	newOriginalIndex := findOriginalIndex(m.navState.Columns[depth], filteredItems, targetIdx)
+	if newOriginalIndex < 0 {
+		return
+	}
+	maxVisibleItems := m.getMaxVisibleItems(depth + 1)
+	m.navState.SelectedIndices[depth] = newOriginalIndex
+	m.navigator.PropagateSelection(m.navState)
+	m.scrollOffsets[depth+1] = (targetIdx / maxVisibleItems) * maxVisibleItems
+}
+
 // moveCommandSelectionPage moves selection in commands column by a full page.
 func (m *Model) moveCommandSelectionPage(isUp bool) {
 	filteredCommands := m.getFilteredCommands()
@@ -651,7 +1316,7 @@ func (m *Model) moveCommandSelectionPage(isUp bool) {
 		m.scrollOffsets = make(map[int]int)
 	}
 
-	maxVisibleItems := m.getMaxVisibleItems()
+	maxVisibleItems := m.getMaxVisibleItems(0)
 
 	// Check if filter is active
 	hasFilter := false
@@ -720,13 +1385,10 @@ func (m *Model) moveNavigationSelectionPage(isUp bool) {
 	currentIdxOriginal := m.navState.SelectedIndices[depth]
 	columnID := depth + 1
 
-	maxVisibleItems := m.getMaxVisibleItems()
+	maxVisibleItems := m.getMaxVisibleItems(columnID)
 
 	// Check filter
-	hasFilter := false
-	if filter, exists := m.columnFilters[columnID]; exists && filter.Value() != "" {
-		hasFilter = true
-	}
+	hasFilter := m.isNavigationColumnFiltered(depth)
 
 	var currentIdx, totalItems int
 	if hasFilter {
@@ -779,7 +1441,7 @@ func (m Model) handleSpaceKey() Model {
 		return m
 	}
 	depth := m.getNavigationDepth()
-	if depth < 0 || depth >= len(m.navState.SelectedIndices) {
+	if !bounds.InRange(depth, len(m.navState.SelectedIndices)) {
 		return m
 	}
 	index := m.navState.SelectedIndices[depth]
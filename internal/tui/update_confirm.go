@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// enterConfirmState switches to the confirmation summary for the current selection.
+func (m Model) enterConfirmState() Model {
+	sel := m.pendingSelection()
+	details := ExecutionDetails{
+		Command:    sel.Command,
+		StackPaths: sel.StackPaths,
+		ExtraArgs:  sel.Args,
+		Branch:     m.branch,
+	}
+	if m.previewer != nil {
+		details = m.previewer(details)
+	}
+
+	m.confirmDetails = details
+	m.awaitingYes = slices.Contains(m.requireConfirmation, sel.Command)
+	m.state = StateConfirm
+	return m
+}
+
+// handleConfirmUpdate handles updates when in StateConfirm mode.
+// Enter proceeds with the execution; esc or q returns to navigation without running it.
+// A command in requireConfirmation proceeds only with y, and n also returns to navigation.
+func (m Model) handleConfirmUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.state = StateNavigation
+		updated, cmd := m.handleNavigationUpdate(msg)
+		result := updated.(Model)
+		result.state = StateConfirm
+		return result, cmd
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEnter:
+			if m.awaitingYes {
+				return m, nil
+			}
+			m.confirmed = true
+			return m, tea.Quit
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+		case tea.KeyEsc:
+			return m.cancelConfirm(), nil
+		case tea.KeyRunes:
+			switch msg.String() {
+			case KeyQ:
+				return m.cancelConfirm(), nil
+			case KeyYes:
+				if m.awaitingYes {
+					m.confirmed = true
+					return m, tea.Quit
+				}
+			case KeyNo:
+				if m.awaitingYes {
+					return m.cancelConfirm(), nil
+				}
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// cancelConfirm leaves the confirmation summary without running the command.
+func (m Model) cancelConfirm() Model {
+	m.state = StateNavigation
+	m.confirmDetails = ExecutionDetails{}
+	m.awaitingYes = false
+	m.favorite = nil
+	m.notice = m.Text(MsgSelectionCancelled)
+	return m
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/israoo/terrax/internal/bounds"
+	"github.com/israoo/terrax/internal/history"
+)
+
+// historyRow is one line of the grouped history view: a stack's group row, or one of
+// the group's runs while it is expanded.
+type historyRow struct {
+	group int // Index into historyGroups
+	run   int // Index into the group's entries; -1 for the group row itself
+}
+
+// setHistoryGrouping switches the history view between one row per run and one row per
+// stack. The cursor returns to the top since rows no longer line up between the two.
+func (m Model) setHistoryGrouping(grouped bool) Model {
+	m.historyGrouped = grouped
+	m.historyGroups = nil
+	if grouped {
+		m.historyGroups = history.GroupByStack(m.history)
+	}
+	if m.historyExpanded == nil {
+		m.historyExpanded = make(map[string]bool)
+	}
+	m.historyCursor = 0
+	return m
+}
+
+// historyRows returns the visible rows of the grouped history view: every group row,
+// each followed by its runs when the group is expanded.
+func (m Model) historyRows() []historyRow {
+	var rows []historyRow
+	for i, group := range m.historyGroups {
+		rows = append(rows, historyRow{group: i, run: -1})
+		if !m.historyExpanded[group.StackPath] {
+			continue
+		}
+		for j := range group.Entries {
+			rows = append(rows, historyRow{group: i, run: j})
+		}
+	}
+	return rows
+}
+
+// historyRowCount returns the number of rows the cursor moves over in the history view.
+func (m Model) historyRowCount() int {
+	if m.historyGrouped {
+		return len(m.historyRows())
+	}
+	return len(m.history)
+}
+
+// historyEntryAtCursor returns the entry under the cursor, or nil when there is none.
+// A group row stands for its stack's latest run.
+func (m Model) historyEntryAtCursor() *history.ExecutionLogEntry {
+	if !m.historyGrouped {
+		if !bounds.InRange(m.historyCursor, len(m.history)) {
+			return nil
+		}
+		return &m.history[m.historyCursor]
+	}
+
+	rows := m.historyRows()
+	if !bounds.InRange(m.historyCursor, len(rows)) {
+		return nil
+	}
+	row := rows[m.historyCursor]
+	return &m.historyGroups[row.group].Entries[max(row.run, 0)]
+}
+
+// setHistoryGroupExpanded expands or collapses the group under the cursor. Collapsing
+// from one of its runs moves the cursor back to the group row.
+func (m Model) setHistoryGroupExpanded(expanded bool) Model {
+	rows := m.historyRows()
+	if !bounds.InRange(m.historyCursor, len(rows)) {
+		return m
+	}
+	row := rows[m.historyCursor]
+	m.historyExpanded[m.historyGroups[row.group].StackPath] = expanded
+	if !expanded {
+		m.historyCursor -= row.run + 1
+	}
+	return m
+}
+
+// openHistoryFilter focuses the history filter input, keeping any text already typed.
+func (m Model) openHistoryFilter() (tea.Model, tea.Cmd) {
+	if m.historyFilter.Value() == "" {
+		m.historyFilter = textinput.New()
+		m.historyFilter.Placeholder = HistoryFilterHint
+	}
+	m.historyFilter.Focus()
+	m.historyFiltering = true
+	return m, textinput.Blink
+}
+
+// handleHistoryFilterInput handles keys while the history filter input has focus: typing
+// narrows the rows, enter keeps the filter and returns to the table and esc clears it.
+func (m Model) handleHistoryFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		return m.clearHistoryFilter(), nil
+	case tea.KeyEnter:
+		m.historyFilter.Blur()
+		m.historyFiltering = false
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.historyFilter, cmd = m.historyFilter.Update(msg)
+	return m.applyHistoryFilter(), cmd
+}
+
+// clearHistoryFilter closes the history filter and clears its text, restoring the rows
+// hidden by it.
+func (m Model) clearHistoryFilter() Model {
+	m.historyFilter.Reset()
+	m.historyFilter.Blur()
+	m.historyFiltering = false
+	return m.applyHistoryFilter()
+}
+
+// setHistoryFailedOnly shows only failed entries, or every entry again, on top of the
+// filter text.
+func (m Model) setHistoryFailedOnly(failedOnly bool) Model {
+	m.historyFailedOnly = failedOnly
+	return m.applyHistoryFilter()
+}
+
+// historyFiltered reports whether the history view shows a filtered subset of the entries.
+func (m Model) historyFiltered() bool {
+	return m.historyFilter.Value() != "" || m.historyFailedOnly
+}
+
+// applyHistoryFilter rebuilds history from historyAll with the entries matching the filter
+// text (and failing, when only failures are shown), regrouping them when grouped. The
+// cursor returns to the top of the new rows.
+func (m Model) applyHistoryFilter() Model {
+	if !m.historyFiltered() {
+		m.history = m.historyAll
+		return m.setHistoryGrouping(m.historyGrouped)
+	}
+
+	query := strings.ToLower(strings.TrimSpace(m.historyFilter.Value()))
+	m.history = nil
+	for _, entry := range m.historyAll {
+		if m.historyFailedOnly && entry.ExitCode == 0 {
+			continue
+		}
+		if historyEntryMatches(entry, query) {
+			m.history = append(m.history, entry)
+		}
+	}
+	return m.setHistoryGrouping(m.historyGrouped)
+}
+
+// historyEntryMatches reports whether the lowercase query is part of the entry's command,
+// stack path or exit status ("success", "failed" or the exit code itself).
+func historyEntryMatches(entry history.ExecutionLogEntry, query string) bool {
+	status := "success"
+	if entry.ExitCode != 0 {
+		status = "failed"
+	}
+	for _, field := range []string{entry.Command, entry.StackPath, status, strconv.Itoa(entry.ExitCode)} {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/israoo/terrax/internal/bounds"
 	"github.com/israoo/terrax/internal/plan"
 )
 
@@ -60,18 +61,12 @@ func (m *Model) handlePlanListNavigation(msg tea.KeyMsg) {
 	case tea.KeyPgUp:
 		// Page up in list
 		pageSize := m.height - PlanContentFrame
-		m.planListCursor -= pageSize
-		if m.planListCursor < 0 {
-			m.planListCursor = 0
-		}
+		m.planListCursor = bounds.ClampIndex(m.planListCursor-pageSize, len(m.planFlatItems))
 		m.planDetailScrollOffset = 0
 	case tea.KeyPgDown:
 		// Page down in list
 		pageSize := m.height - PlanContentFrame
-		m.planListCursor += pageSize
-		if m.planListCursor >= len(m.planFlatItems) {
-			m.planListCursor = len(m.planFlatItems) - 1
-		}
+		m.planListCursor = bounds.ClampIndex(m.planListCursor+pageSize, len(m.planFlatItems))
 		m.planDetailScrollOffset = 0
 	}
 }
@@ -1,12 +1,17 @@
 package tui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/israoo/terrax/internal/config"
 	"github.com/israoo/terrax/internal/stack"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testCommands defines a standard list of commands for testing.
@@ -138,6 +143,99 @@ func TestModel_MoveCommandSelection(t *testing.T) {
 	}
 }
 
+// TestModel_JumpCommandSelection tests jumping to the first or last command.
+func TestModel_JumpCommandSelection(t *testing.T) {
+	tests := []struct {
+		name          string
+		initialIndex  int
+		toLast        bool
+		expectedIndex int
+		filter        string
+	}{
+		{name: "jump to last", initialIndex: 0, toLast: true, expectedIndex: len(testCommands) - 1},
+		{name: "jump to first", initialIndex: 5, toLast: false, expectedIndex: 0},
+		{name: "jump to last filtered", initialIndex: 0, toLast: true, expectedIndex: 4, filter: "i"}, // validate, init
+		{name: "jump to first filtered", initialIndex: 4, toLast: false, expectedIndex: 2, filter: "i"},
+		{name: "filtered list empty - selection stays", initialIndex: 3, toLast: true, expectedIndex: 3, filter: "nonexistent"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(nil, 0, testCommands, 3)
+			m.height = 12 // A few commands per page
+			m.selectedCommand = tt.initialIndex
+			if tt.filter != "" {
+				ti := textinput.New()
+				ti.SetValue(tt.filter)
+				m.columnFilters[0] = ti
+			}
+
+			m.jumpCommandSelection(tt.toLast)
+
+			assert.Equal(t, tt.expectedIndex, m.selectedCommand)
+			if tt.filter == "" {
+				pageSize := m.getMaxVisibleItems(0)
+				assert.Equal(t, (tt.expectedIndex/pageSize)*pageSize, m.scrollOffsets[0], "target is visible")
+			}
+		})
+	}
+}
+
+// TestModel_HandleKeyPress_Jump tests Home/End and g/G in a navigation column.
+func TestModel_HandleKeyPress_Jump(t *testing.T) {
+	root := &stack.Node{Name: "root", Path: "/test"}
+	for _, name := range []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta"} {
+		root.Children = append(root.Children, &stack.Node{Name: name, Path: "/test/" + name,
+			Children: []*stack.Node{{Name: name + "-app", Path: "/test/" + name + "/app"}}})
+	}
+
+	tests := []struct {
+		name          string
+		msg           tea.KeyMsg
+		initialIndex  int
+		filter        string
+		expectedIndex int
+	}{
+		{name: "end", msg: tea.KeyMsg{Type: tea.KeyEnd}, expectedIndex: 7},
+		{name: "G", msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyVimLast)}, expectedIndex: 7},
+		{name: "home", msg: tea.KeyMsg{Type: tea.KeyHome}, initialIndex: 5, expectedIndex: 0},
+		{name: "g", msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyVimFirst)}, initialIndex: 5, expectedIndex: 0},
+		{name: "end with filter", msg: tea.KeyMsg{Type: tea.KeyEnd}, filter: "eta", expectedIndex: 7}, // beta, zeta, eta, theta
+		{name: "home with filter", msg: tea.KeyMsg{Type: tea.KeyHome}, initialIndex: 7, filter: "eta", expectedIndex: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 2, testCommands, 3)
+			m.height = 12
+			m.focusedColumn = 1
+			m.navState.SelectedIndices[0] = tt.initialIndex
+			m.navigator.PropagateSelection(m.navState)
+			if tt.filter != "" {
+				ti := textinput.New()
+				ti.SetValue(tt.filter)
+				m.columnFilters[1] = ti
+			}
+
+			updated, _ := m.handleKeyPress(tt.msg)
+			if result := updated.(Model); result.pendingKey != "" {
+				// g waits for a possible second key before jumping.
+				assert.Equal(t, tt.initialIndex, result.navState.SelectedIndices[0])
+				updated, _ = result.Update(keySequenceTimeoutMsg{seq: result.pendingKeySeq})
+			}
+			result := updated.(Model)
+
+			assert.Equal(t, tt.expectedIndex, result.navState.SelectedIndices[0])
+			assert.Equal(t, root.Children[tt.expectedIndex].Name+"-app", result.navState.Columns[1][0], "selection is propagated")
+			visible := result.getFilteredNavigationItems(0)
+			offset := result.scrollOffsets[1]
+			pageSize := result.getMaxVisibleItems(1)
+			target := findFilteredIndex(result.navState.Columns[0], visible, tt.expectedIndex)
+			assert.True(t, target >= offset && target < offset+pageSize, "target is visible")
+		})
+	}
+}
+
 // TestModel_HandleWindowResize tests window resize message handling.
 func TestModel_HandleWindowResize(t *testing.T) {
 	root := &stack.Node{Name: "root"}
@@ -312,6 +410,258 @@ func TestModel_HandleKeyPress(t *testing.T) {
 	}
 }
 
+// TestModel_HandleKeyPress_KeyBindings tests that actions follow their configured keys and
+// that keys typing text still reach an active filter.
+func TestModel_HandleKeyPress_KeyBindings(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Children: []*stack.Node{
+			{Name: "child1"},
+			{Name: "child2"},
+		},
+	}
+	runes := func(key string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+	newModel := func() Model {
+		m := NewModel(root, 1, testCommands, 3).WithKeyBindings(map[string]string{
+			config.ActionQuit:   "x",
+			config.ActionDown:   "s",
+			config.ActionFilter: "ctrl+f",
+		})
+		m.focusedColumn = 1
+		return m
+	}
+	m := newModel()
+
+	_, cmd := m.handleKeyPress(runes(KeyQ))
+	assert.Nil(t, cmd, "q no longer quits")
+	_, cmd = m.handleKeyPress(runes("x"))
+	assert.NotNil(t, cmd, "x quits")
+
+	updated, _ := newModel().handleKeyPress(runes("s"))
+	assert.Equal(t, 1, updated.(Model).navState.SelectedIndices[0], "s moves down")
+	updated, _ = newModel().handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 0, updated.(Model).navState.SelectedIndices[0], "down is no longer bound")
+	updated, _ = newModel().handleKeyPress(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 1, updated.(Model).navState.SelectedIndices[0], "unchanged actions keep their default key")
+
+	m = newModel()
+	updated, _ = m.handleKeyPress(runes(KeySlash))
+	assert.Equal(t, -1, updated.(Model).activeFilterColumn, "/ no longer filters")
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyCtrlF})
+	filtering := updated.(Model)
+	require.Equal(t, 1, filtering.activeFilterColumn, "ctrl+f filters")
+
+	updated, _ = filtering.handleKeyPress(runes("s"))
+	result := updated.(Model)
+	assert.Equal(t, "s", result.columnFilters[1].Value(), "bound letters are typed into the filter")
+	assert.Equal(t, 0, result.navState.SelectedIndices[0])
+}
+
+// TestModel_HandleKeyPress_VimKeys tests h/j/k/l navigation and that the letters are typed
+// into the filter while one is being edited.
+func TestModel_HandleKeyPress_VimKeys(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Children: []*stack.Node{
+			{Name: "child1"},
+			{Name: "child2"},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		key               string
+		focusedColumn     int
+		initialSelection  int
+		filtering         bool
+		expectedFocus     int
+		expectedSelection int
+		expectedFilter    string
+	}{
+		{
+			name:              "j moves down",
+			key:               KeyVimDown,
+			focusedColumn:     1,
+			expectedFocus:     1,
+			expectedSelection: 1,
+		},
+		{
+			name:              "k moves up",
+			key:               KeyVimUp,
+			focusedColumn:     1,
+			initialSelection:  1,
+			expectedFocus:     1,
+			expectedSelection: 0,
+		},
+		{
+			name:          "h moves left",
+			key:           KeyVimLeft,
+			focusedColumn: 1,
+			expectedFocus: 0,
+		},
+		{
+			name:          "l moves right",
+			key:           KeyVimRight,
+			focusedColumn: 0,
+			expectedFocus: 1,
+		},
+		{
+			name:           "j is typed into an active filter",
+			key:            KeyVimDown,
+			focusedColumn:  1,
+			filtering:      true,
+			expectedFocus:  1,
+			expectedFilter: KeyVimDown,
+		},
+		{
+			name:           "l is typed into an active filter",
+			key:            KeyVimRight,
+			focusedColumn:  1,
+			filtering:      true,
+			expectedFocus:  1,
+			expectedFilter: KeyVimRight,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 1, testCommands, 3)
+			m.focusedColumn = tt.focusedColumn
+			m.navState.SelectedIndices[0] = tt.initialSelection
+			if tt.filtering {
+				ti := textinput.New()
+				ti.Focus()
+				m.columnFilters[tt.focusedColumn] = ti
+				m.activeFilterColumn = tt.focusedColumn
+			}
+
+			updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)})
+			result := updated.(Model)
+
+			assert.Equal(t, tt.expectedFocus, result.focusedColumn)
+			assert.Equal(t, tt.expectedSelection, result.navState.SelectedIndices[0])
+			if tt.filtering {
+				assert.Equal(t, tt.expectedFilter, result.columnFilters[tt.focusedColumn].Value())
+			}
+		})
+	}
+}
+
+// TestModel_HandleKeyPress_TypeToFilter tests that printable keys open the filter with the
+// typed key, and that shortcuts and quit keep working according to the mode.
+func TestModel_HandleKeyPress_TypeToFilter(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Children: []*stack.Node{
+			{Name: "staging"},
+			{Name: "dev"},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		mode              string
+		msg               tea.KeyMsg
+		expectQuit        bool
+		expectedFilter    string // "" = no filter being edited
+		expectedSelection int
+	}{
+		{name: "off: letters do nothing", mode: TypeToFilterOff, msg: runesMsg("d")},
+		{name: "unbound: letter opens filter", mode: TypeToFilterUnbound, msg: runesMsg("d"), expectedFilter: "d", expectedSelection: 1},
+		{name: "unbound: digit opens filter", mode: TypeToFilterUnbound, msg: runesMsg("7"), expectedFilter: "7"},
+		{name: "unbound: q still quits", mode: TypeToFilterUnbound, msg: runesMsg(KeyQ), expectQuit: true},
+		{name: "unbound: vim keys still move", mode: TypeToFilterUnbound, msg: runesMsg(KeyVimDown), expectedSelection: 1},
+		{name: "unbound: alt+letter is not typed", mode: TypeToFilterUnbound, msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d"), Alt: true}},
+		{name: "all: q opens filter", mode: TypeToFilterAll, msg: runesMsg(KeyQ), expectedFilter: "q"},
+		{name: "all: vim keys open filter", mode: TypeToFilterAll, msg: runesMsg(KeyVimDown), expectedFilter: "j"},
+		{name: "all: ctrl+c still quits", mode: TypeToFilterAll, msg: tea.KeyMsg{Type: tea.KeyCtrlC}, expectQuit: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 1, testCommands, 3).WithTypeToFilter(tt.mode)
+			m.focusedColumn = 1
+
+			updated, cmd := m.handleKeyPress(tt.msg)
+			result := updated.(Model)
+
+			if tt.expectQuit {
+				require.NotNil(t, cmd)
+				assert.IsType(t, tea.QuitMsg{}, cmd())
+				return
+			}
+			if tt.expectedFilter == "" {
+				assert.Equal(t, -1, result.activeFilterColumn)
+			} else {
+				require.Equal(t, 1, result.activeFilterColumn)
+				assert.Equal(t, tt.expectedFilter, result.columnFilters[1].Value())
+			}
+			assert.Equal(t, tt.expectedSelection, result.navState.SelectedIndices[0])
+		})
+	}
+
+	t.Run("next keys are typed into the opened filter", func(t *testing.T) {
+		m := NewModel(root, 1, testCommands, 3).WithTypeToFilter(TypeToFilterUnbound)
+		m.focusedColumn = 1
+		for _, key := range []string{"s", "t", "a"} {
+			updated, _ := m.handleKeyPress(runesMsg(key))
+			m = updated.(Model)
+		}
+		assert.Equal(t, "sta", m.columnFilters[1].Value())
+	})
+}
+
+// runesMsg returns the key message for typing text.
+func runesMsg(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+// TestModel_HandleKeyPress_DebugOverlay tests that g then d toggles the debug overlay and
+// that any other key cancels the pending sequence.
+func TestModel_HandleKeyPress_DebugOverlay(t *testing.T) {
+	root := &stack.Node{
+		Name:     "root",
+		Children: []*stack.Node{{Name: "child1"}, {Name: "child2"}},
+	}
+	press := func(m Model, key string) Model {
+		updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model)
+	}
+
+	m := NewModel(root, 1, testCommands, 3)
+	m.focusedColumn = 1
+	m.navState.SelectedIndices[0] = 1
+	m = press(press(m, KeyDebugPrefix), KeyDebug)
+	assert.True(t, m.debugOverlay, "g then d shows the overlay")
+	assert.Equal(t, 1, m.navState.SelectedIndices[0], "g then d does not jump")
+
+	m = press(press(m, KeyDebugPrefix), KeyDebug)
+	assert.False(t, m.debugOverlay, "g then d hides the overlay again")
+
+	m = press(m, KeyDebug)
+	assert.False(t, m.debugOverlay, "d alone does nothing")
+
+	m = press(press(press(m, KeyDebugPrefix), KeyRecenter), KeyDebug)
+	assert.False(t, m.debugOverlay, "another key cancels the sequence")
+	assert.Empty(t, m.pendingKey)
+	assert.Equal(t, 0, m.navState.SelectedIndices[0], "another key performs the deferred jump")
+
+	// Only the timeout of the latest sequence performs the jump.
+	m.navState.SelectedIndices[0] = 1
+	m = press(m, KeyDebugPrefix)
+	updated, _ := m.Update(keySequenceTimeoutMsg{seq: m.pendingKeySeq - 1})
+	m = updated.(Model)
+	assert.Equal(t, 1, m.navState.SelectedIndices[0], "a stale timeout is ignored")
+	assert.Equal(t, KeyDebugPrefix, m.pendingKey)
+
+	updated, _ = m.Update(keySequenceTimeoutMsg{seq: m.pendingKeySeq})
+	m = updated.(Model)
+	assert.Equal(t, 0, m.navState.SelectedIndices[0], "the timeout performs the deferred jump")
+	assert.Empty(t, m.pendingKey)
+}
+
 // TestModel_HandleVerticalMove tests vertical navigation handling.
 func TestModel_HandleVerticalMove(t *testing.T) {
 	root := &stack.Node{
@@ -361,6 +711,62 @@ func TestModel_HandleVerticalMove(t *testing.T) {
 	}
 }
 
+// TestModel_CyclicNavigation tests that up/down wrap around at the ends of the commands
+// and navigation columns by default and stop there when cyclic navigation is disabled.
+func TestModel_CyclicNavigation(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Children: []*stack.Node{
+			{Name: "child1"},
+			{Name: "child2"},
+			{Name: "child3"},
+		},
+	}
+	commands := []string{"plan", "apply", "destroy"}
+
+	tests := []struct {
+		name          string
+		cyclic        bool
+		expectedFirst int // Index after moving up from the first item
+		expectedLast  int // Index after moving down from the last item
+	}{
+		{name: "cyclic wraps around", cyclic: true, expectedFirst: 2, expectedLast: 0},
+		{name: "non-cyclic stops at the ends", cyclic: false, expectedFirst: 0, expectedLast: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 1, commands, 3).WithCyclicNavigation(tt.cyclic)
+
+			m.focusedColumn = 0
+			m.selectedCommand = 0
+			assert.Equal(t, tt.expectedFirst, m.handleVerticalMove(true).selectedCommand)
+			m.selectedCommand = 2
+			assert.Equal(t, tt.expectedLast, m.handleVerticalMove(false).selectedCommand)
+
+			m.focusedColumn = 1
+			m.navState.SelectedIndices[0] = 0
+			m = m.handleVerticalMove(true)
+			assert.Equal(t, tt.expectedFirst, m.navState.SelectedIndices[0])
+			m.navState.SelectedIndices[0] = 2
+			m = m.handleVerticalMove(false)
+			assert.Equal(t, tt.expectedLast, m.navState.SelectedIndices[0])
+		})
+	}
+
+	t.Run("with an active filter", func(t *testing.T) {
+		m := NewModel(root, 1, commands, 3).WithCyclicNavigation(false)
+		ti := textinput.New()
+		ti.SetValue("a") // Matches "plan" and "apply"
+		m.columnFilters[0] = ti
+
+		m.selectedCommand = 1
+		assert.Equal(t, 1, m.handleVerticalMove(false).selectedCommand)
+		m.selectedCommand = 0
+		assert.Equal(t, 0, m.handleVerticalMove(true).selectedCommand)
+	})
+}
+
 // TestModel_MoveNavigationSelection tests navigation column selection movement.
 func TestModel_MoveNavigationSelection(t *testing.T) {
 	tests := []struct {
@@ -1167,6 +1573,90 @@ func TestFilterItems(t *testing.T) {
 	}
 }
 
+// TestFuzzyFilterItems tests subsequence matching and best-first ranking.
+func TestFuzzyFilterItems(t *testing.T) {
+	tests := []struct {
+		name       string
+		items      []string
+		filterText string
+		expected   []string
+	}{
+		{
+			name:       "empty filter returns all items in order",
+			items:      []string{"staging", "dev", "prod"},
+			filterText: "",
+			expected:   []string{"staging", "dev", "prod"},
+		},
+		{
+			name:       "characters match in order but not together",
+			items:      []string{"prod/eu-west-1", "dev/us-east-1", "staging"},
+			filterText: "dvus",
+			expected:   []string{"dev/us-east-1"},
+		},
+		{
+			name:       "characters out of order do not match",
+			items:      []string{"dev/us-east-1"},
+			filterText: "usdv",
+			expected:   []string{},
+		},
+		{
+			name:       "case insensitive",
+			items:      []string{"Dev/US-East-1"},
+			filterText: "DVUS",
+			expected:   []string{"Dev/US-East-1"},
+		},
+		{
+			name:       "equal scores keep original order",
+			items:      []string{"validate", "plan-all", "apply", "plan"},
+			filterText: "pla",
+			expected:   []string{"plan-all", "plan"},
+		},
+		{
+			name:       "contiguous match ranks above scattered match",
+			items:      []string{"n-e-t-x", "network", "dns"},
+			filterText: "net",
+			expected:   []string{"network", "n-e-t-x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, fuzzyFilterItems(tt.items, tt.filterText))
+		})
+	}
+}
+
+// TestModel_FuzzyFilter tests that fuzzy filtering is opt-in and that the ranked order still
+// maps back to the original indices used to track the selection.
+func TestModel_FuzzyFilter(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Children: []*stack.Node{
+			{Name: "dns-vpc-us"},
+			{Name: "dev-us"},
+			{Name: "staging"},
+		},
+	}
+	m := NewModel(root, 1, testCommands, 3)
+	m.focusedColumn = 1
+	m.activeFilterColumn = 1
+	m.navState.SelectedIndices[0] = 2 // "staging"
+	ti := textinput.New()
+	ti.SetValue("dvus")
+	m.columnFilters[1] = ti
+
+	assert.Empty(t, m.getFilteredNavigationItems(0), "substring matching is the default")
+
+	m = m.WithFuzzyFilter(true)
+	items := m.getFilteredNavigationItems(0)
+	require.Equal(t, []string{"dev-us", "dns-vpc-us"}, items)
+
+	m.adjustSelectionAfterFilter()
+	assert.Equal(t, 1, m.navState.SelectedIndices[0], "selection moves to the best match")
+	assert.Equal(t, 0, findFilteredIndex(m.navState.Columns[0], items, 1))
+	assert.Equal(t, 0, findOriginalIndex(m.navState.Columns[0], items, 1))
+}
+
 // TestModel_AdjustSelectionAfterFilter tests selection adjustment after filter changes.
 func TestModel_AdjustSelectionAfterFilter(t *testing.T) {
 	tests := []struct {
@@ -1829,3 +2319,1211 @@ func TestModel_EnterKey_WithMarks_UsesMarkedPaths(t *testing.T) {
 	assert.True(t, finalModel.confirmed)
 	assert.True(t, finalModel.HasSelectedPaths(), "marks remain after confirmation")
 }
+
+// TestHandleKeyPress_ColumnResize tests live column widening/narrowing with +/-.
+func TestHandleKeyPress_ColumnResize(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "a-rather-long-directory-name-for-truncation", Path: "/root/a-rather-long-directory-name-for-truncation"},
+		},
+	}
+
+	newResizableModel := func(width, columnWidth int) Model {
+		m := NewModel(root, 1, testCommands, 3)
+		m.width = width
+		m.height = 30
+		m.columnWidth = columnWidth
+		m.ready = true
+		return m
+	}
+	press := func(m Model, key string) Model {
+		updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model)
+	}
+
+	tests := []struct {
+		name          string
+		width         int
+		columnWidth   int
+		key           string
+		expectedWidth int
+	}{
+		{name: "plus increases width", width: 200, columnWidth: 30, key: KeyPlus, expectedWidth: 30 + ColumnWidthStep},
+		{name: "plus clamped to max", width: 200, columnWidth: MaxColumnWidth, key: KeyPlus, expectedWidth: MaxColumnWidth},
+		{name: "plus clamped to terminal", width: 40, columnWidth: 38, key: KeyPlus, expectedWidth: 40 - ColumnOverhead},
+		{name: "minus decreases width", width: 200, columnWidth: 30, key: KeyMinus, expectedWidth: 30 - ColumnWidthStep},
+		{name: "minus clamped to min", width: 200, columnWidth: MinColumnWidth, key: KeyMinus, expectedWidth: MinColumnWidth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(newResizableModel(tt.width, tt.columnWidth), tt.key)
+			assert.Equal(t, tt.expectedWidth, m.columnWidth)
+		})
+	}
+
+	t.Run("override survives window resize", func(t *testing.T) {
+		m := press(newResizableModel(200, 30), KeyPlus)
+		m = m.handleWindowResize(tea.WindowSizeMsg{Width: 180, Height: 30})
+		assert.Equal(t, 30+ColumnWidthStep, m.columnWidth)
+	})
+
+	t.Run("truncation follows column width", func(t *testing.T) {
+		narrow := newResizableModel(200, 30)
+		wide := press(narrow, KeyPlus)
+
+		narrowWidth := NewRenderer(narrow, NewLayoutCalculator(narrow.width, narrow.height, narrow.columnWidth)).getMaxItemTextWidth(false)
+		wideWidth := NewRenderer(wide, NewLayoutCalculator(wide.width, wide.height, wide.columnWidth)).getMaxItemTextWidth(false)
+		assert.Equal(t, narrowWidth+ColumnWidthStep, wideWidth)
+	})
+}
+
+// TestWithColumnWidth tests the configured initial column width.
+func TestWithColumnWidth(t *testing.T) {
+	root := &stack.Node{Name: "root", Children: []*stack.Node{{Name: "a"}}}
+
+	m := NewModel(root, 1, testCommands, 3).WithColumnWidth(42)
+	m = m.handleWindowResize(tea.WindowSizeMsg{Width: 200, Height: 30})
+	assert.Equal(t, 42, m.columnWidth)
+
+	auto := NewModel(root, 1, testCommands, 3).WithColumnWidth(0)
+	auto = auto.handleWindowResize(tea.WindowSizeMsg{Width: 200, Height: 30})
+	assert.Equal(t, auto.calculateColumnWidth(), auto.columnWidth)
+	assert.Zero(t, auto.columnWidthOverride)
+}
+
+// TestHandleKeyPress_RightArrowOnLeafStack tests right-arrow confirm vs. wrap on leaf stacks.
+func TestHandleKeyPress_RightArrowOnLeafStack(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+			{Name: "env", Path: "/root/env", Depth: 1, Children: []*stack.Node{
+				{Name: "dev", Path: "/root/env/dev", IsStack: true, Depth: 2},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		rightConfirms   bool
+		selectIndex     int
+		expectConfirmed bool
+		expectFocus     int
+	}{
+		{name: "confirms on leaf stack when enabled", rightConfirms: true, selectIndex: 0, expectConfirmed: true, expectFocus: 1},
+		{name: "wraps on leaf stack when disabled", rightConfirms: false, selectIndex: 0, expectConfirmed: false, expectFocus: 0},
+		{name: "advances on non-leaf node when enabled", rightConfirms: true, selectIndex: 1, expectConfirmed: false, expectFocus: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 2, testCommands, 3).WithRightArrowConfirm(tt.rightConfirms)
+			m.width = 200
+			m.height = 30
+			m.columnWidth = 30
+			m.ready = true
+			m.focusedColumn = 1
+			m.navState.SelectedIndices[0] = tt.selectIndex
+			m.navigator.PropagateSelection(m.navState)
+
+			updated, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRight})
+			result := updated.(Model)
+
+			assert.Equal(t, tt.expectConfirmed, result.IsConfirmed())
+			assert.Equal(t, tt.expectFocus, result.focusedColumn)
+			if tt.expectConfirmed {
+				assert.NotNil(t, cmd, "confirming should quit the program")
+				assert.Equal(t, "/root/vpc", result.GetSelectedStackPath())
+			}
+		})
+	}
+}
+
+// TestHandleKeyPress_EnterOnParentStack tests confirm vs. drill on a stack that has children.
+func TestHandleKeyPress_EnterOnParentStack(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "network", Path: "/root/network", IsStack: true, Depth: 1, Children: []*stack.Node{
+				{Name: "vpc", Path: "/root/network/vpc", IsStack: true, Depth: 2},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		mode            string
+		alt             bool
+		expectConfirmed bool
+		expectFocus     int
+	}{
+		{name: "confirm mode runs the parent stack", mode: EnterParentStackConfirm, expectConfirmed: true, expectFocus: 1},
+		{name: "drill mode moves into children", mode: EnterParentStackDrill, expectConfirmed: false, expectFocus: 2},
+		{name: "drill mode with alt confirms", mode: EnterParentStackDrill, alt: true, expectConfirmed: true, expectFocus: 1},
+		{name: "unknown mode confirms", mode: "bogus", expectConfirmed: true, expectFocus: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 2, testCommands, 3).WithEnterOnParentStack(tt.mode)
+			m.width = 200
+			m.height = 30
+			m.columnWidth = 30
+			m.ready = true
+			m.focusedColumn = 1
+
+			updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter, Alt: tt.alt})
+			result := updated.(Model)
+
+			assert.Equal(t, tt.expectConfirmed, result.IsConfirmed())
+			assert.Equal(t, tt.expectFocus, result.focusedColumn)
+			if tt.expectConfirmed {
+				assert.Equal(t, "/root/network", result.GetSelectedStackPath())
+			}
+		})
+	}
+
+	// Drill mode still confirms leaf stacks with plain enter.
+	m := NewModel(root, 2, testCommands, 3).WithEnterOnParentStack(EnterParentStackDrill)
+	m.focusedColumn = 2
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).IsConfirmed())
+	assert.Equal(t, "/root/network/vpc", updated.(Model).GetSelectedStackPath())
+}
+
+// TestHandleKeyPress_IncludeRootTarget tests toggling the commands column target to the include root.
+func TestHandleKeyPress_IncludeRootTarget(t *testing.T) {
+	root := &stack.Node{
+		Name: "live",
+		Path: "/repo/live",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/repo/live/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	rootKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyRoot)}
+
+	m := NewModel(root, 1, testCommands, 3).WithIncludeRoot("/repo")
+	m.focusedColumn = 0
+	assert.Equal(t, "/repo/live", m.GetSelectedStackPath())
+
+	updated, _ := m.handleKeyPress(rootKey)
+	m = updated.(Model)
+	assert.Equal(t, "/repo", m.GetSelectedStackPath())
+	assert.Contains(t, m.getCurrentNavigationPath(), IncludeRootLabel)
+
+	// Navigation columns keep targeting the focused stack.
+	m.focusedColumn = 1
+	assert.Equal(t, "/repo/live/vpc", m.GetSelectedStackPath())
+
+	m.focusedColumn = 0
+	updated, _ = m.handleKeyPress(rootKey)
+	m = updated.(Model)
+	assert.Equal(t, "/repo/live", m.GetSelectedStackPath())
+
+	// Without an include root the key is a no-op.
+	m = NewModel(root, 1, testCommands, 3)
+	updated, _ = m.handleKeyPress(rootKey)
+	assert.Equal(t, "/repo/live", updated.(Model).GetSelectedStackPath())
+}
+
+// TestUpdate_RememberCommandPerStack tests that focusing a stack pre-selects its last command.
+func TestUpdate_RememberCommandPerStack(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+			{Name: "rds", Path: "/root/rds", IsStack: true, Depth: 1},
+		},
+	}
+	lastCommands := map[string]string{"/root/vpc": "apply"}
+
+	press := func(m Model, keyType tea.KeyType) Model {
+		updated, _ := m.Update(tea.KeyMsg{Type: keyType})
+		return updated.(Model)
+	}
+
+	m := NewModel(root, 1, testCommands, 3).WithLastCommands(lastCommands)
+	m.width = 200
+	m.height = 30
+	m.columnWidth = 30
+	m.ready = true
+
+	// Moving into the stacks column focuses vpc, which was last applied.
+	m = press(m, tea.KeyRight)
+	assert.Equal(t, "/root/vpc", m.GetSelectedStackPath())
+	assert.Equal(t, "apply", m.GetSelectedCommand())
+
+	// rds has no history and falls back to the default command.
+	m = press(m, tea.KeyDown)
+	assert.Equal(t, "/root/rds", m.GetSelectedStackPath())
+	assert.Equal(t, testCommands[0], m.GetSelectedCommand())
+
+	// A command chosen by hand is kept while the same stack stays focused.
+	m = press(m, tea.KeyLeft)
+	m = press(m, tea.KeyDown)
+	chosen := m.GetSelectedCommand()
+	m = press(m, tea.KeyRight)
+	assert.Equal(t, chosen, m.GetSelectedCommand())
+
+	// Without the option, focus changes leave the command untouched.
+	m = NewModel(root, 1, testCommands, 3)
+	m.selectedCommand = 2
+	m = press(m, tea.KeyRight)
+	assert.Equal(t, testCommands[2], m.GetSelectedCommand())
+}
+
+// TestHandleKeyPress_ConfigReload tests that ctrl+r applies the reloaded model or reports the error.
+func TestHandleKeyPress_ConfigReload(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	reloadKey := tea.KeyMsg{Type: tea.KeyCtrlR}
+
+	m := NewModel(root, 1, testCommands, 3).WithConfigReloader(func(m Model) (Model, error) {
+		return m.WithCommands([]string{"plan"}).WithMaxNavigationColumns(1), nil
+	})
+	m.selectedCommand = 5
+	updated, _ := m.handleKeyPress(reloadKey)
+	result := updated.(Model)
+	assert.Equal(t, []string{"plan"}, result.GetCommands())
+	assert.Equal(t, "plan", result.GetSelectedCommand(), "selection should be clamped to the new list")
+	assert.Equal(t, 1, result.GetMaxNavigationColumns())
+	assert.Equal(t, result.Text(MsgConfigReloaded), result.notice)
+
+	// The notice is cleared by the next key press.
+	updated, _ = result.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Empty(t, updated.(Model).notice)
+
+	// A failed reload keeps the current settings.
+	m = NewModel(root, 1, testCommands, 3).WithConfigReloader(func(m Model) (Model, error) {
+		return m.WithCommands(nil), errors.New("bad yaml")
+	})
+	updated, _ = m.handleKeyPress(reloadKey)
+	result = updated.(Model)
+	assert.Equal(t, testCommands, result.GetCommands())
+	assert.Contains(t, result.notice, "bad yaml")
+
+	// Without a reloader the key does nothing.
+	m = NewModel(root, 1, testCommands, 3)
+	updated, _ = m.handleKeyPress(reloadKey)
+	assert.Empty(t, updated.(Model).notice)
+}
+
+func TestHandleRecenter(t *testing.T) {
+	root := &stack.Node{Name: "root", Path: "/root"}
+	for i := 0; i < 20; i++ {
+		name := string(rune('a' + i))
+		root.Children = append(root.Children, &stack.Node{Name: name, Path: "/root/" + name, IsStack: true, Depth: 1})
+	}
+	recenterKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyRecenter)}
+
+	m := NewModel(root, 1, testCommands, 3)
+	m.height = 16
+	m.ready = true
+	m.focusedColumn = 1
+	maxVisible := m.getMaxVisibleItems(1)
+	assert.Equal(t, 5, maxVisible)
+
+	tests := []struct {
+		name           string
+		selected       int
+		expectedOffset int
+	}{
+		{name: "middle of the list", selected: 10, expectedOffset: 8},
+		{name: "clamped at the start", selected: 1, expectedOffset: 0},
+		{name: "clamped at the end", selected: 18, expectedOffset: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m.navState.SelectedIndices[0] = tt.selected
+			updated, _ := m.handleKeyPress(recenterKey)
+			result := updated.(Model)
+			offset := result.scrollOffsets[1]
+			assert.Equal(t, tt.expectedOffset, offset)
+			assert.True(t, offset <= tt.selected && tt.selected < offset+maxVisible, "selection should stay visible")
+		})
+	}
+
+	// Moving after a re-center returns to page-based scrolling with the selection visible.
+	m.navState.SelectedIndices[0] = 10
+	updated, _ := m.handleKeyPress(recenterKey)
+	result := updated.(Model).handleVerticalMove(true)
+	assert.Equal(t, 9, result.navState.SelectedIndices[0])
+	assert.Equal(t, 5, result.scrollOffsets[1])
+
+	// The commands column is re-centered too; a list shorter than the window stays at the top.
+	m.focusedColumn = 0
+	m.selectedCommand = 6
+	updated, _ = m.handleKeyPress(recenterKey)
+	assert.Equal(t, 3, updated.(Model).scrollOffsets[0])
+	m.height = 30
+	updated, _ = m.handleKeyPress(recenterKey)
+	assert.Equal(t, 0, updated.(Model).scrollOffsets[0])
+}
+
+func TestConfirmSummary(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	newModel := func() Model {
+		m := NewModel(root, 1, testCommands, 3).
+			WithConfirmSummary(true).
+			WithBranch("main").
+			WithExecutionPreviewer(func(d ExecutionDetails) ExecutionDetails {
+				d.Binary = "/usr/local/bin/terragrunt"
+				d.ExtraArgs = []string{"--non-interactive"}
+				d.EnvVarNames = []string{"AWS_PROFILE", "TG_TOKEN"}
+				return d
+			})
+		m.width = 120
+		m.height = 30
+		m.ready = true
+		m.focusedColumn = 1
+		return m
+	}
+
+	// Confirming shows the summary instead of running.
+	updated, cmd := newModel().handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m := updated.(Model)
+	assert.Nil(t, cmd)
+	assert.False(t, m.IsConfirmed())
+	assert.Equal(t, StateConfirm, m.state)
+	assert.Equal(t, ExecutionDetails{
+		Binary:      "/usr/local/bin/terragrunt",
+		Command:     "plan",
+		StackPaths:  []string{"/root/vpc"},
+		ExtraArgs:   []string{"--non-interactive"},
+		EnvVarNames: []string{"AWS_PROFILE", "TG_TOKEN"},
+		Branch:      "main",
+	}, m.GetExecutionDetails())
+
+	view := m.View()
+	for _, expected := range []string{ConfirmTitle, "/usr/local/bin/terragrunt", "plan", "/root/vpc", "--non-interactive", "AWS_PROFILE, TG_TOKEN", "main"} {
+		assert.Contains(t, view, expected)
+	}
+
+	// Enter proceeds with the execution.
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).IsConfirmed())
+	assert.NotNil(t, cmd)
+
+	// Escape cancels and returns to navigation.
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	cancelled := updated.(Model)
+	assert.Nil(t, cmd)
+	assert.False(t, cancelled.IsConfirmed())
+	assert.Equal(t, StateNavigation, cancelled.state)
+	assert.Equal(t, cancelled.Text(MsgSelectionCancelled), cancelled.notice)
+
+	// Without the option, enter confirms directly.
+	updated, _ = newModel().WithConfirmSummary(false).handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).IsConfirmed())
+}
+
+func TestRequireConfirmation(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	newModel := func(command int) Model {
+		m := NewModel(root, 1, testCommands, 3).WithRequireConfirmation([]string{"destroy", "apply"})
+		m.width = 120
+		m.height = 30
+		m.ready = true
+		m.selectedCommand = command
+		m.focusedColumn = 1
+		return m
+	}
+	const destroy = 7
+
+	// Confirming a listed command asks yes/no with the command and target path.
+	updated, cmd := newModel(destroy).handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m := updated.(Model)
+	assert.Nil(t, cmd)
+	assert.False(t, m.IsConfirmed())
+	assert.Equal(t, StateConfirm, m.state)
+	view := m.View()
+	assert.Contains(t, view, "Run destroy on /root/vpc?")
+	assert.Contains(t, view, ConfirmYesNoHelp)
+
+	// Enter alone does not run it.
+	updated, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, updated.(Model).IsConfirmed())
+	assert.Equal(t, StateConfirm, updated.(Model).state)
+	assert.Nil(t, cmd)
+
+	// y runs it.
+	updated, cmd = m.Update(runesMsg(KeyYes))
+	assert.True(t, updated.(Model).IsConfirmed())
+	assert.NotNil(t, cmd)
+
+	// n and esc return to navigation without quitting.
+	for _, msg := range []tea.KeyMsg{runesMsg(KeyNo), {Type: tea.KeyEsc}} {
+		updated, cmd = m.Update(msg)
+		cancelled := updated.(Model)
+		assert.Nil(t, cmd)
+		assert.False(t, cancelled.IsConfirmed())
+		assert.Equal(t, StateNavigation, cancelled.state)
+
+		// Confirming again asks again.
+		updated, _ = cancelled.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Equal(t, StateConfirm, updated.(Model).state)
+	}
+
+	// Other commands run on enter.
+	updated, _ = newModel(0).handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).IsConfirmed())
+
+	// With the summary enabled, other commands keep proceeding with enter and ignore y.
+	updated, _ = newModel(0).WithConfirmSummary(true).handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	assert.NotContains(t, m.View(), "Run plan")
+	updated, _ = m.Update(runesMsg(KeyYes))
+	assert.False(t, updated.(Model).IsConfirmed())
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.True(t, updated.(Model).IsConfirmed())
+}
+
+func TestFavoriteKeys(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	newModel := func() Model {
+		m := NewModel(root, 1, testCommands, 3).WithFavorites([]string{"plan -refresh=false", "  ", "apply -target=module.vpc -lock=false"})
+		m.focusedColumn = 1
+		return m
+	}
+
+	// F2 maps to the second non-blank preset and runs it against the focused stack.
+	updated, cmd := newModel().handleKeyPress(tea.KeyMsg{Type: tea.KeyF2})
+	m := updated.(Model)
+	assert.True(t, m.IsConfirmed())
+	assert.NotNil(t, cmd)
+	assert.Equal(t, Selection{
+		Confirmed:  true,
+		Command:    "apply",
+		StackPath:  "/root/vpc",
+		StackPaths: []string{"/root/vpc"},
+		Args:       []string{"-target=module.vpc", "-lock=false"},
+	}, m.Selection())
+
+	// An unmapped function key does nothing.
+	updated, cmd = newModel().handleKeyPress(tea.KeyMsg{Type: tea.KeyF5})
+	assert.False(t, updated.(Model).IsConfirmed())
+	assert.Nil(t, cmd)
+
+	// With the confirmation summary, the preset goes through it and esc drops it.
+	updated, _ = newModel().WithConfirmSummary(true).handleKeyPress(tea.KeyMsg{Type: tea.KeyF2})
+	m = updated.(Model)
+	assert.Equal(t, StateConfirm, m.state)
+	assert.Equal(t, "apply", m.GetExecutionDetails().Command)
+	assert.Equal(t, []string{"-target=module.vpc", "-lock=false"}, m.GetExecutionDetails().ExtraArgs)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	assert.Equal(t, "plan", m.GetSelectedCommand(), "the commands column selection is used again")
+	assert.Nil(t, m.GetSelectedArgs())
+}
+
+func TestCommandAliases(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+	m := NewModel(root, 1, []string{"p", "aa", "validate"}, 3).
+		WithCommandAliases(map[string]string{"p": "plan", "aa": "apply -auto-approve", "blank": "  "}).
+		WithDangerousCommands([]string{"apply"}).
+		WithBreadcrumbCommand(true)
+	m.width = 120
+	m.height = 30
+	m.selectedCommand = 1
+
+	// The commands column and breadcrumb show the alias; execution gets the expanded command.
+	assert.Equal(t, "aa", m.GetSelectedCommandLabel())
+	assert.Equal(t, "apply", m.GetSelectedCommand())
+	assert.Equal(t, []string{"-auto-approve"}, m.GetSelectedArgs())
+	assert.Equal(t, []bool{false, true, false}, m.dangerousFlags(m.commands), "an alias of a dangerous command is dangerous")
+	prefix, _, _, _ := m.breadcrumbPath()
+	assert.Equal(t, "aa @ ", prefix)
+	assert.NotContains(t, m.commandAliases, "blank")
+
+	m.focusedColumn = 1
+	updated, _ := m.handleEnterKey()
+	assert.Equal(t, Selection{
+		Confirmed:  true,
+		Command:    "apply",
+		StackPath:  "/root/vpc",
+		StackPaths: []string{"/root/vpc"},
+		Args:       []string{"-auto-approve"},
+	}, updated.(Model).Selection())
+
+	// A command without an alias runs as listed.
+	m.selectedCommand = 2
+	assert.Equal(t, "validate", m.GetSelectedCommand())
+	assert.Nil(t, m.GetSelectedArgs())
+
+	// A command recorded in history selects the alias that expands to it.
+	assert.Equal(t, 0, m.WithLastSelection("plan", "").selectedCommand)
+	assert.Equal(t, 1, m.WithLastSelection("aa", "").selectedCommand)
+}
+
+func TestParseFavorite(t *testing.T) {
+	favorite, ok := ParseFavorite("  plan   -refresh=false -lock=false ")
+	assert.True(t, ok)
+	assert.Equal(t, Favorite{Command: "plan", Args: []string{"-refresh=false", "-lock=false"}}, favorite)
+
+	favorite, ok = ParseFavorite("validate")
+	assert.True(t, ok)
+	assert.Equal(t, Favorite{Command: "validate", Args: []string{}}, favorite)
+
+	_, ok = ParseFavorite("   ")
+	assert.False(t, ok)
+}
+
+// TestModel_SkipSingleCommand tests that a single configured command hides the commands
+// column and that enter runs it against the selected stack.
+func TestModel_SkipSingleCommand(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/test",
+		Children: []*stack.Node{
+			{Name: "dev", Path: "/test/dev", IsStack: true},
+			{Name: "prod", Path: "/test/prod", IsStack: true},
+		},
+	}
+	newModel := func(commands []string) Model {
+		m := NewModel(root, 1, commands, 3)
+		m.ready = true
+		m.width = 120
+		m.height = 30
+		m.columnWidth = 25
+		return m.WithSkipSingleCommand(true)
+	}
+
+	t.Run("single command hides the commands column", func(t *testing.T) {
+		m := newModel([]string{"plan"})
+		assert.Equal(t, 1, m.focusedColumn, "focus starts on the first navigation column")
+
+		columns := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderColumnsWithArrows()
+		assert.Len(t, columns, 1, "only the navigation column is rendered")
+
+		updated, _ := m.handleHorizontalMove(true)
+		assert.Equal(t, 1, updated.(Model).focusedColumn, "left never focuses the hidden column")
+		updated, _ = m.handleHorizontalMove(false)
+		assert.Equal(t, 1, updated.(Model).focusedColumn, "right wraps past the hidden column")
+	})
+
+	t.Run("enter runs the single command against the selected stack", func(t *testing.T) {
+		m := newModel([]string{"plan"})
+		m = m.handleVerticalMove(false)
+
+		updated, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+		result := updated.(Model)
+
+		assert.NotNil(t, cmd, "should quit after confirming")
+		assert.True(t, result.IsConfirmed())
+		assert.Equal(t, "plan", result.GetSelectedCommand())
+		assert.Equal(t, "/test/prod", result.GetSelectedStackPath())
+	})
+
+	t.Run("several commands keep the commands column", func(t *testing.T) {
+		m := newModel([]string{"plan", "apply"})
+		assert.Equal(t, 0, m.focusedColumn)
+
+		columns := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderColumnsWithArrows()
+		assert.Len(t, columns, 2)
+	})
+}
+
+// TestModel_HandleMouse_Breadcrumb tests that clicking a level's name in the breadcrumb bar
+// focuses that level's column.
+func TestModel_HandleMouse_Breadcrumb(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/test",
+		Children: []*stack.Node{{
+			Name: "env",
+			Path: "/test/env",
+			Children: []*stack.Node{{
+				Name:     "dev",
+				Path:     "/test/env/dev",
+				Children: []*stack.Node{{Name: "app", Path: "/test/env/dev/app"}},
+			}},
+		}},
+	}
+	click := func(x, y int) tea.MouseMsg {
+		return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	}
+
+	// The breadcrumb reads "📁 /test/env/dev/app" from column 2 (padding), so the path
+	// starts at column 5: "env" spans 11-13, "dev" 15-17 and "app" 19-21.
+	tests := []struct {
+		name          string
+		width         int
+		msg           tea.MouseMsg
+		expectedFocus int
+	}{
+		{name: "first level", width: 120, msg: click(11, HeaderHeight), expectedFocus: 1},
+		{name: "second level", width: 120, msg: click(17, HeaderHeight), expectedFocus: 2},
+		{name: "focused level", width: 120, msg: click(20, HeaderHeight), expectedFocus: 3},
+		{name: "scan root is not a level", width: 120, msg: click(7, HeaderHeight), expectedFocus: 3},
+		{name: "separator", width: 120, msg: click(14, HeaderHeight), expectedFocus: 3},
+		{name: "other row", width: 120, msg: click(11, 0), expectedFocus: 3},
+		{
+			name:          "release is ignored",
+			width:         120,
+			msg:           tea.MouseMsg{X: 11, Y: HeaderHeight, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft},
+			expectedFocus: 3,
+		},
+		// At width 20 the breadcrumb reads "📁 ...nv/dev/app": "dev" starts at column 11
+		// and the truncated "env" cannot be clicked.
+		{name: "truncated path", width: 20, msg: click(11, HeaderHeight), expectedFocus: 2},
+		{name: "truncated name", width: 20, msg: click(8, HeaderHeight), expectedFocus: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 3, testCommands, 3)
+			m.width = tt.width
+			m.focusedColumn = 3
+
+			updated, _ := m.handleNavigationUpdate(tt.msg)
+			assert.Equal(t, tt.expectedFocus, updated.(Model).focusedColumn)
+		})
+	}
+}
+
+// TestModel_FocusDepth tests that focusing a level outside the sliding window moves the
+// window and leaves filter editing.
+func TestModel_FocusDepth(t *testing.T) {
+	m := NewModel(nil, 0, testCommands, 2)
+	m.navigationOffset = 2
+	m.focusedColumn = 4
+	m.columnFilters[4] = textinput.New()
+	m.activeFilterColumn = 4
+
+	m = m.focusDepth(0)
+	assert.Equal(t, 1, m.focusedColumn)
+	assert.Equal(t, 0, m.navigationOffset)
+	assert.Equal(t, -1, m.activeFilterColumn)
+
+	m = m.focusDepth(3)
+	assert.Equal(t, 4, m.focusedColumn)
+	assert.Equal(t, 2, m.navigationOffset)
+}
+
+// TestModel_BreadcrumbMode tests that in breadcrumb mode left/right select an ancestor
+// level and enter focuses its column, moving the sliding window to show it.
+func TestModel_BreadcrumbMode(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/test",
+		Children: []*stack.Node{{
+			Name: "a",
+			Path: "/test/a",
+			Children: []*stack.Node{{
+				Name: "b",
+				Path: "/test/a/b",
+				Children: []*stack.Node{{
+					Name:     "c",
+					Path:     "/test/a/b/c",
+					Children: []*stack.Node{{Name: "d", Path: "/test/a/b/c/d"}},
+				}},
+			}},
+		}},
+	}
+	key := func(t tea.KeyType) tea.KeyMsg { return tea.KeyMsg{Type: t} }
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	tests := []struct {
+		name           string
+		keys           []tea.KeyMsg
+		expectedFocus  int
+		expectedOffset int
+		expectedMode   bool
+	}{
+		{name: "opens on the focused level", keys: []tea.KeyMsg{runes(KeyBreadcrumb)}, expectedFocus: 4, expectedOffset: 2, expectedMode: true},
+		{
+			name:           "parent inside the window",
+			keys:           []tea.KeyMsg{runes(KeyBreadcrumb), key(tea.KeyLeft), key(tea.KeyEnter)},
+			expectedFocus:  3,
+			expectedOffset: 2,
+		},
+		{
+			name:           "ancestor left of the window",
+			keys:           []tea.KeyMsg{runes(KeyBreadcrumb), key(tea.KeyLeft), key(tea.KeyLeft), key(tea.KeyEnter)},
+			expectedFocus:  2,
+			expectedOffset: 1,
+		},
+		{
+			name:           "left stops at the first level",
+			keys:           []tea.KeyMsg{runes(KeyBreadcrumb), runes(KeyVimLeft), runes(KeyVimLeft), runes(KeyVimLeft), runes(KeyVimLeft), key(tea.KeyEnter)},
+			expectedFocus:  1,
+			expectedOffset: 0,
+		},
+		{
+			name:           "right stops at the focused level",
+			keys:           []tea.KeyMsg{runes(KeyBreadcrumb), key(tea.KeyLeft), key(tea.KeyRight), key(tea.KeyRight), key(tea.KeyEnter)},
+			expectedFocus:  4,
+			expectedOffset: 2,
+		},
+		{
+			name:           "esc keeps the focus",
+			keys:           []tea.KeyMsg{runes(KeyBreadcrumb), key(tea.KeyLeft), key(tea.KeyEsc)},
+			expectedFocus:  4,
+			expectedOffset: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 4, testCommands, 2)
+			m.width = 120
+			m.focusedColumn = 4
+			m.navigationOffset = 2
+
+			for _, msg := range tt.keys {
+				updated, _ := m.handleKeyPress(msg)
+				m = updated.(Model)
+			}
+			assert.Equal(t, tt.expectedFocus, m.focusedColumn)
+			assert.Equal(t, tt.expectedOffset, m.navigationOffset)
+			assert.Equal(t, tt.expectedMode, m.IsBreadcrumbMode())
+		})
+	}
+
+	t.Run("highlights the selected level", func(t *testing.T) {
+		m := NewModel(root, 4, testCommands, 2)
+		m.width = 120
+		m.focusedColumn = 4
+		m.navigationOffset = 2
+		m = m.openBreadcrumbMode()
+		m.breadcrumbCursor = 1
+
+		segment, ok := m.selectedBreadcrumbSegment()
+		require.True(t, ok)
+		// "📁 /test/a/b/c/d" starts the path at column 5, so "b" is column 13.
+		assert.Equal(t, breadcrumbSegment{depth: 1, start: 13, end: 14}, segment)
+		renderer := NewRenderer(m, NewLayoutCalculator(120, 30, 25))
+		assert.Contains(t, renderer.renderBreadcrumbBar(), "/test/a/"+breadcrumbSelectedStyle.Render("b"))
+		assert.Contains(t, renderer.renderFooter(), BreadcrumbHelp)
+	})
+
+	t.Run("commands column has no levels", func(t *testing.T) {
+		m := NewModel(root, 4, testCommands, 2)
+		assert.False(t, m.openBreadcrumbMode().IsBreadcrumbMode())
+	})
+}
+
+// TestModel_HandleMouse_Columns tests that clicks on the rendered columns focus the column
+// and select the item under the pointer, that a second click on the selected item acts like
+// enter and that the wheel moves the selection.
+func TestModel_HandleMouse_Columns(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/repo",
+		Children: []*stack.Node{
+			{Name: "dev", Path: "/repo/dev", IsStack: true},
+			{Name: "prod", Path: "/repo/prod", IsStack: true},
+			{Name: "staging", Path: "/repo/staging", IsStack: true},
+		},
+	}
+	newModel := func() Model {
+		m := NewModel(root, 1, testCommands, 3).WithColumnSeparator("│")
+		return m.handleWindowResize(tea.WindowSizeMsg{Width: 120, Height: 30})
+	}
+	click := func(x, y int) tea.MouseMsg {
+		return tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft}
+	}
+	// locate returns the screen position of text in the rendered view of m.
+	locate := func(m Model, text string) (int, int) {
+		t.Helper()
+		for y, line := range strings.Split(m.View(), "\n") {
+			if i := strings.Index(line, text); i >= 0 {
+				return lipgloss.Width(line[:i]), y
+			}
+		}
+		require.Failf(t, "text not rendered", "%q", text)
+		return 0, 0
+	}
+	update := func(m Model, msg tea.Msg) (Model, tea.Cmd) {
+		updated, cmd := m.handleNavigationUpdate(msg)
+		return updated.(Model), cmd
+	}
+
+	t.Run("click selects a command", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "validate")
+		assert.Equal(t, HeaderHeight+BreadcrumbLineCount+DepthIndicatorLineCount+ColumnItemsOffset+2, y,
+			"the third command is on the third item row")
+
+		m, cmd := update(m, click(x, y))
+		assert.Nil(t, cmd)
+		assert.Equal(t, 0, m.focusedColumn)
+		assert.Equal(t, "validate", m.GetSelectedCommand())
+	})
+
+	t.Run("click focuses a navigation column and selects the stack", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "staging")
+
+		m, cmd := update(m, click(x+2, y))
+		assert.Nil(t, cmd)
+		assert.Equal(t, 1, m.focusedColumn)
+		assert.Equal(t, "/repo/staging", m.GetSelectedStackPath())
+		assert.False(t, m.IsConfirmed())
+
+		// The selected item of the focused column confirms like enter.
+		m, cmd = update(m, click(x, y))
+		assert.NotNil(t, cmd)
+		assert.True(t, m.IsConfirmed())
+		assert.Equal(t, "/repo/staging", m.GetSelectedStackPath())
+	})
+
+	t.Run("click on the selected item of an unfocused column only focuses it", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "dev")
+
+		m, cmd := update(m, click(x, y))
+		assert.Nil(t, cmd)
+		assert.Equal(t, 1, m.focusedColumn)
+		assert.False(t, m.IsConfirmed())
+	})
+
+	t.Run("click on a column title only focuses it", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "Level 1")
+
+		m, _ = update(m, click(x, y))
+		assert.Equal(t, 1, m.focusedColumn)
+		assert.Equal(t, "/repo/dev", m.GetSelectedStackPath())
+	})
+
+	t.Run("click below the items only focuses the column", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "staging")
+
+		m, _ = update(m, click(x, y+1))
+		assert.Equal(t, 1, m.focusedColumn)
+		assert.Equal(t, "/repo/dev", m.GetSelectedStackPath())
+	})
+
+	t.Run("other buttons are ignored", func(t *testing.T) {
+		m := newModel()
+		x, y := locate(m, "apply")
+
+		m, _ = update(m, tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonRight})
+		assert.Equal(t, "plan", m.GetSelectedCommand())
+		m, _ = update(m, tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+		assert.Equal(t, "plan", m.GetSelectedCommand())
+	})
+
+	t.Run("wheel moves the selection of the focused column", func(t *testing.T) {
+		m := newModel()
+		m.focusedColumn = 1
+
+		m, _ = update(m, tea.MouseMsg{X: 1, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+		assert.Equal(t, "/repo/prod", m.GetSelectedStackPath())
+		m, _ = update(m, tea.MouseMsg{X: 1, Y: 1, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+		assert.Equal(t, "/repo/dev", m.GetSelectedStackPath())
+	})
+}
+
+// TestModel_HandleKeyPress_ExtraArgs tests typing extra args for the selected command,
+// pre-filling them from the last run and discarding an edit with esc.
+func TestModel_HandleKeyPress_ExtraArgs(t *testing.T) {
+	root := &stack.Node{Name: "root", Path: "/repo", IsStack: true}
+	press := func(m Model, msgs ...tea.KeyMsg) Model {
+		for _, msg := range msgs {
+			updated, _ := m.handleKeyPress(msg)
+			m = updated.(Model)
+		}
+		return m
+	}
+	newModel := func() Model {
+		m := NewModel(root, 0, []string{"plan", "apply"}, 3).
+			WithLastArgs(map[string]map[string][]string{"": {"apply": {"-target=module.vpc"}}})
+		m.width = 120
+		return m
+	}
+
+	m := press(newModel(), runesMsg(KeyArgs))
+	assert.True(t, m.IsEditingArgs())
+	assert.Empty(t, m.argsInput.Value(), "plan was last run without args")
+
+	m = press(m, runesMsg("-var-file=prod.tfvars  -refresh=false"), tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, m.IsEditingArgs())
+	assert.False(t, m.IsConfirmed(), "enter in the input does not run the command")
+	assert.Equal(t, []string{"-var-file=prod.tfvars", "-refresh=false"}, m.GetExtraArgs())
+	assert.Equal(t, []string{"-var-file=prod.tfvars", "-refresh=false"}, m.pendingSelection().Args)
+
+	m = newModel()
+	m.selectedCommand = 1
+	m = press(m, runesMsg(KeyArgs))
+	assert.Equal(t, "-target=module.vpc", m.argsInput.Value(), "pre-filled with the last-run args")
+	footer := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderFooter()
+	assert.Contains(t, footer, "apply -target=module.vpc")
+	assert.Empty(t, m.GetExtraArgs(), "last-run args apply only once confirmed in the input")
+
+	m = press(m, runesMsg(" -refresh=false"), tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, m.IsEditingArgs())
+	assert.Empty(t, m.GetExtraArgs(), "esc discards the edit")
+}
+
+// TestModel_HandleKeyPress_TreeSearch tests that Ctrl+F searches stacks across the whole tree
+// and that picking a match selects it in every column.
+func TestModel_HandleKeyPress_TreeSearch(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "dev-us", Path: "/root/dev-us", IsStack: true, Depth: 1},
+			{
+				Name:  "dev",
+				Path:  "/root/dev",
+				Depth: 1,
+				Children: []*stack.Node{
+					{Name: "rds", Path: "/root/dev/rds", IsStack: true, Depth: 2},
+					{Name: "vpc", Path: "/root/dev/vpc", IsStack: true, Depth: 2},
+				},
+			},
+		},
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.handleKeyPress(msg)
+		return updated.(Model)
+	}
+	search := func(query string) Model {
+		m := NewModel(root, 2, testCommands, 3)
+		m.width, m.height, m.columnWidth, m.ready = 120, 30, 30, true
+		m = press(m, tea.KeyMsg{Type: tea.KeyCtrlF})
+		require.True(t, m.IsSearching())
+		return press(m, runesMsg(query))
+	}
+
+	t.Run("jumps to a nested match", func(t *testing.T) {
+		m := search("vpc")
+		assert.Equal(t, []string{"dev/vpc"}, m.searchMatches())
+		assert.Contains(t, m.View(), "dev/vpc")
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.False(t, m.IsSearching())
+		assert.Equal(t, []int{1, 1}, m.navState.SelectedIndices)
+		assert.Equal(t, "/root/dev/vpc", m.GetSelectedStackPath())
+		assert.Equal(t, 2, m.focusedColumn, "the match's column gains focus")
+	})
+
+	t.Run("ambiguous prefix lists every match", func(t *testing.T) {
+		m := search("dev")
+		assert.Equal(t, []string{"dev-us", "dev/rds", "dev/vpc"}, m.searchMatches())
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Equal(t, "/root/dev-us", m.GetSelectedStackPath(), "the first match does not resolve to dev")
+		assert.Equal(t, 1, m.focusedColumn)
+
+		m = search("dev")
+		m = press(m, tea.KeyMsg{Type: tea.KeyDown})
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.Equal(t, "/root/dev/rds", m.GetSelectedStackPath())
+	})
+
+	t.Run("no match keeps the search open", func(t *testing.T) {
+		m := search("prod")
+		assert.Empty(t, m.searchMatches())
+		assert.Contains(t, m.View(), "No stacks match")
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.True(t, m.IsSearching(), "enter without matches does nothing")
+		assert.Equal(t, []int{0, 0}, m.navState.SelectedIndices)
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+		assert.False(t, m.IsSearching())
+	})
+
+	t.Run("jumping clears navigation filters", func(t *testing.T) {
+		m := search("rds")
+		ti := textinput.New()
+		ti.SetValue("us")
+		m.columnFilters[1] = ti
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		assert.NotContains(t, m.columnFilters, 1)
+		assert.Equal(t, "/root/dev/rds", m.GetSelectedStackPath())
+	})
+}
+
+// TestModel_ZeroDepthNavigator tests that a tree with no navigable children shows only the
+// commands column, that navigation keys are no-ops and that enter targets the root.
+func TestModel_ZeroDepthNavigator(t *testing.T) {
+	root := &stack.Node{Name: "root", Path: "/root", IsStack: true}
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRight}, {Type: tea.KeyLeft}, {Type: tea.KeyUp}, {Type: tea.KeyDown},
+		{Type: tea.KeyPgUp}, {Type: tea.KeyPgDown}, {Type: tea.KeyHome}, {Type: tea.KeyEnd},
+		{Type: tea.KeySpace}, runesMsg(KeyVimRight), runesMsg(KeyVimLeft), runesMsg(KeyRecenter),
+		{Type: tea.KeyCtrlF}, runesMsg("vpc"), {Type: tea.KeyEnter}, {Type: tea.KeyEsc},
+	}
+
+	tests := []struct {
+		name  string
+		model Model
+	}{
+		{name: "commands", model: NewModel(root, 0, testCommands, 3)},
+		{name: "single command with skip_single_command", model: NewModel(root, 0, []string{"plan"}, 3).WithSkipSingleCommand(true)},
+		{name: "right arrow confirm", model: NewModel(root, 0, testCommands, 3).WithRightArrowConfirm(true)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var model tea.Model = tt.model
+			model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+			initial := model.View()
+			assert.NotEqual(t, ScanningStacks, initial)
+			assert.Contains(t, initial, "plan")
+
+			require.NotPanics(t, func() {
+				for _, key := range keys {
+					model, _ = model.Update(key)
+					_ = model.View()
+				}
+			})
+
+			m := model.(Model)
+			assert.Equal(t, 0, m.focusedColumn, "focus stays on the commands column")
+			assert.False(t, m.IsSearching())
+			assert.False(t, m.HasSelectedPaths())
+			assert.Equal(t, "/root", m.GetSelectedStackPath())
+
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			assert.True(t, model.(Model).IsConfirmed())
+			assert.Equal(t, "/root", model.(Model).GetSelectedStackPath())
+		})
+	}
+}
+
+// TestModel_FilterScope tests that a global filter follows focus from column to column while
+// per-column filters stay with their own column.
+func TestModel_FilterScope(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{
+				Name: "dev", Path: "/root/dev", Depth: 1,
+				Children: []*stack.Node{
+					{Name: "app", Path: "/root/dev/app", IsStack: true, Depth: 2},
+					{Name: "dev-db", Path: "/root/dev/dev-db", IsStack: true, Depth: 2},
+				},
+			},
+			{Name: "prod", Path: "/root/prod", Depth: 1},
+		},
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.handleKeyPress(msg)
+		return updated.(Model)
+	}
+	filterFirstColumn := func(scope string) Model {
+		m := NewModel(root, 2, testCommands, 3).WithFilterScope(scope)
+		m.focusedColumn = 1
+		m = press(m, runesMsg(KeySlash))
+		return press(m, runesMsg("dev"))
+	}
+
+	t.Run("global filter carries to the next column", func(t *testing.T) {
+		m := filterFirstColumn(FilterScopeGlobal)
+		m = press(m, tea.KeyMsg{Type: tea.KeyRight})
+
+		require.Equal(t, 2, m.focusedColumn)
+		assert.NotContains(t, m.columnFilters, 1, "the previous column is no longer filtered")
+		require.Contains(t, m.columnFilters, 2)
+		assert.Equal(t, "dev", m.columnFilters[2].Value())
+		assert.Equal(t, 2, m.activeFilterColumn)
+		assert.Equal(t, []string{"dev-db 📦"}, m.getFilteredNavigationItems(1))
+		assert.Equal(t, "/root/dev/dev-db", m.GetSelectedStackPath(), "selection moves to a match")
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyLeft})
+		assert.Equal(t, "dev", m.columnFilters[1].Value(), "the filter comes back")
+		assert.Len(t, m.columnFilters, 1)
+	})
+
+	t.Run("per-column filters stay with their column", func(t *testing.T) {
+		m := filterFirstColumn(FilterScopeColumn)
+		m = press(m, tea.KeyMsg{Type: tea.KeyRight})
+
+		require.Equal(t, 2, m.focusedColumn)
+		assert.Equal(t, "dev", m.columnFilters[1].Value())
+		assert.NotContains(t, m.columnFilters, 2)
+		assert.Equal(t, "/root/dev/app", m.GetSelectedStackPath())
+
+		m = press(m, runesMsg(KeySlash))
+		m = press(m, runesMsg("app"))
+		m = press(m, tea.KeyMsg{Type: tea.KeyLeft})
+		assert.Equal(t, "dev", m.columnFilters[1].Value())
+		assert.Equal(t, "app", m.columnFilters[2].Value())
+	})
+}
+
+// TestModel_StacksOnlyToggle tests that S hides the plain directories listed next to stacks,
+// moves a hidden selection to a shown stack and shows every directory again when pressed again.
+func TestModel_StacksOnlyToggle(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/repo",
+		Children: []*stack.Node{
+			{Name: "env", Path: "/repo/env", Children: []*stack.Node{{Name: "dev", Path: "/repo/env/dev", IsStack: true}}},
+			{Name: "app", Path: "/repo/app", IsStack: true},
+			{Name: "modules", Path: "/repo/modules", Children: []*stack.Node{{Name: "vpc", Path: "/repo/modules/vpc", IsStack: true}}},
+			{Name: "web", Path: "/repo/web", IsStack: true},
+		},
+	}
+	m := NewModel(root, 2, testCommands, 3)
+	m.focusedColumn = 1
+	m.width, m.height = 120, 30
+	allItems := []string{"env", "app 📦", "modules", "web 📦"}
+	require.Equal(t, allItems, m.getFilteredNavigationItems(0))
+
+	updated, _ := m.handleKeyPress(runesMsg(KeyStacksOnly))
+	m = updated.(Model)
+	assert.Equal(t, []string{"app 📦", "web 📦"}, m.getFilteredNavigationItems(0))
+	assert.Equal(t, "/repo/app", m.GetSelectedStackPath(), "the hidden selection moves to the first stack")
+	assert.Equal(t, m.Text(MsgStacksOnly), m.notice)
+
+	view := NewRenderer(m, NewLayoutCalculator(m.width, m.height, 25)).buildNavigationList(0)
+	assert.NotContains(t, view, "modules")
+	assert.Contains(t, view, "web")
+
+	// Moving skips the hidden directories.
+	m = m.handleVerticalMove(false)
+	assert.Equal(t, "/repo/web", m.GetSelectedStackPath())
+	m = m.handleVerticalMove(false)
+	assert.Equal(t, "/repo/app", m.GetSelectedStackPath(), "wraps to the first shown item")
+
+	updated, _ = m.handleKeyPress(runesMsg(KeyStacksOnly))
+	m = updated.(Model)
+	assert.Equal(t, allItems, m.getFilteredNavigationItems(0), "directories are shown again")
+	assert.Equal(t, "/repo/app", m.GetSelectedStackPath(), "the selection is kept")
+	assert.Equal(t, m.Text(MsgAllDirectories), m.notice)
+}
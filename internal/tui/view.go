@@ -13,18 +13,20 @@ func (m Model) renderNavigationView() string {
 	}
 
 	if !m.ready || m.width == 0 {
-		return Initializing
+		return m.messages.withDefaults(m.locale).Initializing
 	}
 
 	if m.navigator == nil {
 		return "Error: Navigator is not initialized (state=" + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(fmt.Sprintf("%d", m.state)) + ")"
 	}
 
-	if m.navigator.GetMaxDepth() == 0 || m.columnWidth == 0 {
-		return ScanningStacks
+	// A navigator with depth 0 (a root without navigable children) still renders: only
+	// the commands column is shown, targeting the root.
+	if m.columnWidth == 0 {
+		return m.messages.withDefaults(m.locale).ScanningStacks
 	}
 
-	layout := NewLayoutCalculator(m.width, m.height, m.columnWidth)
+	layout := NewLayoutCalculator(m.width, m.height, m.columnWidth).WithPreviewWidth(m.previewPaneWidth())
 	renderer := NewRenderer(m, layout)
 
 	return renderer.Render()
@@ -46,8 +48,19 @@ func NewRenderer(model Model, layout *LayoutCalculator) *Renderer {
 
 // Render builds the complete UI view.
 func (r *Renderer) Render() string {
-	columns := r.renderColumnsWithArrows()
-	content := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	var content string
+	switch {
+	case r.model.searching:
+		content = r.renderSearchOverlay()
+	case r.model.debugOverlay:
+		content = r.renderDebugOverlay()
+	default:
+		columns := r.renderColumnsWithArrows()
+		if r.layout.GetPreviewWidth() > 0 {
+			columns = append(columns, r.renderPreviewPane())
+		}
+		content = lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	}
 
 	header := r.renderHeader()
 	breadcrumbBar := r.renderBreadcrumbBar()
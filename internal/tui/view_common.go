@@ -4,13 +4,15 @@ import (
 	"fmt"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/israoo/terrax/internal/bounds"
 )
 
 // LayoutCalculator handles all layout dimension calculations.
 type LayoutCalculator struct {
-	width       int
-	height      int
-	columnWidth int
+	width        int
+	height       int
+	columnWidth  int
+	previewWidth int // Width reserved for the preview pane (0 = hidden)
 }
 
 // NewLayoutCalculator creates a new layout calculator.
@@ -47,41 +49,193 @@ func (lc *LayoutCalculator) GetColumnWidth() int {
 	return lc.columnWidth
 }
 
-// renderHeader renders the header bar.
+// WithPreviewWidth reserves width for the preview pane beside the columns (0 = hidden).
+func (lc *LayoutCalculator) WithPreviewWidth(width int) *LayoutCalculator {
+	lc.previewWidth = width
+	return lc
+}
+
+// GetPreviewWidth returns the width of the preview pane (0 when hidden).
+func (lc *LayoutCalculator) GetPreviewWidth() int {
+	return lc.previewWidth
+}
+
+// renderHeader renders the header bar, with the checked-out branch when there is one.
 func (r *Renderer) renderHeader() string {
-	return headerStyle.Width(r.model.width).Render("🌍 " + AppTitle)
+	title := "🌍 " + r.model.Text(MsgAppTitle)
+	if r.model.branch != "" {
+		title += "  ⎇ " + r.model.branch
+	}
+	return r.model.theme.header().Width(r.model.width).Render(title)
 }
 
 // renderBreadcrumbBar renders the navigation context bar below the header.
 // When the path is too long it truncates from the left, keeping the deepest
 // (most relevant) portion visible and prepending "...".
+// With breadcrumbCmd the selected command is shown before the path and never truncated.
+// In breadcrumb mode the name of the selected level is highlighted.
 func (r *Renderer) renderBreadcrumbBar() string {
-	navPath := r.model.getCurrentNavigationPath()
+	prefix, navPath, suffix, cut := r.model.breadcrumbPath()
+	if cut > 0 {
+		// Keep the tail; prepend ellipsis so the deepest path segment is always visible.
+		navPath = "..." + navPath[cut:]
+	}
+
+	text := "📁 " + prefix + navPath + suffix
+	if segment, ok := r.model.selectedBreadcrumbSegment(); ok {
+		// Segment columns match byte offsets past the icon: the prefix and path are ASCII.
+		column := breadcrumbLeftPadding + breadcrumbIconWidth
+		head := "📁 " + (prefix + navPath)[:segment.start-column]
+		name := (prefix + navPath)[segment.start-column : segment.end-column]
+		tail := (prefix + navPath)[segment.end-column:] + suffix
+		plain := breadcrumbBarStyle.UnsetPadding()
+		text = plain.Render(head) + breadcrumbSelectedStyle.Render(name) + plain.Render(tail)
+	}
+	return breadcrumbBarStyle.Width(r.model.width).Render(text)
+}
+
+// selectedBreadcrumbSegment returns the segment of the level selected in breadcrumb mode,
+// and false outside the mode or when truncation hides its name.
+func (m Model) selectedBreadcrumbSegment() (breadcrumbSegment, bool) {
+	if !m.breadcrumbMode {
+		return breadcrumbSegment{}, false
+	}
+	for _, segment := range m.breadcrumbSegments() {
+		if segment.depth == m.breadcrumbCursor {
+			return segment, true
+		}
+	}
+	return breadcrumbSegment{}, false
+}
+
+// Layout of the breadcrumb bar: breadcrumbBarStyle has Padding(0, 2), and the "📁 " icon
+// takes 3 terminal columns (emoji = 2, space = 1).
+const (
+	breadcrumbLeftPadding = 2
+	breadcrumbHPadding    = 4
+	breadcrumbIconWidth   = 3
+
+	breadcrumbInfoSeparator = "  — " // Between the path and the focused stack's metadata
+)
 
-	// breadcrumbBarStyle has Padding(0, 2) → 4 chars consumed by padding.
-	// "📁 " prefix: emoji = 2 terminal columns, space = 1 → 3 chars.
-	const iconWidth = 3
-	const styleHPadding = 4
-	maxPathWidth := r.model.width - styleHPadding - iconWidth
+// breadcrumbPath returns the command prefix, full navigation path and stack metadata suffix
+// of the breadcrumb bar, and how many leading bytes of the path are replaced by an ellipsis
+// to fit it. The suffix is dropped when it would leave the path too little room.
+func (m Model) breadcrumbPath() (prefix, navPath, suffix string, cut int) {
+	navPath = m.getCurrentNavigationPath()
+	if m.breadcrumbCmd {
+		prefix = m.GetSelectedCommandLabel() + " @ "
+	}
+
+	maxPathWidth := m.width - breadcrumbHPadding - breadcrumbIconWidth - len(prefix)
+	if info := m.focusedStackInfo(); info != "" {
+		suffix = breadcrumbInfoSeparator + info
+		if maxPathWidth-lipgloss.Width(suffix) >= min(len(navPath), MinItemTextWidth) {
+			maxPathWidth -= lipgloss.Width(suffix)
+		} else {
+			suffix = ""
+		}
+	}
 	if maxPathWidth < 1 {
 		maxPathWidth = 1
 	}
-
 	if len(navPath) > maxPathWidth {
-		// Keep the tail; prepend ellipsis so the deepest path segment is always visible.
-		navPath = "..." + navPath[len(navPath)-(maxPathWidth-EllipsisWidth):]
+		cut = len(navPath) - (maxPathWidth - EllipsisWidth)
+	}
+	return prefix, navPath, suffix, cut
+}
+
+// breadcrumbSegment is the span of a navigation level's name in the breadcrumb bar.
+type breadcrumbSegment struct {
+	depth int // Navigation level (0-based) the name was selected in
+	start int // First terminal column of the name
+	end   int // Terminal column just past the name
+}
+
+// breadcrumbSegments returns where each navigation level's name is drawn in the breadcrumb
+// bar, leaving out names hidden by truncation. The scan root itself is not a level.
+func (m Model) breadcrumbSegments() []breadcrumbSegment {
+	if m.navigator == nil || (m.isCommandsColumnFocused() && m.isTargetingIncludeRoot()) {
+		return nil
+	}
+	prefix, _, _, cut := m.breadcrumbPath()
+	column := breadcrumbLeftPadding + breadcrumbIconWidth + len(prefix)
+	if cut > 0 {
+		column += EllipsisWidth - cut
 	}
 
-	return breadcrumbBarStyle.Width(r.model.width).Render("📁 " + navPath)
+	var segments []breadcrumbSegment
+	parentPath := m.navigator.GetNavigationPath(m.navState, -1)
+	for depth := 0; depth <= m.getNavigationDepth(); depth++ {
+		path := m.navigator.GetNavigationPath(m.navState, depth)
+		start := len(parentPath) + len("/")
+		if len(path) > start && start >= cut {
+			segments = append(segments, breadcrumbSegment{
+				depth: depth,
+				start: column + start,
+				end:   column + len(path),
+			})
+		}
+		parentPath = path
+	}
+	return segments
 }
 
-// renderFooter renders the footer with help text or marks help text when selections are active.
+// renderFooter renders the footer with the extra args input while it has focus, the search
+// or breadcrumb keys while either has focus, otherwise the status of the focused column followed by a pending
+// notice, help text, or marks help text when selections are active.
 func (r *Renderer) renderFooter() string {
-	if r.model.HasSelectedPaths() {
-		text := fmt.Sprintf(HelpTextWithMarks, len(r.model.selectedPaths))
-		return footerStyle.Render(text)
+	if r.model.editingArgs {
+		return footerStyle.UnsetItalic().Render(r.model.argsInput.View())
+	}
+	if r.model.searching {
+		return footerStyle.Render(r.model.Text(MsgSearchHelpText))
+	}
+	if r.model.breadcrumbMode {
+		return footerStyle.Render(r.model.Text(MsgBreadcrumbHelpText))
+	}
+
+	text := r.model.Text(MsgHelpText)
+	switch {
+	case r.model.notice != "":
+		text = r.model.notice
+	case r.model.HasSelectedPaths():
+		text = fmt.Sprintf(r.model.Text(MsgHelpTextWithMarks), len(r.model.selectedPaths))
+	}
+	if status := r.renderStatus(); status != "" {
+		text = status + footerSeparator + text
+	}
+	return footerStyle.Render(text)
+}
+
+// footerSeparator separates the status of the focused column from the rest of the footer.
+const footerSeparator = " | "
+
+// renderStatus returns the position of the selection in the focused column's filtered items
+// (e.g. "3/27"), followed by the column's filter text while one is set.
+func (r *Renderer) renderStatus() string {
+	m := r.model
+	var items []string
+	position := -1
+	if m.isCommandsColumnFocused() {
+		items = m.getFilteredCommands()
+		position = findFilteredIndex(m.commands, items, m.selectedCommand)
+	} else if depth := m.focusedColumn - 1; m.navigator != nil && bounds.InRange(depth, len(m.navState.Columns)) {
+		items = m.getFilteredNavigationItems(depth)
+		position = findFilteredIndex(m.navState.Columns[depth], items, m.navState.SelectedIndices[depth])
+	}
+	if len(items) == 0 {
+		return ""
+	}
+
+	status := fmt.Sprintf("%d/%d", position+1, len(items))
+	if position < 0 {
+		status = fmt.Sprintf("-/%d", len(items))
+	}
+	if filter, exists := m.columnFilters[m.focusedColumn]; exists && filter.Value() != "" {
+		status += " · " + fmt.Sprintf(m.Text(MsgStatusFilter), filter.Value())
 	}
-	return footerStyle.Render(HelpText)
+	return status
 }
 
 // renderArrowIndicator renders an arrow indicator for overflow.
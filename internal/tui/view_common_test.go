@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/israoo/terrax/internal/stack"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -285,6 +288,19 @@ func TestRenderer_RenderHeader(t *testing.T) {
 	assert.NotEmpty(t, header)
 }
 
+// TestRenderer_RenderHeader_Branch tests that the header shows the branch only when known.
+func TestRenderer_RenderHeader_Branch(t *testing.T) {
+	m := Model{width: 120}.WithBranch("feature/vpc")
+	header := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderHeader()
+	assert.Contains(t, header, AppTitle)
+	assert.Contains(t, header, "⎇ feature/vpc")
+
+	m = Model{width: 120}.WithBranch("")
+	header = NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderHeader()
+	assert.Contains(t, header, AppTitle)
+	assert.NotContains(t, header, "⎇")
+}
+
 // TestRenderer_RenderBreadcrumbBar tests breadcrumb bar rendering.
 func TestRenderer_RenderBreadcrumbBar(t *testing.T) {
 	root := &stack.Node{
@@ -315,6 +331,76 @@ func TestRenderer_RenderBreadcrumbBar(t *testing.T) {
 	assert.Contains(t, breadcrumb, "📁")
 }
 
+// TestRenderer_RenderBreadcrumbBar_Command tests that the breadcrumb shows the selected
+// command before the path only when enabled.
+func TestRenderer_RenderBreadcrumbBar_Command(t *testing.T) {
+	root := &stack.Node{
+		Name:     "root",
+		Path:     "/repo",
+		Children: []*stack.Node{{Name: "dev", Path: "/repo/dev", IsStack: true, Depth: 1}},
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		expect  string
+	}{
+		{name: "enabled", enabled: true, expect: "📁 apply @ /repo"},
+		{name: "disabled", enabled: false, expect: "📁 /repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 1, []string{"plan", "apply"}, 3).WithBreadcrumbCommand(tt.enabled)
+			m.width = 120
+			m.selectedCommand = 1
+
+			breadcrumb := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).renderBreadcrumbBar()
+
+			assert.Contains(t, breadcrumb, tt.expect)
+			if !tt.enabled {
+				assert.NotContains(t, breadcrumb, "apply")
+			}
+		})
+	}
+}
+
+// TestRenderer_RenderBreadcrumbBar_StackMetadata tests that the breadcrumb shows the
+// description and owner of a focused stack with a metadata file, and only the path otherwise.
+func TestRenderer_RenderBreadcrumbBar_StackMetadata(t *testing.T) {
+	tmpDir := t.TempDir()
+	vpcPath := filepath.Join(tmpDir, "vpc")
+	dnsPath := filepath.Join(tmpDir, "dns")
+	require.NoError(t, os.MkdirAll(vpcPath, 0755))
+	require.NoError(t, os.MkdirAll(dnsPath, 0755))
+	metadata := "description: Shared VPC\nowner: platform-team\n"
+	require.NoError(t, os.WriteFile(filepath.Join(vpcPath, stack.MetadataFileName), []byte(metadata), 0644))
+
+	root := &stack.Node{
+		Name: "root",
+		Path: tmpDir,
+		Children: []*stack.Node{
+			{Name: "vpc", Path: vpcPath, IsStack: true, Depth: 1},
+			{Name: "dns", Path: dnsPath, IsStack: true, Depth: 1},
+		},
+	}
+	newModel := func(selected int) Model {
+		m := NewModel(root, 1, []string{"plan"}, 3)
+		m.width = 120
+		m.focusedColumn = 1
+		m.navState.SelectedIndices[0] = selected
+		m.navigator.PropagateSelection(m.navState)
+		return m
+	}
+
+	breadcrumb := NewRenderer(newModel(0), NewLayoutCalculator(120, 30, 25)).renderBreadcrumbBar()
+	assert.Contains(t, breadcrumb, breadcrumbInfoSeparator+"Shared VPC · owner: platform-team")
+
+	breadcrumb = NewRenderer(newModel(1), NewLayoutCalculator(120, 30, 25)).renderBreadcrumbBar()
+	assert.Contains(t, breadcrumb, "dns")
+	assert.NotContains(t, breadcrumb, breadcrumbInfoSeparator)
+}
+
 // TestRenderer_RenderFooter tests footer rendering.
 func TestRenderer_RenderFooter(t *testing.T) {
 	m := Model{}
@@ -350,6 +436,40 @@ func TestRenderer_RenderFooter_WithMarks(t *testing.T) {
 	assert.NotContains(t, footer, HelpText, "footer should not show default help text when marks are active")
 }
 
+// TestRenderer_RenderFooter_Status tests the focused column's position, item count and
+// filter shown in the footer.
+func TestRenderer_RenderFooter_Status(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/repo",
+		Children: []*stack.Node{
+			{Name: "dev", Path: "/repo/dev"},
+			{Name: "prod", Path: "/repo/prod"},
+			{Name: "qa", Path: "/repo/qa"},
+		},
+	}
+	m := NewModel(root, 1, []string{"plan", "apply", "destroy", "init"}, 3)
+	m.width = 120
+	m.height = 30
+	m.columnWidth = 25
+	m.ready = true
+	m.selectedCommand = 2
+
+	footer := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).renderFooter()
+	assert.Contains(t, footer, "3/4", "footer should show the selected command's position and count")
+	assert.Contains(t, footer, HelpText)
+
+	m.focusedColumn = 1
+	m.navState.SelectedIndices[0] = 1
+	filter := textinput.New()
+	filter.SetValue("d")
+	m.columnFilters[1] = filter
+
+	footer = NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).renderFooter()
+	assert.Contains(t, footer, "2/2", "footer should count only the items matching the filter")
+	assert.Contains(t, footer, "filter: d")
+}
+
 // TestRenderer_RenderArrowIndicator tests arrow indicator rendering.
 func TestRenderer_RenderArrowIndicator(t *testing.T) {
 	tests := []struct {
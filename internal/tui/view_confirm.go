@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	confirmPanelStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(primaryColor).
+				Padding(1, 2)
+
+	confirmLabelStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(secondaryColor).
+				Width(12)
+
+	confirmPromptStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(dangerColor)
+)
+
+// renderConfirmView renders the execution summary shown before running the selection,
+// ending with a yes/no prompt for commands in requireConfirmation.
+func (m Model) renderConfirmView() string {
+	d := m.confirmDetails
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Binary", d.Binary},
+		{"Command", d.Command},
+		{"Stacks", strings.Join(d.StackPaths, "\n")},
+		{"Extra args", strings.Join(d.ExtraArgs, " ")},
+		{"Env vars", strings.Join(d.EnvVarNames, ", ")},
+		{"Branch", d.Branch},
+	}
+
+	lines := []string{titleStyle.Render(m.Text(MsgConfirmTitle)), ""}
+	for _, row := range rows {
+		value := row.value
+		if value == "" {
+			value = NoItemSelected
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, confirmLabelStyle.Render(row.label), value))
+	}
+	helpText := m.Text(MsgConfirmHelpText)
+	if m.awaitingYes {
+		prompt := fmt.Sprintf(m.Text(MsgConfirmPrompt), d.Command, strings.Join(d.StackPaths, ", "))
+		lines = append(lines, "", confirmPromptStyle.Render(prompt))
+		helpText = m.Text(MsgConfirmYesNoHelp)
+	}
+	panel := confirmPanelStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	header := m.theme.header().Width(m.width).Render(m.Text(MsgAppTitle))
+	footer := footerStyle.Render(helpText)
+	return lipgloss.JoinVertical(lipgloss.Left, header, panel, footer)
+}
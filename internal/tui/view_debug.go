@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// renderDebugOverlay renders the model's internal navigation state in place of the
+// columns, so users can copy it into bug reports.
+func (r *Renderer) renderDebugOverlay() string {
+	m := r.model
+	lines := []string{
+		titleStyle.Render("Debug (g d to close)"),
+		fmt.Sprintf("focusedColumn:      %d", m.focusedColumn),
+		fmt.Sprintf("navigationOffset:   %d", m.navigationOffset),
+		fmt.Sprintf("selectedIndices:    %v", m.navState.SelectedIndices),
+		fmt.Sprintf("scrollOffsets:      %v", m.scrollOffsets),
+		fmt.Sprintf("activeFilterColumn: %d", m.activeFilterColumn),
+		fmt.Sprintf("path:               %s", m.GetSelectedStackPath()),
+		fmt.Sprintf("node:               %s", m.debugNodeSummary()),
+	}
+
+	// Subtract the panel border so the panel fills the columns area exactly.
+	return debugOverlayStyle.
+		Width(max(m.width-2, 0)).
+		Height(max(r.layout.GetContentHeight()-2, 0)).
+		Render(strings.Join(lines, "\n"))
+}
+
+// debugNodeSummary describes the node the current selection resolves to.
+func (m Model) debugNodeSummary() string {
+	node := m.focusedNode()
+	if node == nil {
+		return NoItemSelected
+	}
+
+	kind := "directory"
+	if node.IsStack {
+		kind = "stack"
+	}
+	return fmt.Sprintf("%s (%s, %d children)", node.Name, kind, len(node.Children))
+}
@@ -2,40 +2,140 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/israoo/terrax/internal/history"
 )
 
+// HistoryTableStyle configures the appearance of the history table.
+// Empty color fields fall back to the built-in theme colors.
+type HistoryTableStyle struct {
+	Striped          bool   // Alternate row backgrounds (zebra striping) for readability.
+	StripeColor      string // Background color applied to every other row when Striped is true.
+	CursorForeground string // Foreground color of the highlighted cursor row.
+	CursorBackground string // Background color of the highlighted cursor row.
+
+	// ChangesExitCodes maps a command to the exit codes that mean "changes present"
+	// rather than failure (e.g. plan -detailed-exitcode returns 2). Those codes are shown
+	// with a ± icon. A nil map uses defaultChangesExitCodes; an empty map disables it.
+	ChangesExitCodes map[string][]int
+}
+
+// defaultChangesExitCodes is used when HistoryTableStyle.ChangesExitCodes is nil.
+var defaultChangesExitCodes = map[string][]int{
+	"plan": {2},
+}
+
 // historyTableStyles holds all the lipgloss styles for the history table
 type historyTableStyles struct {
 	headerRow   lipgloss.Style
 	cursor      lipgloss.Style
 	normalRow   lipgloss.Style
+	stripedRow  lipgloss.Style
+	striped     bool
 	successIcon lipgloss.Style
 	errorIcon   lipgloss.Style
+	changesIcon lipgloss.Style
+
+	changesExitCodes map[string][]int
 }
 
-// newHistoryTableStyles creates the styles for the history table
-func newHistoryTableStyles() historyTableStyles {
+// newHistoryTableStyles creates the styles for the history table from the given configuration,
+// taking the colors it leaves unset from theme.
+func newHistoryTableStyles(cfg HistoryTableStyle, theme Theme) historyTableStyles {
+	cursorForeground := theme.SelectedItem
+	if cfg.CursorForeground != "" {
+		cursorForeground = lipgloss.Color(cfg.CursorForeground)
+	}
+	cursorBackground := lipgloss.Color("#3A3A3A")
+	if cfg.CursorBackground != "" {
+		cursorBackground = lipgloss.Color(cfg.CursorBackground)
+	}
+	stripeColor := historyStripeColor
+	if cfg.StripeColor != "" {
+		stripeColor = lipgloss.Color(cfg.StripeColor)
+	}
+	changesExitCodes := cfg.ChangesExitCodes
+	if changesExitCodes == nil {
+		changesExitCodes = defaultChangesExitCodes
+	}
+
 	return historyTableStyles{
 		headerRow: lipgloss.NewStyle().
 			Bold(true).
 			Foreground(secondaryColor),
 		cursor: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(accentColor).
-			Background(lipgloss.Color("#3A3A3A")),
+			Foreground(cursorForeground).
+			Background(cursorBackground),
 		normalRow: lipgloss.NewStyle().
 			Foreground(textColor),
+		stripedRow: lipgloss.NewStyle().
+			Foreground(textColor).
+			Background(stripeColor),
+		striped: cfg.Striped,
 		successIcon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#00FF00")).
+			Foreground(theme.Success).
 			Bold(true),
 		errorIcon: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
+			Foreground(theme.Error).
+			Bold(true),
+		changesIcon: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")).
 			Bold(true),
+		changesExitCodes: changesExitCodes,
+	}
+}
+
+// isChangesExitCode reports whether exitCode means "changes present" for command.
+func (s historyTableStyles) isChangesExitCode(command string, exitCode int) bool {
+	return slices.Contains(s.changesExitCodes[command], exitCode)
+}
+
+// exitCodeIcon returns the unstyled icon for a run of command that exited with exitCode:
+// ✓ for success, ± for a "changes present" code and ✗ for failure.
+func (s historyTableStyles) exitCodeIcon(command string, exitCode int) string {
+	switch {
+	case exitCode == 0:
+		return "✓"
+	case s.isChangesExitCode(command, exitCode):
+		return "±"
+	default:
+		return "✗"
+	}
+}
+
+// outcomeIndicator returns the styled icon summarizing entry's outcome, as shown next to
+// stacks in the navigation columns. A successful run reporting no changes shows a dim =.
+func (s historyTableStyles) outcomeIndicator(entry history.ExecutionLogEntry) string {
+	if entry.HasNoChanges() {
+		return dividerStyle.Render("=")
+	}
+	icon := s.exitCodeIcon(entry.Command, entry.ExitCode)
+	switch icon {
+	case "✓":
+		return s.successIcon.Render(icon)
+	case "±":
+		return s.changesIcon.Render(icon)
+	default:
+		return s.errorIcon.Render(icon)
+	}
+}
+
+// rowStyle returns the style for the row at rowIndex (absolute position in the history list).
+// The cursor style always wins; otherwise odd rows are striped when striping is enabled.
+// Parity is based on the absolute index so stripes don't shift while scrolling.
+func (s historyTableStyles) rowStyle(rowIndex int, isCursor bool) lipgloss.Style {
+	if isCursor {
+		return s.cursor
+	}
+	if s.striped && rowIndex%2 == 1 {
+		return s.stripedRow
 	}
+	return s.normalRow
 }
 
 // historyTableColumns defines the column widths for the history table
@@ -46,6 +146,7 @@ type historyTableColumns struct {
 	stackPath int
 	exitCode  int
 	duration  int
+	changes   int
 	cursor    int
 }
 
@@ -57,11 +158,12 @@ func newHistoryTableColumns(terminalWidth int) historyTableColumns {
 	command := 8
 	exitCode := 9
 	duration := 10
-	cursor := 2  // "▶ " prefix
-	spaces := 10 // Spacing between columns (2 spaces * 5 separators)
+	changes := 14 // "+999 ~999 -999"
+	cursor := 2   // "▶ " prefix
+	spaces := 12  // Spacing between columns (2 spaces * 6 separators)
 
 	// Calculate remaining width for stackPath
-	fixedWidths := id + timestamp + command + exitCode + duration + cursor + spaces
+	fixedWidths := id + timestamp + command + exitCode + duration + changes + cursor + spaces
 	stackPath := terminalWidth - fixedWidths
 
 	// Ensure minimum width for readability
@@ -76,21 +178,25 @@ func newHistoryTableColumns(terminalWidth int) historyTableColumns {
 		stackPath: stackPath,
 		exitCode:  exitCode,
 		duration:  duration,
+		changes:   changes,
 		cursor:    cursor,
 	}
 }
 
-// formatExitCode formats the exit code without applying lipgloss styles
-// to avoid breaking the row's background when the cursor style is applied
-func formatExitCode(exitCode int, styles historyTableStyles, width int) string {
-	var icon string
-	if exitCode == 0 {
-		icon = "✓"
-	} else {
-		icon = "✗"
+// formatEntryExitCode formats the entry's exit code like formatExitCode, except that a
+// successful run reporting no changes is shown with a neutral = instead of ✓.
+func formatEntryExitCode(entry history.ExecutionLogEntry, styles historyTableStyles, width int) string {
+	if !entry.HasNoChanges() {
+		return formatExitCode(entry.Command, entry.ExitCode, styles, width)
 	}
+	return fmt.Sprintf("%-*s", width, "= 0")
+}
 
-	display := fmt.Sprintf("%s %d", icon, exitCode)
+// formatExitCode formats the exit code without applying lipgloss styles
+// to avoid breaking the row's background when the cursor style is applied.
+// Exit codes configured as "changes present" for command are shown with ± instead of ✗.
+func formatExitCode(command string, exitCode int, styles historyTableStyles, width int) string {
+	display := fmt.Sprintf("%s %d", styles.exitCodeIcon(command, exitCode), exitCode)
 
 	// Calculate padding: icon (1) + space (1) + number length
 	visualWidth := 2 + len(fmt.Sprintf("%d", exitCode))
@@ -129,17 +235,18 @@ func calculateVisibleRange(totalItems, cursorPos, contentHeight int) (start, end
 	return start, end
 }
 
-// buildHistoryTableHeader builds the table header row
-func buildHistoryTableHeader(cols historyTableColumns, style lipgloss.Style) string {
+// buildHistoryTableHeader builds the table header row with labels in the given locale
+func buildHistoryTableHeader(cols historyTableColumns, style lipgloss.Style, locale string) string {
 	return style.Render(
 		fmt.Sprintf(
-			"  %-*s  %-*s  %-*s  %-*s  %-*s  %s",
+			"  %-*s  %-*s  %-*s  %-*s  %-*s  %-*s  %s",
 			cols.id, "#",
-			cols.timestamp, "Timestamp",
-			cols.command, "Command",
-			cols.stackPath, "Stack Path",
-			cols.exitCode, "Exit Code",
-			"Duration",
+			cols.timestamp, lookupMessage(locale, MsgHistoryTimestamp),
+			cols.command, lookupMessage(locale, MsgHistoryCommand),
+			cols.stackPath, lookupMessage(locale, MsgHistoryStackPath),
+			cols.exitCode, lookupMessage(locale, MsgHistoryExitCode),
+			cols.duration, lookupMessage(locale, MsgHistoryDuration),
+			lookupMessage(locale, MsgHistoryChanges),
 		),
 	)
 }
@@ -147,70 +254,178 @@ func buildHistoryTableHeader(cols historyTableColumns, style lipgloss.Style) str
 // buildHistoryTableRow builds a single data row for the history table
 // displayID is the sequential ID to show (1, 2, 3...) instead of the actual entry ID
 func buildHistoryTableRow(entry history.ExecutionLogEntry, displayID int, cols historyTableColumns, styles historyTableStyles) string {
-	exitCodeStr := formatExitCode(entry.ExitCode, styles, cols.exitCode)
-	timestampStr := entry.Timestamp.Format("2006-01-02 15:04:05")
-	durationStr := fmt.Sprintf("%.2fs", entry.DurationS)
-
-	// Truncate stack path if it exceeds the column width
-	// Show the end of the path (most relevant) instead of the beginning
+	// Show the stack path with the note, if any
 	stackPathDisplay := entry.StackPath
-	if len(stackPathDisplay) > cols.stackPath {
-		if cols.stackPath > 3 {
-			// Take the last (cols.stackPath - 3) characters and prepend "..."
-			stackPathDisplay = "..." + stackPathDisplay[len(stackPathDisplay)-(cols.stackPath-3):]
-		} else {
-			// If width is too small, just take the last characters
-			stackPathDisplay = stackPathDisplay[len(stackPathDisplay)-cols.stackPath:]
-		}
+	if entry.Note != "" {
+		stackPathDisplay += " [" + entry.Note + "]"
+	}
+	return formatHistoryTableRow(entry, strconv.Itoa(displayID), truncatePathStart(stackPathDisplay, cols.stackPath), cols, styles)
+}
+
+// buildHistoryGroupRow builds the row standing for a stack in the grouped history view:
+// the stack path with an expand marker and run count, next to the stack's latest run.
+func buildHistoryGroupRow(group history.StackGroup, expanded bool, displayID int, cols historyTableColumns, styles historyTableStyles) string {
+	marker := "▸ "
+	if expanded {
+		marker = "▾ "
+	}
+	label := fmt.Sprintf("%s (%d)", group.StackPath, len(group.Entries))
+	stackPathDisplay := marker + truncatePathStart(label, cols.stackPath-2)
+	return formatHistoryTableRow(group.Latest(), strconv.Itoa(displayID), stackPathDisplay, cols, styles)
+}
+
+// buildHistoryRunRow builds the row for one run listed under an expanded stack group.
+// The stack path is left to the group row; only the run's note, if any, is shown.
+func buildHistoryRunRow(entry history.ExecutionLogEntry, cols historyTableColumns, styles historyTableStyles) string {
+	stackPathDisplay := "  └"
+	if entry.Note != "" {
+		stackPathDisplay += " " + truncatePathStart("["+entry.Note+"]", cols.stackPath-4)
 	}
+	return formatHistoryTableRow(entry, "", stackPathDisplay, cols, styles)
+}
+
+// formatHistoryTableRow lays out the cells of a history table row. stackPathDisplay must
+// already fit the stack path column.
+func formatHistoryTableRow(entry history.ExecutionLogEntry, idDisplay, stackPathDisplay string, cols historyTableColumns, styles historyTableStyles) string {
+	exitCodeStr := formatEntryExitCode(entry, styles, cols.exitCode)
+	timestampStr := entry.Timestamp.Format("2006-01-02 15:04:05")
+	durationStr := fmt.Sprintf("%.2fs", entry.DurationS)
 
 	return fmt.Sprintf(
-		"%-*d  %-*s  %-*s  %-*s  %s  %s",
-		cols.id, displayID,
+		"%-*s  %-*s  %-*s  %-*s  %s  %-*s  %s",
+		cols.id, idDisplay,
 		cols.timestamp, timestampStr,
 		cols.command, entry.Command,
 		cols.stackPath, stackPathDisplay,
 		exitCodeStr,
-		durationStr,
+		cols.duration, durationStr,
+		formatChangeCounts(entry.Changes, styles),
 	)
 }
 
+// formatChangeCounts formats the resources a run added, changed and destroyed as
+// "+a ~c -d", coloring the non-zero counts. Runs without a change report show nothing.
+func formatChangeCounts(changes *history.ChangeCounts, styles historyTableStyles) string {
+	if changes == nil {
+		return ""
+	}
+	count := func(style lipgloss.Style, sign string, n int) string {
+		text := sign + strconv.Itoa(n)
+		if n == 0 {
+			return text
+		}
+		return foregroundOnly(style, text)
+	}
+	return strings.Join([]string{
+		count(styles.successIcon, "+", changes.Add),
+		count(styles.changesIcon, "~", changes.Change),
+		count(styles.errorIcon, "-", changes.Destroy),
+	}, " ")
+}
+
+// foregroundOnly renders text with style, ending it with resets for the foreground and bold
+// only instead of a full reset, so the row style's background continues behind the text.
+func foregroundOnly(style lipgloss.Style, text string) string {
+	const fullReset = "\x1b[0m"
+	rendered := style.Render(text)
+	if !strings.HasSuffix(rendered, fullReset) {
+		return rendered // No color profile; nothing to reset.
+	}
+	return strings.TrimSuffix(rendered, fullReset) + "\x1b[22;39m"
+}
+
+// truncatePathStart shortens path to width by dropping its beginning, keeping the end of
+// the path (most relevant) behind "...".
+func truncatePathStart(path string, width int) string {
+	if len(path) <= width {
+		return path
+	}
+	if width > 3 {
+		// Take the last (width - 3) characters and prepend "..."
+		return "..." + path[len(path)-(width-3):]
+	}
+	// If width is too small, just take the last characters
+	return path[len(path)-max(width, 0):]
+}
+
+// FormatHistoryTableHeader renders the history table header and separator for
+// non-interactive output (e.g. terrax history tail), using the same column layout as the TUI.
+func FormatHistoryTableHeader(width int) string {
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
+	cols := newHistoryTableColumns(width)
+	separator := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", width))
+	return lipgloss.JoinVertical(lipgloss.Left, buildHistoryTableHeader(cols, styles.headerRow, DefaultLocale), separator)
+}
+
+// FormatHistoryTableRow renders a single history entry as a table row for non-interactive output.
+// The row is indented to line up with FormatHistoryTableHeader, matching unselected TUI rows.
+func FormatHistoryTableRow(entry history.ExecutionLogEntry, displayID, width int) string {
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
+	cols := newHistoryTableColumns(width)
+	return "  " + buildHistoryTableRow(entry, displayID, cols, styles)
+}
+
 // renderHistoryView renders the history viewing interface as a formatted table.
 func (m Model) renderHistoryView() string {
 	if !m.ready || m.width == 0 {
-		return Initializing
+		return m.messages.withDefaults(m.locale).Initializing
 	}
 
-	header := headerStyle.Width(m.width).Render("📜 Execution History")
+	title := "📜 " + m.Text(MsgHistoryTitle)
+	if m.historyFailedOnly {
+		title += " (" + m.Text(MsgHistoryFailedOnly) + ")"
+	}
+	header := m.theme.header().Width(m.width).Render(title)
 
-	if len(m.history) == 0 {
+	if len(m.historyAll) == 0 {
 		return m.renderEmptyHistory(header)
 	}
 
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(m.historyTableStyle, m.theme)
 	cols := newHistoryTableColumns(m.width)
 
-	tableHeader := buildHistoryTableHeader(cols, styles.headerRow)
+	tableHeader := buildHistoryTableHeader(cols, styles.headerRow, m.locale)
 	separator := lipgloss.NewStyle().Foreground(dimColor).Render(strings.Repeat("─", m.width))
 
 	contentHeight := m.height - HeaderHeight - FooterHeight - 6
-	startIdx, endIdx := calculateVisibleRange(len(m.history), m.historyCursor, contentHeight)
-
-	rows := m.buildHistoryTableRows(startIdx, endIdx, cols, styles)
+	parts := []string{header, ""}
+	if banner := m.renderProjectSwitchBanner(); banner != "" {
+		parts = append(parts, banner)
+		contentHeight--
+	}
+	if m.historyFiltering || m.historyFilter.Value() != "" {
+		parts = append(parts, m.historyFilter.View())
+		contentHeight--
+	}
+	startIdx, endIdx := calculateVisibleRange(m.historyRowCount(), m.historyCursor, contentHeight)
+
+	var rows []string
+	switch {
+	case len(m.history) == 0:
+		rows = []string{lipgloss.NewStyle().Foreground(dimColor).Render("  No entries match the filter.")}
+	case m.historyGrouped:
+		rows = m.buildHistoryGroupRows(startIdx, endIdx, cols, styles)
+	default:
+		rows = m.buildHistoryTableRows(startIdx, endIdx, cols, styles)
+	}
 	tableContent := lipgloss.JoinVertical(lipgloss.Left, rows...)
 
 	footer := m.buildHistoryFooter(startIdx, endIdx)
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		"",
-		tableHeader,
-		separator,
-		tableContent,
-		"",
-		footer,
-	)
+	parts = append(parts, tableHeader, separator, tableContent, "", footer)
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
+}
+
+// renderProjectSwitchBanner renders a notice when the last run happened in a different
+// project, which explains an empty or unfamiliar history. Returns "" when there is none.
+func (m Model) renderProjectSwitchBanner() string {
+	if m.previousProjectRoot == "" {
+		return ""
+	}
+	return projectSwitchStyle.Render(fmt.Sprintf(
+		"ℹ Project changed: your last run was in %s. Showing history for the current project.",
+		m.previousProjectRoot,
+	))
 }
 
 // renderEmptyHistory renders the view when there's no history
@@ -222,14 +437,12 @@ func (m Model) renderEmptyHistory(header string) string {
 
 	footer := footerStyle.Render("Press 'q' or 'esc' to exit")
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		"",
-		emptyMsg,
-		"",
-		footer,
-	)
+	parts := []string{header, ""}
+	if banner := m.renderProjectSwitchBanner(); banner != "" {
+		parts = append(parts, banner)
+	}
+	parts = append(parts, emptyMsg, "", footer)
+	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
 // buildHistoryTableRows builds all visible rows for the history table
@@ -241,12 +454,12 @@ func (m Model) buildHistoryTableRows(startIdx, endIdx int, cols historyTableColu
 		displayID := i + 1
 		row := buildHistoryTableRow(m.history[i], displayID, cols, styles)
 
+		prefix := "  "
 		if i == m.historyCursor {
-			// Set width to ensure the background extends to the terminal edge
-			row = styles.cursor.Width(m.width).Render("▶ " + row)
-		} else {
-			row = styles.normalRow.Width(m.width).Render("  " + row)
+			prefix = "▶ "
 		}
+		// Set width to ensure the background extends to the terminal edge
+		row = styles.rowStyle(i, i == m.historyCursor).Width(m.width).Render(prefix + row)
 
 		rows = append(rows, row)
 	}
@@ -254,10 +467,64 @@ func (m Model) buildHistoryTableRows(startIdx, endIdx int, cols historyTableColu
 	return rows
 }
 
+// buildHistoryGroupRows builds the visible rows of the grouped history view.
+// Group rows are numbered by group; runs under an expanded group are not numbered.
+func (m Model) buildHistoryGroupRows(startIdx, endIdx int, cols historyTableColumns, styles historyTableStyles) []string {
+	allRows := m.historyRows()
+	rows := make([]string, 0, endIdx-startIdx)
+
+	for i := startIdx; i < endIdx; i++ {
+		row := allRows[i]
+		group := m.historyGroups[row.group]
+
+		var line string
+		if row.run < 0 {
+			line = buildHistoryGroupRow(group, m.historyExpanded[group.StackPath], row.group+1, cols, styles)
+		} else {
+			line = buildHistoryRunRow(group.Entries[row.run], cols, styles)
+		}
+
+		prefix := "  "
+		if i == m.historyCursor {
+			prefix = "▶ "
+		}
+		rows = append(rows, styles.rowStyle(i, i == m.historyCursor).Width(m.width).Render(prefix+line))
+	}
+
+	return rows
+}
+
 // buildHistoryFooter builds the footer with navigation info
 func (m Model) buildHistoryFooter(startIdx, endIdx int) string {
+	if m.historyFiltering {
+		return footerStyle.Render(fmt.Sprintf(
+			"%d of %d entries match | Type to filter | Press Enter to browse the results | Press 'esc' to clear the filter",
+			len(m.history),
+			len(m.historyAll),
+		))
+	}
+	if m.historyFiltered() {
+		return footerStyle.Render(fmt.Sprintf(
+			"Showing %d-%d of %d rows (%d of %d entries match) | Use ↑/↓ to navigate | Press Enter to re-execute | '/' to edit the filter | 'f' to toggle failures only | Press 'esc' to clear the filters",
+			min(startIdx+1, endIdx),
+			endIdx,
+			m.historyRowCount(),
+			len(m.history),
+			len(m.historyAll),
+		))
+	}
+	if m.historyGrouped {
+		return footerStyle.Render(fmt.Sprintf(
+			"Showing %d-%d of %d rows (%d stacks) | Use ↑/↓ to navigate, →/← to expand/collapse | Press Enter to re-execute | 'g' for all runs | Press 'q' or 'esc' to exit",
+			startIdx+1,
+			endIdx,
+			m.historyRowCount(),
+			len(m.historyGroups),
+		))
+	}
+
 	footerText := fmt.Sprintf(
-		"Showing %d-%d of %d entries | Use ↑/↓ to navigate | Press Enter to re-execute | Press 'q' or 'esc' to exit",
+		"Showing %d-%d of %d entries | Use ↑/↓ to navigate | Press Enter to re-execute | 'g' to group by stack | '/' to filter | 'f' for failures only | Press 'q' or 'esc' to exit",
 		startIdx+1,
 		endIdx,
 		len(m.history),
@@ -1,10 +1,13 @@
 package tui
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/israoo/terrax/internal/history"
@@ -12,7 +15,7 @@ import (
 
 // TestNewHistoryTableStyles tests history table style creation.
 func TestNewHistoryTableStyles(t *testing.T) {
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
 	assert.NotNil(t, styles.headerRow)
 	assert.NotNil(t, styles.cursor)
@@ -82,7 +85,7 @@ func TestNewHistoryTableColumns(t *testing.T) {
 
 // TestFormatExitCode tests exit code formatting.
 func TestFormatExitCode(t *testing.T) {
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
 	tests := []struct {
 		name          string
@@ -118,7 +121,7 @@ func TestFormatExitCode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatExitCode(tt.exitCode, styles, tt.width)
+			result := formatExitCode("", tt.exitCode, styles, tt.width)
 
 			assert.Contains(t, result, tt.shouldContain)
 			assert.NotEmpty(t, result)
@@ -218,9 +221,9 @@ func TestCalculateVisibleRange(t *testing.T) {
 // TestBuildHistoryTableHeader tests table header construction.
 func TestBuildHistoryTableHeader(t *testing.T) {
 	cols := newHistoryTableColumns(120)
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
-	header := buildHistoryTableHeader(cols, styles.headerRow)
+	header := buildHistoryTableHeader(cols, styles.headerRow, DefaultLocale)
 
 	assert.NotEmpty(t, header)
 	assert.Contains(t, header, "#")
@@ -234,7 +237,7 @@ func TestBuildHistoryTableHeader(t *testing.T) {
 // TestBuildHistoryTableRow tests individual row construction.
 func TestBuildHistoryTableRow(t *testing.T) {
 	cols := newHistoryTableColumns(120)
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
 	tests := []struct {
 		name          string
@@ -255,6 +258,19 @@ func TestBuildHistoryTableRow(t *testing.T) {
 			displayID:     1,
 			shouldContain: []string{"1", "2025-12-16 10:30:00", "plan", "dev/vpc", "✓", "5.25s"},
 		},
+		{
+			name: "no changes row",
+			entry: history.ExecutionLogEntry{
+				ID:        43,
+				Timestamp: time.Date(2025, 12, 16, 10, 30, 0, 0, time.UTC),
+				Command:   "plan",
+				StackPath: "dev/vpc",
+				Summary:   history.NoChangesSummary,
+				DurationS: 2.5,
+			},
+			displayID:     1,
+			shouldContain: []string{"plan", "dev/vpc", "= 0", "2.50s"},
+		},
 		{
 			name: "failure row",
 			entry: history.ExecutionLogEntry{
@@ -294,6 +310,20 @@ func TestBuildHistoryTableRow(t *testing.T) {
 			displayID:     4,
 			shouldContain: []string{"4", "2025-12-16 13:00:00", "destroy", "staging/ec2", "✗", "0.25s"},
 		},
+		{
+			name: "row with note",
+			entry: history.ExecutionLogEntry{
+				ID:        46,
+				Timestamp: time.Date(2025, 12, 16, 14, 0, 0, 0, time.UTC),
+				Command:   "apply",
+				StackPath: "prod/vpc",
+				ExitCode:  0,
+				DurationS: 3,
+				Note:      "hotfix",
+			},
+			displayID:     5,
+			shouldContain: []string{"5", "apply", "prod/vpc [hotfix]", "✓", "3.00s"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -308,6 +338,34 @@ func TestBuildHistoryTableRow(t *testing.T) {
 	}
 }
 
+// TestFormatChangeCounts tests the colored resource counts shown for runs that reported
+// a change report, and the empty cell for runs that did not.
+func TestFormatChangeCounts(t *testing.T) {
+	original := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(original) })
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
+
+	assert.Empty(t, formatChangeCounts(nil, styles), "no change report")
+
+	plain := formatChangeCounts(&history.ChangeCounts{}, styles)
+	assert.Equal(t, "+0 ~0 -0", plain, "zero counts are not colored")
+
+	stripANSI := regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	counts := formatChangeCounts(&history.ChangeCounts{Add: 3, Change: 0, Destroy: 12}, styles)
+	assert.Equal(t, "+3 ~0 -12", stripANSI.ReplaceAllString(counts, ""))
+	assert.NotEqual(t, "+3 ~0 -12", counts, "non-zero counts are colored")
+	assert.NotContains(t, counts, "\x1b[0m", "the row background is not reset")
+
+	row := buildHistoryTableRow(history.ExecutionLogEntry{
+		Command:   "plan",
+		StackPath: "dev/vpc",
+		Summary:   "Plan: 3 to add, 0 to change, 12 to destroy.",
+		Changes:   &history.ChangeCounts{Add: 3, Destroy: 12},
+	}, 1, newHistoryTableColumns(120), styles)
+	assert.True(t, strings.HasSuffix(stripANSI.ReplaceAllString(row, ""), "0.00s       +3 ~0 -12"))
+}
+
 // TestRenderHistoryView tests full history view rendering.
 func TestRenderHistoryView(t *testing.T) {
 	tests := []struct {
@@ -538,7 +596,7 @@ func TestBuildHistoryTableRows(t *testing.T) {
 			m.historyCursor = tt.historyCursor
 
 			cols := newHistoryTableColumns(m.width)
-			styles := newHistoryTableStyles()
+			styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
 			rows := m.buildHistoryTableRows(tt.startIdx, tt.endIdx, cols, styles)
 
@@ -672,7 +730,7 @@ func TestModel_View_HistoryState(t *testing.T) {
 
 // TestFormatExitCode_Padding tests exit code padding logic.
 func TestFormatExitCode_Padding(t *testing.T) {
-	styles := newHistoryTableStyles()
+	styles := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
 
 	tests := []struct {
 		name     string
@@ -702,8 +760,163 @@ func TestFormatExitCode_Padding(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := formatExitCode(tt.exitCode, styles, tt.width)
+			result := formatExitCode("", tt.exitCode, styles, tt.width)
 			assert.GreaterOrEqual(t, len(result), tt.minLen)
 		})
 	}
 }
+
+// TestHistoryTableStyles_RowStyle tests zebra striping and cursor highlighting.
+func TestHistoryTableStyles_RowStyle(t *testing.T) {
+	cfg := HistoryTableStyle{
+		Striped:          true,
+		StripeColor:      "#111111",
+		CursorForeground: "#222222",
+		CursorBackground: "#333333",
+	}
+	styles := newHistoryTableStyles(cfg, DefaultTheme())
+
+	t.Run("alternating rows get alternating styles", func(t *testing.T) {
+		for i := 0; i < 4; i++ {
+			bg := styles.rowStyle(i, false).GetBackground()
+			if i%2 == 1 {
+				assert.Equal(t, lipgloss.Color("#111111"), bg, "row %d should be striped", i)
+			} else {
+				assert.Equal(t, lipgloss.NoColor{}, bg, "row %d should not be striped", i)
+			}
+		}
+	})
+
+	t.Run("cursor row uses cursor style regardless of parity", func(t *testing.T) {
+		for _, i := range []int{0, 1} {
+			style := styles.rowStyle(i, true)
+			assert.Equal(t, lipgloss.Color("#222222"), style.GetForeground())
+			assert.Equal(t, lipgloss.Color("#333333"), style.GetBackground())
+		}
+	})
+
+	t.Run("striping disabled keeps every row normal", func(t *testing.T) {
+		plain := newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme())
+		assert.Equal(t, lipgloss.NoColor{}, plain.rowStyle(1, false).GetBackground())
+		assert.Equal(t, accentColor, plain.rowStyle(1, true).GetForeground())
+	})
+}
+
+// TestWithHistoryTableStyle tests that the configured style is kept on the model.
+func TestWithHistoryTableStyle(t *testing.T) {
+	cfg := HistoryTableStyle{Striped: true}
+	m := NewHistoryModel(nil).WithHistoryTableStyle(cfg)
+	assert.Equal(t, cfg, m.historyTableStyle)
+}
+
+// TestFormatExitCode_ChangesSemantics tests the per-command "changes present" exit codes.
+func TestFormatExitCode_ChangesSemantics(t *testing.T) {
+	tests := []struct {
+		name         string
+		cfg          HistoryTableStyle
+		command      string
+		exitCode     int
+		expectedIcon string
+	}{
+		{name: "plan exit 2 shows changes by default", command: "plan", exitCode: 2, expectedIcon: "± 2"},
+		{name: "plan exit 1 is still an error", command: "plan", exitCode: 1, expectedIcon: "✗ 1"},
+		{name: "apply exit 2 is an error", command: "apply", exitCode: 2, expectedIcon: "✗ 2"},
+		{
+			name:         "custom mapping per command",
+			cfg:          HistoryTableStyle{ChangesExitCodes: map[string][]int{"apply": {2}}},
+			command:      "apply",
+			exitCode:     2,
+			expectedIcon: "± 2",
+		},
+		{
+			name:         "empty mapping disables changes state",
+			cfg:          HistoryTableStyle{ChangesExitCodes: map[string][]int{}},
+			command:      "plan",
+			exitCode:     2,
+			expectedIcon: "✗ 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			styles := newHistoryTableStyles(tt.cfg, DefaultTheme())
+			result := formatExitCode(tt.command, tt.exitCode, styles, 9)
+			assert.Contains(t, result, tt.expectedIcon)
+			assert.Len(t, []rune(result), 9)
+		})
+	}
+}
+
+// TestRenderHistoryView_ProjectSwitchBanner tests the notice shown after switching projects.
+func TestRenderHistoryView_ProjectSwitchBanner(t *testing.T) {
+	tests := []struct {
+		name         string
+		entries      []history.ExecutionLogEntry
+		previousRoot string
+		expectBanner bool
+	}{
+		{name: "mismatch on empty history", entries: []history.ExecutionLogEntry{}, previousRoot: "/work/other-repo", expectBanner: true},
+		{name: "mismatch with entries", entries: []history.ExecutionLogEntry{{ID: 1, Command: "plan"}}, previousRoot: "/work/other-repo", expectBanner: true},
+		{name: "same project", entries: []history.ExecutionLogEntry{{ID: 1, Command: "plan"}}, previousRoot: "", expectBanner: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewHistoryModel(tt.entries).WithProjectSwitchNotice(tt.previousRoot)
+			m.ready = true
+			m.width = 200
+			m.height = 30
+
+			output := m.renderHistoryView()
+			if tt.expectBanner {
+				assert.Contains(t, output, "Project changed")
+				assert.Contains(t, output, tt.previousRoot)
+			} else {
+				assert.NotContains(t, output, "Project changed")
+			}
+		})
+	}
+}
+
+// TestRenderHistoryView_Grouped tests the grouped history view: one row per stack with its
+// latest run and run count, and the runs of an expanded stack listed beneath it.
+func TestRenderHistoryView_Grouped(t *testing.T) {
+	entries := []history.ExecutionLogEntry{
+		{ID: 3, Command: "apply", StackPath: "dev/vpc", ExitCode: 1, Note: "hotfix"},
+		{ID: 2, Command: "plan", StackPath: "prod/rds"},
+		{ID: 1, Command: "plan", StackPath: "dev/vpc"},
+	}
+	m := NewHistoryModel(entries).WithHistoryGrouping(true)
+	m.ready = true
+	m.width = 140
+	m.height = 30
+
+	output := m.renderHistoryView()
+	assert.Contains(t, output, "▸ dev/vpc (2)")
+	assert.Contains(t, output, "▸ prod/rds (1)")
+	assert.Contains(t, output, "✗ 1", "the group row shows the latest outcome")
+	assert.NotContains(t, output, "└")
+	assert.Contains(t, output, "of 2 rows (2 stacks)")
+
+	m.historyExpanded["dev/vpc"] = true
+	output = m.renderHistoryView()
+	assert.Contains(t, output, "▾ dev/vpc (2)")
+	assert.Equal(t, 2, strings.Count(output, "└"), "each run is listed under its group")
+	assert.Contains(t, output, "└ [hotfix]")
+	assert.Contains(t, output, "of 4 rows (2 stacks)")
+}
+
+// TestBuildHistoryGroupRow_KeepsMarkerWhenTruncated tests that a long stack path is
+// shortened from the start without dropping the expand marker.
+func TestBuildHistoryGroupRow_KeepsMarkerWhenTruncated(t *testing.T) {
+	cols := newHistoryTableColumns(0) // Minimum stack path width (20).
+	group := history.StackGroup{
+		StackPath: "very/long/path/to/some/deeply/nested/stack",
+		Entries:   []history.ExecutionLogEntry{{ID: 1, Command: "plan"}},
+	}
+
+	row := buildHistoryGroupRow(group, false, 1, cols, newHistoryTableStyles(HistoryTableStyle{}, DefaultTheme()))
+
+	assert.Contains(t, row, "▸ ...")
+	assert.Contains(t, row, "ested/stack (1)")
+}
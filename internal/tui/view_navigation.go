@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/israoo/terrax/internal/stack"
 )
 
 // renderColumnsWithArrows renders all visible columns without overflow arrows.
@@ -13,10 +15,14 @@ import (
 func (r *Renderer) renderColumnsWithArrows() []string {
 	columns := make([]string, 0)
 
-	// Render commands column (always visible)
-	commandsView := r.renderCommandsColumn()
-	styledCommands := r.styleColumn(commandsView, r.model.isCommandsColumnFocused())
-	columns = append(columns, styledCommands)
+	// Render commands column (unless hidden because it holds the only command)
+	switch {
+	case r.model.commandsHidden():
+	case r.model.commandsCollapsed:
+		columns = append(columns, r.styleColumnWidth(r.renderCollapsedCommandsColumn(), r.model.isCommandsColumnFocused(), CollapsedBarWidth))
+	default:
+		columns = append(columns, r.styleColumn(r.renderCommandsColumn(), r.model.isCommandsColumnFocused()))
+	}
 
 	// Render navigation columns in sliding window (configurable max visible)
 	maxDepth := r.model.navigator.GetMaxDepth()
@@ -32,12 +38,24 @@ func (r *Renderer) renderColumnsWithArrows() []string {
 		navView := r.renderNavigationColumn(depth)
 		isFocused := r.model.focusedColumn == depth+1
 		styledNav := r.styleColumn(navView, isFocused)
+		if depth > startDepth && r.model.columnSeparator != "" {
+			columns = append(columns, r.renderColumnSeparator(lipgloss.Height(styledNav)))
+		}
 		columns = append(columns, styledNav)
 	}
 
 	return columns
 }
 
+// renderColumnSeparator renders the configured separator as a vertical line of height rows.
+func (r *Renderer) renderColumnSeparator(height int) string {
+	lines := make([]string, height)
+	for i := range lines {
+		lines[i] = r.model.columnSeparator
+	}
+	return dividerStyle.Render(strings.Join(lines, "\n"))
+}
+
 // renderDepthIndicator renders a row of dots showing the sliding-window position within the tree.
 // Each dot represents one level of the global tree depth with three visual states:
 //   - ● (visible, bright): level is currently shown in the column window
@@ -88,7 +106,7 @@ func (r *Renderer) renderCommandsColumn() string {
 		parts = append(parts, filterStyle.Render(filterView))
 	} else {
 		// Show normal title
-		title := titleStyle.Render("⚡" + CommandsTitle)
+		title := titleStyle.Render("⚡" + r.model.Text(MsgCommandsTitle))
 		parts = append(parts, title)
 	}
 
@@ -100,23 +118,40 @@ func (r *Renderer) renderCommandsColumn() string {
 	return lipgloss.JoinVertical(lipgloss.Left, parts...)
 }
 
+// renderCollapsedCommandsColumn renders the commands column as a compact bar that
+// shows only the selected command, keeping the same height as the other columns.
+func (r *Renderer) renderCollapsedCommandsColumn() string {
+	commands := r.model.commands
+	if len(commands) == 0 {
+		return titleStyle.Render("⚡")
+	}
+
+	selected := []string{commands[r.model.selectedCommand]}
+	content := renderItemList(r.model.theme, itemList{
+		items:        r.model.commandLabels(selected),
+		start:        0,
+		end:          1,
+		selected:     0,
+		lineCount:    r.model.getListLineCount(),
+		maxTextWidth: CollapsedBarWidth - CursorWidth - ItemStylePadding - ColumnStylePadding,
+		totalPages:   1,
+		dangerous:    r.model.dangerousFlags(selected),
+	})
+
+	return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("⚡"), "", content)
+}
+
 // buildCommandList builds the list of commands with selection indicator.
 func (r *Renderer) buildCommandList() string {
 	originalCommands := r.model.commands
-	commands := r.model.commands
 
-	// Apply filter if this column (0) has an active filter
-	if filter, exists := r.model.columnFilters[0]; exists {
-		filterValue := filter.Value()
-		if filterValue != "" {
-			commands = filterItems(commands, filterValue)
-		}
-	}
+	// Apply the focused stack's allowlist and this column's (0) filter
+	commands := r.model.getFilteredCommands()
 
 	// Map original selected index to filtered index
 	var selectedFilteredIndex int
 	if len(commands) < len(originalCommands) {
-		// Filter is active, need to map
+		// Allowlist or filter is active, need to map
 		selectedFilteredIndex = findFilteredIndex(originalCommands, commands, r.model.selectedCommand)
 	} else {
 		// No filter, use original index
@@ -124,7 +159,7 @@ func (r *Renderer) buildCommandList() string {
 	}
 
 	// Apply scrolling window
-	maxVisibleItems := r.model.getMaxVisibleItems()
+	maxVisibleItems := r.model.getMaxVisibleItems(0)
 	scrollOffset := r.model.scrollOffsets[0] // columnID = 0 for commands
 
 	// Calculate visible range
@@ -132,18 +167,24 @@ func (r *Renderer) buildCommandList() string {
 
 	// Render items with pagination.
 	maxTextWidth := r.getMaxItemTextWidth(false)
-	totalPages := r.model.getTotalPages(len(commands))
+	totalPages := r.model.getTotalPages(0, len(commands))
 	currentPage := r.model.getCurrentPage(0) // columnID = 0 for commands
 
-	return renderItemList(
-		commands,
-		startIdx, endIdx,
-		selectedFilteredIndex,
-		maxVisibleItems,
-		maxTextWidth,
-		totalPages, currentPage,
-		nil,
-	)
+	labels := r.model.commandLabels(commands)
+	return renderItemList(r.model.theme, itemList{
+		items:        labels,
+		start:        startIdx,
+		end:          endIdx,
+		selected:     selectedFilteredIndex,
+		lineCount:    r.model.getListLineCount(),
+		maxTextWidth: maxTextWidth,
+		totalPages:   totalPages,
+		currentPage:  currentPage,
+		dangerous:    r.model.dangerousFlags(commands),
+		descriptions: r.model.commandDescriptions(commands),
+		highlights:   alignHighlights(r.model.filterHighlights(0, commands), labels),
+		headers:      r.model.commandGroupHeaders(commands),
+	})
 }
 
 // renderNavigationColumn renders a navigation column at the given depth.
@@ -177,18 +218,11 @@ func (r *Renderer) renderNavigationColumn(depth int) string {
 // buildNavigationList builds the list of items for a navigation column.
 func (r *Renderer) buildNavigationList(depth int) string {
 	originalItems := r.model.navState.Columns[depth]
-	items := r.model.navState.Columns[depth]
+	items := r.model.getFilteredNavigationItems(depth)
 	selectedIndex := r.model.navState.SelectedIndices[depth]
 
-	// Apply filter if this navigation column has an active filter
 	// Navigation columns are indexed as: depth 0 -> columnID 1, depth 1 -> columnID 2, etc.
 	columnID := depth + 1
-	if filter, exists := r.model.columnFilters[columnID]; exists {
-		filterValue := filter.Value()
-		if filterValue != "" {
-			items = filterItems(items, filterValue)
-		}
-	}
 
 	// Map original selected index to filtered index
 	var selectedFilteredIndex int
@@ -201,7 +235,7 @@ func (r *Renderer) buildNavigationList(depth int) string {
 	}
 
 	// Apply scrolling window.
-	maxVisibleItems := r.model.getMaxVisibleItems()
+	maxVisibleItems := r.model.getMaxVisibleItems(columnID)
 	scrollOffset := r.model.scrollOffsets[columnID]
 
 	// Calculate visible range.
@@ -225,69 +259,167 @@ func (r *Renderer) buildNavigationList(depth int) string {
 
 	// Render items with pagination.
 	maxTextWidth := r.getMaxItemTextWidth(r.model.HasSelectedPaths())
-	totalPages := r.model.getTotalPages(len(items))
+	totalPages := r.model.getTotalPages(columnID, len(items))
 	currentPage := r.model.getCurrentPage(columnID)
 
-	return renderItemList(
-		items,
-		startIdx, endIdx,
-		selectedFilteredIndex,
-		maxVisibleItems,
-		maxTextWidth,
-		totalPages, currentPage,
-		markedItems,
-	)
+	indicators := r.lastRunIndicators(depth, originalItems, items)
+	if indicators != nil {
+		maxTextWidth = max(maxTextWidth-OutcomeIndicatorWidth, MinItemTextWidth)
+	}
+
+	return renderItemList(r.model.theme, itemList{
+		items:        items,
+		start:        startIdx,
+		end:          endIdx,
+		selected:     selectedFilteredIndex,
+		lineCount:    r.model.getListLineCount(),
+		maxTextWidth: maxTextWidth,
+		totalPages:   totalPages,
+		currentPage:  currentPage,
+		marked:       markedItems,
+		indicators:   indicators,
+		highlights:   r.model.filterHighlights(columnID, items),
+		divider:      r.model.stackGroupDivider(depth, originalItems, items),
+	})
+}
+
+// lastRunIndicators returns the styled outcome icon of the latest run against each (filtered)
+// item at depth, or "" for items never run. It returns nil when indicators are disabled.
+func (r *Renderer) lastRunIndicators(depth int, originalItems, items []string) []string {
+	if r.model.lastRuns == nil || r.model.navigator == nil {
+		return nil
+	}
+
+	styles := newHistoryTableStyles(r.model.historyTableStyle, r.model.theme)
+	indicators := make([]string, len(items))
+	for i := range items {
+		origIdx := findOriginalIndex(originalItems, items, i)
+		if origIdx < 0 {
+			continue
+		}
+		path := r.model.navigator.GetPathAtDepthAndIndex(r.model.navState, depth, origIdx)
+		if entry, ok := r.model.lastRuns[path]; ok {
+			indicators[i] = styles.outcomeIndicator(entry)
+		}
+	}
+	return indicators
+}
+
+// stackGroupDivider returns the index in items of the first plain directory that follows
+// a stack when stack grouping is enabled, or -1 when no divider should be drawn.
+// items is the (possibly filtered) list shown for the navigation column at depth.
+func (m Model) stackGroupDivider(depth int, originalItems, items []string) int {
+	if !m.groupStacks {
+		return -1
+	}
+	if filter, exists := m.columnFilters[depth+1]; exists && m.fuzzyFilter && filter.Value() != "" {
+		return -1 // Ranked matches no longer list stacks first.
+	}
+	parent := m.navigator.GetRoot()
+	if depth > 0 {
+		parent = m.navigator.GetNodeAtDepth(m.navState, depth-1)
+	}
+
+	sawStack := false
+	for i := range items {
+		child := parent.FindChildByIndex(findOriginalIndex(originalItems, items, i))
+		if child == nil {
+			continue
+		}
+		if child.IsStack {
+			sawStack = true
+		} else if sawStack {
+			return i
+		}
+	}
+	return -1
 }
 
-// renderItemList renders a list of items with pagination.
-// markedItems is an optional slice of bools (nil = no markers shown).
-func renderItemList(
-	items []string,
-	startIdx, endIdx int,
-	selectedFilteredIndex int,
-	maxVisibleItems int,
-	maxTextWidth int,
-	totalPages, currentPage int,
-	markedItems []bool,
-) string {
+// itemList is a page of a column's items for renderItemList. The per-item slices are
+// optional and indexed like items.
+type itemList struct {
+	items        []string
+	start, end   int // Range of items on the page
+	selected     int // Index of the selected item (-1 = none)
+	lineCount    int // Lines the list fills, padded with blank lines
+	maxTextWidth int
+	totalPages   int
+	currentPage  int
+
+	marked       []bool         // Marker per item (nil = no markers shown)
+	dangerous    []bool         // Items highlighted as dangerous (nil = none)
+	descriptions []string       // Dimmed description after each item (nil = none)
+	indicators   []string       // Rendered after each item, e.g. the last run outcome (nil = none)
+	highlights   [][]bool       // Match mask per item (nil = no characters highlighted)
+	divider      int            // Item a non-selectable divider line is drawn before; skipped at the top of a page, so 0 draws none
+	headers      map[int]string // Non-selectable group header line before the item at each index (nil = none)
+}
+
+// renderItemList renders a page of items with its page indicators.
+func renderItemList(theme Theme, list itemList) string {
+	items, maxTextWidth := list.items, list.maxTextWidth
 	var content string
-	itemsRendered := 0
+	linesRendered := 0
 
 	// Render visible items.
-	for i := startIdx; i < endIdx; i++ {
+	for i := list.start; i < list.end; i++ {
+		if i == list.divider && i > list.start {
+			content += "  " + dividerStyle.Render(strings.Repeat("─", maxTextWidth)) + "\n"
+			linesRendered++
+		}
+		if header, ok := list.headers[i]; ok {
+			content += "  " + commandGroupStyle.Render(truncateText(header, maxTextWidth)) + "\n"
+			linesRendered++
+		}
+
 		cursor := " "
-		style := itemStyle
+		isSelected := i == list.selected
+		isDangerous := i < len(list.dangerous) && list.dangerous[i]
+		style := theme.listItemStyle(isSelected, isDangerous)
+		if !isSelected && !isDangerous && strings.HasSuffix(items[i], stack.StackMarker) {
+			style = style.Foreground(theme.StackMarker)
+		}
 
-		if i == selectedFilteredIndex {
+		if isSelected {
 			cursor = "►"
-			style = selectedItemStyle
 		}
 
 		// Truncate text to fit within column width.
 		displayText := truncateText(items[i], maxTextWidth)
-		if markedItems != nil {
+		renderedText := style.Render(displayText)
+		if i < len(list.highlights) && list.highlights[i] != nil {
+			renderedText = renderHighlightedText(style, items[i], displayText, list.highlights[i])
+		}
+		if list.marked != nil {
 			var marker string
-			if i < len(markedItems) && markedItems[i] {
+			if i < len(list.marked) && list.marked[i] {
 				marker = markedStyle.Render("●") + " "
 			} else {
 				marker = unmarkedStyle.Render("○") + " "
 			}
-			content += fmt.Sprintf("%s %s%s\n", cursor, marker, style.Render(displayText))
+			content += fmt.Sprintf("%s %s%s", cursor, marker, renderedText)
 		} else {
-			content += fmt.Sprintf("%s %s\n", cursor, style.Render(displayText))
+			content += fmt.Sprintf("%s %s", cursor, renderedText)
+		}
+		if i < len(list.indicators) {
+			content += list.indicators[i]
 		}
-		itemsRendered++
+		if i < len(list.descriptions) {
+			content += renderItemDescription(list.descriptions[i], maxTextWidth-lipgloss.Width(displayText))
+		}
+		content += "\n"
+		linesRendered++
 	}
 
-	// Add empty lines to fill remaining space up to maxVisibleItems
+	// Add empty lines to fill remaining space up to lineCount
 	// This ensures all columns have the same height
-	for itemsRendered < maxVisibleItems {
+	for linesRendered < list.lineCount {
 		content += "\n"
-		itemsRendered++
+		linesRendered++
 	}
 
 	// Add page indicators (without extra newline before or after)
-	pageIndicators := renderPageIndicators(currentPage, totalPages)
+	pageIndicators := renderPageIndicators(list.currentPage, list.totalPages)
 	if pageIndicators != "" {
 		content += pageIndicators
 	}
@@ -295,9 +427,24 @@ func renderItemList(
 	return content
 }
 
+// renderItemDescription renders description dimmed after its item, truncated to the width
+// the item leaves free. It renders nothing when the description is empty or has no room.
+func renderItemDescription(description string, width int) string {
+	const separator = "— "
+	width -= len([]rune(separator))
+	if description == "" || width <= EllipsisWidth {
+		return ""
+	}
+	return commandDescriptionStyle.Render(separator + truncateText(description, width))
+}
+
 // styleColumn applies styling to a column based on focus state.
 func (r *Renderer) styleColumn(content string, isFocused bool) string {
-	columnWidth := r.layout.GetColumnWidth()
+	return r.styleColumnWidth(content, isFocused, r.layout.GetColumnWidth())
+}
+
+// styleColumnWidth applies styling to a column of the given width based on focus state.
+func (r *Renderer) styleColumnWidth(content string, isFocused bool, columnWidth int) string {
 
 	// Adjust width for focused columns to account for border width.
 	// Lipgloss adds borders outside the content width, so we need to subtract
@@ -313,7 +460,10 @@ func (r *Renderer) styleColumn(content string, isFocused bool) string {
 	// 2. All show same number of items (controlled by getMaxVisibleItems)
 	// 3. All reserve space for pagination indicators (1 line)
 	// This ensures consistent column heights without forcing artificial padding.
-	return columnStyle(isFocused).
+	marginLeft, marginRight := r.model.columnMargins()
+	return columnStyle(isFocused, r.model.theme).
+		MarginLeft(marginLeft).
+		MarginRight(marginRight).
 		Width(columnWidth).
 		Render(content)
 }
@@ -323,15 +473,16 @@ func (r *Renderer) getLevelTitle(depth int) string {
 	return fmt.Sprintf("Level %d", depth+1)
 }
 
-// columnStyle returns the appropriate style for a column based on focus.
-func columnStyle(focused bool) lipgloss.Style {
+// columnStyle returns the appropriate style for a column based on focus, with the focused
+// border in the theme's color.
+func columnStyle(focused bool, theme Theme) lipgloss.Style {
 	if focused {
 		// Focused column: border with normal padding
 		return lipgloss.NewStyle().
 			Padding(1, 2).
 			Margin(0, 1).
 			Border(focusedBorder).
-			BorderForeground(primaryColor)
+			BorderForeground(theme.FocusedBorder)
 	}
 
 	// Unfocused column: extra padding to compensate for missing border
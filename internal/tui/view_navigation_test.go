@@ -1,10 +1,18 @@
 package tui
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/israoo/terrax/internal/history"
 	"github.com/israoo/terrax/internal/stack"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestRenderColumnsWithArrows tests sliding window column rendering.
@@ -320,7 +328,7 @@ func TestColumnStyle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			style := columnStyle(tt.focused)
+			style := columnStyle(tt.focused, DefaultTheme())
 			assert.NotNil(t, style)
 		})
 	}
@@ -516,6 +524,31 @@ func TestView_ScanningStacks(t *testing.T) {
 	assert.Equal(t, ScanningStacks, view)
 }
 
+// TestView_ConfiguredMessages tests that configured status text replaces the built-in text.
+func TestView_ConfiguredMessages(t *testing.T) {
+	msgs := Messages{Initializing: "Iniciando...", ScanningStacks: "Buscando stacks..."}
+	root := &stack.Node{Name: "root", Path: "/test"}
+
+	notReady := Model{state: StateNavigation}.WithMessages(msgs)
+	assert.Equal(t, msgs.Initializing, notReady.View())
+
+	noStacks := Model{
+		ready:     true,
+		width:     120,
+		height:    30,
+		navigator: stack.NewNavigator(root, 0),
+		state:     StateNavigation,
+	}.WithMessages(msgs)
+	assert.Equal(t, msgs.ScanningStacks, noStacks.View())
+
+	historyNotReady := NewHistoryModel(nil).WithMessages(msgs)
+	assert.Equal(t, msgs.Initializing, historyNotReady.View())
+
+	// Unset messages keep the built-in text.
+	partial := Model{state: StateNavigation}.WithMessages(Messages{ScanningStacks: "custom"})
+	assert.Equal(t, Initializing, partial.View())
+}
+
 func TestIsMarkedOrAncestorMarked(t *testing.T) {
 	selectedPaths := map[string]bool{
 		"/repo/env": true,
@@ -595,3 +628,517 @@ func TestBuildNavigationList_ShowsMarkers(t *testing.T) {
 	assert.Contains(t, col, "●", "marked item should show filled marker")
 	assert.Contains(t, col, "○", "unmarked item should show empty marker when marks are active")
 }
+
+// TestBuildNavigationList_LastRunIndicators tests that each stack shows the outcome of its
+// latest run, and that stacks without history show no indicator.
+func TestBuildNavigationList_LastRunIndicators(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/repo",
+		Children: []*stack.Node{
+			{Name: "app", Path: "/repo/app", IsStack: true},
+			{Name: "db", Path: "/repo/db", IsStack: true},
+			{Name: "dns", Path: "/repo/dns", IsStack: true},
+			{Name: "env", Path: "/repo/env", IsStack: true},
+			{Name: "vpc", Path: "/repo/vpc", IsStack: true},
+		},
+	}
+	m := NewModel(root, 1, []string{"plan"}, 3).
+		WithLastRuns(map[string]history.ExecutionLogEntry{
+			"/repo/app": {Command: "apply", ExitCode: 0},
+			"/repo/db":  {Command: "apply", ExitCode: 1},
+			"/repo/dns": {Command: "plan", ExitCode: 2},
+			"/repo/env": {Command: "plan", ExitCode: 0, Summary: history.NoChangesSummary},
+		})
+	m.width = 120
+	m.height = 30
+	m.columnWidth = 25
+	m.ready = true
+
+	lines := strings.Split(NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildNavigationList(0), "\n")
+
+	expected := []struct{ name, indicator string }{
+		{"app", "✓"}, {"db", "✗"}, {"dns", "±"}, {"env", "="},
+	}
+	for i, tt := range expected {
+		assert.Contains(t, lines[i], tt.name)
+		assert.True(t, strings.HasSuffix(strings.TrimRight(lines[i], " "), tt.indicator),
+			"%s should end with %s: %q", tt.name, tt.indicator, lines[i])
+	}
+	vpc := lines[4]
+	for _, icon := range []string{"✓", "✗", "±", "="} {
+		assert.NotContains(t, vpc, icon, "stacks without history show no indicator")
+	}
+}
+
+// TestBuildNavigationList_NoLastRunIndicators tests that indicators are off by default.
+func TestBuildNavigationList_NoLastRunIndicators(t *testing.T) {
+	root := &stack.Node{
+		Name:     "root",
+		Path:     "/repo",
+		Children: []*stack.Node{{Name: "app", Path: "/repo/app", IsStack: true}},
+	}
+	m := NewModel(root, 1, []string{"plan"}, 3)
+	m.height = 30
+	m.columnWidth = 25
+
+	r := NewRenderer(m, NewLayoutCalculator(120, 30, 25))
+	assert.Nil(t, r.lastRunIndicators(0, m.navState.Columns[0], m.navState.Columns[0]))
+}
+
+// TestDangerousCommands_Style tests that dangerous commands get the warning style
+// independently of the selection highlight, while safe commands keep the normal styles.
+func TestDangerousCommands_Style(t *testing.T) {
+	root := &stack.Node{Name: "root"}
+	m := NewModel(root, 1, []string{"plan", "apply", "destroy"}, 3).
+		WithDangerousCommands([]string{"apply", "destroy"})
+
+	flags := m.dangerousFlags(m.commands)
+	assert.Equal(t, []bool{false, true, true}, flags)
+
+	tests := []struct {
+		name        string
+		isSelected  bool
+		isDangerous bool
+		expectColor lipgloss.TerminalColor
+		expectBold  bool
+	}{
+		{name: "safe unselected", isSelected: false, isDangerous: false, expectColor: textColor},
+		{name: "safe selected", isSelected: true, isDangerous: false, expectColor: accentColor, expectBold: true},
+		{name: "dangerous unselected", isSelected: false, isDangerous: true, expectColor: dangerColor},
+		{name: "dangerous selected", isSelected: true, isDangerous: true, expectColor: dangerColor, expectBold: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := DefaultTheme().listItemStyle(tt.isSelected, tt.isDangerous)
+			assert.Equal(t, tt.expectColor, style.GetForeground())
+			assert.Equal(t, tt.expectBold, style.GetBold())
+		})
+	}
+}
+
+// TestDangerousCommands_NoneConfigured tests that no flags are computed without configuration.
+func TestDangerousCommands_NoneConfigured(t *testing.T) {
+	root := &stack.Node{Name: "root"}
+	m := NewModel(root, 1, []string{"plan", "destroy"}, 3)
+	assert.Nil(t, m.dangerousFlags(m.commands))
+
+	m = m.WithDangerousCommands(nil)
+	assert.Nil(t, m.dangerousFlags(m.commands))
+}
+
+// TestBuildCommandList_CommandIcons tests icon prefixes in the commands column.
+func TestBuildCommandList_CommandIcons(t *testing.T) {
+	icons := map[string]string{"plan": "🔍", "destroy": "💥", "fmt": "~"}
+
+	tests := []struct {
+		name        string
+		emoji       bool
+		contains    []string
+		notContains []string
+	}{
+		{
+			name:        "emoji icons next to configured commands",
+			emoji:       true,
+			contains:    []string{"🔍 plan", "💥 destroy", "~ fmt"},
+			notContains: []string{"* plan"},
+		},
+		{
+			name:        "ascii fallback when emoji disabled",
+			emoji:       false,
+			contains:    []string{"* plan", "* destroy", "~ fmt"},
+			notContains: []string{"🔍", "💥"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &stack.Node{Name: "root"}
+			m := NewModel(root, 1, []string{"plan", "apply", "fmt", "destroy"}, 3).
+				WithCommandIcons(icons, tt.emoji)
+			m.height = 30
+			m.columnWidth = 25
+
+			list := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).buildCommandList()
+
+			for _, s := range tt.contains {
+				assert.Contains(t, list, s)
+			}
+			for _, s := range tt.notContains {
+				assert.NotContains(t, list, s)
+			}
+			// Unconfigured commands render plainly.
+			assert.Equal(t, "apply", m.commandLabels([]string{"apply"})[0])
+		})
+	}
+}
+
+// TestBuildCommandList_CommandDescriptions tests that configured descriptions render beside
+// their commands, truncated to the column width.
+func TestBuildCommandList_CommandDescriptions(t *testing.T) {
+	root := &stack.Node{Name: "root"}
+	m := NewModel(root, 1, []string{"plan", "apply", "fmt"}, 3).
+		WithCommandDescriptions(map[string]string{
+			"plan":  "preview",
+			"apply": "apply changes to real infrastructure resources",
+		})
+	m.height = 30
+	m.columnWidth = 25
+
+	list := NewRenderer(m, NewLayoutCalculator(120, 30, 25)).buildCommandList()
+	lines := strings.Split(list, "\n")
+
+	assert.Contains(t, lines[0], "plan")
+	assert.Contains(t, lines[0], "— preview")
+	assert.Contains(t, lines[1], "— apply")
+	assert.Contains(t, lines[1], "...", "long descriptions are truncated")
+	assert.NotContains(t, lines[1], "resources")
+	assert.NotContains(t, lines[2], "—", "commands without a description render plainly")
+	for _, line := range lines[:3] {
+		assert.LessOrEqual(t, lipgloss.Width(line), m.columnWidth)
+	}
+}
+
+// TestCommandDescriptions_NoneConfigured tests that no descriptions are rendered by default.
+func TestCommandDescriptions_NoneConfigured(t *testing.T) {
+	root := &stack.Node{Name: "root"}
+	m := NewModel(root, 1, []string{"plan", "apply"}, 3)
+	assert.Nil(t, m.commandDescriptions(m.commands))
+
+	m = m.WithCommandDescriptions(map[string]string{"plan": "preview changes"})
+	assert.Equal(t, []string{"preview changes", ""}, m.commandDescriptions(m.commands))
+}
+
+// TestCommandLabels_NoIconsConfigured tests that commands are unchanged without icons.
+func TestCommandLabels_NoIconsConfigured(t *testing.T) {
+	root := &stack.Node{Name: "root"}
+	m := NewModel(root, 1, []string{"plan", "apply"}, 3)
+	assert.Equal(t, []string{"plan", "apply"}, m.commandLabels(m.commands))
+
+	m = m.WithCommandIcons(map[string]string{"plan": ""}, true)
+	assert.Equal(t, []string{"plan", "apply"}, m.commandLabels(m.commands))
+}
+
+// TestStackGrouping tests the divider between stacks and plain directories and that
+// the selection moves across it without landing on it.
+func TestStackGrouping(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "modules", Path: "/root/modules", Depth: 1},
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+			{Name: "rds", Path: "/root/rds", IsStack: true, Depth: 1},
+		},
+	}
+
+	m := NewModel(root, 1, testCommands, 3).WithStackGrouping(true)
+	m.width = 200
+	m.height = 30
+	m.columnWidth = 40
+	m.ready = true
+	m.focusedColumn = 1
+
+	renderer := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+	lines := strings.Split(renderer.buildNavigationList(0), "\n")
+	require.GreaterOrEqual(t, len(lines), 4)
+	assert.Contains(t, lines[0], "vpc")
+	assert.Contains(t, lines[1], "rds")
+	assert.Contains(t, lines[2], "───")
+	assert.Contains(t, lines[3], "modules")
+
+	// The divider takes a line from the list, so the column height is unchanged.
+	ungrouped := NewModel(root, 1, testCommands, 3)
+	ungrouped.width, ungrouped.height, ungrouped.columnWidth, ungrouped.ready = 200, 30, 40, true
+	plain := NewRenderer(ungrouped, NewLayoutCalculator(200, 30, 40)).buildNavigationList(0)
+	assert.Equal(t, strings.Count(plain, "\n"), len(lines)-1)
+	assert.NotContains(t, plain, "───")
+
+	// Moving down from the last stack selects the first directory.
+	m = m.handleVerticalMove(false)
+	assert.Equal(t, "/root/rds", m.GetSelectedStackPath())
+	m = m.handleVerticalMove(false)
+	assert.Equal(t, "/root/modules", m.GetSelectedStackPath())
+}
+
+// TestCommandGroups tests the group headers in the commands column, that the selection
+// moves across them without landing on them and that filtering hides empty groups.
+func TestCommandGroups(t *testing.T) {
+	root := &stack.Node{Name: "root", Path: "/root"}
+	m := NewModel(root, 1, []string{"plan", "apply", "fmt", "validate", "destroy"}, 3).
+		WithCommandGroups([]CommandGroup{
+			{Name: "Read", Commands: []string{"plan", "validate", "missing"}},
+			{Name: "Write", Commands: []string{"apply", "destroy", "plan"}},
+			{Name: "Empty", Commands: []string{"missing"}},
+		})
+	m.width = 200
+	m.height = 30
+	m.columnWidth = 40
+	m.ready = true
+
+	// Ungrouped commands come first, then each group in order; the selection is kept.
+	assert.Equal(t, []string{"fmt", "plan", "validate", "apply", "destroy"}, m.commands)
+	assert.Equal(t, "plan", m.GetSelectedCommand())
+	assert.Len(t, m.commandGroups, 2, "groups without listed commands are dropped")
+
+	lines := strings.Split(NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildCommandList(), "\n")
+	require.GreaterOrEqual(t, len(lines), 7)
+	assert.Contains(t, lines[0], "fmt")
+	assert.Contains(t, lines[1], "Read")
+	assert.Contains(t, lines[2], "plan")
+	assert.Contains(t, lines[3], "validate")
+	assert.Contains(t, lines[4], "Write")
+	assert.Contains(t, lines[5], "apply")
+	assert.Contains(t, lines[6], "destroy")
+
+	// The headers take lines from the list, so the column height is unchanged.
+	plain := NewModel(root, 1, m.commands, 3)
+	plain.width, plain.height, plain.columnWidth, plain.ready = 200, 30, 40, true
+	plainList := NewRenderer(plain, NewLayoutCalculator(200, 30, 40)).buildCommandList()
+	assert.Equal(t, strings.Count(plainList, "\n"), len(lines)-1)
+
+	// Only the commands column gives up lines to the headers; navigation columns keep a
+	// full page and are padded to the same height.
+	assert.Equal(t, plain.getMaxVisibleItems(0)-2, m.getMaxVisibleItems(0))
+	assert.Equal(t, plain.getMaxVisibleItems(1), m.getMaxVisibleItems(1))
+	assert.Equal(t, plain.getListLineCount(), m.getListLineCount())
+
+	// Moving down from the last command of a group selects the first of the next one.
+	m.moveCommandSelection(false)
+	assert.Equal(t, "validate", m.GetSelectedCommand())
+	m.moveCommandSelection(false)
+	assert.Equal(t, "apply", m.GetSelectedCommand())
+
+	// Clicking a header selects nothing; the row below it is the group's first command.
+	assert.Equal(t, -1, m.itemAtRow(0, 4))
+	assert.Equal(t, 3, m.itemAtRow(0, 5))
+
+	// A filter leaving a group without commands hides its header.
+	filter := textinput.New()
+	filter.SetValue("a")
+	m.columnFilters[0] = filter
+	assert.Equal(t, map[int]string{0: "Read", 2: "Write"}, m.commandGroupHeaders(m.getFilteredCommands()))
+	filter.SetValue("de")
+	m.columnFilters[0] = filter
+	assert.Equal(t, map[int]string{0: "Write"}, m.commandGroupHeaders(m.getFilteredCommands()))
+}
+
+func TestCommandsCollapse(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "vpc", Path: "/root/vpc", IsStack: true, Depth: 1},
+		},
+	}
+
+	m := NewModel(root, 1, testCommands, 3)
+	m.width = 200
+	m.height = 30
+	m.ready = true
+	m.columnWidth = m.calculateColumnWidth()
+	m.selectedCommand = 1
+	expandedWidth := m.columnWidth
+
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(Model)
+	require.True(t, m.commandsCollapsed)
+	assert.Greater(t, m.columnWidth, expandedWidth, "navigation columns should get the freed width")
+
+	renderer := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+	collapsed := renderer.renderColumnsWithArrows()[0]
+	assert.Contains(t, collapsed, "apply")
+	assert.NotContains(t, collapsed, "plan")
+	assert.NotContains(t, collapsed, "validate")
+	assert.NotContains(t, collapsed, CommandsTitle)
+	assert.Equal(t, CollapsedBarWidth+ColumnOverhead, lipgloss.Width(collapsed))
+
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m = updated.(Model)
+	assert.False(t, m.commandsCollapsed)
+	assert.Equal(t, expandedWidth, m.columnWidth)
+
+	renderer = NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+	expanded := renderer.renderColumnsWithArrows()[0]
+	for _, cmd := range testCommands {
+		assert.Contains(t, expanded, cmd)
+	}
+}
+
+func TestColumnSeparatorAndGap(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "env", Path: "/root/env", Depth: 1, Children: []*stack.Node{
+				{Name: "dev", Path: "/root/env/dev", Depth: 2, Children: []*stack.Node{
+					{Name: "vpc", Path: "/root/env/dev/vpc", IsStack: true, Depth: 3},
+				}},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		gap       int
+		separator string
+	}{
+		{name: "default spacing", gap: ColumnOverhead},
+		{name: "separator", gap: ColumnOverhead, separator: "¦"},
+		{name: "wide gap and separator", gap: 6, separator: "┃"},
+		{name: "no gap", gap: 0, separator: "|"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(root, 3, testCommands, 3).WithColumnGap(tt.gap).WithColumnSeparator(tt.separator)
+			m.width = 120
+			m.height = 30
+			m.ready = true
+			m.columnWidth = m.calculateColumnWidth()
+
+			renderer := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+			columns := renderer.renderColumnsWithArrows()
+			content := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+
+			assert.LessOrEqual(t, lipgloss.Width(content), m.width, "columns must fit the terminal")
+			if tt.separator == "" {
+				assert.Len(t, columns, 4)
+				return
+			}
+			// commands, level 1, separator, level 2, separator, level 3
+			require.Len(t, columns, 6)
+			assert.Contains(t, columns[2], tt.separator)
+			assert.Contains(t, columns[4], tt.separator)
+			assert.NotContains(t, columns[0]+columns[1], tt.separator, "no separator after the commands column")
+			assert.Equal(t, lipgloss.Height(columns[1]), lipgloss.Height(columns[2]))
+		})
+	}
+}
+
+// TestRender_DebugOverlay tests that the debug overlay replaces the columns with the
+// current navigation state values.
+func TestRender_DebugOverlay(t *testing.T) {
+	root := &stack.Node{
+		Name: "root",
+		Path: "/test",
+		Children: []*stack.Node{
+			{Name: "dev", Path: "/test/dev"},
+			{Name: "prod", Path: "/test/prod", IsStack: true},
+		},
+	}
+	m := NewModel(root, 1, testCommands, 3)
+	m.ready = true
+	m.width = 120
+	m.height = 30
+	m.columnWidth = 25
+	m.focusedColumn = 1
+	m.navState.SelectedIndices[0] = 1
+	m.scrollOffsets[1] = 2
+	m.navigator.PropagateSelection(m.navState)
+
+	renderer := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+	assert.NotContains(t, renderer.Render(), "focusedColumn:", "overlay is hidden by default")
+
+	m = m.WithDebugOverlay(true)
+	renderer = NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth))
+	output := renderer.Render()
+
+	assert.Contains(t, output, "focusedColumn:      1")
+	assert.Contains(t, output, "navigationOffset:   0")
+	assert.Contains(t, output, "selectedIndices:    [1]")
+	assert.Contains(t, output, "scrollOffsets:      map[1:2]")
+	assert.Contains(t, output, "activeFilterColumn: -1")
+	assert.Contains(t, output, "path:               /test/prod")
+	assert.Contains(t, output, "node:               prod (stack, 0 children)")
+	assert.LessOrEqual(t, lipgloss.Height(output), m.height, "overlay fits the terminal")
+}
+
+func TestMatchMask(t *testing.T) {
+	marked := func(text string, mask []bool) string {
+		var b strings.Builder
+		for i := range text {
+			if i < len(mask) && mask[i] {
+				b.WriteByte(text[i])
+			}
+		}
+		return b.String()
+	}
+
+	assert.Equal(t, "VPC", marked("dns-VPC-us", matchMask("dns-VPC-us", "vpc", false)))
+	assert.Equal(t, "dvus", marked("dev/us-east-1", matchMask("dev/us-east-1", "dvus", true)))
+	assert.Nil(t, matchMask("staging", "dvus", true))
+	assert.Nil(t, matchMask("staging", "prod", false))
+	assert.Nil(t, matchMask("staging", "", false))
+}
+
+func TestFilterHighlights(t *testing.T) {
+	original := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(original) })
+
+	root := &stack.Node{
+		Name: "root",
+		Path: "/root",
+		Children: []*stack.Node{
+			{Name: "dev-us", Path: "/root/dev-us", Depth: 1},
+			{Name: "staging", Path: "/root/staging", Depth: 1},
+		},
+	}
+	newModel := func() Model {
+		m := NewModel(root, 1, testCommands, 3)
+		m.width, m.height, m.columnWidth, m.ready = 200, 30, 40, true
+		m.focusedColumn = 1
+		ti := textinput.New()
+		ti.SetValue("us")
+		m.columnFilters[1] = ti
+		return m
+	}
+	ansi := regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	matched := selectedItemStyle.UnsetPadding().Bold(true).Underline(true)
+
+	t.Run("matched characters are bold and underlined", func(t *testing.T) {
+		m := newModel()
+		list := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildNavigationList(0)
+		line := strings.Split(list, "\n")[0]
+
+		assert.Contains(t, line, matched.Render("us"))
+		assert.NotContains(t, line, matched.Render("dev-"))
+		assert.Equal(t, "►  dev-us", strings.TrimRight(ansi.ReplaceAllString(line, ""), " "))
+	})
+
+	t.Run("fuzzy matches highlight each matched rune", func(t *testing.T) {
+		m := newModel().WithFuzzyFilter(true)
+		ti := m.columnFilters[1]
+		ti.SetValue("dvus")
+		m.columnFilters[1] = ti
+		list := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildNavigationList(0)
+		line := strings.Split(list, "\n")[0]
+
+		assert.Contains(t, line, matched.Render("d"))
+		assert.Contains(t, line, matched.Render("v"))
+		assert.Contains(t, line, matched.Render("us"))
+	})
+
+	t.Run("commands with icons highlight past the icon", func(t *testing.T) {
+		m := newModel().WithCommandIcons(map[string]string{"plan": "📋"}, true)
+		m.focusedColumn = 0
+		ti := textinput.New()
+		ti.SetValue("pla")
+		m.columnFilters[0] = ti
+		list := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildCommandList()
+		line := strings.Split(list, "\n")[0]
+
+		assert.Contains(t, line, matched.Render("pla"))
+		assert.Contains(t, ansi.ReplaceAllString(line, ""), "📋 plan")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		m := newModel().WithHighlightMatches(false)
+		list := NewRenderer(m, NewLayoutCalculator(m.width, m.height, m.columnWidth)).buildNavigationList(0)
+
+		assert.NotContains(t, list, matched.Render("us"))
+		assert.Contains(t, list, selectedItemStyle.Render("dev-us"))
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/israoo/terrax/internal/bounds"
 	"github.com/israoo/terrax/internal/plan"
 )
 
@@ -87,9 +88,9 @@ func (m Model) renderPlanReviewView() string {
 
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		headerStyle.Width(m.width).Render("🔎 Plan Viewer"),
+		m.theme.header().Width(m.width).Render("🔎 Plan Viewer"),
 		mainContent,
-		footerStyle.Render(PlanHelpText),
+		footerStyle.Render(m.Text(MsgPlanHelpText)),
 	)
 }
 
@@ -230,7 +231,7 @@ func (m Model) renderPlanDetailView() string {
 
 // getPlanDetailLines generates the strict-wrapped lines for the detail view
 func (m Model) getPlanDetailLines() []string {
-	if m.planListCursor < 0 || m.planListCursor >= len(m.planFlatItems) {
+	if !bounds.InRange(m.planListCursor, len(m.planFlatItems)) {
 		return []string{"Select an item to view details"}
 	}
 
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// stackPreview is the cached terragrunt.hcl preview of a stack.
+type stackPreview struct {
+	stack.ConfigPreview
+	found bool // false when the stack has no readable terragrunt.hcl
+}
+
+// WithPreviewLines returns a copy of the model whose preview pane reads at most n lines of
+// each terragrunt.hcl. Values below 1 keep the default.
+func (m Model) WithPreviewLines(n int) Model {
+	if n < 1 {
+		n = config.DefaultPreviewLines
+	}
+	if n != m.previewLines {
+		m.previews = make(map[string]stackPreview)
+	}
+	m.previewLines = n
+	return m
+}
+
+// previewPaneWidth returns the width of the preview pane, or 0 when it is hidden.
+func (m Model) previewPaneWidth() int {
+	if !m.previewPane {
+		return 0
+	}
+	return max(m.width/PreviewWidthRatio, MinPreviewWidth)
+}
+
+// focusedNode returns the node the current selection resolves to: the tree root from the
+// commands column, otherwise the focused navigation item.
+func (m Model) focusedNode() *stack.Node {
+	if m.navigator == nil {
+		return nil
+	}
+	if m.isCommandsColumnFocused() {
+		return m.navigator.GetRoot()
+	}
+	return m.navigator.GetNodeAtDepth(m.navState, m.getNavigationDepth())
+}
+
+// focusedPreview returns the terragrunt.hcl preview of the focused node. The file is read
+// the first time the node is previewed and cached; nodes that are not stacks have none.
+func (m *Model) focusedPreview() stackPreview {
+	node := m.focusedNode()
+	if node == nil || !node.IsStack {
+		return stackPreview{}
+	}
+
+	if preview, cached := m.previews[node.Path]; cached {
+		return preview
+	}
+	configPreview, err := stack.ReadConfigPreview(node.Path, m.previewLines)
+	preview := stackPreview{ConfigPreview: configPreview, found: err == nil}
+	if m.previews != nil {
+		m.previews[node.Path] = preview
+	}
+	return preview
+}
+
+// renderPreviewPane renders the focused stack's metadata and the first lines of its
+// terragrunt.hcl with syntax highlighting. Lines that do not fit, or were not read, are counted in a note.
+func (r *Renderer) renderPreviewPane() string {
+	innerWidth := max(r.layout.GetPreviewWidth()-PreviewFrameWidth, 1)
+	innerHeight := max(r.layout.GetContentHeight()-2, 1) // Top and bottom border
+
+	lines := []string{titleStyle.Render("terragrunt.hcl")}
+	if info := r.model.focusedStackInfo(); info != "" {
+		lines = append(lines, previewNoteStyle.Render(truncateText(info, innerWidth)))
+	}
+	preview := r.model.focusedPreview()
+	if !preview.found {
+		lines = append(lines, previewNoteStyle.Render(r.model.Text(MsgPreviewNoConfig)))
+	} else {
+		// Keep a line for the note when the file does not fit.
+		fits := innerHeight - len(lines)
+		shown := preview.Lines
+		if preview.TotalLines > len(shown) || len(shown) > fits {
+			shown = shown[:min(len(shown), max(fits-1, 0))]
+		}
+		for _, line := range shown {
+			line = strings.ReplaceAll(line, "\t", "  ")
+			lines = append(lines, highlightHCL(truncateText(line, innerWidth)))
+		}
+		if hidden := preview.TotalLines - len(shown); hidden > 0 {
+			lines = append(lines, previewNoteStyle.Render(fmt.Sprintf(r.model.Text(MsgPreviewMoreLines), hidden)))
+		}
+	}
+
+	return previewPaneStyle.
+		Width(innerWidth + PreviewFrameWidth - 2). // Width includes padding but not the border
+		Height(innerHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// highlightHCL colors one line of HCL: comments, quoted strings, the identifier a line
+// starts with (block type or attribute name) and braces, brackets and "=".
+func highlightHCL(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "//") {
+		return hclCommentStyle.Render(line)
+	}
+
+	var b strings.Builder
+	indent := line[:len(line)-len(trimmed)]
+	b.WriteString(indent)
+
+	identEnd := strings.IndexFunc(trimmed, func(r rune) bool {
+		return !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if identEnd < 0 {
+		identEnd = len(trimmed)
+	}
+	if identEnd > 0 {
+		b.WriteString(hclIdentifierStyle.Render(trimmed[:identEnd]))
+	}
+
+	rest := trimmed[identEnd:]
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == '"':
+			end := closingQuote(rest, i)
+			b.WriteString(hclStringStyle.Render(rest[i:end]))
+			i = end - 1
+		case c == '#':
+			b.WriteString(hclCommentStyle.Render(rest[i:]))
+			return b.String()
+		case strings.IndexByte("{}[]=", c) >= 0:
+			b.WriteString(hclPunctuationStyle.Render(string(c)))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// closingQuote returns the index just past the string literal opened at start, or the
+// length of s when the literal is not closed on this line.
+func closingQuote(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
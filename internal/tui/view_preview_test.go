@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/internal/stack"
+)
+
+// newPreviewModel returns a ready model over a tree with a stack whose terragrunt.hcl holds
+// content, a plain directory, and the preview pane shown.
+func newPreviewModel(t *testing.T, content string) Model {
+	t.Helper()
+	rootDir := t.TempDir()
+	stackDir := filepath.Join(rootDir, "app")
+	require.NoError(t, os.MkdirAll(stackDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(stackDir, "terragrunt.hcl"), []byte(content), 0644))
+
+	root := &stack.Node{
+		Name: "root",
+		Path: rootDir,
+		Children: []*stack.Node{
+			{Name: "app", Path: stackDir, IsStack: true},
+			{Name: "modules", Path: filepath.Join(rootDir, "modules")},
+		},
+	}
+	m := NewModel(root, 1, testCommands, 3)
+	m.ready = true
+	m.width = 120
+	m.height = 30
+	m.columnWidth = m.calculateColumnWidth()
+	m.focusedColumn = 1
+
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(KeyPreview)})
+	return updated.(Model)
+}
+
+// renderPreview renders the navigation view of m.
+func renderPreview(m Model) string {
+	layout := NewLayoutCalculator(m.width, m.height, m.columnWidth).WithPreviewWidth(m.previewPaneWidth())
+	return NewRenderer(m, layout).Render()
+}
+
+// TestPreviewPane_Toggle tests that p shows the pane and narrows the columns to make room.
+func TestPreviewPane_Toggle(t *testing.T) {
+	m := newPreviewModel(t, "inputs = {}\n")
+	require.True(t, m.previewPane)
+	assert.Equal(t, 40, m.previewPaneWidth())
+
+	hidden := m.handlePreviewToggle()
+	assert.False(t, hidden.previewPane)
+	assert.Zero(t, hidden.previewPaneWidth())
+	assert.Less(t, m.columnWidth, hidden.columnWidth, "columns give up width to the pane")
+}
+
+// TestRenderPreviewPane tests the pane content for a stack, a plain directory and a file
+// longer than the pane.
+func TestRenderPreviewPane(t *testing.T) {
+	t.Run("stack config", func(t *testing.T) {
+		m := newPreviewModel(t, "include \"root\" {\n  path = find_in_parent_folders(\"root.hcl\")\n}\n")
+		output := renderPreview(m)
+
+		assert.Contains(t, output, "terragrunt.hcl")
+		assert.Contains(t, output, `include "root" {`)
+		assert.Contains(t, output, "find_in_parent_folders")
+		assert.NotContains(t, output, "more lines")
+		for _, line := range strings.Split(output, "\n") {
+			assert.LessOrEqual(t, lipgloss.Width(line), m.width, "view fits the terminal")
+		}
+	})
+
+	t.Run("not a stack", func(t *testing.T) {
+		m := newPreviewModel(t, "inputs = {}\n")
+		m = m.handleVerticalMove(false) // modules
+		assert.Contains(t, renderPreview(m), "No config")
+	})
+
+	t.Run("large file is truncated", func(t *testing.T) {
+		var content strings.Builder
+		for i := 1; i <= 100; i++ {
+			fmt.Fprintf(&content, "key_%03d = %d\n", i, i)
+		}
+		m := newPreviewModel(t, content.String())
+		output := renderPreview(m)
+
+		// 30 rows leave 24 inside the pane: the title, 22 lines and the note.
+		assert.Contains(t, output, "key_022")
+		assert.NotContains(t, output, "key_023")
+		assert.Contains(t, output, "… 78 more lines")
+		assert.LessOrEqual(t, lipgloss.Height(output), m.height)
+	})
+}
+
+// TestHighlightHCL tests that highlighting colors HCL without changing its text.
+func TestHighlightHCL(t *testing.T) {
+	original := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(original) })
+
+	ansi := regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	for _, line := range []string{
+		`include "root" {`,
+		`  path = find_in_parent_folders("root.hcl") # shared`,
+		`  source = "git::https://example.com/\"quoted\".git"`,
+		`# comment`,
+		`}`,
+		``,
+	} {
+		highlighted := highlightHCL(line)
+		assert.Equal(t, line, ansi.ReplaceAllString(highlighted, ""))
+		if strings.TrimSpace(line) != "" {
+			assert.NotEqual(t, line, highlighted, "line is colored")
+		}
+	}
+
+	assert.Equal(t, hclCommentStyle.Render("  # comment"), highlightHCL("  # comment"))
+	assert.Contains(t, highlightHCL(`x = "a # b"`), hclStringStyle.Render(`"a # b"`), "# inside a string is not a comment")
+}
@@ -0,0 +1,38 @@
+// Package terrax embeds the TerraX stack navigator in other tools: build the stack tree of
+// a directory, show the TUI and get back what the user selected. It never exits the
+// process and runs nothing; executing the selection is left to the caller.
+package terrax
+
+import (
+	"github.com/israoo/terrax/internal/config"
+	"github.com/israoo/terrax/internal/stack"
+	"github.com/israoo/terrax/internal/tui"
+)
+
+type (
+	// Node is a directory of the stack tree.
+	Node = stack.Node
+
+	// Settings holds the commands, columns and display settings of the TUI. Zero values
+	// mean what they mean in the configuration file, e.g. a ColumnGap of 0 is no gap.
+	Settings = config.TUI
+
+	// Config holds the settings for Run.
+	Config = tui.Config
+
+	// Selection is the outcome of a TUI session.
+	Selection = tui.Selection
+)
+
+// BuildTree scans dir for stacks and returns the tree with its deepest level, to pass to Run
+// as the root and Config.MaxDepth. rootConfigFile names the file marking the project root;
+// empty uses root.hcl.
+func BuildTree(dir, rootConfigFile string) (*Node, int, error) {
+	return stack.FindAndBuildTree(dir, rootConfigFile)
+}
+
+// Run shows the navigation TUI for root and returns what the user selected. A cancelled
+// session returns a Selection with Confirmed unset.
+func Run(root *Node, cfg Config) (Selection, error) {
+	return tui.Run(root, cfg)
+}
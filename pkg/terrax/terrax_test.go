@@ -0,0 +1,47 @@
+package terrax_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/israoo/terrax/pkg/terrax"
+)
+
+// TestBuildTree tests that the tree of a directory can be built from outside the module.
+func TestBuildTree(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, dir := range []string{"dev/vpc", "prod/vpc"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, dir), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, dir, "terragrunt.hcl"), []byte(""), 0644))
+	}
+
+	root, maxDepth, err := terrax.BuildTree(tmpDir, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, maxDepth)
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "dev", root.Children[0].Name)
+	assert.Equal(t, "prod", root.Children[1].Name)
+}
+
+// ExampleRun shows a tool picking a stack and a command with the TerraX TUI.
+func ExampleRun() {
+	root, maxDepth, err := terrax.BuildTree(".", "")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	sel, err := terrax.Run(root, terrax.Config{
+		MaxDepth: maxDepth,
+		Settings: terrax.Settings{Commands: []string{"plan", "apply"}},
+	})
+	if err != nil || !sel.Confirmed {
+		return
+	}
+	fmt.Println(sel.Command, sel.StackPaths)
+}